@@ -3,6 +3,8 @@ package client
 import (
 	"io"
 	"math"
+	"path"
+	"strings"
 
 	"github.com/pachyderm/pachyderm/src/client/pfs"
 	"go.pedge.io/proto/stream"
@@ -501,6 +503,65 @@ func (c APIClient) listFile(repoName string, commitID string, path string, fromC
 	return fileInfos.FileInfo, nil
 }
 
+// ListFileStream is like ListFile but sends the infos on the returned
+// channel as they arrive, so they don't all have to be held at once. The
+// channel is closed once they've all been sent, then the error channel
+// gets the error the listing failed with, if any. Cancelling ctx stops the
+// listing.
+func (c APIClient) ListFileStream(ctx context.Context, repoName string, commitID string, path string, fromCommitID string,
+	shard *pfs.Shard, recurse bool) (<-chan *pfs.FileInfo, <-chan error) {
+	return c.listFileStream(ctx, repoName, commitID, path, fromCommitID, shard, recurse, false, "")
+}
+
+// ListFileStreamUnsafe is identical to ListFileStream except that it will
+// consider files in unfinished commits.
+func (c APIClient) ListFileStreamUnsafe(ctx context.Context, repoName string, commitID string, path string, fromCommitID string,
+	shard *pfs.Shard, recurse bool, handle string) (<-chan *pfs.FileInfo, <-chan error) {
+	return c.listFileStream(ctx, repoName, commitID, path, fromCommitID, shard, recurse, true, handle)
+}
+
+func (c APIClient) listFileStream(ctx context.Context, repoName string, commitID string, path string, fromCommitID string,
+	shard *pfs.Shard, recurse bool, unsafe bool, handle string) (<-chan *pfs.FileInfo, <-chan error) {
+	fileInfos := make(chan *pfs.FileInfo)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(errCh)
+		defer close(fileInfos)
+		listFileStreamClient, err := c.PfsAPIClient.ListFileStream(
+			ctx,
+			&pfs.ListFileRequest{
+				File:       NewFile(repoName, commitID, path),
+				Shard:      shard,
+				FromCommit: newFromCommit(repoName, fromCommitID),
+				Recurse:    recurse,
+				Unsafe:     unsafe,
+				Handle:     handle,
+			},
+		)
+		if err != nil {
+			errCh <- sanitizeErr(err)
+			return
+		}
+		for {
+			fileInfo, err := listFileStreamClient.Recv()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				errCh <- sanitizeErr(err)
+				return
+			}
+			select {
+			case fileInfos <- fileInfo:
+			case <-ctx.Done():
+				errCh <- ctx.Err()
+				return
+			}
+		}
+	}()
+	return fileInfos, errCh
+}
+
 // DeleteFile deletes a file from a Commit.
 // DeleteFile leaves a tombstone in the Commit, assuming the file isn't written
 // to later attempting to get the file from the finished commit will result in
@@ -518,6 +579,70 @@ func (c APIClient) DeleteFile(repoName string, commitID string, path string, uns
 	return err
 }
 
+// MoveFile moves the file or directory at oldPath in an unfinished Commit
+// to newPath, replacing whatever was at newPath. Directories are moved
+// along with everything in them. The move copies the data and then deletes
+// oldPath, it isn't atomic.
+// handle is passed to the PutFile, GetFile and DeleteFile calls that make
+// up the move, so that they see the Commit's dirty writes.
+func (c APIClient) MoveFile(repoName string, commitID string, oldPath string, newPath string, handle string) error {
+	fileInfo, err := c.PfsAPIClient.InspectFile(
+		context.Background(),
+		&pfs.InspectFileRequest{
+			File:   NewFile(repoName, commitID, oldPath),
+			Unsafe: true,
+			Handle: handle,
+		},
+	)
+	if err != nil {
+		// This isn't sanitized so that callers can tell from its code
+		// that there's nothing at oldPath.
+		return err
+	}
+	// If InspectFile fails for any reason other than newPath not existing
+	// the writes below will fail too.
+	if _, err := c.InspectFileUnsafe(repoName, commitID, newPath, "", nil, handle); err == nil {
+		if err := c.DeleteFile(repoName, commitID, newPath, true, handle); err != nil {
+			return sanitizeErr(err)
+		}
+	}
+	if err := c.copyFile(repoName, commitID, fileInfo, oldPath, newPath, handle); err != nil {
+		return err
+	}
+	return sanitizeErr(c.DeleteFile(repoName, commitID, oldPath, true, handle))
+}
+
+// copyFile copies the file described by fileInfo, which is at oldPath or
+// under it, to the same place under newPath.
+func (c APIClient) copyFile(repoName string, commitID string, fileInfo *pfs.FileInfo, oldPath string, newPath string, handle string) (retErr error) {
+	toPath := path.Join(newPath, strings.TrimPrefix(fileInfo.File.Path, oldPath))
+	if fileInfo.FileType == pfs.FileType_FILE_TYPE_DIR {
+		if err := c.MakeDirectory(repoName, commitID, toPath); err != nil {
+			return err
+		}
+		children, err := c.ListFileUnsafe(repoName, commitID, fileInfo.File.Path, "", nil, false, handle)
+		if err != nil {
+			return err
+		}
+		for _, child := range children {
+			if err := c.copyFile(repoName, commitID, child, oldPath, newPath, handle); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	writer, err := c.PutFileWriter(repoName, commitID, toPath, pfs.Delimiter_LINE, handle)
+	if err != nil {
+		return sanitizeErr(err)
+	}
+	defer func() {
+		if err := writer.Close(); err != nil && retErr == nil {
+			retErr = sanitizeErr(err)
+		}
+	}()
+	return c.GetFileUnsafe(repoName, commitID, fileInfo.File.Path, 0, 0, "", nil, handle, writer)
+}
+
 // MakeDirectory creates a directory in PFS.
 // Note directories are created implicitly by PutFile, so you technically never
 // need this function unless you want to create an empty directory.
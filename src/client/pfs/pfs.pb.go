@@ -1200,6 +1200,9 @@ type APIClient interface {
 	InspectFile(ctx context.Context, in *InspectFileRequest, opts ...grpc.CallOption) (*FileInfo, error)
 	// ListFile returns info about all files.
 	ListFile(ctx context.Context, in *ListFileRequest, opts ...grpc.CallOption) (*FileInfos, error)
+	// ListFileStream is like ListFile but streams the infos one at a time so
+	// large directories don't need one huge message.
+	ListFileStream(ctx context.Context, in *ListFileRequest, opts ...grpc.CallOption) (API_ListFileStreamClient, error)
 	// DeleteFile deletes a file.
 	DeleteFile(ctx context.Context, in *DeleteFileRequest, opts ...grpc.CallOption) (*google_protobuf1.Empty, error)
 }
@@ -1395,6 +1398,38 @@ func (c *aPIClient) ListFile(ctx context.Context, in *ListFileRequest, opts ...g
 	return out, nil
 }
 
+func (c *aPIClient) ListFileStream(ctx context.Context, in *ListFileRequest, opts ...grpc.CallOption) (API_ListFileStreamClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_API_serviceDesc.Streams[2], c.cc, "/pfs.API/ListFileStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &aPIListFileStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type API_ListFileStreamClient interface {
+	Recv() (*FileInfo, error)
+	grpc.ClientStream
+}
+
+type aPIListFileStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *aPIListFileStreamClient) Recv() (*FileInfo, error) {
+	m := new(FileInfo)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *aPIClient) DeleteFile(ctx context.Context, in *DeleteFileRequest, opts ...grpc.CallOption) (*google_protobuf1.Empty, error) {
 	out := new(google_protobuf1.Empty)
 	err := grpc.Invoke(ctx, "/pfs.API/DeleteFile", in, out, c.cc, opts...)
@@ -1441,6 +1476,9 @@ type APIServer interface {
 	InspectFile(context.Context, *InspectFileRequest) (*FileInfo, error)
 	// ListFile returns info about all files.
 	ListFile(context.Context, *ListFileRequest) (*FileInfos, error)
+	// ListFileStream is like ListFile but streams the infos one at a time so
+	// large directories don't need one huge message.
+	ListFileStream(*ListFileRequest, API_ListFileStreamServer) error
 	// DeleteFile deletes a file.
 	DeleteFile(context.Context, *DeleteFileRequest) (*google_protobuf1.Empty, error)
 }
@@ -1730,6 +1768,27 @@ func _API_ListFile_Handler(srv interface{}, ctx context.Context, dec func(interf
 	return interceptor(ctx, in, info, handler)
 }
 
+func _API_ListFileStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListFileRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(APIServer).ListFileStream(m, &aPIListFileStreamServer{stream})
+}
+
+type API_ListFileStreamServer interface {
+	Send(*FileInfo) error
+	grpc.ServerStream
+}
+
+type aPIListFileStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *aPIListFileStreamServer) Send(m *FileInfo) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _API_DeleteFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DeleteFileRequest)
 	if err := dec(in); err != nil {
@@ -1820,6 +1879,11 @@ var _API_serviceDesc = grpc.ServiceDesc{
 			Handler:       _API_GetFile_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "ListFileStream",
+			Handler:       _API_ListFileStream_Handler,
+			ServerStreams: true,
+		},
 	},
 }
 
@@ -1860,6 +1924,9 @@ type InternalAPIClient interface {
 	InspectFile(ctx context.Context, in *InspectFileRequest, opts ...grpc.CallOption) (*FileInfo, error)
 	// ListFile returns info about all files.
 	ListFile(ctx context.Context, in *ListFileRequest, opts ...grpc.CallOption) (*FileInfos, error)
+	// ListFileStream is like ListFile but streams the infos one at a time, as
+	// the driver produces them.
+	ListFileStream(ctx context.Context, in *ListFileRequest, opts ...grpc.CallOption) (InternalAPI_ListFileStreamClient, error)
 	// DeleteFile deletes a file.
 	DeleteFile(ctx context.Context, in *DeleteFileRequest, opts ...grpc.CallOption) (*google_protobuf1.Empty, error)
 }
@@ -2055,6 +2122,38 @@ func (c *internalAPIClient) ListFile(ctx context.Context, in *ListFileRequest, o
 	return out, nil
 }
 
+func (c *internalAPIClient) ListFileStream(ctx context.Context, in *ListFileRequest, opts ...grpc.CallOption) (InternalAPI_ListFileStreamClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_InternalAPI_serviceDesc.Streams[2], c.cc, "/pfs.InternalAPI/ListFileStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &internalAPIListFileStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type InternalAPI_ListFileStreamClient interface {
+	Recv() (*FileInfo, error)
+	grpc.ClientStream
+}
+
+type internalAPIListFileStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *internalAPIListFileStreamClient) Recv() (*FileInfo, error) {
+	m := new(FileInfo)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *internalAPIClient) DeleteFile(ctx context.Context, in *DeleteFileRequest, opts ...grpc.CallOption) (*google_protobuf1.Empty, error) {
 	out := new(google_protobuf1.Empty)
 	err := grpc.Invoke(ctx, "/pfs.InternalAPI/DeleteFile", in, out, c.cc, opts...)
@@ -2101,6 +2200,9 @@ type InternalAPIServer interface {
 	InspectFile(context.Context, *InspectFileRequest) (*FileInfo, error)
 	// ListFile returns info about all files.
 	ListFile(context.Context, *ListFileRequest) (*FileInfos, error)
+	// ListFileStream is like ListFile but streams the infos one at a time, as
+	// the driver produces them.
+	ListFileStream(*ListFileRequest, InternalAPI_ListFileStreamServer) error
 	// DeleteFile deletes a file.
 	DeleteFile(context.Context, *DeleteFileRequest) (*google_protobuf1.Empty, error)
 }
@@ -2390,6 +2492,27 @@ func _InternalAPI_ListFile_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _InternalAPI_ListFileStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListFileRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(InternalAPIServer).ListFileStream(m, &internalAPIListFileStreamServer{stream})
+}
+
+type InternalAPI_ListFileStreamServer interface {
+	Send(*FileInfo) error
+	grpc.ServerStream
+}
+
+type internalAPIListFileStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *internalAPIListFileStreamServer) Send(m *FileInfo) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _InternalAPI_DeleteFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DeleteFileRequest)
 	if err := dec(in); err != nil {
@@ -2480,6 +2603,11 @@ var _InternalAPI_serviceDesc = grpc.ServiceDesc{
 			Handler:       _InternalAPI_GetFile_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "ListFileStream",
+			Handler:       _InternalAPI_ListFileStream_Handler,
+			ServerStreams: true,
+		},
 	},
 }
 
@@ -2900,134 +3028,136 @@ var _BlockAPI_serviceDesc = grpc.ServiceDesc{
 }
 
 var fileDescriptor0 = []byte{
-	// 2063 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xec, 0x59, 0x4b, 0x8f, 0xdb, 0xd6,
-	0x15, 0x36, 0x45, 0x4a, 0xa2, 0x8e, 0x66, 0x34, 0xe3, 0x6b, 0xc7, 0x9d, 0xd0, 0x6e, 0x32, 0x65,
-	0xd2, 0x36, 0x71, 0xd2, 0x99, 0x60, 0x9c, 0x17, 0x9c, 0xb6, 0xce, 0xd8, 0x23, 0x3b, 0x0a, 0xfc,
-	0x02, 0x3d, 0x69, 0xd1, 0x45, 0x21, 0x50, 0x1a, 0xca, 0x43, 0x58, 0xa2, 0x54, 0x92, 0x4a, 0x30,
-	0x5d, 0x16, 0xe8, 0xa2, 0xdd, 0x76, 0x5d, 0x74, 0xdd, 0x75, 0x17, 0xfd, 0x0f, 0xfe, 0x0b, 0x45,
-	0xd7, 0x5d, 0xf7, 0x1f, 0xf4, 0xdc, 0x73, 0x2f, 0xa9, 0x7b, 0x49, 0x3d, 0x03, 0xa4, 0x2d, 0x90,
-	0x2c, 0x12, 0xdf, 0xd7, 0x39, 0xf7, 0x3c, 0xbe, 0x73, 0xee, 0x27, 0x0e, 0x5c, 0xed, 0x0f, 0xc3,
-	0x20, 0x4a, 0x0f, 0x27, 0x83, 0x84, 0xff, 0x77, 0x30, 0x89, 0xc7, 0xe9, 0x98, 0x99, 0x38, 0x74,
-	0x6e, 0x3c, 0x1f, 0x8f, 0x9f, 0x0f, 0x83, 0x43, 0x7f, 0x12, 0x1e, 0xfa, 0x51, 0x34, 0x4e, 0xfd,
-	0x34, 0x1c, 0x47, 0xf2, 0x88, 0x73, 0x5d, 0xee, 0xd2, 0xac, 0x37, 0x1d, 0x1c, 0x06, 0xa3, 0x49,
-	0x7a, 0x21, 0x37, 0x5f, 0x2f, 0x6e, 0xa6, 0xe1, 0x28, 0x48, 0x52, 0x7f, 0x34, 0x91, 0x07, 0x5e,
-	0x2b, 0x1e, 0xf8, 0x2a, 0xf6, 0x27, 0x93, 0x20, 0xce, 0xb4, 0xdf, 0xc8, 0xcc, 0x7a, 0xf1, 0xfc,
-	0x30, 0x39, 0xf7, 0xe3, 0x33, 0xf1, 0x7f, 0xb1, 0xeb, 0x3a, 0x60, 0x79, 0xc1, 0x64, 0xcc, 0x18,
-	0x58, 0x91, 0x3f, 0x0a, 0xf6, 0x8c, 0x7d, 0xe3, 0xad, 0x86, 0x47, 0x63, 0xf7, 0x23, 0xa8, 0xdd,
-	0x1b, 0x8f, 0x46, 0x61, 0xca, 0xbe, 0x0f, 0x56, 0x8c, 0xa7, 0x68, 0xb7, 0x79, 0xd4, 0x38, 0xe0,
-	0xee, 0x71, 0x31, 0x8f, 0x96, 0x59, 0x0b, 0x2a, 0xe1, 0xd9, 0x5e, 0x85, 0x44, 0x71, 0xe4, 0xde,
-	0x01, 0xeb, 0x7e, 0x38, 0x0c, 0xd8, 0x1b, 0x50, 0xeb, 0x93, 0x02, 0x29, 0xd8, 0x24, 0x41, 0xa1,
-	0xd3, 0x93, 0x5b, 0xfc, 0xe6, 0x89, 0x9f, 0x9e, 0x4b, 0x71, 0x1a, 0xbb, 0xd7, 0xa1, 0x7a, 0x77,
-	0x38, 0xee, 0xbf, 0xe0, 0x9b, 0xe7, 0x7e, 0x72, 0x9e, 0x99, 0xc5, 0xc7, 0xee, 0x31, 0x58, 0x27,
-	0xe1, 0x60, 0xb0, 0x9e, 0xf6, 0xab, 0x50, 0x25, 0x77, 0x49, 0xbd, 0xe5, 0x89, 0x89, 0xfb, 0x57,
-	0x03, 0x6c, 0x6e, 0x7f, 0x27, 0x1a, 0x8c, 0x57, 0x39, 0xf7, 0x3e, 0xd4, 0xfb, 0x71, 0xe0, 0xa7,
-	0x81, 0xd0, 0xd1, 0x3c, 0x72, 0x0e, 0x44, 0xc4, 0x0f, 0xb2, 0x88, 0x1f, 0x9c, 0x66, 0x29, 0xf1,
-	0xb2, 0xa3, 0xa8, 0x14, 0x92, 0xf0, 0xb7, 0x41, 0xb7, 0x77, 0x91, 0x06, 0xc9, 0x9e, 0x49, 0x97,
-	0x37, 0xf8, 0xca, 0x5d, 0xbe, 0xc0, 0xde, 0x06, 0x40, 0xe9, 0x2f, 0x83, 0xc8, 0x8f, 0xfa, 0xc1,
-	0x9e, 0xb5, 0x6f, 0xea, 0x37, 0x2b, 0x9b, 0x98, 0x85, 0x46, 0x66, 0x6a, 0xc2, 0x6e, 0x42, 0x83,
-	0x1b, 0xd5, 0x0d, 0x71, 0x86, 0x06, 0x73, 0xb1, 0xed, 0x5c, 0x8c, 0x1f, 0xf1, 0xec, 0x58, 0x8e,
-	0xdc, 0xdf, 0x9b, 0x00, 0x22, 0x1a, 0xe4, 0xe6, 0x5a, 0xe1, 0xba, 0x06, 0xb5, 0x5e, 0x8c, 0xd7,
-	0x66, 0xe9, 0x90, 0x33, 0xf6, 0x1e, 0x34, 0xc5, 0x89, 0x6e, 0x7a, 0x31, 0x09, 0xc8, 0x9f, 0xd6,
-	0xd1, 0x8e, 0xa2, 0xe1, 0x14, 0x97, 0x3d, 0xe8, 0xe7, 0x63, 0x94, 0xd8, 0x9e, 0xf8, 0x31, 0x02,
-	0xaf, 0x2b, 0x6f, 0xb5, 0xca, 0xb7, 0x6e, 0x89, 0x13, 0x12, 0x64, 0x18, 0x68, 0x0c, 0x62, 0xcc,
-	0x03, 0x5d, 0x5d, 0x1d, 0x68, 0x79, 0x94, 0x7d, 0x08, 0xf6, 0x20, 0x8c, 0xc2, 0xe4, 0x1c, 0xc5,
-	0x6a, 0x2b, 0xc5, 0xf2, 0xb3, 0x85, 0x04, 0xd5, 0x8b, 0x09, 0xba, 0x01, 0x8d, 0x3e, 0x0f, 0xff,
-	0x70, 0x88, 0x7a, 0x6d, 0xdc, 0xb5, 0xbd, 0xd9, 0x02, 0x7b, 0x47, 0x4b, 0x5f, 0x83, 0xf2, 0xa0,
-	0x79, 0xa6, 0x26, 0xf0, 0x0e, 0x34, 0x67, 0x69, 0x48, 0x94, 0x50, 0x2a, 0x49, 0x54, 0x43, 0x49,
-	0x69, 0x94, 0xa1, 0xa4, 0x44, 0xfe, 0xa1, 0x02, 0x36, 0xaf, 0xa7, 0x0c, 0xad, 0x03, 0x1c, 0x6b,
-	0x68, 0xe5, 0x9b, 0x1e, 0x2d, 0x73, 0x80, 0xf0, 0x7f, 0x45, 0x9a, 0x2a, 0x94, 0xa6, 0xed, 0xfc,
-	0x0c, 0x25, 0xc9, 0x1e, 0xc8, 0xd1, 0x2a, 0x8c, 0x62, 0x64, 0x47, 0xe3, 0xb3, 0x70, 0x10, 0x62,
-	0x04, 0xac, 0xd5, 0x91, 0xcd, 0xce, 0x62, 0x1e, 0x77, 0xa4, 0x83, 0xb9, 0x78, 0xb5, 0x9c, 0xfb,
-	0x96, 0x38, 0xf3, 0x28, 0x93, 0xfa, 0x21, 0xd8, 0xfd, 0xf3, 0x70, 0x78, 0x86, 0x80, 0xc0, 0x3c,
-	0x9a, 0xba, 0x6f, 0xf9, 0x16, 0xaf, 0x86, 0x2c, 0x14, 0x49, 0xee, 0x6c, 0xa9, 0x1a, 0xb2, 0x23,
-	0xc2, 0x59, 0x0a, 0x22, 0x0a, 0x72, 0xb7, 0x3c, 0x3f, 0x7a, 0x1e, 0xf0, 0xae, 0x30, 0x1c, 0x7f,
-	0x15, 0xc4, 0x14, 0x45, 0xec, 0x0a, 0x34, 0xe1, 0xab, 0x53, 0xde, 0x39, 0xb3, 0x5e, 0x41, 0x13,
-	0xd7, 0x03, 0x9b, 0x7a, 0x91, 0x17, 0x0c, 0xd8, 0x3e, 0x54, 0x7b, 0x7c, 0x2c, 0xa3, 0x0f, 0x74,
-	0x99, 0xd8, 0x15, 0x1b, 0xec, 0x4d, 0xa8, 0xc6, 0xfc, 0x0a, 0xd9, 0x2b, 0x5a, 0xe2, 0x44, 0x76,
-	0xb1, 0x27, 0x36, 0xc9, 0x18, 0xa9, 0x93, 0xbc, 0x20, 0xd9, 0x6e, 0x1c, 0x0c, 0x34, 0x2f, 0xb2,
-	0x23, 0x9e, 0xdd, 0x93, 0x23, 0xf7, 0x2f, 0x16, 0xd4, 0x8e, 0xd1, 0xac, 0xe8, 0x8c, 0xbd, 0x0b,
-	0x90, 0x8b, 0x25, 0xf3, 0xe5, 0x1a, 0xbd, 0xfc, 0x92, 0x0f, 0x94, 0xf0, 0x56, 0xe8, 0xec, 0xab,
-	0x74, 0x56, 0x28, 0x3b, 0xb8, 0x27, 0xf7, 0xda, 0x51, 0x1a, 0x5f, 0xcc, 0xc2, 0xcd, 0x7e, 0x04,
-	0xf6, 0xd0, 0x4f, 0x52, 0x32, 0xcd, 0x2c, 0x27, 0xb1, 0xce, 0x37, 0x79, 0x60, 0xb0, 0x6f, 0x9c,
-	0x05, 0xc3, 0x20, 0x0d, 0x08, 0x29, 0xb6, 0x27, 0x67, 0xec, 0x08, 0xea, 0xe7, 0x7e, 0x74, 0x36,
-	0x44, 0x7c, 0x55, 0xe9, 0xd6, 0x3d, 0xf5, 0xd6, 0xcf, 0xc4, 0x96, 0xb8, 0x34, 0x3b, 0xc8, 0xda,
-	0xd0, 0x12, 0xc3, 0xae, 0x50, 0x92, 0x48, 0x3c, 0xbc, 0x56, 0x16, 0x3d, 0x11, 0x07, 0x84, 0x82,
-	0xed, 0x73, 0x75, 0x4d, 0xaf, 0x84, 0xfa, 0xd2, 0x4a, 0x70, 0x3e, 0x81, 0x6d, 0x2d, 0x02, 0x6c,
-	0x17, 0xcc, 0x17, 0xc1, 0x85, 0x7c, 0x76, 0xf8, 0x90, 0x83, 0xe3, 0x4b, 0x7f, 0x38, 0x15, 0x89,
-	0xb5, 0x3d, 0x31, 0xb9, 0x5d, 0xf9, 0xd8, 0x70, 0x3e, 0x87, 0x2d, 0xd5, 0x91, 0x39, 0xb2, 0x6f,
-	0xaa, 0xb2, 0x39, 0x28, 0xb2, 0xdc, 0xa8, 0xba, 0x3e, 0x05, 0x56, 0xf6, 0x6c, 0x13, 0x6b, 0xdc,
-	0xdf, 0x19, 0x12, 0x5b, 0xd4, 0x2d, 0x56, 0x03, 0xf6, 0x9b, 0x78, 0xde, 0xdc, 0x4f, 0x00, 0x72,
-	0x1b, 0x12, 0xf6, 0x93, 0x0c, 0xa9, 0x4a, 0x9d, 0x2a, 0x31, 0xa0, 0x42, 0x15, 0x50, 0xa5, 0x4a,
-	0x7d, 0x69, 0x82, 0xcd, 0x1f, 0xf8, 0xac, 0xdd, 0x61, 0x83, 0x18, 0x68, 0xed, 0x8e, 0x6f, 0x7a,
-	0xb4, 0x5c, 0x7e, 0x65, 0x2a, 0xab, 0x5e, 0x99, 0xd9, 0x0b, 0x67, 0x6a, 0x2f, 0x9c, 0xf2, 0xfa,
-	0x58, 0x5f, 0xef, 0xf5, 0xa9, 0x6e, 0xf0, 0xfa, 0xe0, 0x6d, 0x3e, 0x01, 0x39, 0x03, 0xb7, 0x93,
-	0x7b, 0xc6, 0xdd, 0x96, 0x28, 0xcf, 0x2a, 0x43, 0x1e, 0xfd, 0xef, 0xbd, 0x59, 0xce, 0x03, 0xd8,
-	0x52, 0x4d, 0x98, 0x83, 0xc0, 0x1f, 0xe8, 0x98, 0x6e, 0x2a, 0xc5, 0xa9, 0xc2, 0xf1, 0x4f, 0x06,
-	0x54, 0x9f, 0x71, 0xce, 0xc5, 0x5e, 0x87, 0x26, 0xd5, 0x63, 0x34, 0x1d, 0xf5, 0xf2, 0xce, 0x0b,
-	0x7c, 0xe9, 0x31, 0xad, 0xa0, 0xc6, 0x2d, 0x3a, 0x80, 0xaf, 0xc6, 0x74, 0x38, 0x4d, 0x64, 0x17,
-	0x26, 0xa1, 0x47, 0x62, 0x89, 0x1f, 0x11, 0x48, 0x92, 0x4a, 0x04, 0xf0, 0x9a, 0xb4, 0x26, 0xb5,
-	0xbc, 0x01, 0xdb, 0xe2, 0x48, 0xa6, 0xc6, 0xa2, 0x33, 0x42, 0x4e, 0xea, 0xe1, 0x56, 0x5d, 0xbe,
-	0x47, 0x50, 0x26, 0xba, 0x15, 0xfc, 0x66, 0x8a, 0x19, 0xfa, 0x66, 0x88, 0xa0, 0xce, 0xf4, 0xcc,
-	0x65, 0x4c, 0xef, 0x16, 0xb0, 0x4e, 0x94, 0x4c, 0x82, 0x7e, 0xba, 0xbe, 0x55, 0xee, 0x4f, 0x61,
-	0xe7, 0x61, 0x98, 0x68, 0x12, 0xfa, 0x95, 0xc6, 0xb2, 0x2b, 0x8f, 0xe0, 0xb2, 0xe8, 0x34, 0x1b,
-	0xdc, 0xf8, 0x0f, 0x03, 0xd8, 0x33, 0x8e, 0x7f, 0x89, 0x9b, 0xf5, 0xa2, 0x57, 0xf8, 0x8d, 0xc0,
-	0xae, 0x43, 0x43, 0x56, 0x2e, 0x2e, 0x8b, 0x52, 0xb4, 0xc5, 0x42, 0xe7, 0x4c, 0x29, 0x52, 0x6b,
-	0x51, 0x91, 0x6e, 0x40, 0x11, 0x75, 0xe4, 0xd7, 0x96, 0xb3, 0xb5, 0x3f, 0x1a, 0x70, 0xe5, 0x3e,
-	0x95, 0xa9, 0xee, 0xde, 0xba, 0xf4, 0x59, 0x14, 0x9c, 0xec, 0xcb, 0x72, 0xa6, 0xb5, 0x09, 0x73,
-	0xfd, 0x36, 0x81, 0x7d, 0xf4, 0xaa, 0x44, 0xc4, 0xe6, 0xc6, 0xb8, 0xff, 0x42, 0x90, 0x73, 0x68,
-	0x2c, 0x4a, 0x93, 0x39, 0x2f, 0x4d, 0x05, 0xa2, 0x5f, 0x59, 0x4d, 0xf4, 0xdf, 0xc5, 0xba, 0x8e,
-	0xc7, 0xa3, 0xac, 0x01, 0x9b, 0x73, 0xc2, 0xcb, 0xf7, 0x65, 0xfb, 0x7d, 0x67, 0xce, 0x0f, 0x9f,
-	0x45, 0xb9, 0xe0, 0x5d, 0xc7, 0x1f, 0x0e, 0x29, 0xd5, 0xb6, 0xc7, 0x87, 0xfc, 0xdd, 0x13, 0xef,
-	0x59, 0x4d, 0xbc, 0x7b, 0x34, 0xe1, 0x28, 0xe6, 0x8e, 0xde, 0x25, 0x90, 0xac, 0x89, 0xe2, 0xdb,
-	0x70, 0x45, 0x20, 0xff, 0x6b, 0x44, 0xf6, 0xd7, 0xc0, 0xee, 0x63, 0x1b, 0x59, 0x82, 0x10, 0x73,
-	0x11, 0x42, 0x5c, 0xa8, 0xa7, 0xe3, 0x2e, 0x19, 0x56, 0x29, 0x66, 0xa0, 0x96, 0x8e, 0xf9, 0xbf,
-	0xee, 0xbf, 0x0d, 0x68, 0x3d, 0x08, 0x52, 0x62, 0xbe, 0x33, 0x67, 0x96, 0xb1, 0x7e, 0xec, 0x8b,
-	0xe3, 0xc1, 0x20, 0x09, 0x52, 0xf9, 0x34, 0xf0, 0xb4, 0x99, 0x5e, 0x53, 0xac, 0x89, 0xc7, 0xa1,
-	0xfc, 0x62, 0x9b, 0xea, 0xdb, 0xb1, 0x9f, 0xfd, 0x4e, 0xb6, 0x14, 0xa2, 0x40, 0x8d, 0x5b, 0xfe,
-	0x66, 0x2e, 0xe6, 0x79, 0x0e, 0xa5, 0x57, 0xf3, 0x8c, 0x95, 0x30, 0x8d, 0x12, 0x7f, 0x10, 0xc8,
-	0x4c, 0xc9, 0x19, 0x5f, 0x17, 0x34, 0x8d, 0x9e, 0x2f, 0xac, 0x6c, 0x31, 0x73, 0xff, 0x8e, 0x3e,
-	0x3f, 0x9d, 0x6e, 0xe2, 0xf3, 0x26, 0xbf, 0x74, 0x72, 0xba, 0xc4, 0xfd, 0xde, 0x92, 0xef, 0x93,
-	0x62, 0x8b, 0xa5, 0xda, 0x82, 0x9e, 0x36, 0x90, 0x79, 0x86, 0xe8, 0x06, 0x3e, 0x31, 0x55, 0xd2,
-	0x2c, 0xe8, 0xca, 0x49, 0xb6, 0xea, 0xcd, 0x0e, 0xb8, 0x7f, 0x33, 0xf2, 0xb6, 0xbd, 0x81, 0xf5,
-	0xfb, 0xea, 0x77, 0x89, 0x75, 0xe2, 0x6d, 0xae, 0x1b, 0x6f, 0x6b, 0x41, 0xbc, 0xab, 0x5a, 0xbc,
-	0x5f, 0x1a, 0xe2, 0xdd, 0xf8, 0x1f, 0x9a, 0xbc, 0x07, 0xf5, 0x38, 0xe8, 0x4f, 0xe3, 0x24, 0xb3,
-	0x39, 0x9b, 0x2a, 0xce, 0x54, 0x17, 0x38, 0x53, 0xd3, 0x9c, 0xe9, 0x65, 0xaf, 0xd8, 0x06, 0xde,
-	0xcc, 0xee, 0xa8, 0x2c, 0xb8, 0xc3, 0xd4, 0xee, 0xf8, 0x02, 0x76, 0x10, 0x9f, 0x92, 0xb4, 0x8b,
-	0x1b, 0x72, 0x54, 0x19, 0x2a, 0xaa, 0x34, 0xf4, 0x54, 0x56, 0xa1, 0x67, 0x0a, 0x3b, 0x58, 0xea,
-	0x9a, 0xda, 0xd5, 0x9c, 0x7d, 0x5e, 0xb9, 0x5b, 0xab, 0xca, 0x5d, 0x23, 0xe8, 0x1f, 0x02, 0x13,
-	0x11, 0xdb, 0xec, 0x66, 0xfc, 0xe1, 0x7a, 0x45, 0x62, 0x7d, 0x43, 0x41, 0x06, 0xbb, 0xd4, 0xa2,
-	0x15, 0x29, 0x85, 0xef, 0x10, 0xa3, 0x9f, 0xe5, 0x6d, 0x09, 0xe3, 0x77, 0x7f, 0x2c, 0x70, 0xab,
-	0x4a, 0xe4, 0xdf, 0xf8, 0x0c, 0xf5, 0x1b, 0x5f, 0x4e, 0x6d, 0xd6, 0x57, 0x7e, 0xf3, 0x49, 0xf6,
-	0xc5, 0x4c, 0x76, 0x8d, 0xdd, 0x7b, 0x4f, 0x1e, 0x3d, 0xea, 0x9c, 0x76, 0x4f, 0x7f, 0xf5, 0xb4,
-	0xdd, 0x7d, 0xfc, 0xe4, 0x71, 0x7b, 0xf7, 0x52, 0x71, 0xd5, 0x6b, 0x1f, 0x9f, 0xec, 0x1a, 0xec,
-	0x15, 0x24, 0x94, 0xca, 0xea, 0x2f, 0xbd, 0xce, 0x69, 0x7b, 0xb7, 0x72, 0xf3, 0x33, 0xf1, 0xe5,
-	0x86, 0xd4, 0x31, 0x68, 0xdd, 0xef, 0x3c, 0x6c, 0x6b, 0xca, 0x50, 0x6c, 0xb6, 0xe6, 0xb5, 0x1f,
-	0x7c, 0xf1, 0xf0, 0xd8, 0x43, 0x6d, 0x97, 0x61, 0x7b, 0xb6, 0x7c, 0xd2, 0xf1, 0x50, 0xd3, 0xdb,
-	0xd0, 0xc8, 0x01, 0xc4, 0x6c, 0xb0, 0xa4, 0x02, 0x1c, 0x7d, 0xfe, 0xec, 0xc9, 0x63, 0x94, 0xc1,
-	0xd1, 0xc3, 0x0e, 0xae, 0x55, 0x8e, 0x5e, 0xd6, 0xc1, 0x3c, 0x7e, 0xda, 0x61, 0x3f, 0x47, 0x6f,
-	0x72, 0x92, 0xcb, 0xae, 0x89, 0x3a, 0x2c, 0xb2, 0x5e, 0xe7, 0x5a, 0x89, 0x89, 0xb4, 0xf9, 0xe7,
-	0x67, 0xf7, 0x12, 0xfb, 0x08, 0x9a, 0x0a, 0x1f, 0x65, 0xdf, 0x23, 0x05, 0x65, 0x86, 0xea, 0xe8,
-	0x5f, 0x20, 0x51, 0xf0, 0x08, 0xec, 0x8c, 0x93, 0xb2, 0xab, 0xb4, 0x59, 0xa0, 0xa8, 0x4e, 0x4b,
-	0x13, 0x49, 0x50, 0x06, 0x8d, 0x9d, 0x31, 0x51, 0x69, 0x6c, 0x89, 0x9a, 0x2e, 0x31, 0xf6, 0x03,
-	0x68, 0x2a, 0xa4, 0x54, 0x1a, 0x5b, 0xa6, 0xa9, 0x8e, 0xda, 0x8e, 0x50, 0xec, 0x2e, 0x6c, 0xa9,
-	0x6c, 0x8f, 0xed, 0xc9, 0x3e, 0x51, 0x22, 0x80, 0x4b, 0xae, 0xfe, 0x19, 0x6c, 0x6b, 0x2c, 0x8d,
-	0xbd, 0xaa, 0x46, 0x4a, 0xd7, 0x52, 0xfc, 0xd0, 0x87, 0xe2, 0x1f, 0x03, 0xcc, 0x68, 0x9a, 0xf4,
-	0xbc, 0xc4, 0xdb, 0x9c, 0xdd, 0x82, 0x60, 0x22, 0x8c, 0x57, 0x39, 0x8c, 0x34, 0x7e, 0x0e, 0xad,
-	0x59, 0x62, 0xfc, 0x6d, 0x68, 0x2a, 0x5c, 0x46, 0xc6, 0xad, 0xcc, 0x6e, 0xe6, 0xde, 0x2f, 0x2d,
-	0x17, 0xbc, 0x4b, 0xb1, 0x5c, 0x23, 0x62, 0x73, 0x25, 0x6f, 0x43, 0x5d, 0xbe, 0xf6, 0xec, 0x0a,
-	0x6d, 0xeb, 0x6f, 0xff, 0x62, 0x7b, 0xdf, 0x32, 0xd8, 0x1d, 0xa8, 0x4b, 0x76, 0x24, 0x65, 0x75,
-	0xae, 0xe4, 0x5c, 0x2f, 0xc9, 0x52, 0xcf, 0xfb, 0x05, 0xef, 0xce, 0xee, 0xa5, 0xf7, 0x0c, 0x05,
-	0xd7, 0xa4, 0x44, 0xc3, 0xb5, 0xaa, 0x48, 0xff, 0x96, 0x38, 0xc3, 0x35, 0x49, 0xcd, 0x70, 0xad,
-	0x8a, 0xb4, 0x34, 0x11, 0x0d, 0xd7, 0x24, 0xa5, 0xe2, 0x7a, 0x2d, 0x7f, 0x8f, 0xfe, 0x59, 0xe7,
-	0xd6, 0x62, 0xd1, 0x47, 0xfe, 0xf0, 0x5b, 0x57, 0xd4, 0x9f, 0xae, 0x59, 0xd4, 0x8b, 0x35, 0x7c,
-	0x57, 0xdf, 0xdf, 0xd5, 0xf7, 0xff, 0x75, 0x7d, 0xff, 0xd9, 0x92, 0x7f, 0x5f, 0xe0, 0xc5, 0xfd,
-	0x3e, 0xd8, 0x19, 0xc9, 0x94, 0x06, 0x14, 0x38, 0xa7, 0x53, 0xf8, 0x76, 0x4c, 0x01, 0x3b, 0x06,
-	0x3b, 0xe3, 0x90, 0x52, 0xaa, 0x40, 0x29, 0x57, 0x87, 0x0c, 0x0b, 0x4d, 0xe1, 0x83, 0x32, 0x64,
-	0x65, 0x86, 0xb8, 0x14, 0x67, 0x5b, 0x2a, 0x33, 0x94, 0x58, 0x9d, 0x43, 0x16, 0x9d, 0xc2, 0xa7,
-	0x5f, 0x7a, 0xbb, 0x1b, 0x39, 0x39, 0x64, 0xaf, 0xcc, 0x60, 0xa6, 0x4a, 0xed, 0xe8, 0x52, 0x09,
-	0x89, 0xc9, 0x56, 0x48, 0x7f, 0x0e, 0xde, 0xd6, 0xbe, 0xa0, 0xae, 0xd5, 0x01, 0x49, 0x4e, 0x83,
-	0x87, 0xc2, 0x15, 0x1d, 0x5d, 0x21, 0x0a, 0xde, 0x12, 0xf0, 0x20, 0xa9, 0x19, 0x3c, 0x96, 0x89,
-	0x60, 0x64, 0x73, 0x7c, 0x90, 0x98, 0x8a, 0x0f, 0x55, 0x70, 0xa1, 0xb5, 0xbd, 0x1a, 0xad, 0xdc,
-	0xfa, 0x4f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x99, 0x5e, 0x40, 0x00, 0x5e, 0x20, 0x00, 0x00,
+	// 2091 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x59, 0x4f, 0x73, 0xdb, 0xc6,
+	0x15, 0x17, 0x08, 0x90, 0x04, 0x1f, 0x25, 0x8a, 0x5a, 0xdb, 0x2a, 0x43, 0x3b, 0x89, 0x8c, 0xa4,
+	0xad, 0xe3, 0xb8, 0x92, 0x47, 0x76, 0xac, 0xd4, 0x6e, 0x6b, 0xcb, 0x16, 0xad, 0x28, 0x63, 0xcb,
+	0x1e, 0x58, 0x69, 0xa7, 0x87, 0x0e, 0x07, 0x24, 0x17, 0x16, 0xc6, 0x20, 0xc0, 0x02, 0x60, 0x32,
+	0xea, 0xb1, 0x33, 0x39, 0xb4, 0xd7, 0x5e, 0x7a, 0xe9, 0xf4, 0xdc, 0x73, 0x0f, 0xfd, 0x0e, 0xf9,
+	0x0a, 0xfd, 0x00, 0x3d, 0xf7, 0x1b, 0x74, 0xf6, 0xed, 0x02, 0xd8, 0x05, 0xff, 0x67, 0x26, 0xed,
+	0x4c, 0xe3, 0x43, 0xe2, 0xfd, 0xf3, 0xde, 0xdb, 0xf7, 0xe7, 0xb7, 0x6f, 0x7f, 0x84, 0xe0, 0x72,
+	0xdf, 0xf7, 0x68, 0x90, 0xec, 0x8d, 0xdc, 0x98, 0xfd, 0xb7, 0x3b, 0x8a, 0xc2, 0x24, 0x24, 0xfa,
+	0xc8, 0x8d, 0xdb, 0xd7, 0x5e, 0x87, 0xe1, 0x6b, 0x9f, 0xee, 0x39, 0x23, 0x6f, 0xcf, 0x09, 0x82,
+	0x30, 0x71, 0x12, 0x2f, 0x0c, 0x84, 0x48, 0xfb, 0xaa, 0xd8, 0xc5, 0x59, 0x6f, 0xec, 0xee, 0xd1,
+	0xe1, 0x28, 0xb9, 0x10, 0x9b, 0xef, 0x17, 0x37, 0x13, 0x6f, 0x48, 0xe3, 0xc4, 0x19, 0x8e, 0x84,
+	0xc0, 0x7b, 0x45, 0x81, 0xaf, 0x22, 0x67, 0x34, 0xa2, 0x51, 0x6a, 0xfd, 0x5a, 0xea, 0xd6, 0x9b,
+	0xd7, 0x7b, 0xf1, 0xb9, 0x13, 0x0d, 0xf8, 0xff, 0xf9, 0xae, 0xd5, 0x06, 0xc3, 0xa6, 0xa3, 0x90,
+	0x10, 0x30, 0x02, 0x67, 0x48, 0x5b, 0xda, 0x8e, 0x76, 0xa3, 0x66, 0xe3, 0xd8, 0x3a, 0x80, 0xca,
+	0x93, 0x70, 0x38, 0xf4, 0x12, 0xf2, 0x2e, 0x18, 0x11, 0x1d, 0x85, 0xb8, 0x5b, 0xdf, 0xaf, 0xed,
+	0xb2, 0xf0, 0x98, 0x9a, 0x8d, 0xcb, 0xa4, 0x01, 0x25, 0x6f, 0xd0, 0x2a, 0xa1, 0x6a, 0xc9, 0x1b,
+	0x58, 0x0f, 0xc1, 0x78, 0xea, 0xf9, 0x94, 0x7c, 0x00, 0x95, 0x3e, 0x1a, 0x10, 0x8a, 0x75, 0x54,
+	0xe4, 0x36, 0x6d, 0xb1, 0xc5, 0x4e, 0x1e, 0x39, 0xc9, 0xb9, 0x50, 0xc7, 0xb1, 0x75, 0x15, 0xca,
+	0x8f, 0xfd, 0xb0, 0xff, 0x86, 0x6d, 0x9e, 0x3b, 0xf1, 0x79, 0xea, 0x16, 0x1b, 0x5b, 0x87, 0x60,
+	0x1c, 0x79, 0xae, 0xbb, 0x9c, 0xf5, 0xcb, 0x50, 0xc6, 0x70, 0xd1, 0xbc, 0x61, 0xf3, 0x89, 0xf5,
+	0x37, 0x0d, 0x4c, 0xe6, 0xff, 0x49, 0xe0, 0x86, 0x8b, 0x82, 0xbb, 0x0b, 0xd5, 0x7e, 0x44, 0x9d,
+	0x84, 0x72, 0x1b, 0xf5, 0xfd, 0xf6, 0x2e, 0xcf, 0xf8, 0x6e, 0x9a, 0xf1, 0xdd, 0xb3, 0xb4, 0x24,
+	0x76, 0x2a, 0x4a, 0xde, 0x05, 0x88, 0xbd, 0xdf, 0xd1, 0x6e, 0xef, 0x22, 0xa1, 0x71, 0x4b, 0xc7,
+	0xc3, 0x6b, 0x6c, 0xe5, 0x31, 0x5b, 0x20, 0x1f, 0x01, 0x8c, 0xa2, 0xf0, 0x4b, 0x1a, 0x38, 0x41,
+	0x9f, 0xb6, 0x8c, 0x1d, 0x5d, 0x3d, 0x59, 0xda, 0xb4, 0x0e, 0xa0, 0x96, 0xba, 0x1a, 0x93, 0x9b,
+	0x50, 0x63, 0x4e, 0x75, 0xbd, 0xc0, 0x65, 0x0e, 0x33, 0xb5, 0x8d, 0x4c, 0x8d, 0x89, 0xd8, 0x66,
+	0x24, 0x46, 0xd6, 0xd7, 0x3a, 0x00, 0xcf, 0x06, 0x86, 0xb9, 0x54, 0xba, 0xb6, 0xa1, 0xd2, 0x8b,
+	0x9c, 0xa0, 0x9f, 0x96, 0x43, 0xcc, 0xc8, 0x6d, 0xa8, 0x73, 0x89, 0x6e, 0x72, 0x31, 0xa2, 0x18,
+	0x4f, 0x63, 0x7f, 0x53, 0xb2, 0x70, 0x76, 0x31, 0xa2, 0x36, 0xf4, 0xb3, 0x31, 0xb9, 0x0d, 0x1b,
+	0x23, 0x27, 0xa2, 0x41, 0xd2, 0x15, 0xa7, 0x1a, 0x93, 0xa7, 0xae, 0x73, 0x09, 0x3e, 0x63, 0x89,
+	0x8e, 0x13, 0x27, 0x62, 0x89, 0x2e, 0x2f, 0x4e, 0xb4, 0x10, 0x25, 0xf7, 0xc0, 0x74, 0xbd, 0xc0,
+	0x8b, 0xcf, 0xe9, 0xa0, 0x55, 0x59, 0xa8, 0x96, 0xc9, 0x16, 0x0a, 0x54, 0x2d, 0x16, 0xe8, 0x1a,
+	0xd4, 0xfa, 0x2c, 0xfd, 0xbe, 0x4f, 0x07, 0x2d, 0x73, 0x47, 0xbb, 0x61, 0xda, 0xf9, 0x02, 0xf9,
+	0x58, 0x29, 0x5f, 0x6d, 0x47, 0x2f, 0x46, 0x26, 0x17, 0xf0, 0x21, 0xd4, 0xf3, 0x32, 0xc4, 0x52,
+	0x2a, 0xa5, 0x22, 0xca, 0xa9, 0xc4, 0x32, 0x42, 0x3f, 0x1b, 0x5b, 0x7f, 0x28, 0x81, 0xc9, 0xee,
+	0x53, 0x8a, 0x56, 0xd7, 0xf3, 0xa9, 0x82, 0x56, 0xb6, 0x69, 0xe3, 0x32, 0x03, 0x08, 0xfb, 0x97,
+	0x97, 0xa9, 0x84, 0x65, 0xda, 0xc8, 0x64, 0xb0, 0x48, 0xa6, 0x2b, 0x46, 0x8b, 0x30, 0x7a, 0x0f,
+	0xcc, 0x61, 0x38, 0xf0, 0x5c, 0x8f, 0x0e, 0x5a, 0xc6, 0xe2, 0xcc, 0xa6, 0xb2, 0xe4, 0x2e, 0x6c,
+	0x8a, 0x00, 0x33, 0xf5, 0xf2, 0x64, 0xed, 0x1b, 0x5c, 0xe6, 0x79, 0xaa, 0xf5, 0x43, 0x30, 0xfb,
+	0xe7, 0x9e, 0x3f, 0x88, 0x68, 0xd0, 0xaa, 0x48, 0xf7, 0x01, 0x63, 0xcb, 0xb6, 0xd8, 0x6d, 0x48,
+	0x53, 0x11, 0x67, 0xc1, 0x4e, 0xdc, 0x86, 0x54, 0x84, 0x07, 0x8b, 0x49, 0x3c, 0x80, 0x1a, 0x0b,
+	0xcb, 0x76, 0x82, 0xd7, 0x94, 0x75, 0x05, 0x3f, 0xfc, 0x8a, 0x46, 0x98, 0x45, 0xc3, 0xe6, 0x13,
+	0xb6, 0x3a, 0x66, 0x9d, 0x33, 0xed, 0x15, 0x38, 0xb1, 0x6c, 0x30, 0xb1, 0x17, 0xd9, 0xd4, 0x25,
+	0x3b, 0x50, 0xee, 0xb1, 0xb1, 0xc8, 0x3e, 0xe0, 0x61, 0x7c, 0x97, 0x6f, 0x90, 0x0f, 0xa1, 0x1c,
+	0xb1, 0x23, 0x44, 0xaf, 0x68, 0x70, 0x89, 0xf4, 0x60, 0x9b, 0x6f, 0xa2, 0x33, 0xc2, 0x26, 0x46,
+	0x81, 0xba, 0xdd, 0x88, 0xba, 0x4a, 0x14, 0xa9, 0x88, 0x6d, 0xf6, 0xc4, 0xc8, 0xfa, 0xab, 0x01,
+	0x95, 0xc3, 0xd1, 0x88, 0x06, 0x03, 0x72, 0x0b, 0x20, 0x53, 0x8b, 0xa7, 0xeb, 0xd5, 0x7a, 0xd9,
+	0x21, 0x9f, 0x48, 0xe9, 0x2d, 0xa1, 0xec, 0x3b, 0x28, 0xcb, 0x8d, 0xed, 0x3e, 0x11, 0x7b, 0x9d,
+	0x20, 0x89, 0x2e, 0xf2, 0x74, 0x93, 0x1f, 0x81, 0xe9, 0x3b, 0x71, 0x82, 0xae, 0xe9, 0x93, 0x45,
+	0xac, 0xb2, 0x4d, 0x96, 0x98, 0x6d, 0xa8, 0x0c, 0xa8, 0x4f, 0x13, 0x8a, 0x48, 0x31, 0x6d, 0x31,
+	0x23, 0xfb, 0x50, 0x3d, 0x77, 0x82, 0x81, 0x4f, 0xe3, 0x56, 0x19, 0x4f, 0x6d, 0xc9, 0xa7, 0x7e,
+	0xc6, 0xb7, 0xf8, 0xa1, 0xa9, 0x20, 0xe9, 0x40, 0x83, 0x0f, 0xbb, 0xdc, 0x48, 0x2c, 0xf0, 0xf0,
+	0xde, 0xa4, 0xea, 0x11, 0x17, 0xe0, 0x06, 0x36, 0xce, 0xe5, 0x35, 0xf5, 0x26, 0x54, 0xe7, 0xde,
+	0x84, 0xf6, 0x03, 0xd8, 0x50, 0x32, 0x40, 0x9a, 0xa0, 0xbf, 0xa1, 0x17, 0xe2, 0xd9, 0x61, 0x43,
+	0x06, 0x8e, 0x2f, 0x1d, 0x7f, 0xcc, 0x0b, 0x6b, 0xda, 0x7c, 0x72, 0xbf, 0xf4, 0xa9, 0xd6, 0xfe,
+	0x1c, 0xd6, 0xe5, 0x40, 0xa6, 0xe8, 0x7e, 0x28, 0xeb, 0x66, 0xa0, 0x48, 0x6b, 0x23, 0xdb, 0x7a,
+	0x04, 0x64, 0x32, 0xb2, 0x55, 0xbc, 0xb1, 0x7e, 0xaf, 0x09, 0x6c, 0x61, 0xb7, 0x58, 0x0c, 0xd8,
+	0xef, 0xe2, 0x79, 0xb3, 0x1e, 0x00, 0x64, 0x3e, 0xc4, 0xe4, 0x27, 0x29, 0x52, 0xa5, 0x7b, 0x2a,
+	0xe5, 0x00, 0x2f, 0x6a, 0xad, 0x97, 0x0e, 0xad, 0x6f, 0x74, 0x30, 0xd9, 0x03, 0x9f, 0xb6, 0xbb,
+	0x81, 0xe7, 0xba, 0x4a, 0xbb, 0x63, 0x9b, 0x36, 0x2e, 0x4f, 0xbe, 0x32, 0xa5, 0x45, 0xaf, 0x4c,
+	0xfe, 0xc2, 0xe9, 0xca, 0x0b, 0x27, 0xbd, 0x3e, 0xc6, 0xb7, 0x7b, 0x7d, 0xca, 0x2b, 0xbc, 0x3e,
+	0x77, 0xa1, 0xea, 0x20, 0x90, 0x53, 0x70, 0xb7, 0xb3, 0xc8, 0x58, 0xd8, 0x02, 0xe5, 0xe9, 0xcd,
+	0x10, 0xa2, 0xff, 0xbd, 0x37, 0xab, 0x7d, 0x0c, 0xeb, 0xb2, 0x0b, 0x53, 0x10, 0x78, 0x5d, 0xc5,
+	0x74, 0x5d, 0xba, 0x9c, 0x32, 0x1c, 0xff, 0xa4, 0x41, 0xf9, 0x15, 0xe3, 0x5c, 0xe4, 0x7d, 0xa8,
+	0xe3, 0x7d, 0x0c, 0xc6, 0xc3, 0x5e, 0xd6, 0x79, 0x81, 0x2d, 0x9d, 0xe2, 0x0a, 0xb9, 0x0e, 0xeb,
+	0x28, 0x30, 0x0c, 0x07, 0x63, 0x7f, 0x1c, 0x8b, 0x2e, 0x8c, 0x4a, 0xcf, 0xf9, 0x12, 0x13, 0xe1,
+	0x48, 0x12, 0x46, 0x38, 0xf0, 0xea, 0xb8, 0x26, 0xac, 0x7c, 0x00, 0x1b, 0x5c, 0x24, 0x35, 0x63,
+	0xa0, 0x0c, 0xd7, 0x13, 0x76, 0x98, 0x57, 0x5b, 0x4f, 0x10, 0xca, 0x48, 0xb7, 0xe8, 0x6f, 0xc7,
+	0x34, 0x4e, 0xbe, 0x1b, 0x22, 0xa8, 0x32, 0x3d, 0x7d, 0x1e, 0xd3, 0xbb, 0x03, 0xe4, 0x24, 0x88,
+	0x47, 0xb4, 0x9f, 0x2c, 0xef, 0x95, 0xf5, 0x33, 0xd8, 0x7c, 0xe6, 0xc5, 0x8a, 0x86, 0x7a, 0xa4,
+	0x36, 0xef, 0xc8, 0x7d, 0xd8, 0xe2, 0x9d, 0x66, 0x85, 0x13, 0xff, 0xa9, 0x01, 0x79, 0xc5, 0xf0,
+	0x2f, 0x70, 0xb3, 0x5c, 0xf6, 0x0a, 0xbf, 0x11, 0xc8, 0x55, 0xa8, 0x89, 0x9b, 0xeb, 0x0d, 0xc4,
+	0x55, 0x34, 0xf9, 0xc2, 0xc9, 0x40, 0xba, 0xa4, 0xc6, 0xac, 0x4b, 0xba, 0x02, 0x45, 0x54, 0x91,
+	0x5f, 0x99, 0xcf, 0xd6, 0xfe, 0xa8, 0xc1, 0xa5, 0xa7, 0x78, 0x4d, 0xd5, 0xf0, 0x96, 0xa5, 0xcf,
+	0xfc, 0xc2, 0x89, 0xbe, 0x2c, 0x66, 0x4a, 0x9b, 0xd0, 0x97, 0x6f, 0x13, 0xd6, 0x03, 0xb8, 0x2c,
+	0x10, 0xb1, 0xba, 0x33, 0xd6, 0xbf, 0x34, 0xd8, 0x62, 0xd0, 0x98, 0x55, 0x26, 0x7d, 0x5a, 0x99,
+	0x0a, 0x44, 0xbf, 0xb4, 0x98, 0xe8, 0xdf, 0x82, 0xba, 0x1b, 0x85, 0xc3, 0xb4, 0x01, 0xeb, 0x53,
+	0xd2, 0xcb, 0xf6, 0xf9, 0x98, 0x7c, 0x3c, 0xe5, 0x87, 0xcf, 0xac, 0x5a, 0xb0, 0xae, 0xe3, 0xf8,
+	0x3e, 0x96, 0xda, 0xb4, 0xd9, 0x90, 0xbd, 0x7b, 0xfc, 0x3d, 0xab, 0xf0, 0x77, 0x0f, 0x27, 0xd6,
+	0x3e, 0x0f, 0xf4, 0x31, 0x82, 0x64, 0x49, 0x14, 0xdf, 0x87, 0x4b, 0x1c, 0xf9, 0xdf, 0x22, 0xb3,
+	0xbf, 0x01, 0xf2, 0xd4, 0x1f, 0xcf, 0x43, 0x88, 0x3e, 0x0b, 0x21, 0x16, 0x54, 0x93, 0xb0, 0x8b,
+	0x8e, 0x95, 0x8a, 0x15, 0xa8, 0x24, 0x21, 0xfb, 0xd7, 0xfa, 0xb7, 0x06, 0x8d, 0x63, 0x9a, 0x20,
+	0xf3, 0xcd, 0x83, 0x99, 0xc7, 0xfa, 0xaf, 0xc3, 0x7a, 0xe8, 0xba, 0x31, 0x4d, 0xc4, 0xd3, 0xc0,
+	0xca, 0xa6, 0xdb, 0x75, 0xbe, 0xc6, 0x1f, 0x87, 0xc9, 0x17, 0x5b, 0x97, 0xdf, 0x8e, 0x9d, 0xf4,
+	0x77, 0xb2, 0x21, 0x11, 0x05, 0x6c, 0xdc, 0xe2, 0x37, 0x73, 0xb1, 0xce, 0x53, 0x28, 0xbd, 0x5c,
+	0xe7, 0x6d, 0xa8, 0x8c, 0x83, 0xd8, 0x71, 0xa9, 0xa8, 0x94, 0x98, 0xb1, 0x75, 0x4e, 0xd3, 0xf0,
+	0xf9, 0xaa, 0xd9, 0x62, 0x66, 0xfd, 0x43, 0x83, 0xc6, 0xcb, 0xf1, 0x2a, 0x31, 0xaf, 0xf2, 0x4b,
+	0x27, 0xa3, 0x4b, 0x2c, 0xee, 0x75, 0xf1, 0x3e, 0x49, 0xbe, 0x18, 0xb2, 0x2f, 0xe4, 0x16, 0xd4,
+	0x06, 0xd4, 0xf7, 0x86, 0x5e, 0x42, 0x23, 0x8c, 0xb3, 0x21, 0xe8, 0xca, 0x51, 0xba, 0x6a, 0xe7,
+	0x02, 0xd6, 0xdf, 0xb5, 0xac, 0x6d, 0xaf, 0xe0, 0xfd, 0x8e, 0xfc, 0x5d, 0x62, 0x99, 0x7c, 0xeb,
+	0xcb, 0xe6, 0xdb, 0x98, 0x91, 0xef, 0xb2, 0x92, 0xef, 0x6f, 0x34, 0xfe, 0x6e, 0xfc, 0x0f, 0x5d,
+	0x6e, 0x41, 0x35, 0xa2, 0xfd, 0x71, 0x14, 0xa7, 0x3e, 0xa7, 0x53, 0x29, 0x98, 0xf2, 0x8c, 0x60,
+	0x2a, 0x4a, 0x30, 0xbd, 0xf4, 0x15, 0x5b, 0x21, 0x9a, 0xfc, 0x8c, 0xd2, 0x8c, 0x33, 0x74, 0xe5,
+	0x8c, 0x2f, 0x60, 0xf3, 0xe5, 0x38, 0x11, 0xa4, 0x9d, 0x9f, 0x90, 0xa1, 0x4a, 0x93, 0x51, 0xa5,
+	0xa0, 0xa7, 0xb4, 0x08, 0x3d, 0x63, 0xd8, 0x3c, 0xa6, 0xaa, 0xd9, 0xc5, 0x9c, 0x7d, 0xda, 0x75,
+	0x37, 0x16, 0x5d, 0x77, 0x85, 0xa0, 0xdf, 0x03, 0xc2, 0x33, 0xb6, 0xda, 0xc9, 0xd6, 0x01, 0x5c,
+	0x12, 0x58, 0x5f, 0x51, 0x91, 0x40, 0x13, 0x5b, 0xb4, 0xa4, 0x25, 0xf1, 0x1d, 0x64, 0xf4, 0x79,
+	0xdd, 0xe6, 0x30, 0x7e, 0xeb, 0xc7, 0x1c, 0xb7, 0xb2, 0x46, 0xf6, 0x8d, 0x4f, 0x93, 0xbf, 0xf1,
+	0x65, 0xd4, 0x66, 0x79, 0xe3, 0x37, 0x5f, 0xa4, 0x5f, 0xcc, 0x44, 0xd7, 0x68, 0x3e, 0x79, 0xf1,
+	0xfc, 0xf9, 0xc9, 0x59, 0xf7, 0xec, 0xd7, 0x2f, 0x3b, 0xdd, 0xd3, 0x17, 0xa7, 0x9d, 0xe6, 0x5a,
+	0x71, 0xd5, 0xee, 0x1c, 0x1e, 0x35, 0x35, 0x72, 0x05, 0xb6, 0xe4, 0xd5, 0x5f, 0xd9, 0x27, 0x67,
+	0x9d, 0x66, 0xe9, 0xe6, 0x67, 0xfc, 0xcb, 0x0d, 0x9a, 0x23, 0xd0, 0x78, 0x7a, 0xf2, 0xac, 0xa3,
+	0x18, 0xbb, 0x02, 0x5b, 0xf9, 0x9a, 0xdd, 0x39, 0xfe, 0xe2, 0xd9, 0xa1, 0xdd, 0xd4, 0xc8, 0x16,
+	0x6c, 0xe4, 0xcb, 0x47, 0x27, 0x76, 0xb3, 0x74, 0xf3, 0x23, 0xa8, 0x65, 0x00, 0x22, 0x26, 0x18,
+	0xc2, 0x80, 0x09, 0xc6, 0xe7, 0xaf, 0x5e, 0x9c, 0x36, 0x35, 0x36, 0x7a, 0x76, 0x72, 0xda, 0x69,
+	0x96, 0xf6, 0xbf, 0x36, 0x41, 0x3f, 0x7c, 0x79, 0x42, 0x7e, 0x01, 0x90, 0x93, 0x5c, 0xb2, 0xcd,
+	0xef, 0x61, 0x91, 0xf5, 0xb6, 0xb7, 0x27, 0x98, 0x48, 0x87, 0x7d, 0x7e, 0xb6, 0xd6, 0xc8, 0x01,
+	0xd4, 0x25, 0x3e, 0x4a, 0x7e, 0x80, 0x06, 0x26, 0x19, 0x6a, 0x5b, 0xfd, 0x02, 0x69, 0xad, 0x91,
+	0x7d, 0x30, 0x53, 0x4e, 0x4a, 0x2e, 0xe3, 0x66, 0x81, 0xa2, 0xb6, 0x1b, 0x8a, 0x4a, 0x6c, 0xad,
+	0x31, 0x67, 0x73, 0x26, 0x2a, 0x9c, 0x9d, 0xa0, 0xa6, 0x73, 0x9c, 0xfd, 0x04, 0xea, 0x12, 0x29,
+	0x15, 0xce, 0x4e, 0xd2, 0xd4, 0xb6, 0xdc, 0x8e, 0xac, 0x35, 0xf2, 0x18, 0xd6, 0x65, 0xb6, 0x47,
+	0x5a, 0xa2, 0x4f, 0x4c, 0x10, 0xc0, 0x39, 0x47, 0xff, 0x1c, 0x36, 0x14, 0x96, 0x46, 0xde, 0x91,
+	0x33, 0xa5, 0x5a, 0x29, 0x7e, 0xe8, 0xb3, 0xd6, 0xc8, 0xa7, 0x00, 0x39, 0x4d, 0x13, 0x91, 0x4f,
+	0xf0, 0xb6, 0x76, 0xb3, 0xa0, 0x18, 0x73, 0xe7, 0x65, 0x0e, 0x23, 0x9c, 0x9f, 0x42, 0x6b, 0xe6,
+	0x38, 0x7f, 0x1f, 0xea, 0x12, 0x97, 0x11, 0x79, 0x9b, 0x64, 0x37, 0x53, 0xcf, 0x17, 0x9e, 0x73,
+	0xde, 0x25, 0x79, 0xae, 0x10, 0xb1, 0xa9, 0x9a, 0xf7, 0xa1, 0x2a, 0x5e, 0x7b, 0x72, 0x09, 0xb7,
+	0xd5, 0xb7, 0x7f, 0xb6, 0xbf, 0x37, 0x34, 0xf2, 0x10, 0xaa, 0xc7, 0x54, 0xd6, 0x55, 0xb9, 0x52,
+	0xfb, 0xea, 0x84, 0x2e, 0xf6, 0xbc, 0x5f, 0xb2, 0xee, 0x6c, 0xad, 0xdd, 0xd6, 0x24, 0x5c, 0xa3,
+	0x11, 0x05, 0xd7, 0xb2, 0x21, 0xf5, 0x5b, 0x62, 0x8e, 0x6b, 0xd4, 0xca, 0x71, 0x2d, 0xab, 0x34,
+	0x14, 0x15, 0x16, 0xe9, 0x4f, 0xa1, 0x91, 0x0a, 0xbd, 0x4a, 0x22, 0xea, 0x0c, 0x67, 0x68, 0x16,
+	0x0f, 0xbb, 0xad, 0xe5, 0x57, 0x02, 0x0f, 0x94, 0xaf, 0xc4, 0x52, 0xa9, 0xda, 0xff, 0xb3, 0xc9,
+	0x02, 0x4d, 0x68, 0x14, 0x38, 0xfe, 0xf7, 0xae, 0x1f, 0x3c, 0x5a, 0xb2, 0x1f, 0xcc, 0xb6, 0xf0,
+	0xb6, 0x35, 0xbc, 0x6d, 0x0d, 0xff, 0xaf, 0xad, 0xe1, 0x2f, 0x86, 0xf8, 0xab, 0x06, 0xeb, 0x0b,
+	0x77, 0xc1, 0x4c, 0xa9, 0xad, 0xf0, 0xa0, 0xc0, 0x74, 0xdb, 0x85, 0x2f, 0xd6, 0x98, 0xeb, 0x43,
+	0x30, 0x8f, 0xa9, 0xa2, 0x55, 0x20, 0xb2, 0x8b, 0xb3, 0xfd, 0x08, 0xea, 0x12, 0x0b, 0x15, 0xd9,
+	0x9e, 0xe4, 0xa5, 0x73, 0x21, 0xba, 0x2e, 0xf3, 0x51, 0x01, 0xf3, 0x29, 0x14, 0xb5, 0x5d, 0xf8,
+	0xe0, 0x8c, 0x8c, 0xa1, 0x96, 0x51, 0x52, 0x72, 0x25, 0x47, 0xa8, 0xac, 0xb5, 0xa9, 0x6a, 0xc5,
+	0xa8, 0x26, 0xba, 0x28, 0xfe, 0x11, 0x7a, 0x43, 0xf9, 0x6e, 0xbb, 0x54, 0xf3, 0x44, 0x3d, 0x05,
+	0x59, 0x12, 0x43, 0x6d, 0xab, 0x06, 0xad, 0x35, 0x72, 0x87, 0x23, 0x0b, 0xb5, 0x72, 0x7c, 0xcc,
+	0x53, 0x91, 0xf1, 0x81, 0x6a, 0x32, 0x3e, 0x64, 0xc5, 0x99, 0xde, 0xf6, 0x2a, 0xb8, 0x72, 0xe7,
+	0x3f, 0x03, 0x00, 0xba, 0xb6, 0x85, 0xb4, 0xd4, 0x20, 0x00, 0x00,
 }
@@ -0,0 +1,53 @@
+package debugutil
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/loglevel"
+)
+
+// NewJSONHandler returns a read-only http.Handler which responds with the
+// JSON rendering of the value returned by state.
+func NewJSONHandler(state func() (interface{}, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" && r.Method != "HEAD" {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		value, err := state()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		data, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	})
+}
+
+// Serve serves handler at path, and the log level at loglevel.Path, on
+// address until it fails. If address doesn't specify a host, such as
+// ":6060", it's bound to localhost so that debug state isn't exposed to the
+// network by accident.
+func Serve(address string, path string, handler http.Handler) error {
+	mux := http.NewServeMux()
+	mux.Handle(path, handler)
+	mux.Handle(loglevel.Path, loglevel.Handler())
+	return http.ListenAndServe(ListenAddress(address), mux)
+}
+
+// ListenAddress returns address with its host defaulted to localhost.
+func ListenAddress(address string) string {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil || host != "" {
+		return address
+	}
+	return net.JoinHostPort("localhost", port)
+}
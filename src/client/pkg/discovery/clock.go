@@ -0,0 +1,82 @@
+package discovery
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the time a mock Client's records expire by.
+type Clock interface {
+	Now() time.Time
+	// After returns a channel which is closed, or sent the time, once d
+	// has passed.
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// FakeClock is a Clock which only moves when it's advanced, so that tests
+// can expire records without waiting for them to.
+type FakeClock struct {
+	lock sync.Mutex
+	now  time.Time
+	// waiters holds the channels returned by After, by the time they're
+	// closed at. Records set between advances share their expiry, and so
+	// do the watches waiting for it.
+	waiters map[time.Time]chan time.Time
+}
+
+// NewFakeClock returns a FakeClock set to now.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{
+		now:     now,
+		waiters: make(map[time.Time]chan time.Time),
+	}
+}
+
+// Now returns the clock's time.
+func (c *FakeClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+// After returns a channel which is closed once the clock has been advanced
+// by d.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	deadline := c.now.Add(d)
+	waiter, ok := c.waiters[deadline]
+	if !ok {
+		waiter = make(chan time.Time)
+		if d <= 0 {
+			close(waiter)
+			return waiter
+		}
+		c.waiters[deadline] = waiter
+	}
+	return waiter
+}
+
+// Advance moves the clock forward by d, waking everything waiting for a
+// time up to the new one.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.now = c.now.Add(d)
+	for deadline, waiter := range c.waiters {
+		if !deadline.After(c.now) {
+			close(waiter)
+			delete(c.waiters, deadline)
+		}
+	}
+}
@@ -2,6 +2,10 @@ package discovery
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/context"
 )
 
 var ErrCancelled = fmt.Errorf("pachyderm: cancelled by user")
@@ -13,19 +17,46 @@ type Client interface {
 	// Keys can be directories of the form a/b/c, see etcd for details.
 	// the bool will be false if the key does not exist.
 	Get(key string) (string, error)
+	// GetCtx is like Get but gives up when ctx is done.
+	GetCtx(ctx context.Context, key string) (string, error)
 	// GetAll returns all of the keys in a directory and its subdirectories as
 	// a map from absolute keys to values.
 	// the map will be empty if no keys are found.
 	GetAll(key string) (map[string]string, error)
+	// GetAllCtx is like GetAll but gives up when ctx is done.
+	GetAllCtx(ctx context.Context, key string) (map[string]string, error)
 	// Watch calls callBack with changes to a value
 	Watch(key string, cancel chan bool, callBack func(string) error) error
+	// WatchCtx is like Watch but stops, returning ctx.Err(), when ctx is done.
+	WatchCtx(ctx context.Context, key string, callBack func(string) error) error
 	// WatchAll calls callBack with changes to a directory
 	WatchAll(key string, cancel chan bool, callBack func(map[string]string) error) error
+	// WatchAllCtx is like WatchAll but stops, returning ctx.Err(), when ctx is done.
+	WatchAllCtx(ctx context.Context, key string, callBack func(map[string]string) error) error
+	// WatchAllDelta is like WatchAll but calls callBack with only the keys
+	// that changed. The first call, and the first call after the watch has
+	// been re-established, carries a Snapshot of the whole directory.
+	WatchAllDelta(key string, cancel chan bool, callBack func(*Delta) error) error
+	// WatchAllDeltaCtx is like WatchAllDelta but stops, returning ctx.Err(),
+	// when ctx is done.
+	WatchAllDeltaCtx(ctx context.Context, key string, callBack func(*Delta) error) error
 	// Set sets the value for a key.
 	// ttl is in seconds.
 	Set(key string, value string, ttl uint64) error
+	// SetCtx is like Set but gives up when ctx is done.
+	SetCtx(ctx context.Context, key string, value string, ttl uint64) error
+	// SetMulti sets the values for several keys at once.
+	// ttl is in seconds.
+	// Backends that support transactions set all of the keys or none of
+	// them, others set the keys concurrently, in which case a
+	// *SetMultiError reports which of them failed.
+	SetMulti(kvs map[string]string, ttl uint64) error
+	// SetMultiCtx is like SetMulti but gives up when ctx is done.
+	SetMultiCtx(ctx context.Context, kvs map[string]string, ttl uint64) error
 	// Delete deletes a key.
 	Delete(key string) error
+	// DeleteCtx is like Delete but gives up when ctx is done.
+	DeleteCtx(ctx context.Context, key string) error
 	// CheckAndDelete deletes a key only if its value matches oldValue
 	CheckAndDelete(key string, oldValue string) error
 	// Create is like Set but only succeeds if the key doesn't already exist.
@@ -38,10 +69,95 @@ type Client interface {
 	CheckAndSet(key string, value string, ttl uint64, oldValue string) error
 }
 
+// Delta describes a change to a directory watched with WatchAllDelta.
+type Delta struct {
+	// Snapshot is true if Updated contains every key in the directory, in
+	// which case any state accumulated from previous deltas is stale and
+	// should be discarded.
+	Snapshot bool
+	// Updated maps keys that were added or changed to their new values.
+	Updated map[string]string
+	// Deleted lists keys that were removed or expired.
+	Deleted []string
+}
+
+// SetMultiError is returned by SetMulti when some of the keys couldn't be
+// set, the remaining keys were set successfully.
+type SetMultiError struct {
+	// Errors maps each key that couldn't be set to the reason.
+	Errors map[string]error
+}
+
+func (e *SetMultiError) Error() string {
+	var keys []string
+	for key := range e.Errors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var errs []string
+	for _, key := range keys {
+		errs = append(errs, fmt.Sprintf("%s: %s", key, e.Errors[key].Error()))
+	}
+	return fmt.Sprintf("pachyderm: failed to set %d keys: %s", len(keys), strings.Join(errs, "; "))
+}
+
+// cancelToContext returns a context which is cancelled when cancel is closed,
+// done must be called to release the goroutine watching cancel.
+func cancelToContext(cancel chan bool) (context.Context, func()) {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	stop := make(chan struct{})
+	go func() {
+		select {
+		case <-cancel:
+			cancelCtx()
+		case <-stop:
+		}
+	}()
+	return ctx, func() {
+		close(stop)
+		cancelCtx()
+	}
+}
+
+// contextToCancelErr translates the error returned by a watch that was
+// stopped by a context created with cancelToContext into ErrCancelled.
+func contextToCancelErr(err error) error {
+	if err == context.Canceled {
+		return ErrCancelled
+	}
+	return err
+}
+
 func NewEtcdClient(addresses ...string) Client {
 	return newEtcdClient(addresses...)
 }
 
+// NewEtcdClientWithRetryPolicy is like NewEtcdClient but the returned Client
+// retries failed calls according to policy.
+func NewEtcdClientWithRetryPolicy(policy RetryPolicy, addresses ...string) Client {
+	return NewRetryClient(newEtcdClient(addresses...), policy)
+}
+
+// NewMockClient returns an in-memory Client, useful for testing.
+func NewMockClient() Client {
+	return newMockClient(realClock{})
+}
+
+// NewMockClientWithClock is like NewMockClient but records expire by clock,
+// so a test can expire them by advancing a FakeClock.
+func NewMockClientWithClock(clock Clock) Client {
+	return newMockClient(clock)
+}
+
+// NewNamespacedClient returns a Client which stores all of its keys under
+// prefix in client. Keys passed to it are relative to prefix, as are the keys
+// it returns, and keys which would escape prefix, such as "../foo", are
+// rejected. NewNamespacedClient panics if prefix is empty or isn't a clean
+// path.
+func NewNamespacedClient(client Client, prefix string) Client {
+	return newNamespacedClient(client, prefix)
+}
+
 // Registry is an object that allows a value to be registered as
 // valid for the lifetime of a process, and allows all values
 // registered to be retrieved.
@@ -3,10 +3,16 @@ package discovery
 import (
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"go.pedge.io/pkg/time"
+	"golang.org/x/net/context"
 )
 
 func TestEtcdClient(t *testing.T) {
@@ -33,6 +39,417 @@ func TestEtcdWatch(t *testing.T) {
 	runWatchTest(t, client)
 }
 
+func TestEtcdWatchAllDelta(t *testing.T) {
+
+	if os.Getenv("ETCD_PORT_2379_TCP_ADDR") == "" {
+		t.Skip("skipping test; $ETCD_PORT_2379_TCP_ADDR not set")
+	}
+
+	t.Parallel()
+	client, err := getEtcdClient()
+	require.NoError(t, err)
+	runWatchAllDeltaTest(t, client, "etcdWatchAllDelta")
+}
+
+func TestEtcdLease(t *testing.T) {
+
+	if os.Getenv("ETCD_PORT_2379_TCP_ADDR") == "" {
+		t.Skip("skipping test; $ETCD_PORT_2379_TCP_ADDR not set")
+	}
+
+	t.Parallel()
+	client, err := getEtcdClient()
+	require.NoError(t, err)
+	runLeaseTest(t, client, "etcdLease")
+}
+
+func TestEtcdSetCtxHungBackend(t *testing.T) {
+	t.Parallel()
+	hang := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-hang
+	}))
+	defer server.Close()
+	defer close(hang)
+	client := NewEtcdClient(server.URL)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := client.SetCtx(ctx, "key", "value", 0)
+	require.Equal(t, context.DeadlineExceeded, err)
+	require.True(t, time.Since(start) < 5*time.Second)
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = client.GetCtx(ctx, "key")
+	require.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestEtcdSetMultiPartialFailure(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.URL.Path, "/v2/keys")
+		if strings.HasPrefix(key, "/bad") {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprintf(w, `{"errorCode":110,"message":"The request requires user authentication","cause":"%s"}`, key)
+			return
+		}
+		fmt.Fprintf(w, `{"action":"set","node":{"key":"%s","value":"%s"}}`, key, r.FormValue("value"))
+	}))
+	defer server.Close()
+	client := NewEtcdClient(server.URL)
+	err := client.SetMulti(map[string]string{"good1": "1", "bad1": "2", "good2": "3", "bad2": "4"}, 0)
+	require.YesError(t, err)
+	setMultiErr, ok := err.(*SetMultiError)
+	require.True(t, ok)
+	require.Equal(t, 2, len(setMultiErr.Errors))
+	require.YesError(t, setMultiErr.Errors["bad1"])
+	require.YesError(t, setMultiErr.Errors["bad2"])
+	require.NoError(t, client.SetMulti(map[string]string{"good1": "1", "good2": "3"}, 0))
+}
+
+func TestRetryClient(t *testing.T) {
+	t.Parallel()
+	transient := errors.New("transient")
+	client := NewFaultyClient(NewMockClient(), FaultOptions{})
+	client.FailNext(OpSet, 2, transient)
+	var retries []int
+	retryClient := NewRetryClient(client, RetryPolicy{
+		MaxAttempts: 3,
+		BackoffBase: time.Millisecond,
+		Jitter:      0.5,
+		Retryable:   func(err error) bool { return err == transient },
+		OnRetry:     func(attempt int, err error) { retries = append(retries, attempt) },
+	})
+	require.NoError(t, retryClient.Set("foo", "bar", 0))
+	require.Equal(t, []int{1, 2}, retries)
+
+	// Give up after MaxAttempts
+	client.FailNext(OpSet, 3, transient)
+	retries = nil
+	require.Equal(t, transient, retryClient.Set("foo", "bar", 0))
+	require.Equal(t, []int{1, 2}, retries)
+
+	// Errors that aren't transient are returned straight away
+	permanent := errors.New("permanent")
+	client.FailNext(OpSet, 1, permanent)
+	retries = nil
+	require.Equal(t, permanent, retryClient.Set("foo", "bar", 0))
+	require.Equal(t, 0, len(retries))
+
+	// The default policy only makes one attempt
+	client.FailNext(OpSet, 1, transient)
+	require.Equal(t, transient, NewRetryClient(client, DefaultRetryPolicy).Set("foo", "bar", 0))
+}
+
+func TestFaultyClient(t *testing.T) {
+	t.Parallel()
+	var onFault []FaultKind
+	client := NewFaultyClient(NewMockClient(), FaultOptions{
+		OnFault: func(fault Fault) { onFault = append(onFault, fault.Kind) },
+	})
+	runTest(t, client)
+	require.Equal(t, 0, len(client.Faults()))
+
+	// Failures only apply to their op, and only n times
+	injected := errors.New("injected")
+	client.FailNext(OpGet, 2, injected)
+	require.NoError(t, client.Set("foo", "bar", 0))
+	for i := 0; i < 2; i++ {
+		_, err := client.Get("foo")
+		require.Equal(t, injected, err)
+	}
+	value, err := client.Get("foo")
+	require.NoError(t, err)
+	require.Equal(t, "bar", value)
+	client.FailNext(OpAny, 1, injected)
+	require.Equal(t, injected, client.Delete("foo"))
+
+	client.Delay(OpSet, 20*time.Millisecond)
+	start := time.Now()
+	require.NoError(t, client.Set("foo", "baz", 0))
+	require.True(t, time.Since(start) >= 20*time.Millisecond)
+	client.Delay(OpSet, 0)
+
+	client.Partition(50 * time.Millisecond)
+	require.Equal(t, ErrPartitioned, client.Set("foo", "qux", 0))
+	_, err = client.GetAll("")
+	require.Equal(t, ErrPartitioned, err)
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, client.Set("foo", "qux", 0))
+
+	var kinds []FaultKind
+	for _, fault := range client.Faults() {
+		kinds = append(kinds, fault.Kind)
+	}
+	expected := []FaultKind{FaultError, FaultError, FaultError, FaultDelay, FaultPartition, FaultPartition}
+	require.Equal(t, expected, kinds)
+	require.Equal(t, expected, onFault)
+	require.Equal(t, Fault{Kind: FaultError, Op: OpGet, Key: "foo", Err: injected}, client.Faults()[0])
+}
+
+// TestFaultyClientWatchResume drops WatchAllDelta notifications and checks
+// that the watch resumes from a snapshot.
+func TestFaultyClientWatchResume(t *testing.T) {
+	t.Parallel()
+	client := NewFaultyClient(NewMockClient(), FaultOptions{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	deltas := make(chan *Delta)
+	go client.WatchAllDeltaCtx(ctx, "dir", func(delta *Delta) error {
+		deltas <- delta
+		return nil
+	})
+	require.True(t, (<-deltas).Snapshot)
+	client.DropNotifications(2)
+	// Wait for each notification to be dropped, the mock client may coalesce
+	// sets which are close together.
+	for i, key := range []string{"dir/a", "dir/b"} {
+		require.NoError(t, client.Set(key, "1", 0))
+		for len(client.Faults()) <= i {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	require.NoError(t, client.Set("dir/c", "1", 0))
+	delta := <-deltas
+	require.True(t, delta.Snapshot)
+	require.Equal(t, map[string]string{"dir/a": "1", "dir/b": "1", "dir/c": "1"}, delta.Updated)
+	require.NoError(t, client.Set("dir/d", "1", 0))
+	delta = <-deltas
+	require.False(t, delta.Snapshot)
+	require.Equal(t, map[string]string{"dir/d": "1"}, delta.Updated)
+	require.Equal(t, 2, len(client.Faults()))
+}
+
+func TestRateLimitedClient(t *testing.T) {
+	timer := pkgtime.NewFakeTimer()
+	var throttled []string
+	client := NewRateLimitedClient(NewMockClient(), RateLimitPolicy{
+		Limits:     map[Op]RateLimit{OpSet: {Rate: 2, Burst: 2}},
+		PerKey:     true,
+		OnThrottle: func(op Op, key string) { throttled = append(throttled, fmt.Sprintf("%s %s", op, key)) },
+		Timer:      timer,
+	})
+	// The burst is allowed, and then writes fail until a token accrues
+	require.NoError(t, client.Set("foo", "1", 0))
+	require.NoError(t, client.Set("foo", "2", 0))
+	require.Equal(t, ErrRateLimited, client.Set("foo", "3", 0))
+	value, err := client.Get("foo")
+	require.NoError(t, err)
+	require.Equal(t, "2", value)
+	// Other keys, and other kinds of writes, have their own budget
+	require.NoError(t, client.Set("bar", "1", 0))
+	require.NoError(t, client.Delete("bar"))
+	timer.Add(0, int64(500*time.Millisecond))
+	require.NoError(t, client.Set("foo", "3", 0))
+	require.Equal(t, ErrRateLimited, client.Set("foo", "4", 0))
+	// Tokens don't accrue beyond the burst
+	timer.Add(10, 0)
+	require.NoError(t, client.Set("foo", "4", 0))
+	require.NoError(t, client.Set("foo", "5", 0))
+	require.Equal(t, ErrRateLimited, client.Set("foo", "6", 0))
+	require.Equal(t, []string{"Set foo", "Set foo", "Set foo"}, throttled)
+
+	// A blocking client waits for a token, or for its context
+	client = NewRateLimitedClient(NewMockClient(), RateLimitPolicy{
+		Limits: map[Op]RateLimit{OpSet: {Rate: 20, Burst: 1}},
+		Block:  true,
+	})
+	start := time.Now()
+	require.NoError(t, client.Set("foo", "1", 0))
+	require.NoError(t, client.Set("foo", "2", 0))
+	require.True(t, time.Since(start) >= 40*time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.Equal(t, context.Canceled, client.SetCtx(ctx, "foo", "3", 0))
+}
+
+func TestNamespacedClient(t *testing.T) {
+	t.Parallel()
+	mockClient := NewMockClient()
+	client := NewNamespacedClient(mockClient, "/namespace/")
+	runTest(t, client)
+	runWatchTest(t, client)
+	runWatchAllDeltaTest(t, client, "delta")
+	require.NoError(t, client.CreateInDir("dir", "value", 0))
+	require.NoError(t, client.CheckAndSet("lock", "value", 0, ""))
+	_, err := client.Get("../escape")
+	require.YesError(t, err)
+	require.YesError(t, client.Set("a/../../escape", "value", 0))
+	require.YesError(t, client.SetMulti(map[string]string{"ok": "value", "../escape": "value"}, 0))
+	values, err := mockClient.GetAll("")
+	require.NoError(t, err)
+	require.True(t, len(values) > 0)
+	for key := range values {
+		require.True(t, strings.HasPrefix(key, "namespace/"), "%s escaped the namespace", key)
+	}
+	for _, prefix := range []string{"", "/", "a//b", "a/../b", "../a"} {
+		func() {
+			defer func() {
+				require.True(t, recover() != nil, "%q should be rejected", prefix)
+			}()
+			NewNamespacedClient(mockClient, prefix)
+		}()
+	}
+}
+
+func TestEncryptedClient(t *testing.T) {
+	t.Parallel()
+	oldKey := []byte("0123456789abcdef0123456789abcdef")
+	newKey := []byte("fedcba9876543210fedcba9876543210")
+	mockClient := NewMockClient()
+	client, err := NewEncryptedClient(mockClient, oldKey)
+	require.NoError(t, err)
+	runTest(t, client)
+	runWatchTest(t, client)
+	runWatchAllDeltaTest(t, client, "delta")
+	runLeaseTest(t, client, "lease")
+	// Values are stored encrypted, keys aren't
+	require.NoError(t, client.Set("secret", "server0:650", 0))
+	stored, err := mockClient.Get("secret")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(stored, "pachenc:1:"), "%s isn't encrypted", stored)
+	require.False(t, strings.Contains(stored, "server0"))
+	// Every write uses a fresh nonce, but compare operations still compare
+	// the plaintext
+	require.NoError(t, client.Set("secret", "server0:650", 0))
+	restored, err := mockClient.Get("secret")
+	require.NoError(t, err)
+	require.NotEqual(t, stored, restored)
+	require.YesError(t, client.CheckAndSet("secret", "server1:650", 0, "server2:650"))
+	require.NoError(t, client.CheckAndSet("secret", "server1:650", 0, "server0:650"))
+	require.YesError(t, client.CheckAndDelete("secret", "server0:650"))
+	require.NoError(t, client.CheckAndDelete("secret", "server1:650"))
+	// Legacy plaintext values are read as they are
+	require.NoError(t, mockClient.Set("legacy/a", "plaintext", 0))
+	value, err := client.Get("legacy/a")
+	require.NoError(t, err)
+	require.Equal(t, "plaintext", value)
+	require.NoError(t, client.Set("legacy/b", "ciphertext", 0))
+	values, err := client.GetAll("legacy")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"legacy/a": "plaintext", "legacy/b": "ciphertext"}, values)
+
+	// Rotation, a client with the new key and the old one as a decryption
+	// key reads values written with either, and writes with the new key
+	rotatedClient, err := NewEncryptedClient(mockClient, newKey, oldKey)
+	require.NoError(t, err)
+	require.NoError(t, client.Set("rotate/old", "1", 0))
+	require.NoError(t, rotatedClient.Set("rotate/new", "2", 0))
+	values, err = rotatedClient.GetAll("rotate")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"rotate/old": "1", "rotate/new": "2"}, values)
+	_, err = client.Get("rotate/new")
+	require.Equal(t, ErrUnknownEncryptionKey, err)
+	newClient, err := NewEncryptedClient(mockClient, newKey)
+	require.NoError(t, err)
+	value, err = newClient.Get("rotate/new")
+	require.NoError(t, err)
+	require.Equal(t, "2", value)
+	_, err = newClient.Get("rotate/old")
+	require.Equal(t, ErrUnknownEncryptionKey, err)
+	// A watch fails on values it can't decrypt rather than passing them on
+	err = newClient.WatchAll("rotate", nil, func(map[string]string) error { return nil })
+	require.Equal(t, ErrUnknownEncryptionKey, err)
+
+	// Tampered values and bad keys are rejected
+	require.NoError(t, mockClient.Set("tampered", stored[:len(stored)-4]+"AAA=", 0))
+	_, err = client.Get("tampered")
+	require.YesError(t, err)
+	_, err = NewEncryptedClient(mockClient, []byte("short"))
+	require.YesError(t, err)
+	_, err = NewEncryptedClient(mockClient, newKey, []byte("short"))
+	require.YesError(t, err)
+}
+
+func TestMockClient(t *testing.T) {
+	t.Parallel()
+	runTest(t, NewMockClient())
+}
+
+func TestMockWatch(t *testing.T) {
+	t.Parallel()
+	runWatchTest(t, NewMockClient())
+}
+
+func TestMockWatchAllDelta(t *testing.T) {
+	t.Parallel()
+	runWatchAllDeltaTest(t, NewMockClient(), "mockWatchAllDelta")
+}
+
+func TestMockLease(t *testing.T) {
+	t.Parallel()
+	runLeaseTest(t, NewMockClient(), "mockLease")
+}
+
+func TestMockWatchAllDeltaResume(t *testing.T) {
+	t.Parallel()
+	client := newMockClient(realClock{})
+	require.NoError(t, client.Set("resume/a", "1", 0))
+	require.NoError(t, client.Set("resume/b", "2", 0))
+	cancel := make(chan bool)
+	var deltas []*Delta
+	err := client.WatchAllDelta(
+		"resume",
+		cancel,
+		func(delta *Delta) error {
+			deltas = append(deltas, delta)
+			switch len(deltas) {
+			case 1:
+				// Simulate a key disappearing while the watch is down, the
+				// resumed watch can only report it by omitting it from its
+				// snapshot.
+				client.lock.Lock()
+				delete(client.records, "resume/b")
+				client.lock.Unlock()
+				client.resumeWatches()
+			case 2:
+				close(cancel)
+			}
+			return nil
+		},
+	)
+	require.Equal(t, ErrCancelled, err)
+	require.Equal(t, 2, len(deltas))
+	require.True(t, deltas[0].Snapshot)
+	require.Equal(t, map[string]string{"resume/a": "1", "resume/b": "2"}, deltas[0].Updated)
+	require.True(t, deltas[1].Snapshot)
+	require.Equal(t, map[string]string{"resume/a": "1"}, deltas[1].Updated)
+}
+
+func TestMockClientFakeClock(t *testing.T) {
+	t.Parallel()
+	clock := NewFakeClock(time.Unix(0, 0))
+	client := NewMockClientWithClock(clock)
+	require.NoError(t, client.Set("clock/ttl", "1", 10))
+	require.NoError(t, client.Set("clock/forever", "2", 0))
+	cancel := make(chan bool)
+	var values []map[string]string
+	err := client.WatchAll(
+		"clock",
+		cancel,
+		func(value map[string]string) error {
+			values = append(values, value)
+			switch len(values) {
+			case 1:
+				// Nothing expires until the clock gets to the ttl.
+				clock.Advance(9 * time.Second)
+				value, err := client.Get("clock/ttl")
+				require.NoError(t, err)
+				require.Equal(t, "1", value)
+				clock.Advance(time.Second)
+			case 2:
+				close(cancel)
+			}
+			return nil
+		},
+	)
+	require.Equal(t, ErrCancelled, err)
+	require.Equal(t, []map[string]string{
+		{"clock/ttl": "1", "clock/forever": "2"},
+		{"clock/forever": "2"},
+	}, values)
+}
+
 func runTest(t *testing.T, client Client) {
 	err := client.Set("foo", "one", 0)
 	require.NoError(t, err)
@@ -51,6 +468,12 @@ func runTest(t *testing.T, client Client) {
 	require.NoError(t, err)
 	require.Equal(t, map[string]string{"a/b/foo": "one", "a/b/bar": "two"}, values)
 
+	err = client.SetMulti(map[string]string{"a/c/foo": "three", "a/c/bar": "four"}, 0)
+	require.NoError(t, err)
+	values, err = client.GetAll("a/c")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"a/c/foo": "three", "a/c/bar": "four"}, values)
+
 	require.NoError(t, client.Close())
 }
 
@@ -86,6 +509,173 @@ func runWatchTest(t *testing.T, client Client) {
 	require.Equal(t, ErrCancelled, err)
 }
 
+func runWatchAllDeltaTest(t *testing.T, client Client, dir string) {
+	cancel := make(chan bool)
+	var deltas []*Delta
+	err := client.WatchAllDelta(
+		dir,
+		cancel,
+		func(delta *Delta) error {
+			deltas = append(deltas, delta)
+			switch len(deltas) {
+			case 1:
+				return client.Set(dir+"/foo", "bar", 0)
+			case 2:
+				return client.Set(dir+"/buzz", "quux", 0)
+			case 3:
+				return client.Delete(dir + "/foo")
+			default:
+				close(cancel)
+			}
+			return nil
+		},
+	)
+	require.Equal(t, ErrCancelled, err)
+	require.True(t, deltas[0].Snapshot)
+	require.Equal(t, 0, len(deltas[0].Updated))
+	require.False(t, deltas[1].Snapshot)
+	require.Equal(t, map[string]string{dir + "/foo": "bar"}, deltas[1].Updated)
+	require.Equal(t, map[string]string{dir + "/buzz": "quux"}, deltas[2].Updated)
+	require.Equal(t, 0, len(deltas[3].Updated))
+	require.Equal(t, []string{dir + "/foo"}, deltas[3].Deleted)
+}
+
+func runLeaseTest(t *testing.T, client Client, dir string) {
+	ctx := context.Background()
+	options := LeaseOptions{RefreshInterval: 100 * time.Millisecond}
+	blockingOptions := LeaseOptions{Block: true, RefreshInterval: 100 * time.Millisecond}
+
+	// Contention, the second holder fails fast, or waits until the lease
+	// is released.
+	key := dir + "/contention"
+	lease, err := AcquireLease(ctx, client, key, "a", 2, options)
+	require.NoError(t, err)
+	_, err = AcquireLease(ctx, client, key, "b", 2, options)
+	require.Equal(t, ErrLeaseHeld, err)
+	acquired := make(chan *Lease)
+	go func() {
+		lease, err := AcquireLease(ctx, client, key, "b", 2, blockingOptions)
+		require.NoError(t, err)
+		acquired <- lease
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("lease acquired while held")
+	case <-time.After(300 * time.Millisecond):
+	}
+	require.NoError(t, lease.Release())
+	var other *Lease
+	select {
+	case other = <-acquired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("lease not acquired after release")
+	}
+	holder, err := client.Get(key)
+	require.NoError(t, err)
+	require.Equal(t, "b", holder)
+	// A blocked acquirer gives up when its context is done
+	cancelCtx, cancel := context.WithTimeout(ctx, 300*time.Millisecond)
+	defer cancel()
+	_, err = AcquireLease(cancelCtx, client, key, "a", 2, blockingOptions)
+	require.Equal(t, context.DeadlineExceeded, err)
+	require.NoError(t, other.Release())
+
+	// A holder which crashes, so it can't refresh the lease, loses it, and
+	// another holder acquires it once it expires.
+	key = dir + "/crash"
+	faultyClient := NewFaultyClient(client, FaultOptions{})
+	lease, err = AcquireLease(ctx, faultyClient, key, "a", 1, options)
+	require.NoError(t, err)
+	faultyClient.Partition(time.Hour)
+	select {
+	case <-lease.Lost():
+	case <-time.After(5 * time.Second):
+		t.Fatal("lease not lost")
+	}
+	require.NoError(t, lease.Release())
+	_, err = AcquireLease(ctx, client, key, "b", 1, options)
+	require.Equal(t, ErrLeaseHeld, err)
+	start := time.Now()
+	lease, err = AcquireLease(ctx, client, key, "b", 1, blockingOptions)
+	require.NoError(t, err)
+	require.True(t, time.Since(start) < 5*time.Second)
+	require.NoError(t, lease.Release())
+
+	// An unrefreshed lease expires after its TTL, and a lease that's
+	// refreshed doesn't.
+	key = dir + "/expiry"
+	require.NoError(t, client.CheckAndSet(key, "a", 1, ""))
+	lease, err = AcquireLease(ctx, client, key+"2", "a", 1, options)
+	require.NoError(t, err)
+	time.Sleep(2 * time.Second)
+	_, err = client.Get(key)
+	require.YesError(t, err)
+	holder, err = client.Get(key + "2")
+	require.NoError(t, err)
+	require.Equal(t, "a", holder)
+	select {
+	case <-lease.Lost():
+		t.Fatal("refreshed lease lost")
+	default:
+	}
+	require.NoError(t, lease.Release())
+	_, err = client.Get(key + "2")
+	require.YesError(t, err)
+}
+
+func BenchmarkMockSet(b *testing.B) {
+	benchmarkSet(b, NewMockClient())
+}
+
+func BenchmarkMockSetMulti(b *testing.B) {
+	benchmarkSetMulti(b, NewMockClient())
+}
+
+func BenchmarkEtcdSet(b *testing.B) {
+	if os.Getenv("ETCD_PORT_2379_TCP_ADDR") == "" {
+		b.Skip("skipping benchmark; $ETCD_PORT_2379_TCP_ADDR not set")
+	}
+	client, err := getEtcdClient()
+	require.NoError(b, err)
+	benchmarkSet(b, client)
+}
+
+func BenchmarkEtcdSetMulti(b *testing.B) {
+	if os.Getenv("ETCD_PORT_2379_TCP_ADDR") == "" {
+		b.Skip("skipping benchmark; $ETCD_PORT_2379_TCP_ADDR not set")
+	}
+	client, err := getEtcdClient()
+	require.NoError(b, err)
+	benchmarkSetMulti(b, client)
+}
+
+// benchmarkKVs is the size of a version publication for 100 servers.
+func benchmarkKVs() map[string]string {
+	kvs := make(map[string]string)
+	for i := 0; i < 101; i++ {
+		kvs[fmt.Sprintf("benchmark/%d", i)] = fmt.Sprint(i)
+	}
+	return kvs
+}
+
+func benchmarkSet(b *testing.B, client Client) {
+	kvs := benchmarkKVs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for key, value := range kvs {
+			require.NoError(b, client.Set(key, value, 0))
+		}
+	}
+}
+
+func benchmarkSetMulti(b *testing.B, client Client) {
+	kvs := benchmarkKVs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		require.NoError(b, client.SetMulti(kvs, 0))
+	}
+}
+
 func getEtcdClient() (Client, error) {
 	etcdAddress, err := getEtcdAddress()
 	if err != nil {
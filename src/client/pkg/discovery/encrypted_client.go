@@ -0,0 +1,283 @@
+package discovery
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/context"
+)
+
+// encryptedValuePrefix starts every value written by an encrypting client,
+// values without it are legacy plaintext.
+const encryptedValuePrefix = "pachenc:"
+
+// encryptedValueVersion is the version of the envelope written by an
+// encrypting client. An envelope looks like:
+//
+//	pachenc:1:<key id>:<base64 of nonce followed by AES-GCM ciphertext>
+//
+// where the key id is the first 4 bytes of the SHA-256 of the key, in hex.
+const encryptedValueVersion = "1"
+
+// ErrUnknownEncryptionKey is returned when reading a value encrypted with a
+// key the client wasn't given.
+var ErrUnknownEncryptionKey = fmt.Errorf("pachyderm: discovery value encrypted with an unknown key")
+
+// NewEncryptedClient returns a Client which encrypts the values it writes to
+// client with AES-GCM using encryptionKey, and decrypts the values it reads
+// with encryptionKey or any of decryptionKeys, so that keys can be rotated by
+// moving the old key to decryptionKeys. Keys must be 16, 24 or 32 bytes.
+// Keys, as opposed to values, are passed through untouched. Values that
+// aren't encrypted, because they were written before encryption was turned
+// on, are read as they are. A value that can't be decrypted fails the read,
+// or the watch, that returned it.
+func NewEncryptedClient(client Client, encryptionKey []byte, decryptionKeys ...[]byte) (Client, error) {
+	result := &encryptedClient{
+		Client: client,
+		aeads:  make(map[string]cipher.AEAD),
+	}
+	for i, key := range append([][]byte{encryptionKey}, decryptionKeys...) {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		keyID := encryptionKeyID(key)
+		if i == 0 {
+			result.keyID = keyID
+		}
+		result.aeads[keyID] = aead
+	}
+	return result, nil
+}
+
+func encryptionKeyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:4])
+}
+
+type encryptedClient struct {
+	Client
+	// keyID identifies the key used to encrypt
+	keyID string
+	aeads map[string]cipher.AEAD
+}
+
+func (c *encryptedClient) encrypt(value string) (string, error) {
+	aead := c.aeads[c.keyID]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(value), nil)
+	return encryptedValuePrefix + encryptedValueVersion + ":" + c.keyID + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (c *encryptedClient) decrypt(key string, value string) (string, error) {
+	if !strings.HasPrefix(value, encryptedValuePrefix) {
+		return value, nil
+	}
+	parts := strings.SplitN(strings.TrimPrefix(value, encryptedValuePrefix), ":", 3)
+	if len(parts) != 3 || parts[0] != encryptedValueVersion {
+		return "", fmt.Errorf("pachyderm: unrecognized encrypted value for %s", key)
+	}
+	aead, ok := c.aeads[parts[1]]
+	if !ok {
+		return "", ErrUnknownEncryptionKey
+	}
+	sealed, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("pachyderm: malformed encrypted value for %s", key)
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("pachyderm: error decrypting value for %s: %s", key, err.Error())
+	}
+	return string(plaintext), nil
+}
+
+func (c *encryptedClient) decryptMap(values map[string]string) (map[string]string, error) {
+	if values == nil {
+		return nil, nil
+	}
+	result := make(map[string]string, len(values))
+	for key, value := range values {
+		plaintext, err := c.decrypt(key, value)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = plaintext
+	}
+	return result, nil
+}
+
+func (c *encryptedClient) Get(key string) (string, error) {
+	return c.GetCtx(context.Background(), key)
+}
+
+func (c *encryptedClient) GetCtx(ctx context.Context, key string) (string, error) {
+	value, err := c.Client.GetCtx(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	return c.decrypt(key, value)
+}
+
+func (c *encryptedClient) GetAll(key string) (map[string]string, error) {
+	return c.GetAllCtx(context.Background(), key)
+}
+
+func (c *encryptedClient) GetAllCtx(ctx context.Context, key string) (map[string]string, error) {
+	values, err := c.Client.GetAllCtx(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return c.decryptMap(values)
+}
+
+func (c *encryptedClient) Watch(key string, cancel chan bool, callBack func(string) error) error {
+	ctx, done := cancelToContext(cancel)
+	defer done()
+	return contextToCancelErr(c.WatchCtx(ctx, key, callBack))
+}
+
+func (c *encryptedClient) WatchCtx(ctx context.Context, key string, callBack func(string) error) error {
+	return c.Client.WatchCtx(ctx, key, func(value string) error {
+		value, err := c.decrypt(key, value)
+		if err != nil {
+			return err
+		}
+		return callBack(value)
+	})
+}
+
+func (c *encryptedClient) WatchAll(key string, cancel chan bool, callBack func(map[string]string) error) error {
+	ctx, done := cancelToContext(cancel)
+	defer done()
+	return contextToCancelErr(c.WatchAllCtx(ctx, key, callBack))
+}
+
+func (c *encryptedClient) WatchAllCtx(ctx context.Context, key string, callBack func(map[string]string) error) error {
+	return c.Client.WatchAllCtx(ctx, key, func(values map[string]string) error {
+		values, err := c.decryptMap(values)
+		if err != nil {
+			return err
+		}
+		return callBack(values)
+	})
+}
+
+func (c *encryptedClient) WatchAllDelta(key string, cancel chan bool, callBack func(*Delta) error) error {
+	ctx, done := cancelToContext(cancel)
+	defer done()
+	return contextToCancelErr(c.WatchAllDeltaCtx(ctx, key, callBack))
+}
+
+func (c *encryptedClient) WatchAllDeltaCtx(ctx context.Context, key string, callBack func(*Delta) error) error {
+	return c.Client.WatchAllDeltaCtx(ctx, key, func(delta *Delta) error {
+		updated, err := c.decryptMap(delta.Updated)
+		if err != nil {
+			return err
+		}
+		return callBack(&Delta{Snapshot: delta.Snapshot, Updated: updated, Deleted: delta.Deleted})
+	})
+}
+
+func (c *encryptedClient) Set(key string, value string, ttl uint64) error {
+	return c.SetCtx(context.Background(), key, value, ttl)
+}
+
+func (c *encryptedClient) SetCtx(ctx context.Context, key string, value string, ttl uint64) error {
+	value, err := c.encrypt(value)
+	if err != nil {
+		return err
+	}
+	return c.Client.SetCtx(ctx, key, value, ttl)
+}
+
+func (c *encryptedClient) SetMulti(kvs map[string]string, ttl uint64) error {
+	return c.SetMultiCtx(context.Background(), kvs, ttl)
+}
+
+func (c *encryptedClient) SetMultiCtx(ctx context.Context, kvs map[string]string, ttl uint64) error {
+	encrypted := make(map[string]string, len(kvs))
+	for key, value := range kvs {
+		value, err := c.encrypt(value)
+		if err != nil {
+			return err
+		}
+		encrypted[key] = value
+	}
+	return c.Client.SetMultiCtx(ctx, encrypted, ttl)
+}
+
+func (c *encryptedClient) Create(key string, value string, ttl uint64) error {
+	value, err := c.encrypt(value)
+	if err != nil {
+		return err
+	}
+	return c.Client.Create(key, value, ttl)
+}
+
+func (c *encryptedClient) CreateInDir(dir string, value string, ttl uint64) error {
+	value, err := c.encrypt(value)
+	if err != nil {
+		return err
+	}
+	return c.Client.CreateInDir(dir, value, ttl)
+}
+
+// CheckAndDelete and CheckAndSet can't compare oldValue with what's stored,
+// because every encryption of a value is different, so they compare it with
+// the decrypted stored value and then check that the stored value hasn't
+// changed since.
+
+func (c *encryptedClient) CheckAndDelete(key string, oldValue string) error {
+	stored, err := c.storedValue(key, oldValue)
+	if err != nil {
+		return err
+	}
+	return c.Client.CheckAndDelete(key, stored)
+}
+
+func (c *encryptedClient) CheckAndSet(key string, value string, ttl uint64, oldValue string) error {
+	stored := ""
+	if oldValue != "" {
+		var err error
+		if stored, err = c.storedValue(key, oldValue); err != nil {
+			return err
+		}
+	}
+	value, err := c.encrypt(value)
+	if err != nil {
+		return err
+	}
+	return c.Client.CheckAndSet(key, value, ttl, stored)
+}
+
+// storedValue returns the value stored at key, provided it decrypts to
+// oldValue.
+func (c *encryptedClient) storedValue(key string, oldValue string) (string, error) {
+	stored, err := c.Client.Get(key)
+	if err != nil {
+		return "", err
+	}
+	value, err := c.decrypt(key, stored)
+	if err != nil {
+		return "", err
+	}
+	if value != oldValue {
+		return "", fmt.Errorf("pachyderm: precondition not met for %s", key)
+	}
+	return stored, nil
+}
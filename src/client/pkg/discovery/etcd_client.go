@@ -1,11 +1,20 @@
 package discovery
 
 import (
+	"fmt"
+	"net/url"
+	"path"
 	"strings"
+	"sync"
 
 	"github.com/coreos/go-etcd/etcd"
+	"golang.org/x/net/context"
 )
 
+// setMultiParallelism is the maximum number of concurrent requests made by a
+// call to SetMulti.
+const setMultiParallelism = 16
+
 type etcdClient struct {
 	client *etcd.Client
 }
@@ -20,7 +29,11 @@ func (c *etcdClient) Close() error {
 }
 
 func (c *etcdClient) Get(key string) (string, error) {
-	response, err := c.client.Get(key, false, false)
+	return c.GetCtx(context.Background(), key)
+}
+
+func (c *etcdClient) GetCtx(ctx context.Context, key string) (string, error) {
+	response, err := c.get(ctx, key, false)
 	if err != nil {
 		return "", err
 	}
@@ -28,7 +41,11 @@ func (c *etcdClient) Get(key string) (string, error) {
 }
 
 func (c *etcdClient) GetAll(key string) (map[string]string, error) {
-	response, err := c.client.Get(key, false, true)
+	return c.GetAllCtx(context.Background(), key)
+}
+
+func (c *etcdClient) GetAllCtx(ctx context.Context, key string) (map[string]string, error) {
+	response, err := c.get(ctx, key, true)
 	result := make(map[string]string, 0)
 	if err != nil {
 		if strings.HasPrefix(err.Error(), "100: Key not found") {
@@ -41,14 +58,16 @@ func (c *etcdClient) GetAll(key string) (map[string]string, error) {
 }
 
 func (c *etcdClient) Watch(key string, cancel chan bool, callBack func(string) error) error {
+	ctx, done := cancelToContext(cancel)
+	defer done()
+	return contextToCancelErr(c.WatchCtx(ctx, key, callBack))
+}
+
+func (c *etcdClient) WatchCtx(ctx context.Context, key string, callBack func(string) error) error {
 	// This retry is needed for when the etcd cluster gets overloaded.
 	for {
-		if err := c.watchWithoutRetry(key, cancel, callBack); err != nil {
-			etcdErr, ok := err.(*etcd.EtcdError)
-			if ok && etcdErr.ErrorCode == 401 {
-				continue
-			}
-			if ok && etcdErr.ErrorCode == 501 {
+		if err := c.watchWithoutRetry(ctx, key, callBack); err != nil {
+			if retryWatch(err) {
 				continue
 			}
 			return err
@@ -57,13 +76,34 @@ func (c *etcdClient) Watch(key string, cancel chan bool, callBack func(string) e
 }
 
 func (c *etcdClient) WatchAll(key string, cancel chan bool, callBack func(map[string]string) error) error {
+	ctx, done := cancelToContext(cancel)
+	defer done()
+	return contextToCancelErr(c.WatchAllCtx(ctx, key, callBack))
+}
+
+func (c *etcdClient) WatchAllCtx(ctx context.Context, key string, callBack func(map[string]string) error) error {
 	for {
-		if err := c.watchAllWithoutRetry(key, cancel, callBack); err != nil {
-			etcdErr, ok := err.(*etcd.EtcdError)
-			if ok && etcdErr.ErrorCode == 401 {
+		if err := c.watchAllWithoutRetry(ctx, key, callBack); err != nil {
+			if retryWatch(err) {
 				continue
 			}
-			if ok && etcdErr.ErrorCode == 501 {
+			return err
+		}
+	}
+}
+
+func (c *etcdClient) WatchAllDelta(key string, cancel chan bool, callBack func(*Delta) error) error {
+	ctx, done := cancelToContext(cancel)
+	defer done()
+	return contextToCancelErr(c.WatchAllDeltaCtx(ctx, key, callBack))
+}
+
+func (c *etcdClient) WatchAllDeltaCtx(ctx context.Context, key string, callBack func(*Delta) error) error {
+	for {
+		// Each retry starts with a fresh snapshot since we may have missed
+		// events while the watch was down.
+		if err := c.watchAllDeltaWithoutRetry(ctx, key, callBack); err != nil {
+			if retryWatch(err) {
 				continue
 			}
 			return err
@@ -72,9 +112,48 @@ func (c *etcdClient) WatchAll(key string, cancel chan bool, callBack func(map[st
 }
 
 func (c *etcdClient) Set(key string, value string, ttl uint64) error {
-	_, err := c.client.Set(key, value, ttl)
-	if err != nil {
-		return err
+	return c.SetCtx(context.Background(), key, value, ttl)
+}
+
+func (c *etcdClient) SetCtx(ctx context.Context, key string, value string, ttl uint64) error {
+	values := url.Values{}
+	if value != "" {
+		values.Set("value", value)
+	}
+	if ttl > 0 {
+		values.Set("ttl", fmt.Sprint(ttl))
+	}
+	_, err := c.send(ctx, "PUT", key, nil, values)
+	return err
+}
+
+func (c *etcdClient) SetMulti(kvs map[string]string, ttl uint64) error {
+	return c.SetMultiCtx(context.Background(), kvs, ttl)
+}
+
+// SetMultiCtx pipelines the writes since etcd v2 has no transactions.
+func (c *etcdClient) SetMultiCtx(ctx context.Context, kvs map[string]string, ttl uint64) error {
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	errs := make(map[string]error)
+	limiter := make(chan struct{}, setMultiParallelism)
+	for key, value := range kvs {
+		key, value := key, value
+		wg.Add(1)
+		limiter <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-limiter }()
+			if err := c.SetCtx(ctx, key, value, ttl); err != nil {
+				lock.Lock()
+				defer lock.Unlock()
+				errs[key] = err
+			}
+		}()
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		return &SetMultiError{errs}
 	}
 	return nil
 }
@@ -96,11 +175,12 @@ func (c *etcdClient) CreateInDir(dir string, value string, ttl uint64) error {
 }
 
 func (c *etcdClient) Delete(key string) error {
-	_, err := c.client.Delete(key, false)
-	if err != nil {
-		return err
-	}
-	return nil
+	return c.DeleteCtx(context.Background(), key)
+}
+
+func (c *etcdClient) DeleteCtx(ctx context.Context, key string) error {
+	_, err := c.send(ctx, "DELETE", key, nil, nil)
+	return err
 }
 
 func (c *etcdClient) CheckAndDelete(key string, oldValue string) error {
@@ -124,6 +204,60 @@ func (c *etcdClient) CheckAndSet(key string, value string, ttl uint64, oldValue
 	return nil
 }
 
+// get does a (possibly recursive) get of key.
+func (c *etcdClient) get(ctx context.Context, key string, recursive bool) (*etcd.Response, error) {
+	query := url.Values{}
+	if recursive {
+		query.Set("recursive", "true")
+	}
+	return c.send(ctx, "GET", key, query, nil)
+}
+
+// send sends a request for key to etcd.
+func (c *etcdClient) send(ctx context.Context, method string, key string, query url.Values, values url.Values) (*etcd.Response, error) {
+	// Same escaping as etcd.keyToPath, which isn't exported.
+	relativePath := strings.Replace(url.QueryEscape(path.Join("keys", key)), "%2F", "/", -1)
+	if relativePath == "keys" {
+		relativePath = "keys/"
+	}
+	if len(query) > 0 {
+		relativePath += "?" + query.Encode()
+	}
+	return withContext(ctx, func(cancel chan bool) (*etcd.Response, error) {
+		rawResponse, err := c.client.SendRequest(etcd.NewRawRequest(method, relativePath, values, cancel))
+		if err != nil {
+			return nil, err
+		}
+		return rawResponse.Unmarshal()
+	})
+}
+
+// withContext calls f with a channel that's closed once ctx is done. It
+// returns ctx.Err() as soon as ctx is done, even if f hasn't returned yet,
+// because the etcd client can't always interrupt a request that's in flight.
+func withContext(ctx context.Context, f func(cancel chan bool) (*etcd.Response, error)) (*etcd.Response, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	type result struct {
+		response *etcd.Response
+		err      error
+	}
+	cancel := make(chan bool)
+	resultChan := make(chan result, 1)
+	go func() {
+		response, err := f(cancel)
+		resultChan <- result{response, err}
+	}()
+	select {
+	case result := <-resultChan:
+		return result.response, result.err
+	case <-ctx.Done():
+		close(cancel)
+		return nil, ctx.Err()
+	}
+}
+
 // nodeToMap translates the contents of a node into a map
 // nodeToMap can be called on the same map with successive results from watch
 // to accumulate a value
@@ -151,6 +285,30 @@ func nodeToMap(node *etcd.Node, out map[string]string) bool {
 	return changed
 }
 
+// nodeToDelta records the contents of a node returned by a watch in delta.
+func nodeToDelta(node *etcd.Node, delta *Delta) {
+	key := strings.TrimPrefix(node.Key, "/")
+	if !node.Dir {
+		if node.Value == "" {
+			delta.Deleted = append(delta.Deleted, key)
+		} else {
+			delta.Updated[key] = node.Value
+		}
+		return
+	}
+	for _, node := range node.Nodes {
+		nodeToDelta(node, delta)
+	}
+}
+
+// retryWatch returns true if err is a transient error after which a watch
+// should be re-established, this happens when the etcd cluster gets
+// overloaded or when the index we're waiting on has been cleared.
+func retryWatch(err error) bool {
+	etcdErr, ok := err.(*etcd.EtcdError)
+	return ok && (etcdErr.ErrorCode == 401 || etcdErr.ErrorCode == 501)
+}
+
 func maxModifiedIndex(node *etcd.Node) uint64 {
 	result := node.ModifiedIndex
 	for _, node := range node.Nodes {
@@ -161,10 +319,17 @@ func maxModifiedIndex(node *etcd.Node) uint64 {
 	return result
 }
 
-func (c *etcdClient) watchWithoutRetry(key string, cancel chan bool, callBack func(string) error) error {
+// watchOnce waits for the next change under key after waitIndex.
+func (c *etcdClient) watchOnce(ctx context.Context, key string, waitIndex uint64, recursive bool) (*etcd.Response, error) {
+	return withContext(ctx, func(stop chan bool) (*etcd.Response, error) {
+		return c.client.Watch(key, waitIndex, recursive, nil, stop)
+	})
+}
+
+func (c *etcdClient) watchWithoutRetry(ctx context.Context, key string, callBack func(string) error) error {
 	var waitIndex uint64 = 1
 	// First get the starting value of the key
-	response, err := c.client.Get(key, false, false)
+	response, err := c.get(ctx, key, false)
 	if err != nil {
 		if strings.HasPrefix(err.Error(), "100: Key not found") {
 			err = callBack("")
@@ -182,11 +347,8 @@ func (c *etcdClient) watchWithoutRetry(key string, cancel chan bool, callBack fu
 		waitIndex = response.Node.ModifiedIndex + 1
 	}
 	for {
-		response, err := c.client.Watch(key, waitIndex, false, nil, cancel)
+		response, err := c.watchOnce(ctx, key, waitIndex, false)
 		if err != nil {
-			if err == etcd.ErrWatchStoppedByUser {
-				return ErrCancelled
-			}
 			return err
 		}
 		err = callBack(response.Node.Value)
@@ -197,11 +359,11 @@ func (c *etcdClient) watchWithoutRetry(key string, cancel chan bool, callBack fu
 	}
 }
 
-func (c *etcdClient) watchAllWithoutRetry(key string, cancel chan bool, callBack func(map[string]string) error) error {
+func (c *etcdClient) watchAllWithoutRetry(ctx context.Context, key string, callBack func(map[string]string) error) error {
 	var waitIndex uint64 = 1
 	value := make(map[string]string)
 	// First get the starting value of the key
-	response, err := c.client.Get(key, false, false)
+	response, err := c.get(ctx, key, false)
 	if err != nil {
 		if strings.HasPrefix(err.Error(), "100: Key not found") {
 			err = callBack(nil)
@@ -221,11 +383,8 @@ func (c *etcdClient) watchAllWithoutRetry(key string, cancel chan bool, callBack
 		}
 	}
 	for {
-		response, err := c.client.Watch(key, waitIndex, true, nil, cancel)
+		response, err := c.watchOnce(ctx, key, waitIndex, true)
 		if err != nil {
-			if err == etcd.ErrWatchStoppedByUser {
-				return ErrCancelled
-			}
 			return err
 		}
 		responseModifiedIndex := maxModifiedIndex(response.Node)
@@ -238,3 +397,41 @@ func (c *etcdClient) watchAllWithoutRetry(key string, cancel chan bool, callBack
 		}
 	}
 }
+
+func (c *etcdClient) watchAllDeltaWithoutRetry(ctx context.Context, key string, callBack func(*Delta) error) error {
+	var waitIndex uint64 = 1
+	snapshot := &Delta{
+		Snapshot: true,
+		Updated:  make(map[string]string),
+	}
+	// First get the starting value of the directory
+	response, err := c.get(ctx, key, true)
+	if err != nil {
+		if !strings.HasPrefix(err.Error(), "100: Key not found") {
+			return err
+		}
+	} else {
+		waitIndex = maxModifiedIndex(response.Node) + 1
+		nodeToDelta(response.Node, snapshot)
+		// A snapshot doesn't need to report deleted keys
+		snapshot.Deleted = nil
+	}
+	if err := callBack(snapshot); err != nil {
+		return err
+	}
+	for {
+		response, err := c.watchOnce(ctx, key, waitIndex, true)
+		if err != nil {
+			return err
+		}
+		waitIndex = maxModifiedIndex(response.Node) + 1
+		delta := &Delta{Updated: make(map[string]string)}
+		nodeToDelta(response.Node, delta)
+		if len(delta.Updated) == 0 && len(delta.Deleted) == 0 {
+			continue
+		}
+		if err := callBack(delta); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,388 @@
+package discovery
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// ErrPartitioned is returned by a FaultyClient for every call made while
+// it's partitioned.
+var ErrPartitioned = fmt.Errorf("pachyderm: discovery partitioned")
+
+// Ops identifying reads and watches, for FaultyClient. Watch covers every
+// kind of watch.
+const (
+	OpGet    Op = "Get"
+	OpGetAll Op = "GetAll"
+	OpWatch  Op = "Watch"
+	// OpAny matches every kind of call.
+	OpAny Op = ""
+)
+
+// FaultKind is a kind of fault injected by a FaultyClient.
+type FaultKind int
+
+const (
+	// FaultDelay means a call was delayed.
+	FaultDelay FaultKind = iota
+	// FaultError means a call failed with an injected error.
+	FaultError
+	// FaultPartition means a call failed with ErrPartitioned, or a watch
+	// notification was dropped, because the client was partitioned.
+	FaultPartition
+	// FaultDropNotification means a watch notification was dropped.
+	FaultDropNotification
+	// FaultDelayNotification means a watch notification was delayed.
+	FaultDelayNotification
+)
+
+func (k FaultKind) String() string {
+	switch k {
+	case FaultDelay:
+		return "Delay"
+	case FaultError:
+		return "Error"
+	case FaultPartition:
+		return "Partition"
+	case FaultDropNotification:
+		return "DropNotification"
+	case FaultDelayNotification:
+		return "DelayNotification"
+	}
+	return fmt.Sprintf("FaultKind(%d)", int(k))
+}
+
+// Fault describes a fault injected by a FaultyClient.
+type Fault struct {
+	Kind FaultKind
+	Op   Op
+	// Key is the key of the call, or of the watch.
+	Key string
+	// Err is the error returned, if any.
+	Err error
+}
+
+// FaultOptions configures a FaultyClient.
+type FaultOptions struct {
+	// OnFault, if set, is called for every fault injected. It's called with
+	// the client's lock held, so it mustn't call the client.
+	OnFault func(Fault)
+}
+
+// FaultyClient is a Client which injects faults, under the control of a
+// test, into the calls it passes on to another Client.
+type FaultyClient interface {
+	Client
+	// Delay delays every call to op by delay, a delay of 0 stops delaying
+	// them.
+	Delay(op Op, delay time.Duration)
+	// FailNext makes the next n calls to op fail with err.
+	FailNext(op Op, n int, err error)
+	// DropNotifications drops the next n watch notifications. The first
+	// notification a WatchAllDelta delivers afterwards is a Snapshot, as it
+	// would be after a real watch was re-established.
+	DropNotifications(n int)
+	// DelayNotifications delays every watch notification by delay, a delay
+	// of 0 stops delaying them.
+	DelayNotifications(delay time.Duration)
+	// Partition makes every call fail with ErrPartitioned, and drops every
+	// watch notification, for duration.
+	Partition(duration time.Duration)
+	// Faults returns the faults injected so far.
+	Faults() []Fault
+}
+
+// NewFaultyClient returns a FaultyClient which passes calls on to client.
+// It doesn't inject any faults until it's told to.
+func NewFaultyClient(client Client, options FaultOptions) FaultyClient {
+	return &faultyClient{
+		Client:   client,
+		options:  options,
+		delays:   make(map[Op]time.Duration),
+		failures: make(map[Op]*injectedFailure),
+	}
+}
+
+type injectedFailure struct {
+	n   int
+	err error
+}
+
+type faultyClient struct {
+	Client
+	options            FaultOptions
+	delays             map[Op]time.Duration
+	failures           map[Op]*injectedFailure
+	dropNotifications  int
+	delayNotifications time.Duration
+	partitionEnd       time.Time
+	faults             []Fault
+	lock               sync.Mutex
+}
+
+func (c *faultyClient) Delay(op Op, delay time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.delays[op] = delay
+}
+
+func (c *faultyClient) FailNext(op Op, n int, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.failures[op] = &injectedFailure{n, err}
+}
+
+func (c *faultyClient) DropNotifications(n int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.dropNotifications = n
+}
+
+func (c *faultyClient) DelayNotifications(delay time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.delayNotifications = delay
+}
+
+func (c *faultyClient) Partition(duration time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.partitionEnd = time.Now().Add(duration)
+}
+
+func (c *faultyClient) Faults() []Fault {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return append([]Fault(nil), c.faults...)
+}
+
+func (c *faultyClient) Get(key string) (string, error) {
+	return c.GetCtx(context.Background(), key)
+}
+
+func (c *faultyClient) GetCtx(ctx context.Context, key string) (string, error) {
+	if err := c.inject(ctx, OpGet, key); err != nil {
+		return "", err
+	}
+	return c.Client.GetCtx(ctx, key)
+}
+
+func (c *faultyClient) GetAll(key string) (map[string]string, error) {
+	return c.GetAllCtx(context.Background(), key)
+}
+
+func (c *faultyClient) GetAllCtx(ctx context.Context, key string) (map[string]string, error) {
+	if err := c.inject(ctx, OpGetAll, key); err != nil {
+		return nil, err
+	}
+	return c.Client.GetAllCtx(ctx, key)
+}
+
+func (c *faultyClient) Watch(key string, cancel chan bool, callBack func(string) error) error {
+	ctx, done := cancelToContext(cancel)
+	defer done()
+	return contextToCancelErr(c.WatchCtx(ctx, key, callBack))
+}
+
+func (c *faultyClient) WatchCtx(ctx context.Context, key string, callBack func(string) error) error {
+	if err := c.inject(ctx, OpWatch, key); err != nil {
+		return err
+	}
+	return c.Client.WatchCtx(ctx, key, func(value string) error {
+		if !c.notification(ctx, key) {
+			return nil
+		}
+		return callBack(value)
+	})
+}
+
+func (c *faultyClient) WatchAll(key string, cancel chan bool, callBack func(map[string]string) error) error {
+	ctx, done := cancelToContext(cancel)
+	defer done()
+	return contextToCancelErr(c.WatchAllCtx(ctx, key, callBack))
+}
+
+func (c *faultyClient) WatchAllCtx(ctx context.Context, key string, callBack func(map[string]string) error) error {
+	if err := c.inject(ctx, OpWatch, key); err != nil {
+		return err
+	}
+	return c.Client.WatchAllCtx(ctx, key, func(value map[string]string) error {
+		if !c.notification(ctx, key) {
+			return nil
+		}
+		return callBack(value)
+	})
+}
+
+func (c *faultyClient) WatchAllDelta(key string, cancel chan bool, callBack func(*Delta) error) error {
+	ctx, done := cancelToContext(cancel)
+	defer done()
+	return contextToCancelErr(c.WatchAllDeltaCtx(ctx, key, callBack))
+}
+
+func (c *faultyClient) WatchAllDeltaCtx(ctx context.Context, key string, callBack func(*Delta) error) error {
+	if err := c.inject(ctx, OpWatch, key); err != nil {
+		return err
+	}
+	dropped := false
+	return c.Client.WatchAllDeltaCtx(ctx, key, func(delta *Delta) error {
+		if !c.notification(ctx, key) {
+			dropped = true
+			return nil
+		}
+		if dropped && !delta.Snapshot {
+			// The deltas that were dropped are lost, so resume from a
+			// snapshot.
+			value, err := c.Client.GetAllCtx(ctx, key)
+			if err != nil {
+				return err
+			}
+			delta = &Delta{Snapshot: true, Updated: value}
+		}
+		dropped = false
+		return callBack(delta)
+	})
+}
+
+func (c *faultyClient) Set(key string, value string, ttl uint64) error {
+	return c.SetCtx(context.Background(), key, value, ttl)
+}
+
+func (c *faultyClient) SetCtx(ctx context.Context, key string, value string, ttl uint64) error {
+	if err := c.inject(ctx, OpSet, key); err != nil {
+		return err
+	}
+	return c.Client.SetCtx(ctx, key, value, ttl)
+}
+
+func (c *faultyClient) SetMulti(kvs map[string]string, ttl uint64) error {
+	return c.SetMultiCtx(context.Background(), kvs, ttl)
+}
+
+func (c *faultyClient) SetMultiCtx(ctx context.Context, kvs map[string]string, ttl uint64) error {
+	if err := c.inject(ctx, OpSetMulti, ""); err != nil {
+		return err
+	}
+	return c.Client.SetMultiCtx(ctx, kvs, ttl)
+}
+
+func (c *faultyClient) Delete(key string) error {
+	return c.DeleteCtx(context.Background(), key)
+}
+
+func (c *faultyClient) DeleteCtx(ctx context.Context, key string) error {
+	if err := c.inject(ctx, OpDelete, key); err != nil {
+		return err
+	}
+	return c.Client.DeleteCtx(ctx, key)
+}
+
+func (c *faultyClient) CheckAndDelete(key string, oldValue string) error {
+	if err := c.inject(context.Background(), OpCheckAndDelete, key); err != nil {
+		return err
+	}
+	return c.Client.CheckAndDelete(key, oldValue)
+}
+
+func (c *faultyClient) Create(key string, value string, ttl uint64) error {
+	if err := c.inject(context.Background(), OpCreate, key); err != nil {
+		return err
+	}
+	return c.Client.Create(key, value, ttl)
+}
+
+func (c *faultyClient) CreateInDir(dir string, value string, ttl uint64) error {
+	if err := c.inject(context.Background(), OpCreateInDir, dir); err != nil {
+		return err
+	}
+	return c.Client.CreateInDir(dir, value, ttl)
+}
+
+func (c *faultyClient) CheckAndSet(key string, value string, ttl uint64, oldValue string) error {
+	if err := c.inject(context.Background(), OpCheckAndSet, key); err != nil {
+		return err
+	}
+	return c.Client.CheckAndSet(key, value, ttl, oldValue)
+}
+
+// inject injects the faults configured for a call to op on key, a non-nil
+// error means the call must fail with it.
+func (c *faultyClient) inject(ctx context.Context, op Op, key string) error {
+	c.lock.Lock()
+	var err error
+	delay := c.delays[op]
+	if delay == 0 {
+		delay = c.delays[OpAny]
+	}
+	if time.Now().Before(c.partitionEnd) {
+		err = ErrPartitioned
+		c.unsafeRecord(Fault{Kind: FaultPartition, Op: op, Key: key, Err: err})
+	} else if failure := c.takeFailure(op); failure != nil {
+		failure.n--
+		err = failure.err
+		c.unsafeRecord(Fault{Kind: FaultError, Op: op, Key: key, Err: err})
+	} else if delay > 0 {
+		c.unsafeRecord(Fault{Kind: FaultDelay, Op: op, Key: key})
+	}
+	c.lock.Unlock()
+	if err != nil {
+		return err
+	}
+	if delay > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+	return nil
+}
+
+// takeFailure returns the injected failure which applies to op, if any.
+func (c *faultyClient) takeFailure(op Op) *injectedFailure {
+	for _, failureOp := range []Op{op, OpAny} {
+		if failure, ok := c.failures[failureOp]; ok && failure.n > 0 {
+			return failure
+		}
+	}
+	return nil
+}
+
+// notification injects the faults configured for a watch notification on
+// key, it returns false if the notification must be dropped.
+func (c *faultyClient) notification(ctx context.Context, key string) bool {
+	c.lock.Lock()
+	delay := c.delayNotifications
+	switch {
+	case time.Now().Before(c.partitionEnd):
+		c.unsafeRecord(Fault{Kind: FaultPartition, Op: OpWatch, Key: key})
+		c.lock.Unlock()
+		return false
+	case c.dropNotifications > 0:
+		c.dropNotifications--
+		c.unsafeRecord(Fault{Kind: FaultDropNotification, Op: OpWatch, Key: key})
+		c.lock.Unlock()
+		return false
+	}
+	if delay > 0 {
+		c.unsafeRecord(Fault{Kind: FaultDelayNotification, Op: OpWatch, Key: key})
+	}
+	c.lock.Unlock()
+	if delay > 0 {
+		select {
+		case <-ctx.Done():
+		case <-time.After(delay):
+		}
+	}
+	return true
+}
+
+func (c *faultyClient) unsafeRecord(fault Fault) {
+	c.faults = append(c.faults, fault)
+	if c.options.OnFault != nil {
+		c.options.OnFault(fault)
+	}
+}
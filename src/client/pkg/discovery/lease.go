@@ -0,0 +1,136 @@
+package discovery
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// ErrLeaseHeld is returned by AcquireLease when another holder has the
+// lease and LeaseOptions.Block isn't set.
+var ErrLeaseHeld = fmt.Errorf("pachyderm: lease is held by another holder")
+
+var errLeaseReleased = fmt.Errorf("pachyderm: lease released")
+
+// LeaseOptions configures AcquireLease.
+type LeaseOptions struct {
+	// Block makes AcquireLease wait until the lease is available, or ctx is
+	// done, rather than failing with ErrLeaseHeld.
+	Block bool
+	// RefreshInterval is how often the lease is refreshed, and how often a
+	// blocked AcquireLease retries if it doesn't see the lease released. The
+	// default is half of the TTL.
+	RefreshInterval time.Duration
+}
+
+// Lease is a named lease with a TTL, held by at most one holder at a time.
+// It's kept alive by refreshing it until it's released or lost.
+type Lease struct {
+	client   Client
+	name     string
+	holderID string
+	ttl      uint64
+	interval time.Duration
+	lost     chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+	once     sync.Once
+}
+
+// AcquireLease acquires the lease called name, which is a key in client, for
+// holderID, which must be unique among the processes competing for the
+// lease. ttl is in seconds, and is how long the lease outlives its holder
+// if the holder stops refreshing it without releasing it. The lease is
+// taken and refreshed with CheckAndSet, so two holders can never both
+// believe they hold it.
+func AcquireLease(ctx context.Context, client Client, name string, holderID string, ttl uint64, options LeaseOptions) (*Lease, error) {
+	interval := options.RefreshInterval
+	if interval <= 0 {
+		interval = time.Second * time.Duration(ttl) / 2
+	}
+	for {
+		err := client.CheckAndSet(name, holderID, ttl, "")
+		if err != nil {
+			// We may already hold it, for example if we restarted.
+			err = client.CheckAndSet(name, holderID, ttl, holderID)
+		}
+		if err == nil {
+			lease := &Lease{
+				client:   client,
+				name:     name,
+				holderID: holderID,
+				ttl:      ttl,
+				interval: interval,
+				lost:     make(chan struct{}),
+				stop:     make(chan struct{}),
+				done:     make(chan struct{}),
+			}
+			go lease.refresh()
+			return lease, nil
+		}
+		if !options.Block {
+			if holder, getErr := client.GetCtx(ctx, name); getErr == nil && holder != holderID {
+				return nil, ErrLeaseHeld
+			}
+			return nil, err
+		}
+		waitForRelease(ctx, client, name, interval)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// waitForRelease waits until the lease called name is released, or for
+// timeout, whichever comes first. Expiry isn't always reported by watches so
+// the timeout is what notices expired leases.
+func waitForRelease(ctx context.Context, client Client, name string, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	client.WatchCtx(ctx, name, func(value string) error {
+		if value == "" {
+			return errLeaseReleased
+		}
+		return nil
+	})
+}
+
+// Lost returns a channel which is closed if the lease is lost, because
+// refreshing it failed or another holder took it over. It isn't closed when
+// the lease is released.
+func (l *Lease) Lost() <-chan struct{} {
+	return l.lost
+}
+
+// Release stops refreshing the lease and gives it up, so that another holder
+// can acquire it without waiting for it to expire. Releasing a lease which
+// has been lost does nothing.
+func (l *Lease) Release() error {
+	l.once.Do(func() {
+		close(l.stop)
+	})
+	<-l.done
+	select {
+	case <-l.lost:
+		return nil
+	default:
+	}
+	return l.client.CheckAndDelete(l.name, l.holderID)
+}
+
+func (l *Lease) refresh() {
+	defer close(l.done)
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-time.After(l.interval):
+		}
+		if err := l.client.CheckAndSet(l.name, l.holderID, l.ttl, l.holderID); err != nil {
+			close(l.lost)
+			return
+		}
+	}
+}
@@ -8,23 +8,32 @@ import (
 	"time"
 
 	"github.com/pachyderm/pachyderm/src/client/pkg/uuid"
+	"golang.org/x/net/context"
 )
 
 type record struct {
-	directory bool
-	data      string
-	expires   time.Time
+	data    string
+	expires time.Time
 }
 
 type mockClient struct {
 	records map[string]record
-	lock    sync.RWMutex
+	lock    sync.Mutex
+	// changed is closed, and replaced, every time records is modified, it's
+	// how watches learn that they need to look at records again.
+	changed chan struct{}
+	// epoch is incremented to make every watch start over from a snapshot,
+	// as if the underlying watch had to be re-established.
+	epoch int
+	// clock decides when records with a ttl expire.
+	clock Clock
 }
 
-func newMockClient() *mockClient {
+func newMockClient(clock Clock) *mockClient {
 	return &mockClient{
-		make(map[string]record),
-		sync.RWMutex{},
+		records: make(map[string]record),
+		changed: make(chan struct{}),
+		clock:   clock,
 	}
 }
 
@@ -32,127 +41,321 @@ func (c *mockClient) Close() error {
 	return nil
 }
 
-func (c *mockClient) Get(key string) (string, bool, error) {
-	c.lock.RLock()
-	defer c.lock.RUnlock()
-	now := time.Now()
+func (c *mockClient) Get(key string) (string, error) {
+	return c.GetCtx(context.Background(), key)
+}
+
+func (c *mockClient) GetCtx(ctx context.Context, key string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.expire()
 	record, ok := c.records[key]
 	if !ok {
-		return "", false, nil
-	}
-	if record.directory {
-		return "", false, fmt.Errorf("pachyderm: key %s is directory", key)
-	}
-	if (record.expires != time.Time{}) && now.After(record.expires) {
-		delete(c.records, key)
-		return "", false, nil
+		return "", fmt.Errorf("pachyderm: key %s not found", key)
 	}
-	return record.data, true, nil
+	return record.data, nil
 }
 
-func (c *mockClient) GetAll(keyPrefix string) (map[string]string, error) {
-	c.lock.RLock()
-	defer c.lock.RUnlock()
-	now := time.Now()
-	result := make(map[string]string)
-	for key, record := range c.records {
-		if (record.expires != time.Time{}) && now.After(record.expires) {
-			delete(c.records, key)
-		}
-		if strings.HasPrefix(key, keyPrefix) && !record.directory {
-			result[key] = record.data
-		}
+func (c *mockClient) GetAll(key string) (map[string]string, error) {
+	return c.GetAllCtx(context.Background(), key)
+}
+
+func (c *mockClient) GetAllCtx(ctx context.Context, key string) (map[string]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
-	return result, nil
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.expire()
+	return c.unsafeGetAll(key), nil
 }
 
 func (c *mockClient) Watch(key string, cancel chan bool, callBack func(string) error) error {
-	// TODO jdoliner
-	return nil
+	ctx, done := cancelToContext(cancel)
+	defer done()
+	return contextToCancelErr(c.WatchCtx(ctx, key, callBack))
+}
+
+func (c *mockClient) WatchCtx(ctx context.Context, key string, callBack func(string) error) error {
+	var value string
+	first := true
+	return c.watch(ctx, func(bool) func() error {
+		record := c.records[key]
+		if !first && record.data == value {
+			return nil
+		}
+		first = false
+		value = record.data
+		return func() error { return callBack(record.data) }
+	})
 }
 
 func (c *mockClient) WatchAll(key string, cancel chan bool, callBack func(map[string]string) error) error {
-	// TODO jdoliner
-	return nil
+	ctx, done := cancelToContext(cancel)
+	defer done()
+	return contextToCancelErr(c.WatchAllCtx(ctx, key, callBack))
+}
+
+func (c *mockClient) WatchAllCtx(ctx context.Context, key string, callBack func(map[string]string) error) error {
+	var value map[string]string
+	return c.watch(ctx, func(bool) func() error {
+		newValue := c.unsafeGetAll(key)
+		if value != nil && sameMap(value, newValue) {
+			return nil
+		}
+		value = newValue
+		if len(newValue) == 0 {
+			// the etcd client reports a missing directory as nil
+			return func() error { return callBack(nil) }
+		}
+		return func() error { return callBack(copyMap(newValue)) }
+	})
+}
+
+func (c *mockClient) WatchAllDelta(key string, cancel chan bool, callBack func(*Delta) error) error {
+	ctx, done := cancelToContext(cancel)
+	defer done()
+	return contextToCancelErr(c.WatchAllDeltaCtx(ctx, key, callBack))
+}
+
+func (c *mockClient) WatchAllDeltaCtx(ctx context.Context, key string, callBack func(*Delta) error) error {
+	var value map[string]string
+	return c.watch(ctx, func(resumed bool) func() error {
+		newValue := c.unsafeGetAll(key)
+		if value == nil || resumed {
+			value = newValue
+			delta := &Delta{Snapshot: true, Updated: copyMap(newValue)}
+			return func() error { return callBack(delta) }
+		}
+		delta := &Delta{Updated: make(map[string]string)}
+		for key, data := range newValue {
+			if oldData, ok := value[key]; !ok || oldData != data {
+				delta.Updated[key] = data
+			}
+		}
+		for key := range value {
+			if _, ok := newValue[key]; !ok {
+				delta.Deleted = append(delta.Deleted, key)
+			}
+		}
+		value = newValue
+		if len(delta.Updated) == 0 && len(delta.Deleted) == 0 {
+			return nil
+		}
+		return func() error { return callBack(delta) }
+	})
 }
 
 func (c *mockClient) Set(key string, value string, ttl uint64) error {
+	return c.SetCtx(context.Background(), key, value, ttl)
+}
+
+func (c *mockClient) SetCtx(ctx context.Context, key string, value string, ttl uint64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	c.lock.Lock()
 	defer c.lock.Unlock()
-	return c.unsafeSet(key, value, ttl)
+	c.unsafeSet(key, value, ttl)
+	return nil
+}
+
+func (c *mockClient) SetMulti(kvs map[string]string, ttl uint64) error {
+	return c.SetMultiCtx(context.Background(), kvs, ttl)
+}
+
+// SetMultiCtx sets all of the keys atomically.
+func (c *mockClient) SetMultiCtx(ctx context.Context, kvs map[string]string, ttl uint64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for key, value := range kvs {
+		c.unsafeSet(key, value, ttl)
+	}
+	return nil
 }
 
 func (c *mockClient) Create(key string, value string, ttl uint64) error {
 	c.lock.Lock()
 	defer c.lock.Unlock()
-	_, ok := c.records[key]
-	if ok {
+	c.expire()
+	if _, ok := c.records[key]; ok {
 		return fmt.Errorf("pachyderm: key %s already exists", key)
 	}
-	return c.unsafeSet(key, value, ttl)
+	c.unsafeSet(key, value, ttl)
+	return nil
 }
 
 func (c *mockClient) CreateInDir(dir string, value string, ttl uint64) error {
 	c.lock.Lock()
 	defer c.lock.Unlock()
-	key := path.Join(dir, uuid.NewWithoutDashes())
-	return c.unsafeSet(key, value, ttl)
+	c.unsafeSet(path.Join(dir, uuid.NewWithoutDashes()), value, ttl)
+	return nil
 }
 
 func (c *mockClient) Delete(key string) error {
+	return c.DeleteCtx(context.Background(), key)
+}
+
+func (c *mockClient) DeleteCtx(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	c.lock.Lock()
 	defer c.lock.Unlock()
+	if _, ok := c.records[key]; !ok {
+		return fmt.Errorf("pachyderm: key %s not found", key)
+	}
+	delete(c.records, key)
+	c.notify()
+	return nil
+}
+
+func (c *mockClient) CheckAndDelete(key string, oldValue string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.expire()
 	oldRecord, ok := c.records[key]
 	if !ok {
-		return nil
+		return fmt.Errorf("pachyderm: key %s not found", key)
 	}
-	if oldRecord.directory {
-		return fmt.Errorf("pachyderm: can't delete directory %s", key)
+	if oldRecord.data != oldValue {
+		return fmt.Errorf("pachyderm: precondition not met for %s", key)
 	}
 	delete(c.records, key)
+	c.notify()
 	return nil
 }
 
 func (c *mockClient) CheckAndSet(key string, value string, ttl uint64, oldValue string) error {
 	c.lock.Lock()
 	defer c.lock.Unlock()
+	c.expire()
 	oldRecord, ok := c.records[key]
-	if !ok {
-		return fmt.Errorf("pachyderm: key %s not found", key)
-	}
-	if oldRecord.directory {
-		return fmt.Errorf("pachyderm: can't set directory %s", key)
+	// Like the etcd client, an empty oldValue means the key must not exist.
+	if oldValue == "" && ok {
+		return fmt.Errorf("pachyderm: key %s already exists", key)
 	}
-	if oldRecord.data != oldValue {
+	if oldValue != "" && (!ok || oldRecord.data != oldValue) {
 		return fmt.Errorf("pachyderm: precondition not met for %s", key)
 	}
-	return c.unsafeSet(key, value, ttl)
+	c.unsafeSet(key, value, ttl)
+	return nil
+}
+
+// resumeWatches makes every running watch behave as though it had been
+// re-established, WatchAllDelta will deliver a fresh snapshot.
+func (c *mockClient) resumeWatches() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.epoch++
+	c.notify()
+}
+
+// watch calls f with c.lock held, first immediately and then every time
+// records changes, until ctx is done. resumed is true if resumeWatches
+// was called since the last call to f. f returns the callback to run, once
+// the lock has been released, or nil if there's nothing to report.
+func (c *mockClient) watch(ctx context.Context, f func(resumed bool) func() error) error {
+	epoch := -1
+	for {
+		c.lock.Lock()
+		c.expire()
+		resumed := epoch != -1 && epoch != c.epoch
+		epoch = c.epoch
+		changed := c.changed
+		nextExpiry := c.nextExpiry()
+		callBack := f(resumed)
+		c.lock.Unlock()
+		if callBack != nil {
+			if err := callBack(); err != nil {
+				return err
+			}
+		}
+		var expiry <-chan time.Time
+		if !nextExpiry.IsZero() {
+			expiry = c.clock.After(nextExpiry.Sub(c.clock.Now()))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		case <-expiry:
+		}
+	}
+}
+
+func (c *mockClient) unsafeGetAll(key string) map[string]string {
+	result := make(map[string]string)
+	for recordKey, record := range c.records {
+		if key == "" || recordKey == key || strings.HasPrefix(recordKey, key+"/") {
+			result[recordKey] = record.data
+		}
+	}
+	return result
 }
 
-func (c *mockClient) unsafeSet(key string, value string, ttl uint64) error {
+func (c *mockClient) unsafeSet(key string, value string, ttl uint64) {
 	var expires time.Time
 	if ttl != 0 {
-		expires = time.Now().Add(time.Second * time.Duration(ttl))
+		expires = c.clock.Now().Add(time.Second * time.Duration(ttl))
 	}
-	parts := strings.Split(key, "/")
-	for i := range parts[:len(parts)-1] {
-		oldRecord, ok := c.records["/"+path.Join(parts[:i]...)]
-		if ok && !oldRecord.directory {
-			return fmt.Errorf("pachyderm: key %s not found", key)
+	c.records[key] = record{value, expires}
+	c.notify()
+}
+
+// expire removes expired records, it must be called with c.lock held.
+func (c *mockClient) expire() {
+	now := c.clock.Now()
+	expired := false
+	for key, record := range c.records {
+		if !record.expires.IsZero() && !now.Before(record.expires) {
+			delete(c.records, key)
+			expired = true
 		}
-		if !ok {
-			c.records["/"+path.Join(parts[:i]...)] = record{true, "", expires}
+	}
+	if expired {
+		c.notify()
+	}
+}
+
+func (c *mockClient) nextExpiry() time.Time {
+	var result time.Time
+	for _, record := range c.records {
+		if !record.expires.IsZero() && (result.IsZero() || record.expires.Before(result)) {
+			result = record.expires
 		}
 	}
-	oldRecord, ok := c.records[key]
-	if ok && oldRecord.directory {
-		return fmt.Errorf("pachyderm: can't set directory %s", key)
+	return result
+}
+
+// notify wakes up all watches, it must be called with c.lock held.
+func (c *mockClient) notify() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+func sameMap(a map[string]string, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
 	}
-	c.records[key] = record{false, value, expires}
-	return nil
+	for key, value := range a {
+		if bValue, ok := b[key]; !ok || bValue != value {
+			return false
+		}
+	}
+	return true
 }
 
-func (c *mockClient) Hold(key string, value string, oldValue string, cancel chan bool) error {
-	// TODO jdoliner
-	return nil
+func copyMap(m map[string]string) map[string]string {
+	result := make(map[string]string, len(m))
+	for key, value := range m {
+		result[key] = value
+	}
+	return result
 }
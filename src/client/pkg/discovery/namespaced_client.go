@@ -0,0 +1,217 @@
+package discovery
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"golang.org/x/net/context"
+)
+
+type namespacedClient struct {
+	client Client
+	prefix string
+}
+
+func newNamespacedClient(client Client, prefix string) *namespacedClient {
+	cleanPrefix := strings.Trim(path.Clean("/"+prefix), "/")
+	if cleanPrefix == "" || cleanPrefix != strings.Trim(prefix, "/") {
+		panic(fmt.Sprintf("pachyderm: invalid discovery prefix %q", prefix))
+	}
+	return &namespacedClient{client, cleanPrefix}
+}
+
+func (c *namespacedClient) Close() error {
+	return c.client.Close()
+}
+
+func (c *namespacedClient) Get(key string) (string, error) {
+	return c.GetCtx(context.Background(), key)
+}
+
+func (c *namespacedClient) GetCtx(ctx context.Context, key string) (string, error) {
+	key, err := c.key(key)
+	if err != nil {
+		return "", err
+	}
+	return c.client.GetCtx(ctx, key)
+}
+
+func (c *namespacedClient) GetAll(key string) (map[string]string, error) {
+	return c.GetAllCtx(context.Background(), key)
+}
+
+func (c *namespacedClient) GetAllCtx(ctx context.Context, key string) (map[string]string, error) {
+	key, err := c.key(key)
+	if err != nil {
+		return nil, err
+	}
+	result, err := c.client.GetAllCtx(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return c.stripMap(result), nil
+}
+
+func (c *namespacedClient) Watch(key string, cancel chan bool, callBack func(string) error) error {
+	ctx, done := cancelToContext(cancel)
+	defer done()
+	return contextToCancelErr(c.WatchCtx(ctx, key, callBack))
+}
+
+func (c *namespacedClient) WatchCtx(ctx context.Context, key string, callBack func(string) error) error {
+	key, err := c.key(key)
+	if err != nil {
+		return err
+	}
+	return c.client.WatchCtx(ctx, key, callBack)
+}
+
+func (c *namespacedClient) WatchAll(key string, cancel chan bool, callBack func(map[string]string) error) error {
+	ctx, done := cancelToContext(cancel)
+	defer done()
+	return contextToCancelErr(c.WatchAllCtx(ctx, key, callBack))
+}
+
+func (c *namespacedClient) WatchAllCtx(ctx context.Context, key string, callBack func(map[string]string) error) error {
+	key, err := c.key(key)
+	if err != nil {
+		return err
+	}
+	return c.client.WatchAllCtx(ctx, key, func(value map[string]string) error {
+		if value == nil {
+			return callBack(nil)
+		}
+		return callBack(c.stripMap(value))
+	})
+}
+
+func (c *namespacedClient) WatchAllDelta(key string, cancel chan bool, callBack func(*Delta) error) error {
+	ctx, done := cancelToContext(cancel)
+	defer done()
+	return contextToCancelErr(c.WatchAllDeltaCtx(ctx, key, callBack))
+}
+
+func (c *namespacedClient) WatchAllDeltaCtx(ctx context.Context, key string, callBack func(*Delta) error) error {
+	key, err := c.key(key)
+	if err != nil {
+		return err
+	}
+	return c.client.WatchAllDeltaCtx(ctx, key, func(delta *Delta) error {
+		result := &Delta{
+			Snapshot: delta.Snapshot,
+			Updated:  c.stripMap(delta.Updated),
+		}
+		for _, key := range delta.Deleted {
+			result.Deleted = append(result.Deleted, c.strip(key))
+		}
+		return callBack(result)
+	})
+}
+
+func (c *namespacedClient) Set(key string, value string, ttl uint64) error {
+	return c.SetCtx(context.Background(), key, value, ttl)
+}
+
+func (c *namespacedClient) SetCtx(ctx context.Context, key string, value string, ttl uint64) error {
+	key, err := c.key(key)
+	if err != nil {
+		return err
+	}
+	return c.client.SetCtx(ctx, key, value, ttl)
+}
+
+func (c *namespacedClient) SetMulti(kvs map[string]string, ttl uint64) error {
+	return c.SetMultiCtx(context.Background(), kvs, ttl)
+}
+
+func (c *namespacedClient) SetMultiCtx(ctx context.Context, kvs map[string]string, ttl uint64) error {
+	prefixedKVs := make(map[string]string, len(kvs))
+	for key, value := range kvs {
+		prefixedKey, err := c.key(key)
+		if err != nil {
+			return err
+		}
+		prefixedKVs[prefixedKey] = value
+	}
+	err := c.client.SetMultiCtx(ctx, prefixedKVs, ttl)
+	if setMultiErr, ok := err.(*SetMultiError); ok {
+		errs := make(map[string]error, len(setMultiErr.Errors))
+		for key, err := range setMultiErr.Errors {
+			errs[c.strip(key)] = err
+		}
+		return &SetMultiError{errs}
+	}
+	return err
+}
+
+func (c *namespacedClient) Delete(key string) error {
+	return c.DeleteCtx(context.Background(), key)
+}
+
+func (c *namespacedClient) DeleteCtx(ctx context.Context, key string) error {
+	key, err := c.key(key)
+	if err != nil {
+		return err
+	}
+	return c.client.DeleteCtx(ctx, key)
+}
+
+func (c *namespacedClient) CheckAndDelete(key string, oldValue string) error {
+	key, err := c.key(key)
+	if err != nil {
+		return err
+	}
+	return c.client.CheckAndDelete(key, oldValue)
+}
+
+func (c *namespacedClient) Create(key string, value string, ttl uint64) error {
+	key, err := c.key(key)
+	if err != nil {
+		return err
+	}
+	return c.client.Create(key, value, ttl)
+}
+
+func (c *namespacedClient) CreateInDir(dir string, value string, ttl uint64) error {
+	dir, err := c.key(dir)
+	if err != nil {
+		return err
+	}
+	return c.client.CreateInDir(dir, value, ttl)
+}
+
+func (c *namespacedClient) CheckAndSet(key string, value string, ttl uint64, oldValue string) error {
+	key, err := c.key(key)
+	if err != nil {
+		return err
+	}
+	return c.client.CheckAndSet(key, value, ttl, oldValue)
+}
+
+// key returns the absolute key for key, it returns an error if key would
+// escape the prefix.
+func (c *namespacedClient) key(key string) (string, error) {
+	result := path.Join(c.prefix, key)
+	if result != c.prefix && !strings.HasPrefix(result, c.prefix+"/") {
+		return "", fmt.Errorf("pachyderm: key %s escapes namespace %s", key, c.prefix)
+	}
+	return result, nil
+}
+
+// strip returns the key, relative to the prefix, for an absolute key.
+func (c *namespacedClient) strip(key string) string {
+	key = strings.TrimPrefix(key, "/")
+	if key == c.prefix {
+		return ""
+	}
+	return strings.TrimPrefix(key, c.prefix+"/")
+}
+
+func (c *namespacedClient) stripMap(m map[string]string) map[string]string {
+	result := make(map[string]string, len(m))
+	for key, value := range m {
+		result[c.strip(key)] = value
+	}
+	return result
+}
@@ -0,0 +1,213 @@
+package discovery
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.pedge.io/pkg/time"
+	"golang.org/x/net/context"
+)
+
+// ErrRateLimited is returned by a Client returned by NewRateLimitedClient for
+// writes beyond its budget.
+var ErrRateLimited = fmt.Errorf("pachyderm: discovery write rate limited")
+
+// Op identifies a kind of call to a Client.
+type Op string
+
+const (
+	OpSet            Op = "Set"
+	OpSetMulti       Op = "SetMulti"
+	OpDelete         Op = "Delete"
+	OpCheckAndDelete Op = "CheckAndDelete"
+	OpCreate         Op = "Create"
+	OpCreateInDir    Op = "CreateInDir"
+	OpCheckAndSet    Op = "CheckAndSet"
+)
+
+// RateLimit is a token bucket.
+type RateLimit struct {
+	// Rate is how many writes per second are allowed on average.
+	Rate float64
+	// Burst is how many writes are allowed at once, values less than 1 are
+	// treated as 1.
+	Burst int
+}
+
+// RateLimitPolicy controls how a Client returned by NewRateLimitedClient
+// limits writes. Reads and watches are never limited.
+type RateLimitPolicy struct {
+	// Limits maps each kind of write to its limit, kinds without a limit
+	// aren't limited.
+	Limits map[Op]RateLimit
+	// PerKey applies the limits to every key separately, rather than to all
+	// writes made through the client. SetMulti uses a token for each key.
+	PerKey bool
+	// Block makes writes beyond the budget wait for a token, or for their
+	// context to be done, rather than failing with ErrRateLimited.
+	Block bool
+	// OnThrottle, if set, is called every time a write is delayed or
+	// rejected, it can be used to count them.
+	OnThrottle func(op Op, key string)
+	// Timer is the limiter's clock, if nil the system time is used.
+	Timer pkgtime.Timer
+}
+
+// NewRateLimitedClient returns a Client which limits the rate of client's
+// writes according to policy.
+func NewRateLimitedClient(client Client, policy RateLimitPolicy) Client {
+	if policy.Timer == nil {
+		policy.Timer = pkgtime.NewSystemTimer()
+	}
+	return &rateLimitedClient{
+		Client:  client,
+		policy:  policy,
+		buckets: make(map[bucketKey]*tokenBucket),
+	}
+}
+
+type bucketKey struct {
+	op  Op
+	key string
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type rateLimitedClient struct {
+	Client
+	policy  RateLimitPolicy
+	buckets map[bucketKey]*tokenBucket
+	lock    sync.Mutex
+}
+
+func (c *rateLimitedClient) Set(key string, value string, ttl uint64) error {
+	return c.SetCtx(context.Background(), key, value, ttl)
+}
+
+func (c *rateLimitedClient) SetCtx(ctx context.Context, key string, value string, ttl uint64) error {
+	if err := c.wait(ctx, OpSet, key); err != nil {
+		return err
+	}
+	return c.Client.SetCtx(ctx, key, value, ttl)
+}
+
+func (c *rateLimitedClient) SetMulti(kvs map[string]string, ttl uint64) error {
+	return c.SetMultiCtx(context.Background(), kvs, ttl)
+}
+
+func (c *rateLimitedClient) SetMultiCtx(ctx context.Context, kvs map[string]string, ttl uint64) error {
+	if c.policy.PerKey {
+		for key := range kvs {
+			if err := c.wait(ctx, OpSetMulti, key); err != nil {
+				return err
+			}
+		}
+	} else if err := c.wait(ctx, OpSetMulti, ""); err != nil {
+		return err
+	}
+	return c.Client.SetMultiCtx(ctx, kvs, ttl)
+}
+
+func (c *rateLimitedClient) Delete(key string) error {
+	return c.DeleteCtx(context.Background(), key)
+}
+
+func (c *rateLimitedClient) DeleteCtx(ctx context.Context, key string) error {
+	if err := c.wait(ctx, OpDelete, key); err != nil {
+		return err
+	}
+	return c.Client.DeleteCtx(ctx, key)
+}
+
+func (c *rateLimitedClient) CheckAndDelete(key string, oldValue string) error {
+	if err := c.wait(context.Background(), OpCheckAndDelete, key); err != nil {
+		return err
+	}
+	return c.Client.CheckAndDelete(key, oldValue)
+}
+
+func (c *rateLimitedClient) Create(key string, value string, ttl uint64) error {
+	if err := c.wait(context.Background(), OpCreate, key); err != nil {
+		return err
+	}
+	return c.Client.Create(key, value, ttl)
+}
+
+func (c *rateLimitedClient) CreateInDir(dir string, value string, ttl uint64) error {
+	if err := c.wait(context.Background(), OpCreateInDir, dir); err != nil {
+		return err
+	}
+	return c.Client.CreateInDir(dir, value, ttl)
+}
+
+func (c *rateLimitedClient) CheckAndSet(key string, value string, ttl uint64, oldValue string) error {
+	if err := c.wait(context.Background(), OpCheckAndSet, key); err != nil {
+		return err
+	}
+	return c.Client.CheckAndSet(key, value, ttl, oldValue)
+}
+
+// wait takes a token for op on key, waiting for one if the policy blocks.
+func (c *rateLimitedClient) wait(ctx context.Context, op Op, key string) error {
+	for {
+		delay, ok := c.take(op, key)
+		if ok {
+			return nil
+		}
+		if c.policy.OnThrottle != nil {
+			c.policy.OnThrottle(op, key)
+		}
+		if !c.policy.Block {
+			return ErrRateLimited
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// take takes a token for op on key, if there isn't one it returns how long
+// until there will be.
+func (c *rateLimitedClient) take(op Op, key string) (time.Duration, bool) {
+	limit, ok := c.policy.Limits[op]
+	if !ok {
+		return 0, true
+	}
+	burst := float64(limit.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	if !c.policy.PerKey {
+		key = ""
+	}
+	now := c.policy.Timer.Now()
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	bucket, ok := c.buckets[bucketKey{op, key}]
+	if !ok {
+		bucket = &tokenBucket{tokens: burst, last: now}
+		c.buckets[bucketKey{op, key}] = bucket
+	}
+	if elapsed := now.Sub(bucket.last); elapsed > 0 {
+		bucket.tokens += elapsed.Seconds() * limit.Rate
+		if bucket.tokens > burst {
+			bucket.tokens = burst
+		}
+		bucket.last = now
+	}
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return 0, true
+	}
+	if limit.Rate <= 0 {
+		// The bucket never refills
+		return time.Second, false
+	}
+	return time.Duration((1 - bucket.tokens) / limit.Rate * float64(time.Second)), false
+}
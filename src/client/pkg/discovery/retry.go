@@ -0,0 +1,139 @@
+package discovery
+
+import (
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/coreos/go-etcd/etcd"
+	"golang.org/x/net/context"
+)
+
+// RetryPolicy controls how a Client returned by NewRetryClient retries Get,
+// GetAll, Set and Delete after a transient failure. Watches aren't retried
+// by the policy, they resume on their own.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts made, values less than 2
+	// mean calls are only attempted once.
+	MaxAttempts int
+	// BackoffBase is the delay before the first retry, it doubles with every
+	// subsequent retry.
+	BackoffBase time.Duration
+	// BackoffCap is the maximum delay between attempts, 0 means no maximum.
+	BackoffCap time.Duration
+	// Jitter is the fraction, between 0 and 1, of each delay that's
+	// randomized so that clients don't retry in lockstep.
+	Jitter float64
+	// Retryable reports whether an error is transient, if nil
+	// DefaultRetryable is used.
+	Retryable func(error) bool
+	// OnRetry, if set, is called before every retry with the number of the
+	// attempt that failed and its error, it can be used to count retries.
+	OnRetry func(attempt int, err error)
+}
+
+// DefaultRetryPolicy attempts each call once, which is how clients behave
+// unless they're configured otherwise.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 1}
+
+// DefaultRetryable returns true for errors that indicate that etcd is
+// unreachable or busy electing a leader.
+func DefaultRetryable(err error) bool {
+	if etcdErr, ok := err.(*etcd.EtcdError); ok {
+		switch etcdErr.ErrorCode {
+		case 300, 301, etcd.ErrCodeEtcdNotReachable:
+			return true
+		}
+		return false
+	}
+	_, ok := err.(net.Error)
+	return ok
+}
+
+// NewRetryClient returns a Client which retries client's Get, GetAll, Set
+// and Delete calls according to policy.
+func NewRetryClient(client Client, policy RetryPolicy) Client {
+	if policy.Retryable == nil {
+		policy.Retryable = DefaultRetryable
+	}
+	return &retryClient{client, policy}
+}
+
+type retryClient struct {
+	Client
+	policy RetryPolicy
+}
+
+func (c *retryClient) Get(key string) (string, error) {
+	return c.GetCtx(context.Background(), key)
+}
+
+func (c *retryClient) GetCtx(ctx context.Context, key string) (string, error) {
+	var result string
+	err := c.retry(ctx, func() error {
+		var err error
+		result, err = c.Client.GetCtx(ctx, key)
+		return err
+	})
+	return result, err
+}
+
+func (c *retryClient) GetAll(key string) (map[string]string, error) {
+	return c.GetAllCtx(context.Background(), key)
+}
+
+func (c *retryClient) GetAllCtx(ctx context.Context, key string) (map[string]string, error) {
+	var result map[string]string
+	err := c.retry(ctx, func() error {
+		var err error
+		result, err = c.Client.GetAllCtx(ctx, key)
+		return err
+	})
+	return result, err
+}
+
+func (c *retryClient) Set(key string, value string, ttl uint64) error {
+	return c.SetCtx(context.Background(), key, value, ttl)
+}
+
+func (c *retryClient) SetCtx(ctx context.Context, key string, value string, ttl uint64) error {
+	return c.retry(ctx, func() error {
+		return c.Client.SetCtx(ctx, key, value, ttl)
+	})
+}
+
+func (c *retryClient) Delete(key string) error {
+	return c.DeleteCtx(context.Background(), key)
+}
+
+func (c *retryClient) DeleteCtx(ctx context.Context, key string) error {
+	return c.retry(ctx, func() error {
+		return c.Client.DeleteCtx(ctx, key)
+	})
+}
+
+func (c *retryClient) retry(ctx context.Context, f func() error) error {
+	backoff := c.policy.BackoffBase
+	for attempt := 1; ; attempt++ {
+		err := f()
+		if err == nil || attempt >= c.policy.MaxAttempts || !c.policy.Retryable(err) {
+			return err
+		}
+		if c.policy.OnRetry != nil {
+			c.policy.OnRetry(attempt, err)
+		}
+		delay := backoff
+		if c.policy.Jitter > 0 {
+			delay -= time.Duration(c.policy.Jitter * rand.Float64() * float64(delay))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		backoff *= 2
+		if c.policy.BackoffCap != 0 && backoff > c.policy.BackoffCap {
+			backoff = c.policy.BackoffCap
+		}
+	}
+}
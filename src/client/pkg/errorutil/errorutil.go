@@ -0,0 +1,203 @@
+package errorutil
+
+import (
+	"fmt"
+	"strings"
+	"syscall"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// Code classifies an Error.
+type Code int
+
+const (
+	// Internal is for errors that don't fit any other code.
+	Internal Code = iota
+	// NotFound means the thing being looked up doesn't exist.
+	NotFound
+	// Unavailable means the operation might succeed if it's retried later.
+	Unavailable
+	// Conflict means the operation conflicts with the current state.
+	Conflict
+	// Cancelled means the operation was cancelled by the caller.
+	Cancelled
+)
+
+var codeToString = map[Code]string{
+	Internal:    "Internal",
+	NotFound:    "NotFound",
+	Unavailable: "Unavailable",
+	Conflict:    "Conflict",
+	Cancelled:   "Cancelled",
+}
+
+func (c Code) String() string {
+	if s, ok := codeToString[c]; ok {
+		return s
+	}
+	return fmt.Sprintf("Code(%d)", int(c))
+}
+
+// Error is an error with a Code, an optional underlying Cause and fields
+// describing what it refers to. Only the fields that are set are reported.
+type Error struct {
+	Code    Code
+	Message string
+	Cause   error
+	Shard   *uint64
+	Version *int64
+	Repo    string
+	Commit  string
+	Path    string
+}
+
+// New returns a new Error.
+func New(code Code, format string, args ...interface{}) *Error {
+	return &Error{
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// Wrap returns a new Error caused by cause, its Code is the Code of cause.
+func Wrap(cause error, format string, args ...interface{}) *Error {
+	return &Error{
+		Code:    CodeOf(cause),
+		Message: fmt.Sprintf(format, args...),
+		Cause:   cause,
+	}
+}
+
+// WithShard sets e's shard and returns e.
+func (e *Error) WithShard(shard uint64) *Error {
+	e.Shard = &shard
+	return e
+}
+
+// WithVersion sets e's version and returns e.
+func (e *Error) WithVersion(version int64) *Error {
+	e.Version = &version
+	return e
+}
+
+// WithFile sets e's repo, commit and path and returns e.
+func (e *Error) WithFile(repo string, commit string, path string) *Error {
+	e.Repo = repo
+	e.Commit = commit
+	e.Path = path
+	return e
+}
+
+// Unwrap returns e's Cause, so that errors.Is and errors.As look through
+// e.
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+func (e *Error) Error() string {
+	var fields []string
+	if e.Shard != nil {
+		fields = append(fields, fmt.Sprintf("shard=%d", *e.Shard))
+	}
+	if e.Version != nil {
+		fields = append(fields, fmt.Sprintf("version=%d", *e.Version))
+	}
+	if e.Repo != "" {
+		fields = append(fields, fmt.Sprintf("repo=%s", e.Repo))
+	}
+	if e.Commit != "" {
+		fields = append(fields, fmt.Sprintf("commit=%s", e.Commit))
+	}
+	if e.Path != "" {
+		fields = append(fields, fmt.Sprintf("path=%s", e.Path))
+	}
+	result := e.Message
+	if len(fields) > 0 {
+		result = fmt.Sprintf("%s (%s)", result, strings.Join(fields, " "))
+	}
+	if e.Cause != nil {
+		result = fmt.Sprintf("%s: %s", result, e.Cause.Error())
+	}
+	return result
+}
+
+// CodeOf returns the Code of err, errors that aren't an *Error are
+// classified by their gRPC code, or as Cancelled if they're a context
+// error.
+func CodeOf(err error) Code {
+	switch err := err.(type) {
+	case *Error:
+		return err.Code
+	}
+	switch err {
+	case context.Canceled, context.DeadlineExceeded:
+		return Cancelled
+	}
+	switch grpc.Code(err) {
+	case codes.NotFound:
+		return NotFound
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+		return Unavailable
+	case codes.AlreadyExists, codes.FailedPrecondition, codes.Aborted:
+		return Conflict
+	case codes.Canceled:
+		return Cancelled
+	}
+	return Internal
+}
+
+// Is returns true if err has Code code.
+func Is(err error, code Code) bool {
+	return err != nil && CodeOf(err) == code
+}
+
+// GRPCCode returns the gRPC code for code.
+func GRPCCode(code Code) codes.Code {
+	switch code {
+	case NotFound:
+		return codes.NotFound
+	case Unavailable:
+		return codes.Unavailable
+	case Conflict:
+		return codes.FailedPrecondition
+	case Cancelled:
+		return codes.Canceled
+	}
+	return codes.Internal
+}
+
+// ToGRPC returns a gRPC error with the same message as err and the gRPC code
+// for err's Code.
+func ToGRPC(err error) error {
+	if err == nil {
+		return nil
+	}
+	return grpc.Errorf(GRPCCode(CodeOf(err)), "%s", err.Error())
+}
+
+// Errno returns the errno for code, this is what a fuse filesystem should
+// return to the kernel.
+func Errno(code Code) syscall.Errno {
+	switch code {
+	case NotFound:
+		return syscall.ENOENT
+	case Unavailable:
+		return syscall.EAGAIN
+	case Conflict:
+		return syscall.EEXIST
+	case Cancelled:
+		return syscall.EINTR
+	}
+	return syscall.EIO
+}
+
+// String returns err.Error(), or "" if err is nil.
+func String(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
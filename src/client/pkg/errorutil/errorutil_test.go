@@ -0,0 +1,41 @@
+package errorutil
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func TestError(t *testing.T) {
+	err := New(NotFound, "no server found").WithShard(0).WithVersion(3)
+	require.Equal(t, "no server found (shard=0 version=3)", err.Error())
+	require.True(t, Is(err, NotFound))
+	require.Equal(t, codes.NotFound, grpc.Code(ToGRPC(err)))
+	require.Equal(t, syscall.ENOENT, Errno(err.Code))
+
+	wrapped := Wrap(grpc.Errorf(codes.Unavailable, "try again"), "InspectFile failed").WithFile("repo", "commit", "/file")
+	require.Equal(t, Unavailable, wrapped.Code)
+	require.True(t, strings.HasPrefix(wrapped.Error(), "InspectFile failed (repo=repo commit=commit path=/file): "))
+
+	require.Equal(t, Cancelled, CodeOf(context.Canceled))
+	require.Equal(t, Internal, CodeOf(fmt.Errorf("unknown")))
+	require.False(t, Is(nil, Internal))
+	require.Equal(t, "", String(nil))
+}
+
+func TestUnwrap(t *testing.T) {
+	err := fmt.Errorf("listing files: %w", Wrap(context.DeadlineExceeded, "no servers").WithShard(2))
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+	var codedErr *Error
+	require.True(t, errors.As(err, &codedErr))
+	require.Equal(t, Cancelled, codedErr.Code)
+	require.Equal(t, uint64(2), *codedErr.Shard)
+	require.Equal(t, nil, New(NotFound, "no cause").Unwrap())
+}
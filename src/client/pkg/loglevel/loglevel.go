@@ -0,0 +1,79 @@
+package loglevel
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"go.pedge.io/lion"
+)
+
+// EnvVar is the environment variable which sets the initial level.
+const EnvVar = "PACH_LOG_LEVEL"
+
+// Path is the path at which debugutil.Serve serves Handler.
+const Path = "/debug/loglevel"
+
+// level mirrors lion's global level, it's read on hot paths so it's kept
+// in an int32 rather than behind lion's lock.
+var level = int32(lion.DefaultLevel)
+
+func init() {
+	lion.AddGlobalHook(func(logger lion.Logger) {
+		atomic.StoreInt32(&level, int32(logger.Level()))
+	})
+	if name := os.Getenv(EnvVar); name != "" {
+		newLevel, err := lion.NameToLevel(strings.ToUpper(name))
+		if err != nil {
+			lion.Errorf("ignoring %s: %s", EnvVar, err.Error())
+			return
+		}
+		Set(newLevel)
+	}
+}
+
+// Get returns the current level.
+func Get() lion.Level {
+	return lion.Level(atomic.LoadInt32(&level))
+}
+
+// Set sets the level of lion's global logger, and so of protolion.
+func Set(newLevel lion.Level) {
+	lion.SetLevel(newLevel)
+}
+
+// DebugEnabled returns true if debug events are logged, callers should
+// check it before constructing an event that's only logged at debug level.
+func DebugEnabled() bool {
+	return Get() <= lion.LevelDebug
+}
+
+// Handler returns an http.Handler which reports the level for GET requests
+// and sets it to the level named in the body of PUT requests.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET", "HEAD":
+		case "PUT":
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			newLevel, err := lion.NameToLevel(strings.ToUpper(strings.TrimSpace(string(body))))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			Set(newLevel)
+		default:
+			w.Header().Set("Allow", "GET, HEAD, PUT")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		fmt.Fprintln(w, Get().String())
+	})
+}
@@ -0,0 +1,43 @@
+package loglevel
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"go.pedge.io/lion"
+)
+
+func TestHandler(t *testing.T) {
+	defer Set(Get())
+	server := httptest.NewServer(Handler())
+	defer server.Close()
+
+	request, err := http.NewRequest("PUT", server.URL, strings.NewReader("debug\n"))
+	require.NoError(t, err)
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	response.Body.Close()
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	require.True(t, DebugEnabled())
+
+	// Setting lion's level directly is reflected too
+	lion.SetLevel(lion.LevelWarn)
+	require.False(t, DebugEnabled())
+	response, err = http.Get(server.URL)
+	require.NoError(t, err)
+	body, err := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	require.NoError(t, err)
+	require.Equal(t, "WARN\n", string(body))
+
+	request, err = http.NewRequest("PUT", server.URL, strings.NewReader("loud"))
+	require.NoError(t, err)
+	response, err = http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	response.Body.Close()
+	require.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
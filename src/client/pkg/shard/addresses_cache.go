@@ -0,0 +1,86 @@
+package shard
+
+import (
+	"container/list"
+)
+
+// defaultAddressCacheSize is how many versions of the addresses a sharder
+// caches by default, frontends only use the latest couple.
+const defaultAddressCacheSize = 16
+
+// addressesCache is a least recently used cache of Addresses by version. It
+// isn't safe for concurrent use.
+type addressesCache struct {
+	size    int
+	entries map[int64]*list.Element
+	// order holds the cached versions, most recently used first
+	order     *list.List
+	evictions uint64
+}
+
+type addressesCacheEntry struct {
+	version   int64
+	addresses *Addresses
+}
+
+// newAddressesCache returns a cache holding at most size versions, values
+// less than 1 are treated as 1.
+func newAddressesCache(size int) *addressesCache {
+	if size < 1 {
+		size = 1
+	}
+	return &addressesCache{
+		size:    size,
+		entries: make(map[int64]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (c *addressesCache) get(version int64) (*Addresses, bool) {
+	element, ok := c.entries[version]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*addressesCacheEntry).addresses, true
+}
+
+// add caches addresses under version, evicting the least recently used
+// version if the cache is full.
+func (c *addressesCache) add(version int64, addresses *Addresses) {
+	if element, ok := c.entries[version]; ok {
+		element.Value.(*addressesCacheEntry).addresses = addresses
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[version] = c.order.PushFront(&addressesCacheEntry{version, addresses})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*addressesCacheEntry).version)
+		c.evictions++
+	}
+}
+
+// removeBefore removes the versions before version, they've been deleted
+// from discovery. It doesn't count as evicting them.
+func (c *addressesCache) removeBefore(version int64) {
+	for cachedVersion, element := range c.entries {
+		if cachedVersion < version {
+			c.order.Remove(element)
+			delete(c.entries, cachedVersion)
+		}
+	}
+}
+
+func (c *addressesCache) len() int {
+	return c.order.Len()
+}
+
+func (c *addressesCache) versions() []int64 {
+	var result []int64
+	for version := range c.entries {
+		result = append(result, version)
+	}
+	return result
+}
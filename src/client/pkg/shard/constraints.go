@@ -0,0 +1,226 @@
+package shard
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type constraintKind int
+
+const (
+	requireLabel constraintKind = iota
+	avoidLabel
+	spreadBy
+	spreadByRack
+)
+
+// Constraint restricts, or guides, which servers shards are placed on,
+// based on the labels the servers registered with. Hard constraints,
+// RequireLabel and AvoidLabel, are never violated, soft constraints,
+// SpreadBy, are followed as far as the hard constraints and an even
+// distribution of shards allow.
+type Constraint struct {
+	kind  constraintKind
+	label string
+	value string
+}
+
+// RequireLabel only allows shards to be placed on servers whose label is
+// value.
+func RequireLabel(label string, value string) Constraint {
+	return Constraint{requireLabel, label, value}
+}
+
+// AvoidLabel never allows shards to be placed on servers whose label is
+// value.
+func AvoidLabel(label string, value string) Constraint {
+	return Constraint{avoidLabel, label, value}
+}
+
+// SpreadBy prefers placing shards so that every value of label, for example
+// every region, holds a similar number of them.
+func SpreadBy(label string) Constraint {
+	return Constraint{spreadBy, label, ""}
+}
+
+// Hard returns true if the constraint must never be violated.
+func (c Constraint) Hard() bool {
+	return c.kind != spreadBy && c.kind != spreadByRack
+}
+
+func (c Constraint) String() string {
+	switch c.kind {
+	case requireLabel:
+		return fmt.Sprintf("RequireLabel(%s=%s)", c.label, c.value)
+	case avoidLabel:
+		return fmt.Sprintf("AvoidLabel(%s=%s)", c.label, c.value)
+	case spreadByRack:
+		return "SpreadByRack"
+	}
+	return fmt.Sprintf("SpreadBy(%s)", c.label)
+}
+
+// spreadValue returns the value of serverState that a soft constraint
+// spreads shards between.
+func (c Constraint) spreadValue(serverState *ServerState) string {
+	if c.kind == spreadByRack {
+		return rackOf(serverState)
+	}
+	return serverState.Labels[c.label]
+}
+
+// rackOf returns the rack serverState runs in: its rack_id or, for servers
+// that only describe their rack with labels, its "rack" label.
+func rackOf(serverState *ServerState) string {
+	if serverState.RackId != "" {
+		return serverState.RackId
+	}
+	return serverState.Labels["rack"]
+}
+
+// allows returns true if serverState satisfies the constraint, soft
+// constraints allow every server.
+func (c Constraint) allows(serverState *ServerState) bool {
+	value, ok := serverState.Labels[c.label]
+	switch c.kind {
+	case requireLabel:
+		return ok && value == c.value
+	case avoidLabel:
+		return !ok || value != c.value
+	}
+	return true
+}
+
+// ConstraintError is reported when no server satisfies the sharder's hard
+// constraints.
+type ConstraintError struct {
+	// Constraints are the constraints which can't be satisfied, either
+	// because no server satisfies them individually or, if there are no
+	// such constraints, because no server satisfies all of them together.
+	Constraints []Constraint
+	// NumServers is the number of servers that were considered.
+	NumServers int
+}
+
+func (e *ConstraintError) Error() string {
+	var constraints []string
+	for _, constraint := range e.Constraints {
+		constraints = append(constraints, constraint.String())
+	}
+	return fmt.Sprintf("pachyderm: none of %d servers satisfy %s", e.NumServers, strings.Join(constraints, " and "))
+}
+
+// placement evaluates constraints while shards are being assigned.
+type placement struct {
+	constraints  []Constraint
+	serverStates map[string]*ServerState
+	// spread counts the shards placed on each value of each soft
+	// constraint
+	spread map[Constraint]map[string]int
+	// zones counts the shards placed in each zone, shards are always
+	// spread by zone.
+	zones map[string]int
+}
+
+func newPlacement(constraints []Constraint, serverStates map[string]*ServerState) *placement {
+	p := &placement{
+		constraints:  constraints,
+		serverStates: serverStates,
+		spread:       make(map[Constraint]map[string]int),
+		zones:        make(map[string]int),
+	}
+	for _, constraint := range constraints {
+		if !constraint.Hard() {
+			p.spread[constraint] = make(map[string]int)
+		}
+	}
+	return p
+}
+
+// eligible returns the addresses of the servers which satisfy every hard
+// constraint, in order, or a *ConstraintError if there are none.
+func (p *placement) eligible() ([]string, error) {
+	var result []string
+	for address, serverState := range p.serverStates {
+		if p.allowed(serverState) {
+			result = append(result, address)
+		}
+	}
+	if len(result) > 0 {
+		sort.Strings(result)
+		return result, nil
+	}
+	var unsatisfiable []Constraint
+	var hard []Constraint
+	for _, constraint := range p.constraints {
+		if !constraint.Hard() {
+			continue
+		}
+		hard = append(hard, constraint)
+		satisfied := false
+		for _, serverState := range p.serverStates {
+			if constraint.allows(serverState) {
+				satisfied = true
+				break
+			}
+		}
+		if !satisfied {
+			unsatisfiable = append(unsatisfiable, constraint)
+		}
+	}
+	if len(unsatisfiable) == 0 {
+		unsatisfiable = hard
+	}
+	return nil, &ConstraintError{Constraints: unsatisfiable, NumServers: len(p.serverStates)}
+}
+
+func (p *placement) allowed(serverState *ServerState) bool {
+	for _, constraint := range p.constraints {
+		if !constraint.allows(serverState) {
+			return false
+		}
+	}
+	return true
+}
+
+// candidates returns addresses ordered from the most to the least
+// preferred place for the next shard, according to the soft constraints.
+func (p *placement) candidates(addresses []string) []string {
+	candidates := &byCrowding{addresses: append([]string(nil), addresses...)}
+	for _, address := range addresses {
+		candidates.crowding = append(candidates.crowding, p.crowding(address))
+	}
+	sort.Stable(candidates)
+	return candidates.addresses
+}
+
+type byCrowding struct {
+	addresses []string
+	crowding  []int
+}
+
+func (s *byCrowding) Len() int           { return len(s.addresses) }
+func (s *byCrowding) Less(i, j int) bool { return s.crowding[i] < s.crowding[j] }
+func (s *byCrowding) Swap(i, j int) {
+	s.addresses[i], s.addresses[j] = s.addresses[j], s.addresses[i]
+	s.crowding[i], s.crowding[j] = s.crowding[j], s.crowding[i]
+}
+
+// crowding is the number of shards already placed alongside address, in its
+// zone and on every value the shards are spread by.
+func (p *placement) crowding(address string) int {
+	result := p.zones[p.serverStates[address].Zone]
+	for constraint, counts := range p.spread {
+		result += counts[constraint.spreadValue(p.serverStates[address])]
+	}
+	return result
+}
+
+// place records that a shard was placed on address.
+func (p *placement) place(address string) {
+	p.zones[p.serverStates[address].Zone]++
+	for constraint, counts := range p.spread {
+		counts[constraint.spreadValue(p.serverStates[address])]++
+	}
+}
@@ -0,0 +1,359 @@
+package shard
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/discovery"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"golang.org/x/net/context"
+)
+
+// testTopology returns servers spread over regions and racks:
+// east-0..2 in us-east, west-0 in us-west, and central-0, which has no
+// labels.
+func testTopology() map[string]*ServerState {
+	serverStates := make(map[string]*ServerState)
+	for i := 0; i < 3; i++ {
+		address := fmt.Sprintf("east-%d", i)
+		serverStates[address] = &ServerState{
+			Address: address,
+			Labels:  map[string]string{"region": "us-east", "rack": fmt.Sprintf("r%d", i%2)},
+		}
+	}
+	serverStates["west-0"] = &ServerState{
+		Address: "west-0",
+		Labels:  map[string]string{"region": "us-west", "rack": "r0"},
+	}
+	serverStates["central-0"] = &ServerState{Address: "central-0"}
+	return serverStates
+}
+
+func TestConstraintEligible(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		constraints []Constraint
+		eligible    []string
+		err         string
+	}{
+		{
+			eligible: []string{"central-0", "east-0", "east-1", "east-2", "west-0"},
+		},
+		{
+			constraints: []Constraint{RequireLabel("region", "us-east")},
+			eligible:    []string{"east-0", "east-1", "east-2"},
+		},
+		{
+			constraints: []Constraint{AvoidLabel("region", "us-east")},
+			eligible:    []string{"central-0", "west-0"},
+		},
+		{
+			constraints: []Constraint{RequireLabel("region", "us-east"), AvoidLabel("rack", "r0")},
+			eligible:    []string{"east-1"},
+		},
+		{
+			// Soft constraints never make servers ineligible
+			constraints: []Constraint{SpreadBy("region"), SpreadBy("zone")},
+			eligible:    []string{"central-0", "east-0", "east-1", "east-2", "west-0"},
+		},
+		{
+			constraints: []Constraint{RequireLabel("region", "eu-west"), AvoidLabel("rack", "r0")},
+			err:         "pachyderm: none of 5 servers satisfy RequireLabel(region=eu-west)",
+		},
+		{
+			// Each constraint is satisfiable, but not together
+			constraints: []Constraint{RequireLabel("region", "us-west"), AvoidLabel("rack", "r0"), SpreadBy("region")},
+			err:         "pachyderm: none of 5 servers satisfy RequireLabel(region=us-west) and AvoidLabel(rack=r0)",
+		},
+	} {
+		eligible, err := newPlacement(test.constraints, testTopology()).eligible()
+		if test.err != "" {
+			require.YesError(t, err)
+			require.Equal(t, test.err, err.Error())
+			_, ok := err.(*ConstraintError)
+			require.True(t, ok)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, test.eligible, eligible, "%v", test.constraints)
+	}
+}
+
+func TestConstraintSpread(t *testing.T) {
+	t.Parallel()
+	serverStates := testTopology()
+	delete(serverStates, "central-0")
+	placement := newPlacement([]Constraint{SpreadBy("region")}, serverStates)
+	eligible, err := placement.eligible()
+	require.NoError(t, err)
+	perServer := make(map[string]int)
+	perRegion := make(map[string]int)
+	for shard := 0; shard < 12; shard++ {
+		address := placement.candidates(eligible)[0]
+		placement.place(address)
+		perServer[address]++
+		perRegion[serverStates[address].Labels["region"]]++
+	}
+	// us-west has a single server, but it's preferred until it holds as
+	// many shards as all of us-east
+	require.Equal(t, map[string]int{"us-east": 6, "us-west": 6}, perRegion)
+	require.Equal(t, 6, perServer["west-0"])
+
+	// Without soft constraints candidates stay in address order
+	placement = newPlacement(nil, serverStates)
+	placement.place("east-0")
+	require.Equal(t, []string{"east-0", "east-1", "east-2", "west-0"}, placement.candidates(eligible))
+}
+
+func TestConstraintString(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "RequireLabel(region=us-west)", RequireLabel("region", "us-west").String())
+	require.Equal(t, "AvoidLabel(rack=r1)", AvoidLabel("rack", "r1").String())
+	require.Equal(t, "SpreadBy(zone)", SpreadBy("zone").String())
+	require.True(t, RequireLabel("region", "us-west").Hard())
+	require.False(t, SpreadBy("zone").Hard())
+	require.Equal(t, "SpreadByRack", Constraint{kind: spreadByRack}.String())
+	require.False(t, Constraint{kind: spreadByRack}.Hard())
+}
+
+func TestAssignRolesConstraints(t *testing.T) {
+	t.Parallel()
+	sharder := newSharder(discovery.NewMockClient(), 16, WithNamespace("TestAssignRolesConstraints"),
+		WithConstraints(AvoidLabel("region", "us-east"), SpreadBy("rack")))
+	runCtx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+	labels := map[string]map[string]string{
+		"east-0": {"region": "us-east", "rack": "r0"},
+		"east-1": {"region": "us-east", "rack": "r1"},
+		"west-0": {"region": "us-west", "rack": "r0"},
+		"west-1": {"region": "us-west", "rack": "r1"},
+	}
+	var serverAddresses []string
+	for address, serverLabels := range labels {
+		address, serverLabels := address, serverLabels
+		serverAddresses = append(serverAddresses, address)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sharder.Register(runCtx, address, []Server{newTestServer()}, WithLabels(serverLabels))
+		}()
+	}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		sharder.RegisterFrontends(runCtx, "frontend", []Frontend{&testFrontend{}})
+	}()
+	go func() {
+		defer wg.Done()
+		sharder.AssignRoles(runCtx, "master")
+	}()
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelCtx()
+	require.NoError(t, sharder.WaitForAvailability(ctx, []string{"frontend"}, serverAddresses))
+	serverRoles, err := sharder.getServerRoles()
+	require.NoError(t, err)
+	// Earlier versions, from before every server registered, may not
+	// have been cleaned up yet
+	latest := InvalidVersion
+	for _, versionToServerRole := range serverRoles {
+		for version := range versionToServerRole {
+			if version > latest {
+				latest = version
+			}
+		}
+	}
+	for address, versionToServerRole := range serverRoles {
+		serverRole := versionToServerRole[latest]
+		if labels[address]["region"] == "us-east" {
+			require.Equal(t, 0, len(serverRole.Shards), "%s has shards", address)
+		} else {
+			require.Equal(t, 8, len(serverRole.Shards), "%s doesn't have half the shards", address)
+		}
+	}
+}
+
+func TestAssignRolesUnsatisfiable(t *testing.T) {
+	t.Parallel()
+	sharder := newSharder(discovery.NewMockClient(), 16, WithNamespace("TestAssignRolesUnsatisfiable"),
+		WithConstraints(RequireLabel("region", "eu-west")))
+	runCtx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		sharder.Register(runCtx, "server", []Server{newTestServer()}, WithLabels(map[string]string{"region": "us-west"}))
+	}()
+	go func() {
+		defer wg.Done()
+		sharder.AssignRoles(runCtx, "master")
+	}()
+	// No roles are assigned, and the debug state says why
+	for i := 0; ; i++ {
+		state, err := sharder.debugState()
+		require.NoError(t, err)
+		if state.PlacementError != "" {
+			require.Equal(t, "pachyderm: none of 1 servers satisfy RequireLabel(region=eu-west)", state.PlacementError)
+			break
+		}
+		require.True(t, i < 100, "placement error not reported")
+		time.Sleep(50 * time.Millisecond)
+	}
+	serverRoles, err := sharder.getServerRoles()
+	require.NoError(t, err)
+	require.Equal(t, 0, len(serverRoles))
+}
+
+func TestZoneSpread(t *testing.T) {
+	t.Parallel()
+	serverStates := map[string]*ServerState{
+		"a-0": {Address: "a-0", Zone: "a"},
+		"a-1": {Address: "a-1", Zone: "a"},
+		"b-0": {Address: "b-0", Zone: "b"},
+	}
+	// Zones are spread without a SpreadBy constraint
+	placement := newPlacement(nil, serverStates)
+	eligible, err := placement.eligible()
+	require.NoError(t, err)
+	perZone := make(map[string]int)
+	for shard := 0; shard < 12; shard++ {
+		address := placement.candidates(eligible)[0]
+		placement.place(address)
+		perZone[serverStates[address].Zone]++
+	}
+	require.Equal(t, map[string]int{"a": 6, "b": 6}, perZone)
+}
+
+type zonedTestServer struct {
+	*testServer
+	zone string
+}
+
+func (s *zonedTestServer) Zone() string {
+	return s.zone
+}
+
+func TestAssignRolesZones(t *testing.T) {
+	t.Parallel()
+	sharder := newSharder(discovery.NewMockClient(), 16, WithNamespace("TestAssignRolesZones"))
+	runCtx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+	zones := map[string]string{"a-0": "a", "a-1": "a", "b-0": "b", "b-1": "b"}
+	var serverAddresses []string
+	for address, zone := range zones {
+		address, server := address, &zonedTestServer{testServer: newTestServer(), zone: zone}
+		serverAddresses = append(serverAddresses, address)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sharder.Register(runCtx, address, []Server{server})
+		}()
+	}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		sharder.RegisterFrontends(runCtx, "frontend", []Frontend{&testFrontend{}})
+	}()
+	go func() {
+		defer wg.Done()
+		sharder.AssignRoles(runCtx, "master")
+	}()
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelCtx()
+	require.NoError(t, sharder.WaitForAvailability(ctx, []string{"frontend"}, serverAddresses))
+	serverStates, err := sharder.getServerStates()
+	require.NoError(t, err)
+	for address, serverState := range serverStates {
+		require.Equal(t, zones[address], serverState.Zone)
+	}
+	version, err := sharder.GetVersion()
+	require.NoError(t, err)
+	shardToAddress, err := sharder.GetShardToAddress(version)
+	require.NoError(t, err)
+	perZone := make(map[string]int)
+	for _, address := range shardToAddress {
+		perZone[zones[address]]++
+	}
+	require.Equal(t, map[string]int{"a": 8, "b": 8}, perZone)
+}
+
+func TestRackAwareness(t *testing.T) {
+	t.Parallel()
+	// 8 servers in 4 racks, servers are sorted so that a rack's servers
+	// come one after another
+	rackIDs := func() map[string]*ServerState {
+		serverStates := make(map[string]*ServerState)
+		for i := 0; i < 8; i++ {
+			address := fmt.Sprintf("server-%d", i)
+			serverStates[address] = &ServerState{Address: address, RackId: fmt.Sprintf("r%d", i/2)}
+		}
+		return serverStates
+	}
+	rackLabels := func() map[string]*ServerState {
+		serverStates := rackIDs()
+		for _, serverState := range serverStates {
+			serverState.Labels = map[string]string{"rack": serverState.RackId}
+			serverState.RackId = ""
+		}
+		return serverStates
+	}
+	for _, test := range []struct {
+		name         string
+		serverStates map[string]*ServerState
+		options      []SharderOption
+		perRack      map[string]int
+	}{
+		{
+			name:         "rack IDs",
+			serverStates: rackIDs(),
+			options:      []SharderOption{WithRackAwareness()},
+			perRack:      map[string]int{"r0": 8, "r1": 8, "r2": 8, "r3": 8},
+		},
+		{
+			name:         "rack labels",
+			serverStates: rackLabels(),
+			options:      []SharderOption{WithRackAwareness()},
+			perRack:      map[string]int{"r0": 8, "r1": 8, "r2": 8, "r3": 8},
+		},
+		{
+			// WithConstraints doesn't replace WithRackAwareness
+			name:         "with constraints",
+			serverStates: rackIDs(),
+			options:      []SharderOption{WithRackAwareness(), WithConstraints(AvoidLabel("region", "us-east"))},
+			perRack:      map[string]int{"r0": 8, "r1": 8, "r2": 8, "r3": 8},
+		},
+		{
+			// Without rack awareness the candidates stay in address order
+			name:         "not rack aware",
+			serverStates: rackIDs(),
+			perRack:      map[string]int{"r0": 32},
+		},
+	} {
+		sharder := newSharder(discovery.NewMockClient(), 32, test.options...)
+		placement := newPlacement(sharder.constraints, test.serverStates)
+		eligible, err := placement.eligible()
+		require.NoError(t, err, test.name)
+		perRack := make(map[string]int)
+		for shard := 0; shard < 32; shard++ {
+			address := placement.candidates(eligible)[0]
+			placement.place(address)
+			perRack[rackOf(test.serverStates[address])]++
+		}
+		require.Equal(t, test.perRack, perRack, test.name)
+		// The quotas spread whole assignments between racks regardless
+		addresses, _, err := planRoles(32, StrategyGreedy, sharder.constraints, test.serverStates, nil, 0, "")
+		require.NoError(t, err, test.name)
+		perRack = make(map[string]int)
+		for _, address := range addresses.Addresses {
+			perRack[rackOf(test.serverStates[address])]++
+		}
+		require.Equal(t, map[string]int{"r0": 8, "r1": 8, "r2": 8, "r3": 8}, perRack, test.name)
+	}
+}
@@ -0,0 +1,86 @@
+package shard
+
+import (
+	"sync"
+	"time"
+)
+
+// debouncer calls f with the latest server states it's been given once
+// delay has passed without them changing, so that many servers arriving
+// together are assigned roles once. Calls to f are serialized, and once f
+// fails cancel is called and the error is returned from then on.
+type debouncer struct {
+	delay  time.Duration
+	f      func(serverStates serverStateCache) error
+	cancel func()
+
+	lock    sync.Mutex
+	timer   *time.Timer
+	pending serverStateCache
+	err     error
+	stopped bool
+	// runLock is held while f runs.
+	runLock sync.Mutex
+}
+
+func newDebouncer(delay time.Duration, f func(serverStates serverStateCache) error, cancel func()) *debouncer {
+	return &debouncer{
+		delay:  delay,
+		f:      f,
+		cancel: cancel,
+	}
+}
+
+// call is passed to watchServerStates in place of f, serverStates is copied
+// since the watch keeps updating it.
+func (d *debouncer) call(serverStates serverStateCache) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.err != nil {
+		return d.err
+	}
+	d.pending = make(serverStateCache)
+	for key, serverState := range serverStates {
+		d.pending[key] = serverState
+	}
+	if d.timer == nil {
+		d.timer = time.AfterFunc(d.delay, d.run)
+	} else {
+		d.timer.Reset(d.delay)
+	}
+	return nil
+}
+
+func (d *debouncer) run() {
+	d.runLock.Lock()
+	defer d.runLock.Unlock()
+	d.lock.Lock()
+	serverStates := d.pending
+	done := d.err != nil || d.stopped
+	d.lock.Unlock()
+	if done {
+		return
+	}
+	if err := d.f(serverStates); err != nil {
+		d.lock.Lock()
+		d.err = err
+		d.lock.Unlock()
+		d.cancel()
+	}
+}
+
+// stop cancels the pending call of f, waits for a running one, and returns
+// the error f failed with, if any. f isn't called after stop returns.
+func (d *debouncer) stop() error {
+	d.lock.Lock()
+	d.stopped = true
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	d.lock.Unlock()
+	d.runLock.Lock()
+	defer d.runLock.Unlock()
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.err
+}
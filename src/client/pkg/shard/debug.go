@@ -0,0 +1,209 @@
+package shard
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/debugutil"
+	"github.com/pachyderm/pachyderm/src/client/pkg/discovery"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errorutil"
+)
+
+// DebugPath is the path at which ServeDebug serves a sharder's DebugState.
+const DebugPath = "/debug/sharder"
+
+// DebugState is a snapshot of a sharder's internal state, it's only meant
+// for humans debugging a running cluster.
+type DebugState struct {
+	// AddressesVersions are the versions with cached addresses.
+	AddressesVersions []int64
+	// AddressCacheEvictions counts the versions evicted from the addresses
+	// cache to keep it within its size.
+	AddressCacheEvictions uint64
+	// ServerStates are the server states last observed by AssignRoles or
+	// RegisterFrontends, keyed by discovery key.
+	ServerStates map[string]*ServerState
+	// Announces describes how announcing this process's servers and frontends
+	// has been going, keyed by discovery key.
+	Announces map[string]*AnnounceHealth
+	// Liveness is the liveness of every registered server and frontend.
+	Liveness *Liveness
+	// Throttled counts the announcements which were delayed by the announce
+	// rate limit, keyed by discovery key.
+	Throttled map[string]uint64
+	// Watches describes the sharder's watches of the server states.
+	Watches map[string]*WatchHealth
+	// PlacementError is why AssignRoles last failed to place shards, if
+	// no server satisfies its constraints.
+	PlacementError string
+	// DeferredRebalances counts the times AssignRoles left shards where they
+	// were although the servers had changed, because the change was below
+	// its rebalance threshold.
+	DeferredRebalances uint64
+	// StuckShardCalls counts the times a call to a server's AddShard,
+	// DeleteShard or SplitShard was found still running after the shard
+	// call timeout.
+	StuckShardCalls uint64
+}
+
+// AnnounceHealth describes the recent results of refreshing an announced
+// key.
+type AnnounceHealth struct {
+	LastSuccess   time.Time
+	LastError     string
+	LastErrorTime time.Time
+}
+
+// NewDebugHandler returns a read-only http.Handler which serves sharder's
+// DebugState as JSON.
+func NewDebugHandler(sharder Sharder) http.Handler {
+	return debugutil.NewJSONHandler(func() (interface{}, error) {
+		debugSharder, ok := sharder.(interface {
+			debugState() (*DebugState, error)
+		})
+		if !ok {
+			return nil, errorutil.New(errorutil.Internal, "%T doesn't expose debug state", sharder)
+		}
+		return debugSharder.debugState()
+	})
+}
+
+// ServeDebug serves sharder's DebugState at DebugPath on address, if address
+// has no host it's bound to localhost.
+func ServeDebug(sharder Sharder, address string) error {
+	return debugutil.Serve(address, DebugPath, NewDebugHandler(sharder))
+}
+
+// debugRecorder holds the state reported by DebugState which the sharder
+// doesn't otherwise keep around.
+type debugRecorder struct {
+	serverStates map[string]*ServerState
+	announces    map[string]*AnnounceHealth
+	throttled    map[string]uint64
+	watches      map[string]*watchMonitor
+	placementErr error
+	deferred     uint64
+	stuck        uint64
+	// reassignments, addShards and removeShards are reported by Stats
+	reassignments uint64
+	addShards     uint64
+	removeShards  uint64
+	lock          sync.Mutex
+}
+
+func newDebugRecorder() *debugRecorder {
+	return &debugRecorder{
+		serverStates: make(map[string]*ServerState),
+		announces:    make(map[string]*AnnounceHealth),
+		throttled:    make(map[string]uint64),
+		watches:      make(map[string]*watchMonitor),
+	}
+}
+
+// observePlacement records the result of the last attempt to place shards,
+// err is nil if it succeeded.
+func (r *debugRecorder) observePlacement(err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.placementErr = err
+}
+
+func (r *debugRecorder) observeDeferredRebalance() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.deferred++
+}
+
+func (r *debugRecorder) observeStuckShardCall() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.stuck++
+}
+
+func (r *debugRecorder) observeReassignment() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.reassignments++
+}
+
+// observeShardCall records a call to AddShard if add is true, and to
+// DeleteShard otherwise.
+func (r *debugRecorder) observeShardCall(add bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if add {
+		r.addShards++
+	} else {
+		r.removeShards++
+	}
+}
+
+func (r *debugRecorder) observeServerStates(serverStates serverStateCache) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.serverStates = make(map[string]*ServerState, len(serverStates))
+	for key, serverState := range serverStates {
+		r.serverStates[key] = serverState
+	}
+}
+
+func (r *debugRecorder) observeAnnounce(key string, err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	health, ok := r.announces[key]
+	if !ok {
+		health = &AnnounceHealth{}
+		r.announces[key] = health
+	}
+	if err != nil {
+		health.LastError = err.Error()
+		health.LastErrorTime = time.Now()
+	} else {
+		health.LastSuccess = time.Now()
+	}
+}
+
+func (r *debugRecorder) observeThrottle(op discovery.Op, key string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.throttled[key]++
+}
+
+func (a *sharder) debugState() (*DebugState, error) {
+	liveness, err := a.GetLiveness()
+	if err != nil {
+		return nil, err
+	}
+	result := &DebugState{
+		Liveness:     liveness,
+		ServerStates: make(map[string]*ServerState),
+		Announces:    make(map[string]*AnnounceHealth),
+		Throttled:    make(map[string]uint64),
+	}
+	a.addressesLock.Lock()
+	result.AddressesVersions = a.addresses.versions()
+	result.AddressCacheEvictions = a.addresses.evictions
+	a.addressesLock.Unlock()
+	sort.Sort(int64Slice(result.AddressesVersions))
+	a.debug.lock.Lock()
+	defer a.debug.lock.Unlock()
+	for key, serverState := range a.debug.serverStates {
+		result.ServerStates[key] = serverState
+	}
+	for key, health := range a.debug.announces {
+		healthCopy := *health
+		result.Announces[key] = &healthCopy
+	}
+	for key, count := range a.debug.throttled {
+		result.Throttled[key] = count
+	}
+	result.Watches = a.debug.watchHealth(a.now())
+	if a.debug.placementErr != nil {
+		result.PlacementError = a.debug.placementErr.Error()
+	}
+	result.DeferredRebalances = a.debug.deferred
+	result.StuckShardCalls = a.debug.stuck
+	return result, nil
+}
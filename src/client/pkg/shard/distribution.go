@@ -0,0 +1,46 @@
+package shard
+
+// ShardDistribution is how a version's shards are spread between servers.
+type ShardDistribution struct {
+	// Servers is the number of shards each server holds, keyed by address.
+	// Servers which hold no shards aren't included.
+	Servers map[string]uint64
+	// Imbalance is the number of shards held by the server with the most
+	// minus the number held by the server with the fewest, it's 0 or 1 for
+	// equally weighted servers.
+	Imbalance uint64
+}
+
+// GetShardDistribution returns how version's shards are spread between
+// servers.
+func (a *sharder) GetShardDistribution(version int64) (*ShardDistribution, error) {
+	addresses, err := a.getAddresses(version)
+	if err != nil {
+		return nil, err
+	}
+	return shardDistribution(addresses.Addresses), nil
+}
+
+func shardDistribution(shardToAddress map[uint64]string) *ShardDistribution {
+	result := &ShardDistribution{Servers: make(map[string]uint64)}
+	for _, address := range shardToAddress {
+		result.Servers[address]++
+	}
+	first := true
+	var min, max uint64
+	for _, count := range result.Servers {
+		if first || count < min {
+			min = count
+		}
+		if first || count > max {
+			max = count
+		}
+		first = false
+	}
+	result.Imbalance = max - min
+	return result
+}
+
+func (s *localSharder) GetShardDistribution(version int64) (*ShardDistribution, error) {
+	return shardDistribution(s.shardToAddress), nil
+}
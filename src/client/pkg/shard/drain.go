@@ -0,0 +1,140 @@
+package shard
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errorutil"
+	"go.pedge.io/lion/proto"
+	"golang.org/x/net/context"
+)
+
+// DrainError is returned by GracefulDrainServer when ctx is done before the
+// server has handed off all of its shards.
+type DrainError struct {
+	Address string
+	// Shards are the shards the server still held at the latest version,
+	// in order. It's empty if the server had given up its shards but the
+	// servers taking them over hadn't added them yet.
+	Shards []uint64
+	// Err is why GracefulDrainServer stopped waiting, ctx.Err().
+	Err error
+}
+
+func (e *DrainError) Error() string {
+	return fmt.Sprintf("pachyderm: %s still holds %d shards %v: %s", e.Address, len(e.Shards), e.Shards, e.Err.Error())
+}
+
+// GracefulDrainServer asks the server at address, which may have been
+// registered by any sharder, to drain. The server is announced as draining,
+// AssignRoles moves its shards to other servers, and once they have all
+// been added elsewhere the server's state is deleted, rather than left to
+// expire, and its Register call returns nil. If ctx is done first the error
+// is a *DrainError, and the server keeps draining.
+func (a *sharder) GracefulDrainServer(ctx context.Context, address string) error {
+	serverStates, err := a.getServerStates()
+	if err != nil {
+		return err
+	}
+	if _, ok := serverStates[address]; !ok {
+		return errorutil.New(errorutil.NotFound, "no server registered at %s", address)
+	}
+	if err := a.set(a.serverDrainKey(address), "drain", 0); err != nil {
+		return err
+	}
+	// The request is only needed until the server has seen it, which it
+	// has if it's been drained, and mustn't drain a server which later
+	// registers with the same address.
+	defer func() {
+		if err := a.delete(a.serverDrainKey(address)); err != nil {
+			protolion.Errorf("sharder.GracefulDrainServer error deleting drain request: %s", err.Error())
+		}
+	}()
+	for {
+		covered, shards, err := a.drainedShards(address)
+		if err != nil {
+			return err
+		}
+		if covered {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return &DrainError{Address: address, Shards: shards, Err: ctx.Err()}
+		case <-time.After(a.announceInterval):
+		}
+	}
+	// The server deletes its state once it's drained, unless it's stuck,
+	// in which case we do it for it.
+	serverStates, err = a.getServerStates()
+	if err != nil {
+		return err
+	}
+	if _, ok := serverStates[address]; ok {
+		a.deregister(a.serverStateKey(address))
+	}
+	return nil
+}
+
+func (s *localSharder) GracefulDrainServer(ctx context.Context, address string) error {
+	return nil
+}
+
+// drainedShards returns true if the server at address has no shards at the
+// latest version and every other server is on it, so its shards are covered.
+// Otherwise it returns the shards the server still has, in order.
+func (a *sharder) drainedShards(address string) (bool, []uint64, error) {
+	version, err := a.GetVersion()
+	if err == ErrNoVersion {
+		return true, nil, nil
+	}
+	if err != nil {
+		return false, nil, err
+	}
+	addresses, err := a.getAddresses(version)
+	if err != nil {
+		return false, nil, err
+	}
+	var shards []uint64
+	for shard, shardAddress := range addresses.Addresses {
+		if shardAddress == address {
+			shards = append(shards, shard)
+		}
+	}
+	if len(shards) > 0 {
+		sort.Sort(uint64Slice(shards))
+		return false, shards, nil
+	}
+	serverStates, err := a.getServerStates()
+	if err != nil {
+		return false, nil, err
+	}
+	for _, serverState := range serverStates {
+		if serverState.Address != address && serverState.Version < version {
+			return false, nil, nil
+		}
+	}
+	return true, nil, nil
+}
+
+// watchDrainRequest drains registration once GracefulDrainServer asks for
+// the server at address to be drained, or until ctx is done.
+func (a *sharder) watchDrainRequest(ctx context.Context, address string, registration *registration) {
+	err := a.discoveryClient.WatchAllCtx(ctx, a.serverDrainKey(address),
+		func(drainRequest map[string]string) error {
+			if len(drainRequest) == 0 {
+				return nil
+			}
+			registration.startDrain()
+			return errComplete
+		})
+	if err != nil && err != errComplete && ctx.Err() == nil {
+		protolion.Errorf("sharder.Register error watching for drain requests: %s", err.Error())
+	}
+}
+
+func (a *sharder) serverDrainKey(address string) string {
+	return path.Join(a.serverDir(), "drain", address)
+}
@@ -0,0 +1,172 @@
+package shard
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strings"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errorutil"
+)
+
+// Encoding is how a sharder encodes the values it writes to discovery.
+// Values are decoded according to their own format, whatever the sharder's
+// Encoding, so sharders with different Encodings can share a cluster.
+type Encoding int
+
+const (
+	// JSONEncoding encodes values as jsonpb, it's the default.
+	JSONEncoding Encoding = iota
+	// BinaryEncoding encodes values as base64 encoded protobuf, which is
+	// much smaller and faster to decode for large Addresses.
+	BinaryEncoding
+)
+
+func (e Encoding) String() string {
+	switch e {
+	case JSONEncoding:
+		return "JSON"
+	case BinaryEncoding:
+		return "Binary"
+	}
+	return fmt.Sprintf("Encoding(%d)", int(e))
+}
+
+var marshaler = &jsonpb.Marshaler{}
+
+// SchemaVersion is the schema_version written in ServerState, FrontendState,
+// ServerRole and Addresses. Values written before schema_version existed
+// decode with a SchemaVersion of 0 and have the same fields as version 1.
+//
+// Fields added in later versions must decode correctly when they're missing,
+// that is their zero value must mean "the behavior before this field
+// existed". Fields from versions this sharder doesn't know about are ignored
+// when decoding, so it can keep working alongside newer sharders during a
+// rollout.
+const SchemaVersion int64 = 1
+
+// defaultCompressionThreshold is the size above which values are
+// compressed, it keeps typical states and roles readable in etcd.
+const defaultCompressionThreshold = 32 * 1024
+
+// The first byte of an encoded value identifies its format, jsonpb values
+// always start with '{'.
+const (
+	// binaryMarker prefixes base64 encoded protobuf.
+	binaryMarker = "b"
+	// compressedMarker prefixes base64 encoded gzip of another encoded
+	// value.
+	compressedMarker = "z"
+)
+
+// encode encodes message with encoding, and compresses the result if it's
+// longer than compressionThreshold. A compressionThreshold of 0 disables
+// compression.
+func encode(encoding Encoding, compressionThreshold int, message proto.Message) (string, error) {
+	var encoded string
+	switch encoding {
+	case JSONEncoding:
+		var err error
+		if encoded, err = marshaler.MarshalToString(message); err != nil {
+			return "", err
+		}
+	case BinaryEncoding:
+		data, err := proto.Marshal(message)
+		if err != nil {
+			return "", err
+		}
+		encoded = binaryMarker + base64.StdEncoding.EncodeToString(data)
+	default:
+		return "", errorutil.New(errorutil.Internal, "unknown encoding %d", encoding)
+	}
+	if compressionThreshold <= 0 || len(encoded) <= compressionThreshold {
+		return encoded, nil
+	}
+	var buffer bytes.Buffer
+	w := gzip.NewWriter(&buffer)
+	if _, err := w.Write([]byte(encoded)); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return compressedMarker + base64.StdEncoding.EncodeToString(buffer.Bytes()), nil
+}
+
+func decode(encoded string, message proto.Message) error {
+	switch {
+	case strings.HasPrefix(encoded, compressedMarker):
+		data, err := base64.StdEncoding.DecodeString(encoded[len(compressedMarker):])
+		if err != nil {
+			return err
+		}
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		decompressed, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(string(decompressed), compressedMarker) {
+			return errorutil.New(errorutil.Internal, "nested compressed value")
+		}
+		return decode(string(decompressed), message)
+	case strings.HasPrefix(encoded, binaryMarker):
+		data, err := base64.StdEncoding.DecodeString(encoded[len(binaryMarker):])
+		if err != nil {
+			return err
+		}
+		return proto.Unmarshal(data, message)
+	}
+	if err := jsonpb.UnmarshalString(encoded, message); err != nil {
+		// jsonpb rejects unknown fields, which newer sharders may write, so
+		// retry without them.
+		known, stripped, stripErr := stripUnknownFields(encoded, message)
+		if stripErr != nil || !stripped {
+			return err
+		}
+		message.Reset()
+		return jsonpb.UnmarshalString(known, message)
+	}
+	return nil
+}
+
+// stripUnknownFields returns encoded without the fields that message doesn't
+// have, and whether there were any.
+func stripUnknownFields(encoded string, message proto.Message) (string, bool, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(encoded), &fields); err != nil {
+		return "", false, err
+	}
+	names := make(map[string]bool)
+	for _, prop := range proto.GetProperties(reflect.TypeOf(message).Elem()).Prop {
+		names[prop.OrigName] = true
+		names[prop.JSONName] = true
+	}
+	stripped := false
+	for name := range fields {
+		if !names[name] {
+			delete(fields, name)
+			stripped = true
+		}
+	}
+	if !stripped {
+		return encoded, false, nil
+	}
+	known, err := json.Marshal(fields)
+	if err != nil {
+		return "", false, err
+	}
+	return string(known), true, nil
+}
+
+func (a *sharder) encode(message proto.Message) (string, error) {
+	return encode(a.encoding, a.compressionThreshold, message)
+}
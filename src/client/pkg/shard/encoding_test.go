@@ -0,0 +1,152 @@
+package shard
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pachyderm/pachyderm/src/client/pkg/discovery"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"golang.org/x/net/context"
+)
+
+func TestEncodingRoundTrip(t *testing.T) {
+	messages := []proto.Message{
+		&ServerState{Address: "server", Version: 3},
+		&ServerRole{Address: "server", Version: 3, Shards: map[uint64]bool{0: true, 7: true}, CorrelationId: "id-3"},
+		&FrontendState{Address: "frontend", Version: 3},
+		largeAddresses(64),
+	}
+	for _, format := range encodingFormats {
+		for _, message := range messages {
+			encoded, err := encode(format.encoding, format.compressionThreshold, message)
+			require.NoError(t, err)
+			require.True(t, strings.HasPrefix(encoded, format.marker), "%s: %s", format.name, encoded)
+			decoded := proto.Clone(message)
+			decoded.Reset()
+			require.NoError(t, decode(encoded, decoded))
+			require.True(t, proto.Equal(message, decoded), "%s: %v != %v", format.name, message, decoded)
+		}
+	}
+	require.YesError(t, decode(binaryMarker+"!!!", &ServerState{}))
+	require.YesError(t, decode(compressedMarker+"!!!", &ServerState{}))
+}
+
+func TestCompressionThreshold(t *testing.T) {
+	addresses := largeAddresses(64)
+	uncompressed, err := encode(JSONEncoding, 0, addresses)
+	require.NoError(t, err)
+	for _, test := range []struct {
+		threshold int
+		marker    string
+	}{
+		{len(uncompressed), "{"},
+		{len(uncompressed) - 1, compressedMarker},
+	} {
+		encoded, err := encode(JSONEncoding, test.threshold, addresses)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(encoded, test.marker), "threshold %d: %s", test.threshold, encoded)
+		var decoded Addresses
+		require.NoError(t, decode(encoded, &decoded))
+		require.True(t, proto.Equal(addresses, &decoded))
+	}
+}
+
+// TestMixedEncodings runs the servers with one encoding and the controller
+// and frontend with another, like a cluster in the middle of a rollout.
+func TestMixedEncodings(t *testing.T) {
+	t.Parallel()
+	discoveryClient := discovery.NewMockClient()
+	jsonSharder := newSharder(discoveryClient, 16, WithNamespace("TestMixedEncodings"))
+	binarySharder := newSharder(discoveryClient, 16, WithNamespace("TestMixedEncodings"), WithEncoding(BinaryEncoding), WithCompressionThreshold(1))
+	runCtx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+	var serverAddresses []string
+	for i := 0; i < 2; i++ {
+		address := fmt.Sprintf("server%d", i)
+		serverAddresses = append(serverAddresses, address)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			jsonSharder.Register(runCtx, address, []Server{newTestServer()})
+		}()
+	}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		binarySharder.RegisterFrontends(runCtx, "frontend", []Frontend{&testFrontend{}})
+	}()
+	go func() {
+		defer wg.Done()
+		binarySharder.AssignRoles(runCtx, "master")
+	}()
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelCtx()
+	require.NoError(t, jsonSharder.WaitForAvailability(ctx, []string{"frontend"}, serverAddresses))
+	version, err := jsonSharder.GetVersion()
+	require.NoError(t, err)
+	shardToAddress, err := jsonSharder.GetShardToAddress(version)
+	require.NoError(t, err)
+	require.Equal(t, 16, len(shardToAddress))
+}
+
+func BenchmarkEncodeAddresses(b *testing.B) {
+	addresses := largeAddresses(4096)
+	for _, format := range encodingFormats {
+		b.Run(format.name, func(b *testing.B) {
+			var encoded string
+			for i := 0; i < b.N; i++ {
+				var err error
+				encoded, err = encode(format.encoding, format.compressionThreshold, addresses)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(len(encoded)), "bytes")
+		})
+	}
+}
+
+func BenchmarkDecodeAddresses(b *testing.B) {
+	addresses := largeAddresses(4096)
+	for _, format := range encodingFormats {
+		encoded, err := encode(format.encoding, format.compressionThreshold, addresses)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(format.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				var decoded Addresses
+				if err := decode(encoded, &decoded); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(len(encoded)), "bytes")
+		})
+	}
+}
+
+var encodingFormats = []struct {
+	name                 string
+	encoding             Encoding
+	compressionThreshold int
+	marker               string
+}{
+	{"JSON", JSONEncoding, 0, "{"},
+	{"Binary", BinaryEncoding, 0, binaryMarker},
+	{"CompressedJSON", JSONEncoding, 1, compressedMarker},
+	{"CompressedBinary", BinaryEncoding, 1, compressedMarker},
+}
+
+func largeAddresses(numShards uint64) *Addresses {
+	addresses := &Addresses{Version: 1, Addresses: make(map[uint64]string), CorrelationId: "id-1"}
+	for shard := uint64(0); shard < numShards; shard++ {
+		addresses.Addresses[shard] = fmt.Sprintf("10.0.%d.%d:650", shard/256, shard%256)
+	}
+	return addresses
+}
@@ -0,0 +1,123 @@
+package shard
+
+import (
+	"sort"
+
+	"go.pedge.io/lion/proto"
+	"golang.org/x/net/context"
+)
+
+// ShardRoleMaster is the role of the server a shard is assigned to, it's the
+// only role a shard has.
+const ShardRoleMaster = "master"
+
+// shardAssignmentEventBuffer is how many events WatchShardAssignments holds
+// for a consumer that's fallen behind.
+const shardAssignmentEventBuffer = 256
+
+// ShardAssignmentEvent is a change to one server's role for one shard. A
+// shard moving between servers is two events: one with OldAddress set, for
+// the server losing it, and one with NewAddress set, for the server gaining
+// it.
+type ShardAssignmentEvent struct {
+	// Version is the version the change was published in.
+	Version int64
+	Shard   uint64
+	Role    string
+	// OldAddress is the server that no longer has the shard, or "".
+	OldAddress string
+	// NewAddress is the server that now has the shard, or "".
+	NewAddress string
+}
+
+// WatchShardAssignments returns a channel of the changes made by each
+// version published from now on, in order. If no version has been published
+// yet the first one's assignments are all reported as changes. The channel
+// is closed once ctx is done.
+func (a *sharder) WatchShardAssignments(ctx context.Context) (<-chan ShardAssignmentEvent, error) {
+	oldShards := make(map[uint64]string)
+	version, err := a.GetVersion()
+	if err != nil && err != ErrNoVersion {
+		return nil, err
+	}
+	if err == nil {
+		addresses, err := a.getAddresses(version)
+		if err != nil {
+			return nil, err
+		}
+		oldShards = addresses.Addresses
+	}
+	events := make(chan ShardAssignmentEvent, shardAssignmentEventBuffer)
+	go func() {
+		defer close(events)
+		err := a.WatchVersion(ctx, func(latest int64) error {
+			if latest <= version {
+				return nil
+			}
+			addresses, err := a.getAddresses(latest)
+			if err != nil {
+				return err
+			}
+			for _, event := range diffShardAssignments(latest, oldShards, addresses.Addresses) {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			version, oldShards = latest, addresses.Addresses
+			return nil
+		})
+		if err != nil && err != ErrCancelled {
+			protolion.Errorf("sharder.WatchShardAssignments error: %s", err.Error())
+		}
+	}()
+	return events, nil
+}
+
+func (s *localSharder) WatchShardAssignments(ctx context.Context) (<-chan ShardAssignmentEvent, error) {
+	// The assignments never change
+	events := make(chan ShardAssignmentEvent)
+	go func() {
+		<-ctx.Done()
+		close(events)
+	}()
+	return events, nil
+}
+
+// diffShardAssignments returns the events which take oldShards to newShards
+// at version, ordered by shard with the server losing a shard first.
+func diffShardAssignments(version int64, oldShards map[uint64]string, newShards map[uint64]string) []ShardAssignmentEvent {
+	var shards []uint64
+	for shard, oldAddress := range oldShards {
+		if newShards[shard] != oldAddress {
+			shards = append(shards, shard)
+		}
+	}
+	for shard := range newShards {
+		if _, ok := oldShards[shard]; !ok {
+			shards = append(shards, shard)
+		}
+	}
+	sort.Sort(uint64Slice(shards))
+	var result []ShardAssignmentEvent
+	for _, shard := range shards {
+		if oldAddress, ok := oldShards[shard]; ok {
+			result = append(result, ShardAssignmentEvent{
+				Version:    version,
+				Shard:      shard,
+				Role:       ShardRoleMaster,
+				OldAddress: oldAddress,
+			})
+		}
+		if newAddress, ok := newShards[shard]; ok {
+			result = append(result, ShardAssignmentEvent{
+				Version:    version,
+				Shard:      shard,
+				Role:       ShardRoleMaster,
+				NewAddress: newAddress,
+			})
+		}
+	}
+	return result
+}
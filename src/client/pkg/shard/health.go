@@ -0,0 +1,115 @@
+package shard
+
+import (
+	"fmt"
+)
+
+// ShardHealthStatus is whether a shard is being served.
+type ShardHealthStatus int
+
+const (
+	// ShardHealthy means the shard's server is registered and refreshing its
+	// state on time, or predates last_refreshed.
+	ShardHealthy ShardHealthStatus = iota
+	// ShardOwnerLagging means the shard's server is registered but has
+	// missed refreshes, so it may be about to expire.
+	ShardOwnerLagging
+	// ShardNoOwner means the shard has no server, or its server is no longer
+	// registered, so nobody is serving it.
+	ShardNoOwner
+)
+
+func (s ShardHealthStatus) String() string {
+	switch s {
+	case ShardHealthy:
+		return "healthy"
+	case ShardOwnerLagging:
+		return "owner-lagging"
+	case ShardNoOwner:
+		return "no-owner"
+	}
+	return fmt.Sprintf("ShardHealthStatus(%d)", int(s))
+}
+
+// MarshalText renders s as its String so that it's readable in JSON.
+func (s ShardHealthStatus) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// ShardHealth is whether a single shard is being served.
+type ShardHealth struct {
+	Shard uint64
+	// Address is the shard's server, it's empty if the shard has none.
+	Address string
+	Status  ShardHealthStatus
+}
+
+// ClusterHealthSummary counts the shards of a version by health: healthy
+// shards are ShardHealthy, degraded ones ShardOwnerLagging and critical
+// ones ShardNoOwner.
+type ClusterHealthSummary struct {
+	Version  int64
+	Healthy  uint64
+	Degraded uint64
+	Critical uint64
+}
+
+// GetShardHealth returns the health of each of version's shards, in order,
+// judged by the liveness of the servers they're assigned to.
+func (a *sharder) GetShardHealth(version int64) ([]*ShardHealth, error) {
+	addresses, err := a.getAddresses(version)
+	if err != nil {
+		return nil, err
+	}
+	liveness, err := a.GetLiveness()
+	if err != nil {
+		return nil, err
+	}
+	var result []*ShardHealth
+	for shard := uint64(0); shard < a.addressesNumShards(addresses); shard++ {
+		health := &ShardHealth{Shard: shard, Address: addresses.Addresses[shard]}
+		memberLiveness, ok := liveness.Servers[health.Address]
+		switch {
+		case !ok:
+			health.Status = ShardNoOwner
+		case memberLiveness.Status == LivenessLagging || memberLiveness.Status == LivenessNearExpiry:
+			health.Status = ShardOwnerLagging
+		default:
+			health.Status = ShardHealthy
+		}
+		result = append(result, health)
+	}
+	return result, nil
+}
+
+// GetClusterHealthSummary counts version's shards by health.
+func (a *sharder) GetClusterHealthSummary(version int64) (*ClusterHealthSummary, error) {
+	shardHealths, err := a.GetShardHealth(version)
+	if err != nil {
+		return nil, err
+	}
+	result := &ClusterHealthSummary{Version: version}
+	for _, shardHealth := range shardHealths {
+		switch shardHealth.Status {
+		case ShardHealthy:
+			result.Healthy++
+		case ShardOwnerLagging:
+			result.Degraded++
+		default:
+			result.Critical++
+		}
+	}
+	return result, nil
+}
+
+func (s *localSharder) GetShardHealth(version int64) ([]*ShardHealth, error) {
+	var result []*ShardHealth
+	for shard := uint64(0); shard < uint64(len(s.shardToAddress)); shard++ {
+		result = append(result, &ShardHealth{Shard: shard, Address: s.shardToAddress[shard], Status: ShardHealthy})
+	}
+	return result, nil
+}
+
+func (s *localSharder) GetClusterHealthSummary(version int64) (*ClusterHealthSummary, error) {
+	return &ClusterHealthSummary{Version: version, Healthy: uint64(len(s.shardToAddress))}, nil
+}
@@ -0,0 +1,166 @@
+package shard
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/net/context"
+)
+
+// ShutdownStage is a stage of Lifecycle.Shutdown, stages run in the order
+// they're declared in.
+type ShutdownStage int
+
+const (
+	// StopRoleChanges stops servers and frontends from picking up new
+	// versions.
+	StopRoleChanges ShutdownStage = iota
+	// FlushRoles waits for in-flight AddShard, DeleteShard and Version calls
+	// to return.
+	FlushRoles
+	// Deregister stops announcing servers and frontends and deletes their
+	// state keys so that the controller can reassign their shards.
+	Deregister
+	// StopController stops AssignRoles and releases its lock. It comes last
+	// so that if this process is the controller it can still react to its
+	// own servers deregistering.
+	StopController
+)
+
+var shutdownStages = []ShutdownStage{StopRoleChanges, FlushRoles, Deregister, StopController}
+
+var shutdownStageToString = map[ShutdownStage]string{
+	StopRoleChanges: "StopRoleChanges",
+	FlushRoles:      "FlushRoles",
+	Deregister:      "Deregister",
+	StopController:  "StopController",
+}
+
+func (s ShutdownStage) String() string {
+	if str, ok := shutdownStageToString[s]; ok {
+		return str
+	}
+	return fmt.Sprintf("ShutdownStage(%d)", int(s))
+}
+
+// Lifecycle coordinates shutting down the components of a process that use
+// a sharder. Pass it to a sharder with WithLifecycle and Register,
+// RegisterFrontends and AssignRoles will stop when Shutdown is called, in
+// an order which lets the rest of the cluster take over cleanly.
+type Lifecycle struct {
+	hooks  map[ShutdownStage]map[int]func(context.Context) error
+	nextID int
+	lock   sync.Mutex
+}
+
+// NewLifecycle returns a new Lifecycle.
+func NewLifecycle() *Lifecycle {
+	return &Lifecycle{hooks: make(map[ShutdownStage]map[int]func(context.Context) error)}
+}
+
+// OnShutdown registers hook to be called during stage of Shutdown. hook
+// should return once its part of stage is done, or ctx is done. The
+// returned function removes hook.
+func (l *Lifecycle) OnShutdown(stage ShutdownStage, hook func(ctx context.Context) error) func() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	id := l.nextID
+	l.nextID++
+	if l.hooks[stage] == nil {
+		l.hooks[stage] = make(map[int]func(context.Context) error)
+	}
+	l.hooks[stage][id] = hook
+	return func() {
+		l.lock.Lock()
+		defer l.lock.Unlock()
+		delete(l.hooks[stage], id)
+	}
+}
+
+// ShutdownError is returned by Shutdown when some stages didn't finish.
+type ShutdownError struct {
+	// TimedOut are the stages which were still running when the context
+	// passed to Shutdown was done.
+	TimedOut []ShutdownStage
+	// Errors are the errors returned by hooks, other than context errors.
+	Errors []error
+}
+
+func (e *ShutdownError) Error() string {
+	var parts []string
+	if len(e.TimedOut) > 0 {
+		var stages []string
+		for _, stage := range e.TimedOut {
+			stages = append(stages, stage.String())
+		}
+		parts = append(parts, fmt.Sprintf("stages timed out: %s", strings.Join(stages, ", ")))
+	}
+	for _, err := range e.Errors {
+		parts = append(parts, err.Error())
+	}
+	return fmt.Sprintf("shutdown: %s", strings.Join(parts, "; "))
+}
+
+// Shutdown runs the hooks of each stage in order, the hooks of a stage run
+// concurrently and the next stage starts once they've all returned. Once ctx
+// is done the remaining stages are still started, so that everything gets
+// cancelled, but they aren't waited on.
+func (l *Lifecycle) Shutdown(ctx context.Context) error {
+	var timedOut []ShutdownStage
+	var errs []error
+	var errLock sync.Mutex
+	for _, stage := range shutdownStages {
+		l.lock.Lock()
+		var hooks []func(context.Context) error
+		for _, hook := range l.hooks[stage] {
+			hooks = append(hooks, hook)
+		}
+		l.lock.Unlock()
+		var wg sync.WaitGroup
+		// cutShort is set if a hook gave up because ctx is done
+		var cutShort int32
+		for _, hook := range hooks {
+			hook := hook
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				err := hook(ctx)
+				if err == nil {
+					return
+				}
+				if err == ctx.Err() {
+					atomic.StoreInt32(&cutShort, 1)
+					return
+				}
+				errLock.Lock()
+				defer errLock.Unlock()
+				errs = append(errs, err)
+			}()
+		}
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+			if atomic.LoadInt32(&cutShort) != 0 {
+				timedOut = append(timedOut, stage)
+			}
+		case <-ctx.Done():
+			timedOut = append(timedOut, stage)
+		}
+	}
+	errLock.Lock()
+	defer errLock.Unlock()
+	if len(timedOut) == 0 && len(errs) == 0 {
+		return nil
+	}
+	// Hooks that timed out may still return errors, so errs is copied.
+	return &ShutdownError{
+		TimedOut: timedOut,
+		Errors:   append([]error(nil), errs...),
+	}
+}
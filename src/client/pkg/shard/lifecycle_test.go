@@ -0,0 +1,145 @@
+package shard
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/discovery"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"golang.org/x/net/context"
+)
+
+func TestLifecycleShutdownTimeout(t *testing.T) {
+	t.Parallel()
+	lifecycle := NewLifecycle()
+	recorder := &callRecorder{}
+	lifecycle.OnShutdown(StopRoleChanges, recorder.hook("StopRoleChanges"))
+	lifecycle.OnShutdown(StopRoleChanges, func(ctx context.Context) error {
+		return fmt.Errorf("etcd is down")
+	})
+	lifecycle.OnShutdown(FlushRoles, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	lifecycle.OnShutdown(Deregister, recorder.hook("Deregister"))
+	remove := lifecycle.OnShutdown(StopController, recorder.hook("removed"))
+	remove()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := lifecycle.Shutdown(ctx)
+	shutdownErr, ok := err.(*ShutdownError)
+	require.True(t, ok, "unexpected error: %v", err)
+	require.Equal(t, FlushRoles, shutdownErr.TimedOut[0])
+	require.Equal(t, 1, len(shutdownErr.Errors))
+	// Stages after the deadline are still started
+	time.Sleep(10 * time.Millisecond)
+	require.Equal(t, []string{"StopRoleChanges", "Deregister"}, recorder.get())
+}
+
+func TestLifecycleShutdownSharder(t *testing.T) {
+	t.Parallel()
+	recorder := &callRecorder{}
+	discoveryClient := &recordingClient{discovery.NewMockClient(), recorder}
+	lifecycle := NewLifecycle()
+	sharder := newSharder(discoveryClient, 16, WithNamespace("TestLifecycleShutdownSharder"), WithLifecycle(lifecycle))
+	var wg sync.WaitGroup
+	errs := make(chan error, 3)
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		errs <- sharder.Register(context.Background(), "server", []Server{newTestServer()})
+	}()
+	go func() {
+		defer wg.Done()
+		errs <- sharder.RegisterFrontends(context.Background(), "frontend", []Frontend{&testFrontend{}})
+	}()
+	go func() {
+		defer wg.Done()
+		errs <- sharder.AssignRoles(context.Background(), "master")
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	require.NoError(t, sharder.WaitForAvailability(ctx, []string{"frontend"}, []string{"server"}))
+
+	for _, stage := range shutdownStages {
+		lifecycle.OnShutdown(stage, recorder.hook(stage.String()))
+	}
+	require.NoError(t, lifecycle.Shutdown(ctx))
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.Equal(t, ErrCancelled, err)
+	}
+
+	calls := recorder.get()
+	index := func(call string) int {
+		for i, c := range calls {
+			if c == call {
+				return i
+			}
+		}
+		t.Fatalf("%s not called: %v", call, calls)
+		return -1
+	}
+	require.True(t, index("StopRoleChanges") < index("FlushRoles"))
+	require.True(t, index("FlushRoles") < index("Delete server/state/server"))
+	require.True(t, index("FlushRoles") < index("Delete frontend/state/frontend"))
+	require.True(t, index("Delete server/state/server") < index("CheckAndDelete lock"))
+	require.True(t, index("Delete frontend/state/frontend") < index("CheckAndDelete lock"))
+
+	values, err := discoveryClient.GetAll("")
+	require.NoError(t, err)
+	for key := range values {
+		require.False(t, strings.HasSuffix(key, "/state/server"), "%s not deregistered", key)
+		require.False(t, strings.HasSuffix(key, "/state/frontend"), "%s not deregistered", key)
+		require.False(t, strings.HasSuffix(key, "/lock"), "lock not released")
+	}
+}
+
+type callRecorder struct {
+	calls []string
+	lock  sync.Mutex
+}
+
+func (r *callRecorder) record(call string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.calls = append(r.calls, call)
+}
+
+func (r *callRecorder) get() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]string(nil), r.calls...)
+}
+
+func (r *callRecorder) hook(call string) func(context.Context) error {
+	return func(context.Context) error {
+		r.record(call)
+		return nil
+	}
+}
+
+// recordingClient records the deletes made through it, with keys relative
+// to the sharder's namespace.
+type recordingClient struct {
+	discovery.Client
+	recorder *callRecorder
+}
+
+func (c *recordingClient) DeleteCtx(ctx context.Context, key string) error {
+	c.recorder.record("Delete " + relativeKey(key))
+	return c.Client.DeleteCtx(ctx, key)
+}
+
+func (c *recordingClient) CheckAndDelete(key string, oldValue string) error {
+	c.recorder.record("CheckAndDelete " + relativeKey(key))
+	return c.Client.CheckAndDelete(key, oldValue)
+}
+
+func relativeKey(key string) string {
+	return key[strings.Index(key, "/pfs/route/")+len("/pfs/route/"):]
+}
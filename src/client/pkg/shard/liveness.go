@@ -0,0 +1,116 @@
+package shard
+
+import (
+	"fmt"
+	"time"
+)
+
+// LivenessStatus buckets how recently a server or frontend refreshed its
+// state relative to when it's expected to, and to when it expires.
+type LivenessStatus int
+
+const (
+	// LivenessUnknown means the state doesn't record when it was refreshed,
+	// because it was written by a sharder which predates last_refreshed.
+	LivenessUnknown LivenessStatus = iota
+	// LivenessHealthy means the state was refreshed within the announce
+	// interval, jitter included.
+	LivenessHealthy
+	// LivenessLagging means the state has missed at least one refresh.
+	LivenessLagging
+	// LivenessNearExpiry means the state is in the last quarter of its TTL
+	// and will be gone soon unless it's refreshed.
+	LivenessNearExpiry
+)
+
+func (s LivenessStatus) String() string {
+	switch s {
+	case LivenessUnknown:
+		return "unknown"
+	case LivenessHealthy:
+		return "healthy"
+	case LivenessLagging:
+		return "lagging"
+	case LivenessNearExpiry:
+		return "near-expiry"
+	}
+	return fmt.Sprintf("LivenessStatus(%d)", int(s))
+}
+
+// MarshalText renders s as its String so that it's readable in JSON.
+func (s LivenessStatus) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Liveness is the liveness of every registered server and frontend, keyed
+// by address, or by ID for frontends registered with WithFrontendID.
+type Liveness struct {
+	Servers   map[string]*MemberLiveness
+	Frontends map[string]*MemberLiveness
+}
+
+// MemberLiveness is the liveness of a single server or frontend.
+type MemberLiveness struct {
+	// LastRefreshed is when the member last announced its state, it's zero
+	// if the status is LivenessUnknown.
+	LastRefreshed time.Time
+	// Version is the version the member announced.
+	Version int64
+	Status  LivenessStatus
+}
+
+func newLiveness() *Liveness {
+	return &Liveness{
+		Servers:   make(map[string]*MemberLiveness),
+		Frontends: make(map[string]*MemberLiveness),
+	}
+}
+
+func (a *sharder) GetLiveness() (*Liveness, error) {
+	encodedServerStates, err := a.discoveryClient.GetAll(a.serverStateDir())
+	if err != nil {
+		return nil, err
+	}
+	encodedFrontendStates, err := a.discoveryClient.GetAll(a.frontendStateDir())
+	if err != nil {
+		return nil, err
+	}
+	now := a.now()
+	result := newLiveness()
+	for key, encodedServerState := range encodedServerStates {
+		serverState, err := decodeServerState(encodedServerState)
+		if err != nil {
+			a.corruptEntry(key, encodedServerState, err)
+			continue
+		}
+		result.Servers[serverState.Address] = a.memberLiveness(now, serverState.LastRefreshed, serverState.Version)
+	}
+	for key, encodedFrontendState := range encodedFrontendStates {
+		frontendState, err := decodeFrontendState(encodedFrontendState)
+		if err != nil {
+			a.corruptEntry(key, encodedFrontendState, err)
+			continue
+		}
+		result.Frontends[frontendState.Id] = a.memberLiveness(now, frontendState.LastRefreshed, frontendState.Version)
+	}
+	return result, nil
+}
+
+func (a *sharder) memberLiveness(now time.Time, lastRefreshed int64, version int64) *MemberLiveness {
+	result := &MemberLiveness{Version: version}
+	if lastRefreshed == 0 {
+		return result
+	}
+	result.LastRefreshed = time.Unix(0, lastRefreshed)
+	age := now.Sub(result.LastRefreshed)
+	ttl := a.holdTTLDuration()
+	switch {
+	case age >= ttl*3/4:
+		result.Status = LivenessNearExpiry
+	case age > a.announceInterval+time.Duration(a.announceJitter*float64(a.announceInterval)):
+		result.Status = LivenessLagging
+	default:
+		result.Status = LivenessHealthy
+	}
+	return result
+}
@@ -0,0 +1,130 @@
+package shard
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Registerer registers Prometheus collectors, prometheus.Registerer
+// satisfies it.
+type Registerer interface {
+	Register(prometheus.Collector) error
+}
+
+type defaultRegisterer struct{}
+
+func (defaultRegisterer) Register(collector prometheus.Collector) error {
+	return prometheus.Register(collector)
+}
+
+// Metrics are the Prometheus metrics of a sharder. A nil *Metrics records
+// nothing.
+type Metrics struct {
+	assignments        prometheus.Counter
+	assignmentFailures prometheus.Counter
+	roleVersion        prometheus.Gauge
+	cacheHits          prometheus.Counter
+	cacheMisses        prometheus.Counter
+	assignRoles        prometheus.Histogram
+}
+
+// NewMetrics returns Metrics registered with registerer, or with the default
+// Prometheus registry if registerer is nil. The metrics are:
+//
+//	pachyderm_sharder_shard_assignment_total
+//	pachyderm_sharder_shard_assignment_failures_total
+//	pachyderm_sharder_role_version
+//	pachyderm_sharder_address_cache_hits_total
+//	pachyderm_sharder_address_cache_misses_total
+//	pachyderm_sharder_assign_roles_duration_seconds
+func NewMetrics(registerer Registerer) (*Metrics, error) {
+	if registerer == nil {
+		registerer = defaultRegisterer{}
+	}
+	result := &Metrics{
+		assignments: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "pachyderm",
+			Subsystem: "sharder",
+			Name:      "shard_assignment_total",
+			Help:      "Shards assigned to servers, every version assigns all of them.",
+		}),
+		assignmentFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "pachyderm",
+			Subsystem: "sharder",
+			Name:      "shard_assignment_failures_total",
+			Help:      "Attempts to assign roles which failed to place every shard.",
+		}),
+		roleVersion: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "pachyderm",
+			Subsystem: "sharder",
+			Name:      "role_version",
+			Help:      "The last version of roles assigned.",
+		}),
+		cacheHits: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "pachyderm",
+			Subsystem: "sharder",
+			Name:      "address_cache_hits_total",
+			Help:      "Address lookups served from the cache.",
+		}),
+		cacheMisses: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "pachyderm",
+			Subsystem: "sharder",
+			Name:      "address_cache_misses_total",
+			Help:      "Address lookups which read discovery.",
+		}),
+		assignRoles: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "pachyderm",
+			Subsystem: "sharder",
+			Name:      "assign_roles_duration_seconds",
+			Help:      "Time taken to assign and publish a version of roles.",
+		}),
+	}
+	for _, collector := range []prometheus.Collector{
+		result.assignments,
+		result.assignmentFailures,
+		result.roleVersion,
+		result.cacheHits,
+		result.cacheMisses,
+		result.assignRoles,
+	} {
+		if err := registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// WithMetrics makes the sharder record metrics.
+func WithMetrics(metrics *Metrics) SharderOption {
+	return func(s *sharder) {
+		s.metrics = metrics
+	}
+}
+
+func (m *Metrics) observeAssignment(version int64, numShards int, start time.Time) {
+	if m == nil {
+		return
+	}
+	m.assignments.Add(float64(numShards))
+	m.roleVersion.Set(float64(version))
+	m.assignRoles.Observe(time.Since(start).Seconds())
+}
+
+func (m *Metrics) observeAssignmentFailure() {
+	if m == nil {
+		return
+	}
+	m.assignmentFailures.Inc()
+}
+
+func (m *Metrics) observeCacheLookup(hit bool) {
+	if m == nil {
+		return
+	}
+	if hit {
+		m.cacheHits.Inc()
+	} else {
+		m.cacheMisses.Inc()
+	}
+}
@@ -0,0 +1,84 @@
+package shard
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/discovery"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"golang.org/x/net/context"
+)
+
+type testRegisterer struct {
+	collectors []prometheus.Collector
+}
+
+func (r *testRegisterer) Register(collector prometheus.Collector) error {
+	r.collectors = append(r.collectors, collector)
+	return nil
+}
+
+func metricValue(t *testing.T, metric prometheus.Metric) *dto.Metric {
+	var result dto.Metric
+	require.NoError(t, metric.Write(&result))
+	return &result
+}
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+	registerer := &testRegisterer{}
+	metrics, err := NewMetrics(registerer)
+	require.NoError(t, err)
+	require.Equal(t, 6, len(registerer.collectors))
+	sharder := newSharder(discovery.NewMockClient(), 16, WithNamespace("TestMetrics"), WithMetrics(metrics))
+	runCtx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+	for _, address := range []string{"a", "b"} {
+		address := address
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sharder.Register(runCtx, address, []Server{newTestServer()})
+		}()
+	}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		sharder.RegisterFrontends(runCtx, "frontend", []Frontend{&testFrontend{}})
+	}()
+	go func() {
+		defer wg.Done()
+		sharder.AssignRoles(runCtx, "master")
+	}()
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelCtx()
+	require.NoError(t, sharder.WaitForAvailability(ctx, []string{"frontend"}, []string{"a", "b"}))
+	version, err := sharder.GetVersion()
+	require.NoError(t, err)
+	require.Equal(t, float64(16*(version+1)), metricValue(t, metrics.assignments).Counter.GetValue())
+	require.Equal(t, float64(0), metricValue(t, metrics.assignmentFailures).Counter.GetValue())
+	require.Equal(t, float64(version), metricValue(t, metrics.roleVersion).Gauge.GetValue())
+	require.Equal(t, uint64(version+1), metricValue(t, metrics.assignRoles).Histogram.GetSampleCount())
+
+	hits := metricValue(t, metrics.cacheHits).Counter.GetValue()
+	misses := metricValue(t, metrics.cacheMisses).Counter.GetValue()
+	_, _, err = sharder.GetAddress(0, version)
+	require.NoError(t, err)
+	_, _, err = sharder.GetAddress(0, version)
+	require.NoError(t, err)
+	require.True(t, metricValue(t, metrics.cacheHits).Counter.GetValue() >= hits+1)
+	require.True(t, metricValue(t, metrics.cacheMisses).Counter.GetValue() <= misses+1)
+}
+
+func TestNilMetrics(t *testing.T) {
+	t.Parallel()
+	var metrics *Metrics
+	metrics.observeAssignment(1, 16, time.Now())
+	metrics.observeAssignmentFailure()
+	metrics.observeCacheLookup(true)
+}
@@ -0,0 +1,71 @@
+package shard
+
+import (
+	"sort"
+)
+
+// shardMove is a shard planRoles moved off the server at from.
+type shardMove struct {
+	shard uint64
+	from  string
+	// excess is how many more shards from had than planRoles gave it.
+	excess int
+}
+
+// limitMoves undoes all but budget of the moves from oldShards that
+// addresses makes, leaving those shards where they were, so that a version
+// moves at most budget shards. Shards whose server isn't one of
+// activeServerStates, or was given no shards, have to move and always do,
+// even beyond budget, then shards move off the servers which are furthest
+// over their share. It returns the resulting addresses and roles, keyed by
+// address, and how many moves were undone. A budget of 0 allows any number
+// of moves.
+func limitMoves(
+	budget int,
+	addresses *Addresses,
+	roles map[string]*ServerRole,
+	oldShards map[uint64]string,
+	activeServerStates map[string]*ServerState,
+) (*Addresses, map[string]*ServerRole, int) {
+	if budget <= 0 {
+		return addresses, roles, 0
+	}
+	oldCounts := shardDistribution(oldShards).Servers
+	newCounts := shardDistribution(addresses.Addresses).Servers
+	var moves []shardMove
+	for shard, address := range addresses.Addresses {
+		from, ok := oldShards[shard]
+		if !ok || from == address {
+			continue
+		}
+		if _, ok := activeServerStates[from]; !ok || newCounts[from] == 0 {
+			budget--
+			continue
+		}
+		moves = append(moves, shardMove{shard, from, int(oldCounts[from]) - int(newCounts[from])})
+	}
+	if len(moves) <= budget {
+		return addresses, roles, 0
+	}
+	sort.Sort(byExcess(moves))
+	if budget < 0 {
+		budget = 0
+	}
+	for _, move := range moves[budget:] {
+		delete(roles[addresses.Addresses[move.shard]].Shards, move.shard)
+		roles[move.from].Shards[move.shard] = true
+		addresses.Addresses[move.shard] = move.from
+	}
+	return addresses, roles, len(moves) - budget
+}
+
+type byExcess []shardMove
+
+func (s byExcess) Len() int { return len(s) }
+func (s byExcess) Less(i, j int) bool {
+	if s[i].excess != s[j].excess {
+		return s[i].excess > s[j].excess
+	}
+	return s[i].shard < s[j].shard
+}
+func (s byExcess) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
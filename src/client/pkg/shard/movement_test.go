@@ -0,0 +1,68 @@
+package shard
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestLimitMoves(t *testing.T) {
+	t.Parallel()
+	serverStates := make(map[string]*ServerState)
+	for i := 0; i < 20; i++ {
+		address := fmt.Sprintf("server%d", i)
+		serverStates[address] = &ServerState{Address: address}
+	}
+	addresses, _, err := planRoles(256, StrategyGreedy, nil, serverStates, nil, 0, "")
+	require.NoError(t, err)
+	oldShards := addresses.Addresses
+	moved := func(shards map[uint64]string) int {
+		result := 0
+		for shard, address := range shards {
+			if oldShards[shard] != address {
+				result++
+			}
+		}
+		return result
+	}
+
+	// A server dying moves only its own shards, all of which must move
+	delete(serverStates, "server7")
+	addresses, roles, err := planRoles(256, StrategyGreedy, nil, serverStates, oldShards, 1, "")
+	require.NoError(t, err)
+	addresses, roles, deferred := limitMoves(16, addresses, roles, oldShards, serverStates)
+	require.Equal(t, 0, deferred)
+	require.True(t, moved(addresses.Addresses) <= 16)
+	for shard, address := range addresses.Addresses {
+		_, ok := serverStates[address]
+		require.True(t, ok, "shard %d is on %s", shard, address)
+		require.True(t, roles[address].Shards[shard])
+	}
+	// Even when there are more of them than the budget
+	addresses, roles, err = planRoles(256, StrategyGreedy, nil, serverStates, oldShards, 1, "")
+	require.NoError(t, err)
+	addresses, _, _ = limitMoves(4, addresses, roles, oldShards, serverStates)
+	for _, address := range addresses.Addresses {
+		require.True(t, address != "server7")
+	}
+
+	// A server joining takes shards from the others, budget at a time
+	serverStates["server7"] = &ServerState{Address: "server7"}
+	serverStates["server20"] = &ServerState{Address: "server20"}
+	oldShards = addresses.Addresses
+	planned, _, err := planRoles(256, StrategyGreedy, nil, serverStates, oldShards, 2, "")
+	require.NoError(t, err)
+	toMove := moved(planned.Addresses)
+	require.True(t, toMove > 4)
+	for version := int64(2); toMove > 0; version++ {
+		addresses, roles, err := planRoles(256, StrategyGreedy, nil, serverStates, oldShards, version, "")
+		require.NoError(t, err)
+		addresses, roles, deferred := limitMoves(4, addresses, roles, oldShards, serverStates)
+		require.Equal(t, toMove-moved(addresses.Addresses), deferred)
+		require.True(t, moved(addresses.Addresses) <= 4)
+		require.Equal(t, addresses.Addresses, rolesToShards(roles))
+		toMove, oldShards = deferred, addresses.Addresses
+	}
+	require.Equal(t, uint64(1), shardDistribution(oldShards).Imbalance)
+}
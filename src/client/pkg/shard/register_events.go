@@ -0,0 +1,82 @@
+package shard
+
+import (
+	"sort"
+
+	"golang.org/x/net/context"
+)
+
+// RegisterEventType is what happened to a registered server.
+type RegisterEventType int
+
+const (
+	// RegisterEventAnnounced is sent once the server's state has first
+	// been announced, so AssignRoles can see it.
+	RegisterEventAnnounced RegisterEventType = iota
+	// RegisterEventRolesReceived is sent once the server has added the
+	// shards of a version's role.
+	RegisterEventRolesReceived
+	// RegisterEventRolesRemoved is sent once the server has deleted the
+	// shards it no longer needs from a version's role.
+	RegisterEventRolesRemoved
+	// RegisterEventError is sent when announcing, adding shards or deleting
+	// them fails.
+	RegisterEventError
+)
+
+func (t RegisterEventType) String() string {
+	switch t {
+	case RegisterEventAnnounced:
+		return "Announced"
+	case RegisterEventRolesReceived:
+		return "RolesReceived"
+	case RegisterEventRolesRemoved:
+		return "RolesRemoved"
+	case RegisterEventError:
+		return "Error"
+	}
+	return "Unknown"
+}
+
+// RegisterEvent is a change in the state of a server registered with
+// RegisterWithEvents.
+type RegisterEvent struct {
+	Type RegisterEventType
+	// Version is the version of the role received or removed.
+	Version int64
+	// Shards are the shards of the role received, in order.
+	Shards []uint64
+	// Err is the error of a RegisterEventError.
+	Err error
+}
+
+// RegisterWithEvents is Register, except it also sends the server's state
+// changes to events. Events are dropped rather than holding up the server
+// if events is full.
+func (a *sharder) RegisterWithEvents(ctx context.Context, address string, servers []Server, events chan<- RegisterEvent, options ...RegisterOption) error {
+	return a.register(ctx, address, servers, events, options...)
+}
+
+func (s *localSharder) RegisterWithEvents(ctx context.Context, address string, servers []Server, events chan<- RegisterEvent, options ...RegisterOption) error {
+	return nil
+}
+
+// sendRegisterEvent sends event to events without blocking, events may be
+// nil.
+func sendRegisterEvent(events chan<- RegisterEvent, event RegisterEvent) {
+	select {
+	case events <- event:
+	default:
+	}
+}
+
+// rolesReceived is the event for serverRole having been added.
+func rolesReceived(serverRole ServerRole) RegisterEvent {
+	result := shards(serverRole)
+	sort.Sort(uint64Slice(result))
+	return RegisterEvent{
+		Type:    RegisterEventRolesReceived,
+		Version: serverRole.Version,
+		Shards:  result,
+	}
+}
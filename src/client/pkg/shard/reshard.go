@@ -0,0 +1,183 @@
+package shard
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errorutil"
+	"golang.org/x/net/context"
+)
+
+// Reshard asks AssignRoles to split the shards, by setting numShardsKey, and
+// waits for the split to be published and for every server to pick it up.
+func (a *sharder) Reshard(ctx context.Context, oldNumShards uint64, newNumShards uint64) error {
+	if oldNumShards == 0 || newNumShards < oldNumShards || newNumShards%oldNumShards != 0 {
+		return errorutil.New(errorutil.Conflict, "can't split %d shards into %d, it must be a multiple of them", oldNumShards, newNumShards)
+	}
+	numShards, err := a.currentNumShards()
+	if err != nil {
+		return err
+	}
+	if numShards != oldNumShards {
+		return errorutil.New(errorutil.Conflict, "can't split %d shards, there are %d", oldNumShards, numShards)
+	}
+	if newNumShards == oldNumShards {
+		return nil
+	}
+	// Only one split can be pending, if another Reshard got there first
+	// the key isn't oldNumShards, or unset.
+	encodedNumShards := fmt.Sprint(newNumShards)
+	if err := a.discoveryClient.CheckAndSet(a.numShardsKey(), encodedNumShards, 0, fmt.Sprint(oldNumShards)); err != nil {
+		if err := a.discoveryClient.Create(a.numShardsKey(), encodedNumShards, 0); err != nil {
+			return errorutil.Wrap(err, "another split of the %d shards is pending", oldNumShards)
+		}
+	}
+	version := InvalidVersion
+	if err := a.WatchVersion(ctx, func(latest int64) error {
+		addresses, err := a.getAddresses(latest)
+		if err != nil {
+			return err
+		}
+		if addresses.NumShards >= newNumShards {
+			version = latest
+			return errComplete
+		}
+		return nil
+	}); err != errComplete {
+		return err
+	}
+	// Like a frontend, we consider the split done once every server has
+	// reported that it's on the version.
+	err = a.watchServerStates(ctx, "Reshard", func(serverStates serverStateCache) error {
+		for _, serverState := range serverStates {
+			if serverState.Version < version {
+				return nil
+			}
+		}
+		return errComplete
+	})
+	if ctx.Err() != nil {
+		return ErrCancelled
+	}
+	if err != errComplete {
+		return err
+	}
+	return nil
+}
+
+func (s *localSharder) Reshard(ctx context.Context, oldNumShards uint64, newNumShards uint64) error {
+	return errorutil.New(errorutil.Conflict, "a local sharder's shards can't be split")
+}
+
+// currentNumShards returns the number of shards at the latest version, or
+// the number the sharder was created with if no roles have been assigned.
+func (a *sharder) currentNumShards() (uint64, error) {
+	version, err := a.GetVersion()
+	if err == ErrNoVersion {
+		return a.numShards, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	addresses, err := a.getAddresses(version)
+	if err != nil {
+		return 0, err
+	}
+	return a.addressesNumShards(addresses), nil
+}
+
+// addressesNumShards returns the number of shards addresses has, addresses
+// published before the shards could be split don't record it.
+func (a *sharder) addressesNumShards(addresses *Addresses) uint64 {
+	if addresses.NumShards == 0 {
+		return a.numShards
+	}
+	return addresses.NumShards
+}
+
+// requestedNumShards returns the number of shards Reshard has asked for, or
+// 0 if it hasn't been called.
+func (a *sharder) requestedNumShards(ctx context.Context) (uint64, error) {
+	encodedNumShards, err := a.discoveryClient.GetAllCtx(ctx, a.reshardDir())
+	if err != nil {
+		return 0, err
+	}
+	var result uint64
+	for key, encoded := range encodedNumShards {
+		numShards, err := strconv.ParseUint(encoded, 10, 64)
+		if err != nil {
+			a.corruptEntry(key, encoded, err)
+			continue
+		}
+		result = numShards
+	}
+	return result, nil
+}
+
+// splitRoles splits each of numShards shards into newNumShards / numShards
+// shards, which stay with the server oldShards says the shard is on, as
+// version. It returns the roles of every server in serverStates, keyed by
+// address, and the resulting addresses.
+func splitRoles(
+	numShards uint64,
+	newNumShards uint64,
+	serverStates map[string]*ServerState,
+	oldShards map[uint64]string,
+	version int64,
+	correlationID string,
+) (*Addresses, map[string]*ServerRole) {
+	roles := make(map[string]*ServerRole)
+	for address := range serverStates {
+		roles[address] = &ServerRole{
+			Address:       address,
+			Version:       version,
+			Shards:        make(map[uint64]bool),
+			SplitFrom:     make(map[uint64]uint64),
+			CorrelationId: correlationID,
+			SchemaVersion: SchemaVersion,
+		}
+	}
+	shards := make(map[uint64]string)
+	for shard := uint64(0); shard < numShards; shard++ {
+		address := oldShards[shard]
+		serverRole := roles[address]
+		serverRole.Shards[shard] = true
+		shards[shard] = address
+		for child := shard + numShards; child < newNumShards; child += numShards {
+			serverRole.Shards[child] = true
+			serverRole.SplitFrom[child] = shard
+			shards[child] = address
+		}
+	}
+	addresses := &Addresses{
+		Version:       version,
+		Addresses:     shards,
+		CorrelationId: correlationID,
+		NumShards:     newNumShards,
+		SchemaVersion: SchemaVersion,
+	}
+	return addresses, roles
+}
+
+// canSplit returns true if newNumShards is a split of numShards and every
+// one of the shards is on one of serverStates, whose servers will keep them.
+func canSplit(numShards uint64, newNumShards uint64, oldShards map[uint64]string, serverStates map[string]*ServerState) bool {
+	if newNumShards <= numShards || newNumShards%numShards != 0 {
+		return false
+	}
+	for shard := uint64(0); shard < numShards; shard++ {
+		if _, ok := serverStates[oldShards[shard]]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *sharder) reshardDir() string {
+	return "reshard"
+}
+
+func (a *sharder) numShardsKey() string {
+	return path.Join(a.reshardDir(), "numshards")
+}
@@ -0,0 +1,207 @@
+package shard
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden fixtures in testdata")
+
+// The v0 messages are the definitions from before schema_version existed,
+// and the v2 messages are a hypothetical next version which adds a zone to
+// every message. ServerState has since gained it.
+// Fixtures for both are checked into testdata so that changes to the
+// current definitions which break either direction of a rollout fail here.
+
+type v0ServerState struct {
+	Address string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+	Version int64  `protobuf:"varint,2,opt,name=version" json:"version,omitempty"`
+}
+
+func (m *v0ServerState) Reset()         { *m = v0ServerState{} }
+func (m *v0ServerState) String() string { return proto.CompactTextString(m) }
+func (*v0ServerState) ProtoMessage()    {}
+
+type v0FrontendState struct {
+	Address string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+	Version int64  `protobuf:"varint,2,opt,name=version" json:"version,omitempty"`
+}
+
+func (m *v0FrontendState) Reset()         { *m = v0FrontendState{} }
+func (m *v0FrontendState) String() string { return proto.CompactTextString(m) }
+func (*v0FrontendState) ProtoMessage()    {}
+
+type v0ServerRole struct {
+	Address       string          `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+	Version       int64           `protobuf:"varint,2,opt,name=version" json:"version,omitempty"`
+	Shards        map[uint64]bool `protobuf:"bytes,3,rep,name=shards" json:"shards,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	CorrelationId string          `protobuf:"bytes,4,opt,name=correlation_id,json=correlationId" json:"correlation_id,omitempty"`
+}
+
+func (m *v0ServerRole) Reset()         { *m = v0ServerRole{} }
+func (m *v0ServerRole) String() string { return proto.CompactTextString(m) }
+func (*v0ServerRole) ProtoMessage()    {}
+
+type v0Addresses struct {
+	Version       int64             `protobuf:"varint,1,opt,name=version" json:"version,omitempty"`
+	Addresses     map[uint64]string `protobuf:"bytes,2,rep,name=addresses" json:"addresses,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	CorrelationId string            `protobuf:"bytes,3,opt,name=correlation_id,json=correlationId" json:"correlation_id,omitempty"`
+}
+
+func (m *v0Addresses) Reset()         { *m = v0Addresses{} }
+func (m *v0Addresses) String() string { return proto.CompactTextString(m) }
+func (*v0Addresses) ProtoMessage()    {}
+
+type v2ServerState struct {
+	Address       string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+	Version       int64  `protobuf:"varint,2,opt,name=version" json:"version,omitempty"`
+	SchemaVersion int64  `protobuf:"varint,15,opt,name=schema_version,json=schemaVersion" json:"schema_version,omitempty"`
+	Zone          string `protobuf:"bytes,16,opt,name=zone" json:"zone,omitempty"`
+}
+
+func (m *v2ServerState) Reset()         { *m = v2ServerState{} }
+func (m *v2ServerState) String() string { return proto.CompactTextString(m) }
+func (*v2ServerState) ProtoMessage()    {}
+
+type v2FrontendState struct {
+	Address       string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+	Version       int64  `protobuf:"varint,2,opt,name=version" json:"version,omitempty"`
+	SchemaVersion int64  `protobuf:"varint,15,opt,name=schema_version,json=schemaVersion" json:"schema_version,omitempty"`
+	Zone          string `protobuf:"bytes,16,opt,name=zone" json:"zone,omitempty"`
+}
+
+func (m *v2FrontendState) Reset()         { *m = v2FrontendState{} }
+func (m *v2FrontendState) String() string { return proto.CompactTextString(m) }
+func (*v2FrontendState) ProtoMessage()    {}
+
+type v2ServerRole struct {
+	Address       string          `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+	Version       int64           `protobuf:"varint,2,opt,name=version" json:"version,omitempty"`
+	Shards        map[uint64]bool `protobuf:"bytes,3,rep,name=shards" json:"shards,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	CorrelationId string          `protobuf:"bytes,4,opt,name=correlation_id,json=correlationId" json:"correlation_id,omitempty"`
+	SchemaVersion int64           `protobuf:"varint,15,opt,name=schema_version,json=schemaVersion" json:"schema_version,omitempty"`
+	Zone          string          `protobuf:"bytes,16,opt,name=zone" json:"zone,omitempty"`
+}
+
+func (m *v2ServerRole) Reset()         { *m = v2ServerRole{} }
+func (m *v2ServerRole) String() string { return proto.CompactTextString(m) }
+func (*v2ServerRole) ProtoMessage()    {}
+
+type v2Addresses struct {
+	Version       int64             `protobuf:"varint,1,opt,name=version" json:"version,omitempty"`
+	Addresses     map[uint64]string `protobuf:"bytes,2,rep,name=addresses" json:"addresses,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	CorrelationId string            `protobuf:"bytes,3,opt,name=correlation_id,json=correlationId" json:"correlation_id,omitempty"`
+	SchemaVersion int64             `protobuf:"varint,15,opt,name=schema_version,json=schemaVersion" json:"schema_version,omitempty"`
+	Zone          string            `protobuf:"bytes,16,opt,name=zone" json:"zone,omitempty"`
+}
+
+func (m *v2Addresses) Reset()         { *m = v2Addresses{} }
+func (m *v2Addresses) String() string { return proto.CompactTextString(m) }
+func (*v2Addresses) ProtoMessage()    {}
+
+var schemaFixtures = []struct {
+	name string
+	// v0 and v2 are the fixture contents, current is what they decode to
+	// with the current definitions, apart from SchemaVersion.
+	v0      proto.Message
+	v2      proto.Message
+	current proto.Message
+}{
+	{
+		"server_state",
+		&v0ServerState{Address: "server", Version: 3},
+		&v2ServerState{Address: "server", Version: 3, SchemaVersion: 2, Zone: "us-west1-a"},
+		&ServerState{Address: "server", Version: 3},
+	},
+	{
+		"frontend_state",
+		&v0FrontendState{Address: "frontend", Version: 3},
+		&v2FrontendState{Address: "frontend", Version: 3, SchemaVersion: 2, Zone: "us-west1-a"},
+		&FrontendState{Address: "frontend", Version: 3},
+	},
+	{
+		"server_role",
+		&v0ServerRole{Address: "server", Version: 3, Shards: map[uint64]bool{0: true, 7: true}, CorrelationId: "id-3"},
+		&v2ServerRole{Address: "server", Version: 3, Shards: map[uint64]bool{0: true, 7: true}, CorrelationId: "id-3", SchemaVersion: 2, Zone: "us-west1-a"},
+		&ServerRole{Address: "server", Version: 3, Shards: map[uint64]bool{0: true, 7: true}, CorrelationId: "id-3"},
+	},
+	{
+		"addresses",
+		&v0Addresses{Version: 3, Addresses: map[uint64]string{0: "server", 7: "server"}, CorrelationId: "id-3"},
+		&v2Addresses{Version: 3, Addresses: map[uint64]string{0: "server", 7: "server"}, CorrelationId: "id-3", SchemaVersion: 2, Zone: "us-west1-a"},
+		&Addresses{Version: 3, Addresses: map[uint64]string{0: "server", 7: "server"}, CorrelationId: "id-3"},
+	},
+}
+
+var schemaEncodings = map[string]Encoding{
+	"json":   JSONEncoding,
+	"binary": BinaryEncoding,
+}
+
+// TestSchemaGolden decodes values written by older and newer sharders with
+// the current definitions.
+func TestSchemaGolden(t *testing.T) {
+	for _, fixture := range schemaFixtures {
+		for version, message := range map[string]proto.Message{"v0": fixture.v0, "v2": fixture.v2} {
+			for extension, encoding := range schemaEncodings {
+				path := filepath.Join("testdata", version, fixture.name+"."+extension)
+				if *updateGolden {
+					encoded, err := encode(encoding, 0, message)
+					require.NoError(t, err)
+					require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+					require.NoError(t, ioutil.WriteFile(path, []byte(encoded+"\n"), 0644))
+				}
+				data, err := ioutil.ReadFile(path)
+				require.NoError(t, err)
+				decoded := proto.Clone(fixture.current)
+				decoded.Reset()
+				require.NoError(t, decode(string(data[:len(data)-1]), decoded), path)
+				expected := proto.Clone(fixture.current)
+				if version == "v2" {
+					setSchemaVersion(expected, 2)
+					// ServerState's zone is the one field of v2 we know
+					if serverState, ok := expected.(*ServerState); ok {
+						serverState.Zone = "us-west1-a"
+					}
+				}
+				require.True(t, proto.Equal(expected, decoded), "%s: %v != %v", path, expected, decoded)
+			}
+		}
+	}
+}
+
+// TestSchemaDowngrade decodes values written by the current definitions
+// with the v0 definitions, as a sharder which hasn't been upgraded yet does.
+func TestSchemaDowngrade(t *testing.T) {
+	for _, fixture := range schemaFixtures {
+		current := proto.Clone(fixture.current)
+		setSchemaVersion(current, SchemaVersion)
+		for _, format := range encodingFormats {
+			encoded, err := encode(format.encoding, format.compressionThreshold, current)
+			require.NoError(t, err)
+			decoded := proto.Clone(fixture.v0)
+			decoded.Reset()
+			require.NoError(t, decode(encoded, decoded), "%s %s", fixture.name, format.name)
+			require.True(t, proto.Equal(fixture.v0, decoded), "%s %s: %v != %v", fixture.name, format.name, fixture.v0, decoded)
+		}
+	}
+}
+
+func setSchemaVersion(message proto.Message, schemaVersion int64) {
+	switch message := message.(type) {
+	case *ServerState:
+		message.SchemaVersion = schemaVersion
+	case *FrontendState:
+		message.SchemaVersion = schemaVersion
+	case *ServerRole:
+		message.SchemaVersion = schemaVersion
+	case *Addresses:
+		message.SchemaVersion = schemaVersion
+	}
+}
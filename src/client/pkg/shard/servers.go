@@ -0,0 +1,92 @@
+package shard
+
+import (
+	"path"
+	"sort"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errorutil"
+	"golang.org/x/net/context"
+)
+
+// GetServerState returns the state last announced by the server at address,
+// or a NotFound error if it isn't registered.
+func (a *sharder) GetServerState(ctx context.Context, address string) (*ServerState, error) {
+	encodedServerStates, err := a.discoveryClient.GetAllCtx(ctx, a.serverStateKey(address))
+	if err != nil {
+		return nil, errorutil.Wrap(err, "could not get the state of %s", address)
+	}
+	encodedServerState, ok := encodedServerStates[a.serverStateKey(address)]
+	if !ok {
+		return nil, errorutil.New(errorutil.NotFound, "no server registered at %s", address)
+	}
+	return decodeServerState(encodedServerState)
+}
+
+// GetServerRole returns the roles assigned to the server at address, keyed
+// by version. A server with no roles, registered or not, has an empty map.
+func (a *sharder) GetServerRole(ctx context.Context, address string) (map[int64]*ServerRole, error) {
+	encodedServerRoles, err := a.discoveryClient.GetAllCtx(ctx, a.serverRoleKey(address))
+	if err != nil {
+		return nil, errorutil.Wrap(err, "could not get the roles of %s", address)
+	}
+	result := make(map[int64]*ServerRole)
+	for key, encodedServerRole := range encodedServerRoles {
+		serverRole, err := decodeServerRole(encodedServerRole)
+		if err != nil {
+			a.corruptEntry(key, encodedServerRole, err)
+			continue
+		}
+		result[serverRole.Version] = serverRole
+	}
+	return result, nil
+}
+
+// ListServerAddresses returns the addresses of the registered servers, in
+// order.
+func (a *sharder) ListServerAddresses(ctx context.Context) ([]string, error) {
+	encodedServerStates, err := a.discoveryClient.GetAllCtx(ctx, a.serverStateDir())
+	if err != nil {
+		return nil, errorutil.Wrap(err, "could not list servers")
+	}
+	var result []string
+	for key := range encodedServerStates {
+		result = append(result, path.Base(key))
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+func (s *localSharder) GetServerState(ctx context.Context, address string) (*ServerState, error) {
+	for _, shardAddress := range s.shardToAddress {
+		if shardAddress == address {
+			return &ServerState{Address: address, SchemaVersion: SchemaVersion}, nil
+		}
+	}
+	return nil, errorutil.New(errorutil.NotFound, "no server registered at %s", address)
+}
+
+func (s *localSharder) GetServerRole(ctx context.Context, address string) (map[int64]*ServerRole, error) {
+	serverRole := &ServerRole{
+		Address:       address,
+		Shards:        make(map[uint64]bool),
+		SchemaVersion: SchemaVersion,
+	}
+	for shard, shardAddress := range s.shardToAddress {
+		if shardAddress == address {
+			serverRole.Shards[shard] = true
+		}
+	}
+	if len(serverRole.Shards) == 0 {
+		return make(map[int64]*ServerRole), nil
+	}
+	return map[int64]*ServerRole{0: serverRole}, nil
+}
+
+func (s *localSharder) ListServerAddresses(ctx context.Context) ([]string, error) {
+	var result []string
+	for address := range shardDistribution(s.shardToAddress).Servers {
+		result = append(result, address)
+	}
+	sort.Strings(result)
+	return result, nil
+}
@@ -1,32 +1,378 @@
 package shard
 
 import (
+	"math"
+	"time"
+
 	"github.com/pachyderm/pachyderm/src/client/pkg/discovery"
 	"github.com/pachyderm/pachyderm/src/client/pkg/grpcutil"
+	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 )
 
 // Sharder distributes shards between a set of servers.
 type Sharder interface {
+	// GetAddress returns the address of the server holding shard at
+	// version, or false if version has no such shard. Errors are reserved
+	// for lookups that failed, ErrInvalidVersion among them.
 	GetAddress(shard uint64, version int64) (string, bool, error)
 	GetShardToAddress(version int64) (map[uint64]string, error)
+	// GetShardDistribution returns how many of version's shards each server
+	// holds.
+	GetShardDistribution(version int64) (*ShardDistribution, error)
+	// Snapshot returns the assignment published as version, for debugging,
+	// MarshalSnapshotJSON encodes it.
+	Snapshot(version int64) (*ClusterSnapshot, error)
+	// Stats returns numbers describing the assignment and how much it's
+	// been churning, for monitoring.
+	Stats() (*Stats, error)
+	// GetAddressVersionCount returns how many versions of the shard to
+	// address mapping are stored, AssignRoles deletes the ones nobody is
+	// using beyond WithAddressRetentionVersions.
+	GetAddressVersionCount() (int, error)
+	// GetVersion returns the current version of the shard to address
+	// mapping, or InvalidVersion and ErrNoVersion if no roles have been
+	// assigned yet.
+	GetVersion() (int64, error)
+	// GetCurrentVersion is GetVersion, cached for a while so that it can be
+	// called before every lookup, and GetAddressCurrent is GetAddress at
+	// that version.
+	GetCurrentVersion(ctx context.Context) (int64, error)
+	GetAddressCurrent(ctx context.Context, shard uint64) (string, bool, error)
+	// WatchVersion calls f with the current version, and again every time
+	// there's a newer one, until ctx is done or f returns an error.
+	WatchVersion(ctx context.Context, f func(version int64) error) error
+	// WatchShardAssignments returns a channel of every change to which
+	// server each shard is assigned to, as versions are published.
+	WatchShardAssignments(ctx context.Context) (<-chan ShardAssignmentEvent, error)
+
+	// Register, RegisterFrontends and AssignRoles run until ctx is done, in
+	// which case they return ErrCancelled, or until they fail.
+	Register(ctx context.Context, address string, servers []Server, options ...RegisterOption) error
+	// RegisterWithEvents is Register, but it also reports when the server
+	// is announced and when it receives and removes roles.
+	RegisterWithEvents(ctx context.Context, address string, servers []Server, events chan<- RegisterEvent, options ...RegisterOption) error
+	// RegisterFrontends identifies the frontend by its address, unless
+	// it's given an ID with WithFrontendID.
+	RegisterFrontends(ctx context.Context, address string, frontends []Frontend, options ...FrontendOption) error
+	AssignRoles(ctx context.Context, address string) error
+	// Deregister drains a server registered by this Sharder, moving its
+	// shards to other servers before its Register call returns.
+	Deregister(ctx context.Context, address string) error
+	// GracefulDrainServer is like Deregister, but for a server registered
+	// by any Sharder. It returns once the server's shards have been added
+	// to other servers and its state has been removed.
+	GracefulDrainServer(ctx context.Context, address string) error
+	// SwapShardMaster moves shard from the server at fromAddress to the one
+	// at toAddress, which must have room for it. It returns once both
+	// servers are on the version that moves it.
+	SwapShardMaster(ctx context.Context, shard uint64, fromAddress string, toAddress string) error
+	// AssignRolesOnce returns the addresses and roles, keyed by address,
+	// that AssignRoles would assign if the servers registered were the ones
+	// in serverStates, without writing them to discovery.
+	AssignRolesOnce(ctx context.Context, serverStates map[string]*ServerState) (*Addresses, map[string]*ServerRole, error)
+
+	// Namespace returns the namespace, from WithNamespace, that the
+	// sharder's state is kept under in discovery.
+	Namespace() string
+	// GetServerState returns the state the server at address last
+	// announced, GetServerRole its roles keyed by version, and
+	// ListServerAddresses the addresses of every registered server, for
+	// inspecting servers without reading discovery.
+	GetServerState(ctx context.Context, address string) (*ServerState, error)
+	GetServerRole(ctx context.Context, address string) (map[int64]*ServerRole, error)
+	ListServerAddresses(ctx context.Context) ([]string, error)
+	// GetLiveness reports when each registered server and frontend last
+	// refreshed its state, and how close it is to expiring.
+	GetLiveness() (*Liveness, error)
+	// GetShardHealth reports whether each of version's shards is being
+	// served, and GetClusterHealthSummary counts them.
+	GetShardHealth(version int64) ([]*ShardHealth, error)
+	GetClusterHealthSummary(version int64) (*ClusterHealthSummary, error)
+
+	Resharder
+}
 
-	Register(cancel chan bool, address string, servers []Server) error
-	RegisterFrontends(cancel chan bool, address string, frontends []Frontend) error
-	AssignRoles(address string, cancel chan bool) error
+// Resharder changes the number of shards without re-registering the servers.
+type Resharder interface {
+	// Reshard splits each of oldNumShards shards into newNumShards /
+	// oldNumShards shards, newNumShards must be a multiple of oldNumShards.
+	// Shard s is split into s, s + oldNumShards, s + 2*oldNumShards and so
+	// on, so that a key's shard modulo oldNumShards is unchanged, and its
+	// server gets all of them. Reshard returns once every server has split
+	// its shards, it fails with a Conflict if the sharder doesn't currently
+	// have oldNumShards shards.
+	Reshard(ctx context.Context, oldNumShards uint64, newNumShards uint64) error
 }
 
 type TestSharder interface {
 	Sharder
-	WaitForAvailability(frontendIds []string, serverIds []string) error
+	// WaitForAvailability blocks until the given frontends and servers are
+	// all up and on the same version, or ctx is done, in which case the
+	// error names the ones that weren't.
+	WaitForAvailability(ctx context.Context, frontendIds []string, serverIds []string) error
+}
+
+// SharderOption configures a Sharder.
+type SharderOption func(*sharder)
+
+// WithLifecycle makes the sharder's Register, RegisterFrontends and
+// AssignRoles calls stop when lifecycle is shut down.
+func WithLifecycle(lifecycle *Lifecycle) SharderOption {
+	return func(s *sharder) {
+		s.lifecycle = lifecycle
+	}
+}
+
+// WithAnnounceJitter sets the fraction, between 0 and 1, of the interval
+// between announcements which is randomized so that servers and frontends
+// don't all write to discovery at the same time. The default is 0.2.
+func WithAnnounceJitter(jitter float64) SharderOption {
+	return func(s *sharder) {
+		s.announceJitter = math.Max(0, math.Min(1, jitter))
+	}
+}
+
+// WithRepairCorruptEntries makes the sharder move discovery entries it can't
+// decode under a corrupt/ prefix, rather than only skipping them, so they
+// can be inspected and stop being reported.
+func WithRepairCorruptEntries(repair bool) SharderOption {
+	return func(s *sharder) {
+		s.repairCorrupt = repair
+	}
+}
+
+// WithEncoding sets how the sharder encodes the values it writes to
+// discovery, the default is JSONEncoding.
+func WithEncoding(encoding Encoding) SharderOption {
+	return func(s *sharder) {
+		s.encoding = encoding
+	}
+}
+
+// WithCompressionThreshold sets the size, in bytes, above which the values
+// the sharder writes to discovery are gzipped. The default is 32KB, 0 means
+// values are never compressed.
+func WithCompressionThreshold(threshold int) SharderOption {
+	return func(s *sharder) {
+		s.compressionThreshold = threshold
+	}
+}
+
+// WithAnnounceRateLimit limits how often each server and frontend state is
+// written to discovery, writes beyond the limit wait. The default is 5 per
+// second.
+func WithAnnounceRateLimit(limit discovery.RateLimit) SharderOption {
+	return func(s *sharder) {
+		s.announceRateLimit = limit
+	}
+}
+
+// WithWatchStaleness sets how long the sharder's watches of the server
+// states can go without a notification, while servers are registered,
+// before they're reported as stale, and before they're re-established.
+// The defaults are the TTL of the server states and never. A resumeAfter of
+// 0 means stale watches are never re-established.
+func WithWatchStaleness(staleAfter time.Duration, resumeAfter time.Duration) SharderOption {
+	return func(s *sharder) {
+		if staleAfter > 0 {
+			s.watchStaleAfter = staleAfter
+		}
+		s.watchResumeAfter = resumeAfter
+	}
+}
+
+// WithAddressCacheSize sets how many versions of the shard to address
+// mapping the sharder caches, the least recently used version is evicted
+// when there are more. The default is 16, values less than 1 are treated as
+// 1.
+func WithAddressCacheSize(size int) SharderOption {
+	return func(s *sharder) {
+		s.addresses = newAddressesCache(size)
+	}
+}
+
+// WithAddressRetentionVersions sets how many of the latest versions of the
+// shard to address mapping AssignRoles keeps in discovery once no server or
+// frontend is using them, for debugging with Snapshot. Versions in use are
+// always kept. The default is 1, values less than 1 are treated as 1.
+func WithAddressRetentionVersions(n int) SharderOption {
+	return func(s *sharder) {
+		if n < 1 {
+			n = 1
+		}
+		s.addressRetention = n
+	}
+}
+
+// WithAssignmentStrategy sets how AssignRoles chooses a server for each
+// shard, the default is StrategyGreedy.
+func WithAssignmentStrategy(strategy AssignmentStrategy) SharderOption {
+	return func(s *sharder) {
+		s.strategy = strategy
+	}
+}
+
+// WithShardCallTimeout sets how long a call to a server's AddShard,
+// DeleteShard or SplitShard can run before it's logged as stuck, it's logged
+// again every timeout until it returns. The default is a minute, 0 turns
+// the logging off.
+func WithShardCallTimeout(timeout time.Duration) SharderOption {
+	return func(s *sharder) {
+		s.shardCallTimeout = timeout
+	}
+}
+
+// WithFailStuckShardCalls makes Register fail with an Unavailable error,
+// rather than keep waiting, once a call to one of its servers has been
+// running for the shard call timeout.
+func WithFailStuckShardCalls() SharderOption {
+	return func(s *sharder) {
+		s.failStuckShardCalls = true
+	}
+}
+
+// WithAssignRoleDebounce makes AssignRoles wait until the server states
+// have stopped changing for debounce before assigning roles, so that
+// servers starting together, for example after a deploy, cause one
+// assignment rather than one each. The default is 0, which assigns roles
+// on every change.
+func WithAssignRoleDebounce(debounce time.Duration) SharderOption {
+	return func(s *sharder) {
+		s.assignRolesDebounce = debounce
+	}
+}
+
+// WithMaxShardMovesPerVersion limits how many shards AssignRoles moves
+// between servers in one version, so that a server joining or restarting
+// doesn't rebalance the whole cluster at once. The moves off the servers
+// furthest over their share are made first and the rest are left for
+// later versions. Shards whose server has gone or is draining always
+// move. The default is 0, which doesn't limit moves.
+func WithMaxShardMovesPerVersion(maxShardMoves int) SharderOption {
+	return func(s *sharder) {
+		s.maxShardMoves = maxShardMoves
+	}
+}
+
+// WithNamespace keeps the sharder's state under namespace in discovery, so
+// that several clusters can share a discovery service. Trailing slashes are
+// ignored, and empty, "." or ".." segments make NewSharder fail. It has no
+// effect on sharders from NewNamespacedSharder.
+func WithNamespace(namespace string) SharderOption {
+	return func(s *sharder) {
+		s.namespace = namespace
+	}
+}
+
+// WithHoldTTL sets how long, in seconds, the states of servers and frontends
+// outlive their last announcement, and so how long a failed server holds its
+// shards. States are announced every half of it. The default is 20, 0 keeps
+// the default.
+func WithHoldTTL(ttl uint64) SharderOption {
+	return func(s *sharder) {
+		if ttl > 0 {
+			s.holdTTL = ttl
+		}
+	}
+}
+
+// WithAnnounceInterval sets how often servers and frontends announce their
+// state. The default is half of the hold TTL, 0 keeps the default.
+func WithAnnounceInterval(interval time.Duration) SharderOption {
+	return func(s *sharder) {
+		if interval > 0 {
+			s.announceInterval = interval
+		}
+	}
+}
+
+// WithRebalanceThreshold sets the fraction, between 0 and 1, of servers that
+// must have joined or left since shards were last assigned before AssignRoles
+// reassigns them, so that a server briefly dropping out doesn't move shards.
+// Servers that left keep their shards until then. The default is 0, which
+// reassigns shards on every change.
+func WithRebalanceThreshold(threshold float64) SharderOption {
+	return func(s *sharder) {
+		s.rebalanceThreshold = math.Max(0, math.Min(1, threshold))
+	}
+}
+
+// WithConstraints sets the placement constraints AssignRoles follows. If no
+// server satisfies the hard constraints no roles are assigned, the servers
+// keep their current roles, and the unsatisfiable constraints are logged and
+// reported in the sharder's DebugState until the servers change.
+func WithConstraints(constraints ...Constraint) SharderOption {
+	return func(s *sharder) {
+		s.constraints = constraints
+	}
 }
 
-func NewSharder(discoveryClient discovery.Client, numShards uint64, namespace string) Sharder {
-	return newSharder(discoveryClient, numShards, namespace)
+// WithRackAwareness makes AssignRoles prefer to spread shards evenly between
+// racks, as given by the servers' rack IDs or "rack" labels, so that a rack
+// losing power loses as few shards as possible. Like SpreadBy it's a soft
+// constraint.
+func WithRackAwareness() SharderOption {
+	return func(s *sharder) {
+		s.rackAware = true
+	}
 }
 
-func NewTestSharder(discoveryClient discovery.Client, numShards uint64, namespace string) TestSharder {
-	return newSharder(discoveryClient, numShards, namespace)
+// RegisterOption configures the state a server registers with.
+type RegisterOption func(*ServerState)
+
+// FrontendOption configures the state a frontend registers with.
+type FrontendOption func(*FrontendState)
+
+// WithFrontendID identifies the frontend by id rather than by its address,
+// so that frontends sharing an address, for example behind a load
+// balancer, are registered separately. WaitForAvailability's frontendIds
+// are these ids.
+func WithFrontendID(id string) FrontendOption {
+	return func(frontendState *FrontendState) {
+		frontendState.Id = id
+	}
+}
+
+// WithLabels sets the labels, for example region and rack, that placement
+// constraints are evaluated against.
+func WithLabels(labels map[string]string) RegisterOption {
+	return func(serverState *ServerState) {
+		serverState.Labels = labels
+	}
+}
+
+// WithRackID sets the rack the server runs in, for WithRackAwareness.
+func WithRackID(rackID string) RegisterOption {
+	return func(serverState *ServerState) {
+		serverState.RackId = rackID
+	}
+}
+
+// NewSharder returns a Sharder which keeps its state in discoveryClient,
+// under its namespace. It fails if the namespace set with WithNamespace
+// is invalid.
+func NewSharder(discoveryClient discovery.Client, numShards uint64, options ...SharderOption) (Sharder, error) {
+	result, err := newValidSharder(discoveryClient, numShards, options...)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// NewNamespacedSharder is like NewSharder but namespacedClient is used as is,
+// it should already be scoped to the sharder's namespace, for example with
+// discovery.NewNamespacedClient.
+func NewNamespacedSharder(namespacedClient discovery.Client, numShards uint64, options ...SharderOption) Sharder {
+	return newNamespacedSharder(namespacedClient, numShards, options...)
+}
+
+func NewTestSharder(discoveryClient discovery.Client, numShards uint64, options ...SharderOption) (TestSharder, error) {
+	result, err := newValidSharder(discoveryClient, numShards, options...)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
 func NewLocalSharder(addresses []string, numShards uint64) Sharder {
@@ -40,6 +386,37 @@ type Server interface {
 	DeleteShard(shard uint64) error
 }
 
+// WeightedServer is a Server which can handle more, or fewer, shards than
+// others. Servers which aren't WeightedServers have a weight of 1, and
+// AssignRoles gives each server a share of the shards proportional to its
+// weight.
+type WeightedServer interface {
+	Server
+	// Weight returns the server's weight, 0 is treated as 1.
+	Weight() uint64
+}
+
+// SplittingServer is a Server which can split a shard's data between its
+// children when the shards are resharded. Servers which aren't
+// SplittingServers get AddShard for each child instead.
+type SplittingServer interface {
+	Server
+	// SplitShard tells the server that newShard has been split from
+	// oldShard, which it holds, at version. It's called before the server
+	// reports that it's on version, so frontends don't route to newShard
+	// until it returns.
+	SplitShard(oldShard uint64, newShard uint64, version int64) error
+}
+
+// ZonedServer is a Server which knows which zone, or other failure domain,
+// it runs in. AssignRoles prefers to spread shards evenly between zones, so
+// that losing one loses as few shards as possible.
+type ZonedServer interface {
+	Server
+	// Zone returns the server's zone, "" if it's unknown.
+	Zone() string
+}
+
 type Frontend interface {
 	// Version tells the Frontend a new version exists.
 	// Version should block until the Frontend is done using the previous version.
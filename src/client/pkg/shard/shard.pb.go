@@ -28,6 +28,11 @@ It has these top-level messages:
 	SetAddresses
 	GetAddress
 	GetShardToAddress
+	CorruptEntry
+	StaleWatch
+	DeleteAddresses
+	StuckShardCall
+	DeferredShardMoves
 */
 package shard
 
@@ -45,8 +50,15 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion1
 
 type ServerState struct {
-	Address string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
-	Version int64  `protobuf:"varint,2,opt,name=version" json:"version,omitempty"`
+	Address       string            `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+	Version       int64             `protobuf:"varint,2,opt,name=version" json:"version,omitempty"`
+	LastRefreshed int64             `protobuf:"varint,3,opt,name=last_refreshed,json=lastRefreshed" json:"last_refreshed,omitempty"`
+	Labels        map[string]string `protobuf:"bytes,4,rep,name=labels" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Weight        uint64            `protobuf:"varint,5,opt,name=weight" json:"weight,omitempty"`
+	Draining      bool              `protobuf:"varint,6,opt,name=draining" json:"draining,omitempty"`
+	SchemaVersion int64             `protobuf:"varint,15,opt,name=schema_version,json=schemaVersion" json:"schema_version,omitempty"`
+	Zone          string            `protobuf:"bytes,16,opt,name=zone" json:"zone,omitempty"`
+	RackId        string            `protobuf:"bytes,17,opt,name=rack_id,json=rackId" json:"rack_id,omitempty"`
 }
 
 func (m *ServerState) Reset()                    { *m = ServerState{} }
@@ -55,8 +67,11 @@ func (*ServerState) ProtoMessage()               {}
 func (*ServerState) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
 
 type FrontendState struct {
-	Address string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
-	Version int64  `protobuf:"varint,2,opt,name=version" json:"version,omitempty"`
+	Address       string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+	Version       int64  `protobuf:"varint,2,opt,name=version" json:"version,omitempty"`
+	LastRefreshed int64  `protobuf:"varint,3,opt,name=last_refreshed,json=lastRefreshed" json:"last_refreshed,omitempty"`
+	Id            string `protobuf:"bytes,4,opt,name=id" json:"id,omitempty"`
+	SchemaVersion int64  `protobuf:"varint,15,opt,name=schema_version,json=schemaVersion" json:"schema_version,omitempty"`
 }
 
 func (m *FrontendState) Reset()                    { *m = FrontendState{} }
@@ -65,9 +80,12 @@ func (*FrontendState) ProtoMessage()               {}
 func (*FrontendState) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
 
 type ServerRole struct {
-	Address string          `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
-	Version int64           `protobuf:"varint,2,opt,name=version" json:"version,omitempty"`
-	Shards  map[uint64]bool `protobuf:"bytes,3,rep,name=shards" json:"shards,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	Address       string            `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+	Version       int64             `protobuf:"varint,2,opt,name=version" json:"version,omitempty"`
+	Shards        map[uint64]bool   `protobuf:"bytes,3,rep,name=shards" json:"shards,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	CorrelationId string            `protobuf:"bytes,4,opt,name=correlation_id,json=correlationId" json:"correlation_id,omitempty"`
+	SplitFrom     map[uint64]uint64 `protobuf:"bytes,5,rep,name=split_from,json=splitFrom" json:"split_from,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	SchemaVersion int64             `protobuf:"varint,15,opt,name=schema_version,json=schemaVersion" json:"schema_version,omitempty"`
 }
 
 func (m *ServerRole) Reset()                    { *m = ServerRole{} }
@@ -82,9 +100,19 @@ func (m *ServerRole) GetShards() map[uint64]bool {
 	return nil
 }
 
+func (m *ServerRole) GetSplitFrom() map[uint64]uint64 {
+	if m != nil {
+		return m.SplitFrom
+	}
+	return nil
+}
+
 type Addresses struct {
-	Version   int64             `protobuf:"varint,1,opt,name=version" json:"version,omitempty"`
-	Addresses map[uint64]string `protobuf:"bytes,2,rep,name=addresses" json:"addresses,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Version       int64             `protobuf:"varint,1,opt,name=version" json:"version,omitempty"`
+	Addresses     map[uint64]string `protobuf:"bytes,2,rep,name=addresses" json:"addresses,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	CorrelationId string            `protobuf:"bytes,3,opt,name=correlation_id,json=correlationId" json:"correlation_id,omitempty"`
+	NumShards     uint64            `protobuf:"varint,4,opt,name=num_shards,json=numShards" json:"num_shards,omitempty"`
+	SchemaVersion int64             `protobuf:"varint,15,opt,name=schema_version,json=schemaVersion" json:"schema_version,omitempty"`
 }
 
 func (m *Addresses) Reset()                    { *m = Addresses{} }
@@ -100,7 +128,8 @@ func (m *Addresses) GetAddresses() map[uint64]string {
 }
 
 type StartRegister struct {
-	Address string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+	Address       string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+	CorrelationId string `protobuf:"bytes,2,opt,name=correlation_id,json=correlationId" json:"correlation_id,omitempty"`
 }
 
 func (m *StartRegister) Reset()                    { *m = StartRegister{} }
@@ -109,8 +138,9 @@ func (*StartRegister) ProtoMessage()               {}
 func (*StartRegister) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
 
 type FinishRegister struct {
-	Address string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
-	Error   string `protobuf:"bytes,2,opt,name=error" json:"error,omitempty"`
+	Address       string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+	Error         string `protobuf:"bytes,2,opt,name=error" json:"error,omitempty"`
+	CorrelationId string `protobuf:"bytes,3,opt,name=correlation_id,json=correlationId" json:"correlation_id,omitempty"`
 }
 
 func (m *FinishRegister) Reset()                    { *m = FinishRegister{} }
@@ -129,6 +159,7 @@ func (*Version) ProtoMessage()               {}
 func (*Version) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
 
 type StartAssignRoles struct {
+	CorrelationId string `protobuf:"bytes,1,opt,name=correlation_id,json=correlationId" json:"correlation_id,omitempty"`
 }
 
 func (m *StartAssignRoles) Reset()                    { *m = StartAssignRoles{} }
@@ -137,7 +168,8 @@ func (*StartAssignRoles) ProtoMessage()               {}
 func (*StartAssignRoles) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
 
 type FinishAssignRoles struct {
-	Error string `protobuf:"bytes,1,opt,name=error" json:"error,omitempty"`
+	Error         string `protobuf:"bytes,1,opt,name=error" json:"error,omitempty"`
+	CorrelationId string `protobuf:"bytes,2,opt,name=correlation_id,json=correlationId" json:"correlation_id,omitempty"`
 }
 
 func (m *FinishAssignRoles) Reset()                    { *m = FinishAssignRoles{} }
@@ -146,9 +178,10 @@ func (*FinishAssignRoles) ProtoMessage()               {}
 func (*FinishAssignRoles) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
 
 type FailedToAssignRoles struct {
-	ServerStates map[string]*ServerState `protobuf:"bytes,1,rep,name=server_states,json=serverStates" json:"server_states,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	NumShards    uint64                  `protobuf:"varint,2,opt,name=num_shards,json=numShards" json:"num_shards,omitempty"`
-	NumReplicas  uint64                  `protobuf:"varint,3,opt,name=num_replicas,json=numReplicas" json:"num_replicas,omitempty"`
+	ServerStates  map[string]*ServerState `protobuf:"bytes,1,rep,name=server_states,json=serverStates" json:"server_states,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	NumShards     uint64                  `protobuf:"varint,2,opt,name=num_shards,json=numShards" json:"num_shards,omitempty"`
+	NumReplicas   uint64                  `protobuf:"varint,3,opt,name=num_replicas,json=numReplicas" json:"num_replicas,omitempty"`
+	Unsatisfiable string                  `protobuf:"bytes,4,opt,name=unsatisfiable" json:"unsatisfiable,omitempty"`
 }
 
 func (m *FailedToAssignRoles) Reset()                    { *m = FailedToAssignRoles{} }
@@ -164,7 +197,8 @@ func (m *FailedToAssignRoles) GetServerStates() map[string]*ServerState {
 }
 
 type SetServerState struct {
-	ServerState *ServerState `protobuf:"bytes,1,opt,name=serverState" json:"serverState,omitempty"`
+	ServerState   *ServerState `protobuf:"bytes,1,opt,name=serverState" json:"serverState,omitempty"`
+	CorrelationId string       `protobuf:"bytes,2,opt,name=correlation_id,json=correlationId" json:"correlation_id,omitempty"`
 }
 
 func (m *SetServerState) Reset()                    { *m = SetServerState{} }
@@ -308,6 +342,71 @@ func (m *GetShardToAddress) GetResult() map[uint64]string {
 	return nil
 }
 
+type CorruptEntry struct {
+	Key         string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	Payload     string `protobuf:"bytes,2,opt,name=payload" json:"payload,omitempty"`
+	Error       string `protobuf:"bytes,3,opt,name=error" json:"error,omitempty"`
+	Quarantined bool   `protobuf:"varint,4,opt,name=quarantined" json:"quarantined,omitempty"`
+}
+
+func (m *CorruptEntry) Reset()                    { *m = CorruptEntry{} }
+func (m *CorruptEntry) String() string            { return proto.CompactTextString(m) }
+func (*CorruptEntry) ProtoMessage()               {}
+func (*CorruptEntry) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+type StaleWatch struct {
+	Watch      string `protobuf:"bytes,1,opt,name=watch" json:"watch,omitempty"`
+	Key        string `protobuf:"bytes,2,opt,name=key" json:"key,omitempty"`
+	LagSeconds int64  `protobuf:"varint,3,opt,name=lag_seconds,json=lagSeconds" json:"lag_seconds,omitempty"`
+	Resumed    bool   `protobuf:"varint,4,opt,name=resumed" json:"resumed,omitempty"`
+}
+
+func (m *StaleWatch) Reset()                    { *m = StaleWatch{} }
+func (m *StaleWatch) String() string            { return proto.CompactTextString(m) }
+func (*StaleWatch) ProtoMessage()               {}
+func (*StaleWatch) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+
+type DeleteAddresses struct {
+	Addresses *Addresses `protobuf:"bytes,1,opt,name=addresses" json:"addresses,omitempty"`
+}
+
+func (m *DeleteAddresses) Reset()                    { *m = DeleteAddresses{} }
+func (m *DeleteAddresses) String() string            { return proto.CompactTextString(m) }
+func (*DeleteAddresses) ProtoMessage()               {}
+func (*DeleteAddresses) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+
+func (m *DeleteAddresses) GetAddresses() *Addresses {
+	if m != nil {
+		return m.Addresses
+	}
+	return nil
+}
+
+type StuckShardCall struct {
+	Method         string `protobuf:"bytes,1,opt,name=method" json:"method,omitempty"`
+	Shard          uint64 `protobuf:"varint,2,opt,name=shard" json:"shard,omitempty"`
+	ElapsedSeconds int64  `protobuf:"varint,3,opt,name=elapsed_seconds,json=elapsedSeconds" json:"elapsed_seconds,omitempty"`
+	// abandoned is true if the sharder gave up waiting for the call.
+	Abandoned bool `protobuf:"varint,4,opt,name=abandoned" json:"abandoned,omitempty"`
+}
+
+func (m *StuckShardCall) Reset()                    { *m = StuckShardCall{} }
+func (m *StuckShardCall) String() string            { return proto.CompactTextString(m) }
+func (*StuckShardCall) ProtoMessage()               {}
+func (*StuckShardCall) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+
+type DeferredShardMoves struct {
+	Version int64 `protobuf:"varint,1,opt,name=version" json:"version,omitempty"`
+	// deferred is how many moves were left for later versions.
+	Deferred      uint64 `protobuf:"varint,2,opt,name=deferred" json:"deferred,omitempty"`
+	CorrelationId string `protobuf:"bytes,3,opt,name=correlation_id,json=correlationId" json:"correlation_id,omitempty"`
+}
+
+func (m *DeferredShardMoves) Reset()                    { *m = DeferredShardMoves{} }
+func (m *DeferredShardMoves) String() string            { return proto.CompactTextString(m) }
+func (*DeferredShardMoves) ProtoMessage()               {}
+func (*DeferredShardMoves) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+
 func init() {
 	proto.RegisterType((*ServerState)(nil), "shard.ServerState")
 	proto.RegisterType((*FrontendState)(nil), "shard.FrontendState")
@@ -328,48 +427,81 @@ func init() {
 	proto.RegisterType((*SetAddresses)(nil), "shard.SetAddresses")
 	proto.RegisterType((*GetAddress)(nil), "shard.GetAddress")
 	proto.RegisterType((*GetShardToAddress)(nil), "shard.GetShardToAddress")
+	proto.RegisterType((*CorruptEntry)(nil), "shard.CorruptEntry")
+	proto.RegisterType((*StaleWatch)(nil), "shard.StaleWatch")
+	proto.RegisterType((*DeleteAddresses)(nil), "shard.DeleteAddresses")
+	proto.RegisterType((*StuckShardCall)(nil), "shard.StuckShardCall")
+	proto.RegisterType((*DeferredShardMoves)(nil), "shard.DeferredShardMoves")
 }
 
 var fileDescriptor0 = []byte{
-	// 633 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xb4, 0x55, 0x5b, 0x6b, 0x13, 0x41,
-	0x14, 0x66, 0xb3, 0xbd, 0xb8, 0x27, 0x17, 0x92, 0xb5, 0x48, 0x08, 0x16, 0xeb, 0xe2, 0x43, 0x04,
-	0xd9, 0x62, 0x55, 0xd4, 0x52, 0xc5, 0xa8, 0x8d, 0x6f, 0x82, 0x13, 0x11, 0xc1, 0x87, 0xb2, 0x36,
-	0x63, 0xba, 0x64, 0xbb, 0x1b, 0x66, 0x26, 0x81, 0xfa, 0x73, 0xfc, 0x09, 0x3e, 0xfb, 0xdf, 0x74,
-	0x6e, 0x9b, 0x3d, 0x9b, 0x8b, 0xd5, 0x8a, 0x2f, 0x61, 0xcf, 0x99, 0x73, 0xf9, 0xce, 0xed, 0x0b,
-	0xdc, 0x3c, 0x4d, 0x62, 0x9a, 0x8a, 0xfd, 0xc9, 0x78, 0xb4, 0xcf, 0xcf, 0x22, 0x36, 0x34, 0xbf,
-	0xe1, 0x84, 0x65, 0x22, 0xf3, 0x37, 0xb5, 0x10, 0xf4, 0xa0, 0x3a, 0xa0, 0x6c, 0x46, 0xd9, 0x40,
-	0x44, 0x82, 0xfa, 0x6d, 0xd8, 0x8e, 0x86, 0x43, 0x46, 0x39, 0x6f, 0x3b, 0x7b, 0x4e, 0xd7, 0x23,
-	0xb9, 0xa8, 0x5e, 0xa4, 0x15, 0x8f, 0xb3, 0xb4, 0x5d, 0x91, 0x2f, 0x2e, 0xc9, 0xc5, 0xe0, 0x15,
-	0xd4, 0xfb, 0x2c, 0x4b, 0x05, 0x4d, 0x87, 0x57, 0x0f, 0xf2, 0xdd, 0x01, 0x30, 0x40, 0x48, 0x96,
-	0x5c, 0x29, 0x84, 0xff, 0x08, 0xb6, 0x74, 0x4d, 0xbc, 0xed, 0xee, 0xb9, 0xdd, 0xea, 0xc1, 0x6e,
-	0x68, 0xea, 0x2d, 0xc2, 0x86, 0x03, 0xfd, 0x7e, 0x9c, 0x0a, 0x76, 0x41, 0xac, 0x71, 0xe7, 0xa9,
-	0xec, 0x40, 0xa1, 0xf6, 0x9b, 0xe0, 0x8e, 0xe9, 0x85, 0xce, 0xba, 0x41, 0xd4, 0xa7, 0xbf, 0x03,
-	0x9b, 0xb3, 0x28, 0x99, 0x52, 0x9d, 0xef, 0x1a, 0x31, 0xc2, 0x61, 0xe5, 0x89, 0x13, 0x7c, 0x73,
-	0xc0, 0xeb, 0x19, 0x5c, 0xb4, 0x84, 0xcc, 0x29, 0x23, 0x7b, 0x06, 0x5e, 0x94, 0x9b, 0xc9, 0x28,
-	0x0a, 0xdc, 0x2d, 0x0b, 0x6e, 0xee, 0x5e, 0x7c, 0x19, 0x78, 0x85, 0x47, 0xe7, 0x08, 0x1a, 0xe5,
-	0xc7, 0xcb, 0x40, 0x7a, 0x18, 0xe4, 0x5d, 0xa8, 0xcb, 0xb1, 0x30, 0x41, 0xe8, 0x28, 0xe6, 0x82,
-	0xb2, 0xf5, 0xbd, 0x0d, 0x5e, 0x40, 0xa3, 0x1f, 0xa7, 0x31, 0x3f, 0xbb, 0xdc, 0x56, 0x25, 0xa4,
-	0x8c, 0x65, 0x2c, 0x4f, 0xa8, 0x85, 0xe0, 0x31, 0x6c, 0x7f, 0xb0, 0x45, 0xdf, 0x80, 0x2d, 0x69,
-	0x38, 0x4d, 0x84, 0xed, 0x86, 0x95, 0xd6, 0x38, 0xfa, 0xd0, 0xd4, 0x28, 0x7b, 0x9c, 0xc7, 0xa3,
-	0x54, 0x0d, 0x8b, 0x4b, 0xe4, 0x2d, 0x03, 0x07, 0x29, 0x0b, 0x77, 0x07, 0xbb, 0xff, 0x74, 0xe0,
-	0x7a, 0x3f, 0x8a, 0x13, 0x3a, 0x7c, 0x9f, 0x61, 0xeb, 0x77, 0x50, 0xe7, 0x7a, 0xfc, 0x27, 0x5c,
-	0xad, 0xa6, 0xaa, 0x42, 0x75, 0xff, 0x9e, 0xed, 0xfe, 0x0a, 0x97, 0x10, 0x9d, 0x83, 0x1d, 0x45,
-	0x8d, 0x23, 0x95, 0xbf, 0x0b, 0x90, 0x4e, 0xcf, 0x4f, 0xec, 0xaa, 0x55, 0xf4, 0x08, 0x3c, 0xa9,
-	0x31, 0x4b, 0xe4, 0xdf, 0x86, 0x9a, 0x7a, 0x66, 0x74, 0x92, 0xc4, 0xa7, 0x91, 0xda, 0x45, 0x65,
-	0x50, 0x95, 0x3a, 0x62, 0x55, 0x9d, 0x01, 0xb4, 0x96, 0x92, 0xe0, 0x91, 0x7a, 0x66, 0xa4, 0x5d,
-	0x3c, 0xd2, 0xea, 0x81, 0x5f, 0x5a, 0x67, 0xed, 0x8a, 0xc7, 0xdc, 0x87, 0xc6, 0x80, 0x0a, 0x7c,
-	0xcb, 0x0f, 0xa1, 0x8a, 0x80, 0xeb, 0xc8, 0xab, 0xa3, 0x60, 0xb3, 0xe0, 0xad, 0x1c, 0x04, 0x15,
-	0xe5, 0x83, 0x3e, 0x84, 0xfa, 0x17, 0xac, 0xb0, 0xb1, 0x76, 0xf2, 0x2e, 0xe2, 0x37, 0x52, 0x36,
-	0x0d, 0x3e, 0x42, 0x5d, 0x2e, 0x2f, 0x3a, 0xed, 0xfb, 0x00, 0x7c, 0x2e, 0xd9, 0x48, 0xad, 0xa5,
-	0x53, 0x25, 0xc8, 0x68, 0xcd, 0xca, 0x7c, 0x82, 0x26, 0xa1, 0xe7, 0xd9, 0x8c, 0xfe, 0x8f, 0xe0,
-	0x2f, 0xe5, 0xd5, 0xe4, 0xed, 0x5c, 0x11, 0xb9, 0xf2, 0x07, 0x91, 0x83, 0x63, 0x68, 0xbe, 0xa6,
-	0x09, 0x15, 0xf4, 0xdf, 0xc2, 0x3c, 0x87, 0x9a, 0x84, 0x52, 0xf0, 0x4c, 0x88, 0xd9, 0xc4, 0x94,
-	0xd8, 0x5c, 0x64, 0x13, 0x44, 0x1f, 0xc1, 0x57, 0x80, 0x37, 0x73, 0x7f, 0x55, 0xae, 0xb6, 0xb5,
-	0xe4, 0x61, 0x84, 0xdf, 0xb0, 0x6a, 0x71, 0xc6, 0xae, 0xee, 0x4f, 0x7e, 0xc6, 0x0d, 0xa8, 0x64,
-	0xe3, 0xf6, 0x86, 0xa6, 0x44, 0xf9, 0x55, 0xb4, 0x71, 0x13, 0xb7, 0xf1, 0x87, 0x03, 0x2d, 0x99,
-	0x5c, 0xdf, 0x86, 0x3c, 0xb3, 0x65, 0x0e, 0x5f, 0x60, 0xca, 0xa3, 0x79, 0x36, 0x43, 0x93, 0x77,
-	0x6c, 0x61, 0x4b, 0x31, 0x42, 0xa2, 0xcd, 0x2c, 0x95, 0x2f, 0x52, 0x8b, 0x8b, 0x30, 0x28, 0x82,
-	0x47, 0xc6, 0x7f, 0xc3, 0x9d, 0x9f, 0xb7, 0xf4, 0x7f, 0xe5, 0x83, 0x5f, 0x01, 0x00, 0x00, 0xff,
-	0xff, 0x33, 0xf0, 0xb7, 0x0e, 0x4b, 0x07, 0x00, 0x00,
+	// 1073 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x57, 0x5f, 0x8f, 0xdb, 0x44,
+	0x10, 0x97, 0x9d, 0x3f, 0x77, 0x99, 0x5c, 0x72, 0x39, 0x73, 0x2a, 0xd6, 0xa9, 0xa5, 0xc1, 0x2a,
+	0x22, 0x0f, 0x28, 0x15, 0xe5, 0x6f, 0xab, 0x03, 0x74, 0xb4, 0xbd, 0xaa, 0x12, 0xa0, 0x62, 0x57,
+	0x80, 0xc4, 0x43, 0xb4, 0x17, 0xcf, 0x25, 0x56, 0xd6, 0xde, 0x74, 0x77, 0x73, 0xd5, 0xf5, 0x9d,
+	0x67, 0x3e, 0x03, 0x0f, 0x7c, 0x0b, 0xbe, 0x0d, 0x1f, 0x04, 0xb4, 0xeb, 0xb5, 0xb3, 0x49, 0x7c,
+	0xd7, 0x50, 0xd4, 0x97, 0xc8, 0x33, 0x9e, 0x9d, 0xf9, 0xcd, 0x6f, 0x66, 0x67, 0x1c, 0xb8, 0x39,
+	0xa6, 0x09, 0x66, 0xf2, 0xee, 0x7c, 0x36, 0xb9, 0x2b, 0xa6, 0x84, 0xc7, 0xf9, 0xef, 0x70, 0xce,
+	0x99, 0x64, 0x5e, 0x43, 0x0b, 0xc1, 0xdf, 0x2e, 0xb4, 0x23, 0xe4, 0x17, 0xc8, 0x23, 0x49, 0x24,
+	0x7a, 0x3e, 0xec, 0x90, 0x38, 0xe6, 0x28, 0x84, 0xef, 0xf4, 0x9d, 0x41, 0x2b, 0x2c, 0x44, 0xf5,
+	0xe6, 0x02, 0xb9, 0x48, 0x58, 0xe6, 0xbb, 0x7d, 0x67, 0x50, 0x0b, 0x0b, 0xd1, 0xfb, 0x00, 0xba,
+	0x94, 0x08, 0x39, 0xe2, 0x78, 0xce, 0x51, 0x4c, 0x31, 0xf6, 0x6b, 0xda, 0xa0, 0xa3, 0xb4, 0x61,
+	0xa1, 0xf4, 0x3e, 0x87, 0x26, 0x25, 0x67, 0x48, 0x85, 0x5f, 0xef, 0xd7, 0x06, 0xed, 0x7b, 0xef,
+	0x0d, 0x73, 0x3c, 0x56, 0xf8, 0xe1, 0x77, 0xda, 0xe0, 0x71, 0x26, 0xf9, 0x65, 0x68, 0xac, 0xbd,
+	0x1b, 0xd0, 0x7c, 0x89, 0xc9, 0x64, 0x2a, 0xfd, 0x46, 0xdf, 0x19, 0xd4, 0x43, 0x23, 0x79, 0x47,
+	0xb0, 0x1b, 0x73, 0x92, 0x64, 0x49, 0x36, 0xf1, 0x9b, 0x7d, 0x67, 0xb0, 0x1b, 0x96, 0xb2, 0x82,
+	0x24, 0xc6, 0x53, 0x4c, 0xc9, 0xa8, 0xc0, 0xbc, 0x9f, 0x43, 0xca, 0xb5, 0x3f, 0x19, 0xe4, 0x1e,
+	0xd4, 0x5f, 0xb1, 0x0c, 0xfd, 0x9e, 0x4e, 0x55, 0x3f, 0x7b, 0xef, 0xc2, 0x0e, 0x27, 0xe3, 0xd9,
+	0x28, 0x89, 0xfd, 0x03, 0xad, 0x6e, 0x2a, 0xf1, 0x69, 0x7c, 0x74, 0x1f, 0xda, 0x16, 0x3c, 0xaf,
+	0x07, 0xb5, 0x19, 0x5e, 0x1a, 0x96, 0xd4, 0xa3, 0x77, 0x08, 0x8d, 0x0b, 0x42, 0x17, 0xa8, 0xf9,
+	0x69, 0x85, 0xb9, 0xf0, 0xc0, 0xfd, 0xd2, 0x09, 0xfe, 0x70, 0xa0, 0x73, 0xca, 0x59, 0x26, 0x31,
+	0x8b, 0xdf, 0x3a, 0xcf, 0x5d, 0x70, 0x93, 0xd8, 0xaf, 0x6b, 0xaf, 0x6e, 0x12, 0x6f, 0xc9, 0x45,
+	0xf0, 0x8f, 0x0b, 0x90, 0x97, 0x22, 0x64, 0xf4, 0xcd, 0x00, 0x7e, 0x06, 0x4d, 0x5d, 0x52, 0xe1,
+	0xd7, 0x74, 0x85, 0x6f, 0xad, 0x54, 0x58, 0xb9, 0x1d, 0x46, 0xfa, 0xbd, 0x29, 0x70, 0x6e, 0xac,
+	0x00, 0x8e, 0x19, 0xe7, 0x48, 0x89, 0x4c, 0x58, 0x36, 0x2a, 0xc1, 0x77, 0x2c, 0xed, 0xd3, 0xd8,
+	0xfb, 0x06, 0x40, 0xcc, 0x69, 0x22, 0x47, 0xe7, 0x9c, 0xa5, 0x7e, 0x43, 0x47, 0xe8, 0x57, 0x44,
+	0x50, 0x36, 0xa7, 0x9c, 0xa5, 0x79, 0x90, 0x96, 0x28, 0xe4, 0x2d, 0x89, 0x50, 0x75, 0xb6, 0x50,
+	0xda, 0x75, 0xae, 0x57, 0xd4, 0x79, 0xd7, 0xaa, 0xf3, 0xd1, 0x31, 0x74, 0x57, 0xc3, 0xbf, 0xee,
+	0x74, 0xdd, 0xee, 0x92, 0xdf, 0x5d, 0x68, 0x9d, 0xe4, 0x24, 0xe3, 0x0a, 0xcd, 0xce, 0x2a, 0xcd,
+	0x5f, 0x41, 0x8b, 0x14, 0x66, 0xbe, 0xab, 0x79, 0xb8, 0x6d, 0x78, 0x28, 0x8f, 0x2f, 0x9f, 0x0c,
+	0x0d, 0xe5, 0x89, 0x0a, 0xba, 0x6b, 0x55, 0x74, 0xdf, 0x02, 0xc8, 0x16, 0xe9, 0xc8, 0x14, 0xb4,
+	0xae, 0xc1, 0xb6, 0xb2, 0x45, 0x1a, 0x95, 0x45, 0xdb, 0x86, 0xcc, 0x63, 0xe8, 0xae, 0x22, 0x79,
+	0x1d, 0x23, 0x2b, 0xf7, 0xe6, 0x19, 0x74, 0x22, 0x49, 0xb8, 0x0c, 0x71, 0x92, 0x08, 0x89, 0xfc,
+	0x9a, 0xae, 0xdc, 0xcc, 0xca, 0xad, 0xc8, 0x2a, 0x98, 0x40, 0xf7, 0x34, 0xc9, 0x12, 0x31, 0xdd,
+	0xc2, 0xe5, 0x21, 0x34, 0x90, 0x73, 0xc6, 0x0b, 0x5c, 0x5a, 0xd8, 0x92, 0xbe, 0xe0, 0x0b, 0xd8,
+	0x29, 0xa6, 0xcc, 0x0d, 0x68, 0x72, 0x14, 0x0b, 0x2a, 0x4d, 0x21, 0x8d, 0x54, 0xed, 0x3f, 0xb8,
+	0x0f, 0x3d, 0x9d, 0xf3, 0x89, 0x10, 0xc9, 0x24, 0x53, 0x2d, 0x5d, 0x95, 0x9c, 0x53, 0x15, 0xf3,
+	0x19, 0x1c, 0xe4, 0xc9, 0xd9, 0x67, 0xcb, 0x28, 0xce, 0xf5, 0x59, 0x54, 0xd2, 0xf5, 0xa7, 0x0b,
+	0xef, 0x9c, 0x92, 0x84, 0x62, 0xfc, 0x9c, 0xd9, 0x4e, 0x7f, 0x84, 0x8e, 0xd0, 0x57, 0x6e, 0x24,
+	0xd4, 0x38, 0x53, 0xd4, 0xa9, 0x36, 0xfc, 0xc8, 0xb4, 0x61, 0xc5, 0x11, 0x7b, 0xcc, 0x9b, 0x9e,
+	0xdc, 0x13, 0x96, 0x6a, 0xad, 0xdf, 0xdc, 0xf5, 0x7e, 0x7b, 0x1f, 0xf6, 0xd4, 0x6b, 0x8e, 0x73,
+	0x9a, 0x8c, 0x89, 0xd0, 0xa4, 0xd7, 0xc3, 0x76, 0xb6, 0x48, 0x43, 0xa3, 0xf2, 0xee, 0x40, 0x67,
+	0x91, 0x09, 0x22, 0x13, 0x71, 0x9e, 0x90, 0x33, 0x8a, 0xc5, 0x18, 0x59, 0x51, 0x1e, 0x45, 0x70,
+	0xb0, 0x01, 0xa5, 0x62, 0x98, 0x0f, 0xec, 0xa6, 0x6c, 0xdf, 0xf3, 0x36, 0x97, 0x95, 0xdd, 0xa8,
+	0x29, 0x74, 0x23, 0x94, 0xd6, 0x4b, 0xef, 0x53, 0x68, 0x5b, 0xe9, 0xf9, 0xce, 0x95, 0x5e, 0x6c,
+	0xb3, 0x6d, 0xcb, 0xf2, 0x03, 0xf4, 0x22, 0x94, 0xab, 0x1b, 0xe5, 0x01, 0x74, 0xce, 0x6d, 0x85,
+	0x09, 0x79, 0x58, 0x94, 0xc4, 0x7e, 0x17, 0xae, 0x9a, 0x06, 0xbf, 0x40, 0xe7, 0x24, 0x8e, 0xad,
+	0xe9, 0xff, 0x31, 0x80, 0x28, 0x25, 0xe3, 0xe9, 0x60, 0x63, 0xd6, 0x86, 0x96, 0xd1, 0x15, 0xdd,
+	0xfc, 0x2b, 0xf4, 0x42, 0x4c, 0xd9, 0x05, 0xbe, 0x0d, 0xe7, 0xdf, 0x42, 0xa7, 0x64, 0xbd, 0xc2,
+	0xb3, 0xbb, 0x85, 0xe7, 0xe0, 0x31, 0xf4, 0x1e, 0x21, 0x45, 0x89, 0xff, 0xcf, 0xcd, 0xd7, 0xb0,
+	0x17, 0xa1, 0x5c, 0x4e, 0xef, 0xa1, 0x3d, 0xa3, 0xf3, 0x14, 0x7b, 0xeb, 0x33, 0xda, 0x1a, 0xca,
+	0xc1, 0x2b, 0x80, 0x27, 0xe5, 0x79, 0x95, 0xae, 0xb6, 0x35, 0x53, 0x32, 0x17, 0xae, 0x59, 0xbc,
+	0xcb, 0x09, 0x53, 0x33, 0x9f, 0x2c, 0x5a, 0x52, 0x9f, 0x02, 0x6c, 0xa6, 0xaf, 0xc1, 0x6e, 0xe8,
+	0xb2, 0xd9, 0x92, 0xc6, 0x86, 0x4d, 0xe3, 0x5f, 0x0e, 0x1c, 0x3c, 0x41, 0xa9, 0x2f, 0xda, 0x73,
+	0x76, 0xb2, 0xb9, 0xe6, 0xd7, 0xf6, 0xcf, 0x71, 0x19, 0x2d, 0x5f, 0x3e, 0x77, 0x4c, 0x62, 0x1b,
+	0x3e, 0x86, 0xa1, 0x36, 0x33, 0xdb, 0x7e, 0x7d, 0xea, 0xd5, 0x2c, 0x0c, 0x6a, 0xe9, 0x5a, 0xc6,
+	0xff, 0x69, 0x49, 0x70, 0xd8, 0x7b, 0xc8, 0x38, 0x5f, 0xcc, 0xe5, 0x55, 0x77, 0xd9, 0x87, 0x9d,
+	0x39, 0xb9, 0xa4, 0x8c, 0x14, 0xd7, 0xa9, 0x10, 0xab, 0xc1, 0x78, 0x7d, 0x68, 0xbf, 0x58, 0x10,
+	0x4e, 0x32, 0x99, 0x64, 0x18, 0x1b, 0xfe, 0x6c, 0x55, 0xc0, 0x00, 0x22, 0x49, 0x28, 0xfe, 0x4c,
+	0xe4, 0x78, 0xaa, 0xbc, 0xbc, 0x54, 0x0f, 0xc5, 0x88, 0xd5, 0x42, 0x81, 0xc3, 0x5d, 0xe2, 0xb8,
+	0x0d, 0x6d, 0x4a, 0x26, 0x23, 0x81, 0x63, 0x96, 0xc5, 0xc2, 0x7c, 0xbd, 0x01, 0x25, 0x93, 0x28,
+	0xd7, 0x28, 0xa0, 0x8a, 0xa5, 0xb4, 0x0c, 0x5a, 0x88, 0xc1, 0x09, 0xec, 0xe7, 0x6d, 0xfa, 0xe6,
+	0x2d, 0xf6, 0x9b, 0x03, 0xdd, 0x48, 0x2e, 0xc6, 0x33, 0x5d, 0xa4, 0x87, 0x84, 0x52, 0xd5, 0x37,
+	0x29, 0xca, 0x29, 0x2b, 0xf6, 0x89, 0x91, 0x96, 0xfd, 0xe7, 0xda, 0xfd, 0xf7, 0x21, 0xec, 0x23,
+	0x25, 0x73, 0x81, 0xf1, 0x5a, 0x0a, 0x5d, 0xa3, 0x2e, 0xd2, 0xb8, 0x09, 0x2d, 0x72, 0x46, 0xb2,
+	0x98, 0x2d, 0xd9, 0x5b, 0x2a, 0x82, 0x17, 0xe0, 0x3d, 0xc2, 0x73, 0xe4, 0x1c, 0x63, 0x8d, 0xe4,
+	0x7b, 0x76, 0x71, 0xed, 0xe7, 0x8e, 0xfa, 0xce, 0x37, 0xf6, 0x06, 0x4f, 0x29, 0x6f, 0xb9, 0x8c,
+	0xcf, 0x9a, 0xfa, 0x3f, 0xcf, 0x27, 0xff, 0x0e, 0x00, 0x05, 0x5b, 0xea, 0xcf, 0x13, 0x0d, 0x00,
+	0x00,
 }
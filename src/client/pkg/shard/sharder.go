@@ -3,41 +3,229 @@ package shard
 import (
 	"fmt"
 	"math"
+	"math/rand"
 	"path"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/golang/protobuf/jsonpb"
 	"github.com/pachyderm/pachyderm/src/client/pkg/discovery"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errorutil"
+	"github.com/pachyderm/pachyderm/src/client/pkg/loglevel"
+	"github.com/pachyderm/pachyderm/src/client/pkg/uuid"
 	"go.pedge.io/lion/proto"
+	"golang.org/x/net/context"
 )
 
 const InvalidVersion int64 = -1
 
 var (
-	holdTTL      uint64 = 20
-	marshaler           = &jsonpb.Marshaler{}
-	ErrCancelled        = fmt.Errorf("cancelled by user")
-	errComplete         = fmt.Errorf("COMPLETE")
+	// defaultHoldTTL is how long, in seconds, server and frontend states
+	// and the AssignRoles lock outlive their last refresh.
+	defaultHoldTTL uint64 = 20
+	// defaultAnnounceJitter spreads announcements by ±20% of their interval.
+	defaultAnnounceJitter = 0.2
+	ErrCancelled          = errorutil.New(errorutil.Cancelled, "cancelled by user")
+	errComplete           = fmt.Errorf("COMPLETE")
+	// maxCorruptPayload is how much of a corrupt entry is logged.
+	maxCorruptPayload = 256
+	// defaultAnnounceRateLimit allows announcing each state 5 times a
+	// second, far more than the announce interval and version changes need.
+	defaultAnnounceRateLimit = discovery.RateLimit{Rate: 5, Burst: 5}
+	// defaultShardCallTimeout is how long a call to a server's AddShard,
+	// DeleteShard or SplitShard runs before it's reported as stuck.
+	defaultShardCallTimeout = time.Minute
+	// defaultAddressRetention keeps only the latest version's addresses
+	// once no older version is in use.
+	defaultAddressRetention = 1
+	// ErrNoVersion is returned by GetVersion when no roles have been
+	// assigned yet.
+	ErrNoVersion = errorutil.New(errorutil.NotFound, "no roles have been assigned")
+	// ErrInvalidVersion is returned when addresses are looked up at
+	// InvalidVersion, which frontends see until roles are first assigned,
+	// so it's Unavailable: the lookup can be retried at a real version.
+	ErrInvalidVersion = errorutil.New(errorutil.Unavailable, "no addresses for invalid version")
+	// errDrained is returned by fillRoles once a draining server has
+	// handed off all of its shards.
+	errDrained = fmt.Errorf("DRAINED")
 )
 
 type sharder struct {
 	discoveryClient discovery.Client
 	numShards       uint64
 	namespace       string
-	addresses       map[int64]*Addresses
-	addressesLock   sync.RWMutex
+	// holdTTL is how long, in seconds, announced states and the
+	// AssignRoles lock outlive their last refresh.
+	holdTTL       uint64
+	addresses     *addressesCache
+	addressesLock sync.Mutex
+	debug         *debugRecorder
+	lifecycle     *Lifecycle
+	// announceInterval is how often servers and frontends refresh their
+	// state, announceJitter is the fraction of it that's randomized.
+	announceInterval time.Duration
+	announceJitter   float64
+	repairCorrupt    bool
+	encoding         Encoding
+	// compressionThreshold is the size above which values are compressed
+	compressionThreshold int
+	// now is the sharder's clock, tests replace it.
+	now func() time.Time
+	// announceClient is discoveryClient limited to announceRateLimit
+	// writes per key, it's used to refresh states so that a process stuck
+	// in a loop can't flood discovery.
+	announceClient    discovery.Client
+	announceRateLimit discovery.RateLimit
+	// A watch of the server states which has been quiet for
+	// watchStaleAfter is reported as stale, and re-established after
+	// watchResumeAfter if that's set.
+	watchStaleAfter  time.Duration
+	watchResumeAfter time.Duration
+	// constraints restrict which servers AssignRoles places shards on
+	constraints []Constraint
+	// rebalanceThreshold is the fraction of servers which must change
+	// before AssignRoles reassigns shards.
+	rebalanceThreshold float64
+	// currentVersion is cached by GetCurrentVersion until
+	// currentVersionExpires, or until WatchVersion sees a newer version.
+	currentVersionLock    sync.Mutex
+	currentVersion        int64
+	currentVersionExpires time.Time
+	// registrations holds the servers registered by this sharder, by
+	// address, so that Deregister can drain them.
+	registrationsLock sync.Mutex
+	registrations     map[string]*registration
+	// metrics is nil unless WithMetrics is used
+	metrics *Metrics
+	// rackAware adds SpreadByRack to the constraints
+	rackAware bool
+	// addressRetention is how many of the latest versions' addresses are
+	// kept once no server or frontend is using them.
+	addressRetention int
+	// strategy is how AssignRoles chooses servers for shards
+	strategy AssignmentStrategy
+	// Calls to servers which are still running after shardCallTimeout are
+	// logged, and again every shardCallTimeout, or if failStuckShardCalls
+	// is set they fail.
+	shardCallTimeout    time.Duration
+	failStuckShardCalls bool
+	// assignRolesDebounce is how long AssignRoles waits for the server
+	// states to stop changing before assigning roles, 0 doesn't wait.
+	assignRolesDebounce time.Duration
+	// maxShardMoves is the most shards a version moves between servers
+	// that are still there, 0 is no limit.
+	maxShardMoves int
 }
 
-func newSharder(discoveryClient discovery.Client, numShards uint64, namespace string) *sharder {
-	return &sharder{discoveryClient, numShards, namespace, make(map[int64]*Addresses), sync.RWMutex{}}
+// registration is a server registered with Register.
+type registration struct {
+	// drain is closed to make the server hand off its shards, done is
+	// closed when Register returns.
+	drain     chan struct{}
+	drainOnce sync.Once
+	done      chan struct{}
+}
+
+// newValidSharder returns a sharder which keeps its state under its
+// namespace in discoveryClient, or an error if the namespace is invalid.
+func newValidSharder(discoveryClient discovery.Client, numShards uint64, options ...SharderOption) (*sharder, error) {
+	result := newNamespacedSharder(discoveryClient, numShards, options...)
+	namespace, err := normalizeNamespace(result.namespace)
+	if err != nil {
+		return nil, err
+	}
+	result.namespace = namespace
+	result.setDiscoveryClient(discovery.NewNamespacedClient(discoveryClient, path.Join(namespace, "pfs", "route")))
+	return result, nil
+}
+
+// newSharder is newValidSharder for namespaces known to be valid.
+func newSharder(discoveryClient discovery.Client, numShards uint64, options ...SharderOption) *sharder {
+	result, err := newValidSharder(discoveryClient, numShards, options...)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// normalizeNamespace strips namespace's trailing slashes, so that "prod" and
+// "prod/" are the same namespace, and rejects namespaces with empty, "." or
+// ".." segments, which would put the sharder's state somewhere else.
+func normalizeNamespace(namespace string) (string, error) {
+	if namespace == "" {
+		return "", nil
+	}
+	result := strings.TrimRight(namespace, "/")
+	for _, segment := range strings.Split(result, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", errorutil.New(errorutil.Internal, "invalid namespace %q", namespace)
+		}
+	}
+	return result, nil
+}
+
+// Namespace returns the namespace the sharder keeps its state under.
+func (a *sharder) Namespace() string {
+	return a.namespace
+}
+
+// newNamespacedSharder returns a sharder which keeps its state directly
+// under discoveryClient, which should already be namespaced.
+func newNamespacedSharder(discoveryClient discovery.Client, numShards uint64, options ...SharderOption) *sharder {
+	result := &sharder{
+		numShards:            numShards,
+		holdTTL:              defaultHoldTTL,
+		addresses:            newAddressesCache(defaultAddressCacheSize),
+		debug:                newDebugRecorder(),
+		announceJitter:       defaultAnnounceJitter,
+		encoding:             JSONEncoding,
+		compressionThreshold: defaultCompressionThreshold,
+		now:                  time.Now,
+		announceRateLimit:    defaultAnnounceRateLimit,
+		currentVersion:       InvalidVersion,
+		registrations:        make(map[string]*registration),
+		addressRetention:     defaultAddressRetention,
+		strategy:             StrategyGreedy,
+		shardCallTimeout:     defaultShardCallTimeout,
+	}
+	for _, option := range options {
+		option(result)
+	}
+	if result.rackAware {
+		result.constraints = append(append([]Constraint(nil), result.constraints...), Constraint{kind: spreadByRack})
+	}
+	if result.announceInterval == 0 {
+		result.announceInterval = result.holdTTLDuration() / 2
+	}
+	if result.watchStaleAfter == 0 {
+		result.watchStaleAfter = result.holdTTLDuration()
+	}
+	result.setDiscoveryClient(discoveryClient)
+	return result
+}
+
+// setDiscoveryClient makes the sharder keep its state in discoveryClient.
+func (a *sharder) setDiscoveryClient(discoveryClient discovery.Client) {
+	a.discoveryClient = discoveryClient
+	a.announceClient = discovery.NewRateLimitedClient(discoveryClient, discovery.RateLimitPolicy{
+		Limits:     map[discovery.Op]discovery.RateLimit{discovery.OpSet: a.announceRateLimit},
+		PerKey:     true,
+		Block:      true,
+		OnThrottle: a.debug.observeThrottle,
+	})
+}
+
+func (a *sharder) holdTTLDuration() time.Duration {
+	return time.Second * time.Duration(a.holdTTL)
 }
 
 func (a *sharder) GetAddress(shard uint64, version int64) (result string, ok bool, retErr error) {
 	defer func() {
-		protolion.Debug(&GetAddress{shard, version, result, ok, errorToString(retErr)})
+		if loglevel.DebugEnabled() {
+			protolion.Debug(&GetAddress{shard, version, result, ok, errorutil.String(retErr)})
+		}
 	}()
 	addresses, err := a.getAddresses(version)
 	if err != nil {
@@ -52,7 +240,9 @@ func (a *sharder) GetAddress(shard uint64, version int64) (result string, ok boo
 
 func (a *sharder) GetShardToAddress(version int64) (result map[uint64]string, retErr error) {
 	defer func() {
-		protolion.Debug(&GetShardToAddress{version, result, errorToString(retErr)})
+		if loglevel.DebugEnabled() {
+			protolion.Debug(&GetShardToAddress{version, result, errorutil.String(retErr)})
+		}
 	}()
 	addresses, err := a.getAddresses(version)
 	if err != nil {
@@ -65,290 +255,808 @@ func (a *sharder) GetShardToAddress(version int64) (result map[uint64]string, re
 	return _result, nil
 }
 
-func (a *sharder) Register(cancel chan bool, address string, servers []Server) (retErr error) {
-	protolion.Info(&StartRegister{address})
+// GetVersion returns the highest version with published addresses.
+// AssignRoles publishes a version's addresses, in a single write, only once
+// all of its roles have been set, so the version returned is always
+// complete.
+func (a *sharder) GetVersion() (int64, error) {
+	encodedAddresses, err := a.discoveryClient.GetAll(a.addressesDir())
+	if err != nil {
+		return InvalidVersion, err
+	}
+	return a.latestVersion(encodedAddresses)
+}
+
+// WatchVersion calls f with the highest version with published addresses,
+// and again every time a higher one is published, until ctx is done or f
+// returns an error.
+func (a *sharder) WatchVersion(ctx context.Context, f func(version int64) error) error {
+	version := InvalidVersion
+	err := a.discoveryClient.WatchAllCtx(ctx, a.addressesDir(),
+		func(encodedAddresses map[string]string) error {
+			// AssignRoles deletes the versions nobody's using anymore,
+			// so we can stop caching them.
+			if oldest, ok := oldestVersion(encodedAddresses); ok {
+				a.uncacheAddresses(oldest)
+			}
+			latest, err := a.latestVersion(encodedAddresses)
+			if err != nil || latest <= version {
+				// Nothing has been published yet, or nothing new
+				return nil
+			}
+			version = latest
+			a.cacheCurrentVersion(version)
+			return f(version)
+		})
+	if ctx.Err() != nil {
+		return ErrCancelled
+	}
+	return err
+}
+
+// GetCurrentVersion is like GetVersion, except that the version is cached
+// for holdTTL seconds, or until a WatchVersion on this sharder sees a newer
+// one, so it's cheap enough to call before every lookup.
+func (a *sharder) GetCurrentVersion(ctx context.Context) (int64, error) {
+	a.currentVersionLock.Lock()
+	version, expires := a.currentVersion, a.currentVersionExpires
+	a.currentVersionLock.Unlock()
+	if version != InvalidVersion && a.now().Before(expires) {
+		return version, nil
+	}
+	encodedAddresses, err := a.discoveryClient.GetAllCtx(ctx, a.addressesDir())
+	if err != nil {
+		return InvalidVersion, err
+	}
+	version, err = a.latestVersion(encodedAddresses)
+	if err != nil {
+		return InvalidVersion, err
+	}
+	a.cacheCurrentVersion(version)
+	return version, nil
+}
+
+// GetAddressCurrent is GetAddress at the current version.
+func (a *sharder) GetAddressCurrent(ctx context.Context, shard uint64) (string, bool, error) {
+	version, err := a.GetCurrentVersion(ctx)
+	if err != nil {
+		return "", false, err
+	}
+	return a.GetAddress(shard, version)
+}
+
+// cacheCurrentVersion caches version for GetCurrentVersion, unless a newer
+// version is already cached.
+func (a *sharder) cacheCurrentVersion(version int64) {
+	a.currentVersionLock.Lock()
+	defer a.currentVersionLock.Unlock()
+	if version < a.currentVersion {
+		return
+	}
+	a.currentVersion = version
+	a.currentVersionExpires = a.now().Add(a.holdTTLDuration())
+}
+
+func (a *sharder) latestVersion(encodedAddresses map[string]string) (int64, error) {
+	version := InvalidVersion
+	for key, encoded := range encodedAddresses {
+		var addresses Addresses
+		if err := decode(encoded, &addresses); err != nil {
+			a.corruptEntry(key, encoded, err)
+			continue
+		}
+		if addresses.Version > version {
+			version = addresses.Version
+		}
+	}
+	if version == InvalidVersion {
+		return InvalidVersion, ErrNoVersion
+	}
+	return version, nil
+}
+
+func (a *sharder) Register(ctx context.Context, address string, servers []Server, options ...RegisterOption) error {
+	return a.register(ctx, address, servers, nil, options...)
+}
+
+func (a *sharder) register(
+	ctx context.Context,
+	address string,
+	servers []Server,
+	events chan<- RegisterEvent,
+	options ...RegisterOption,
+) (retErr error) {
+	correlationID := uuid.NewWithoutDashes()
+	protolion.Info(&StartRegister{address, correlationID})
 	defer func() {
-		protolion.Info(&FinishRegister{address, errorToString(retErr)})
+		protolion.Info(&FinishRegister{address, errorutil.String(retErr), correlationID})
 	}()
-	var once sync.Once
+	registration := a.addRegistration(address)
+	defer a.removeRegistration(address, registration)
 	versionChan := make(chan int64)
-	internalCancel := make(chan bool)
-	var wg sync.WaitGroup
-	wg.Add(3)
+	err := a.runRegistration(
+		ctx,
+		func(ctx context.Context) error {
+			go a.watchDrainRequest(ctx, address, registration)
+			return a.announceServers(ctx, address, servers, versionChan, registration.drain, events, correlationID, options...)
+		},
+		func(ctx context.Context) error {
+			return a.fillRoles(ctx, address, servers, versionChan, registration.drain, events)
+		},
+	)
+	if err == errDrained {
+		return nil
+	}
+	return err
+}
+
+// Deregister drains the server registered at address by this sharder: it's
+// announced as draining, AssignRoles moves all of its shards to other
+// servers, and once the other servers have added them the server's shards
+// are removed. Its Register call then returns nil, and so does Deregister.
+// If ctx is done first Deregister returns ErrCancelled, and the server keeps
+// draining.
+func (a *sharder) Deregister(ctx context.Context, address string) error {
+	a.registrationsLock.Lock()
+	registration, ok := a.registrations[address]
+	a.registrationsLock.Unlock()
+	if !ok {
+		return errorutil.New(errorutil.NotFound, "no server registered at %s", address)
+	}
+	registration.startDrain()
+	select {
+	case <-registration.done:
+		return nil
+	case <-ctx.Done():
+		return ErrCancelled
+	}
+}
+
+// startDrain makes the registered server hand off its shards, it can be
+// called more than once.
+func (r *registration) startDrain() {
+	r.drainOnce.Do(func() {
+		close(r.drain)
+	})
+}
+
+func (a *sharder) addRegistration(address string) *registration {
+	a.registrationsLock.Lock()
+	defer a.registrationsLock.Unlock()
+	result := &registration{
+		drain: make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	a.registrations[address] = result
+	return result
+}
+
+func (a *sharder) removeRegistration(address string, registration *registration) {
+	a.registrationsLock.Lock()
+	defer a.registrationsLock.Unlock()
+	if a.registrations[address] == registration {
+		delete(a.registrations, address)
+	}
+	close(registration.done)
+}
+
+func (a *sharder) RegisterFrontends(ctx context.Context, address string, frontends []Frontend, options ...FrontendOption) error {
+	versionChan := make(chan int64)
+	return a.runRegistration(
+		ctx,
+		func(ctx context.Context) error {
+			return a.announceFrontends(ctx, address, frontends, versionChan, options...)
+		},
+		func(ctx context.Context) error {
+			return a.runFrontends(ctx, address, frontends, versionChan)
+		},
+	)
+}
+
+// runRegistration runs announce and run until one of them fails or ctx is
+// done, in which case it returns ErrCancelled. If the sharder has a
+// Lifecycle, run is stopped during StopRoleChanges and waited on during
+// FlushRoles, and announce is stopped during Deregister.
+func (a *sharder) runRegistration(
+	ctx context.Context,
+	announce func(ctx context.Context) error,
+	run func(ctx context.Context) error,
+) (retErr error) {
+	var once sync.Once
+	registrationCtx, cancelRegistration := context.WithCancel(ctx)
+	defer cancelRegistration()
+	runCtx, cancelRun := context.WithCancel(registrationCtx)
+	defer cancelRun()
+	stop := func(err error) {
+		once.Do(func() {
+			if ctx.Err() != nil {
+				// announce and run fail when ctx is done, but it's the
+				// caller who stopped them.
+				err = ErrCancelled
+			}
+			retErr = err
+			cancelRegistration()
+		})
+	}
+	announceDone := make(chan struct{})
+	runDone := make(chan struct{})
 	go func() {
-		defer wg.Done()
-		if err := a.announceServers(address, servers, versionChan, internalCancel); err != nil {
-			once.Do(func() {
-				retErr = err
-				close(internalCancel)
-			})
+		defer close(announceDone)
+		if err := announce(registrationCtx); err != nil {
+			stop(err)
 		}
 	}()
 	go func() {
-		defer wg.Done()
-		if err := a.fillRoles(address, servers, versionChan, internalCancel); err != nil {
-			once.Do(func() {
-				retErr = err
-				close(internalCancel)
-			})
+		defer close(runDone)
+		if err := run(runCtx); err != nil {
+			stop(err)
 		}
 	}()
-	go func() {
-		defer wg.Done()
-		select {
-		case <-cancel:
+	if a.lifecycle != nil {
+		defer a.lifecycle.OnShutdown(StopRoleChanges, func(context.Context) error {
+			// Setting retErr first means run returning because it's
+			// cancelled doesn't stop announce.
 			once.Do(func() {
 				retErr = ErrCancelled
-				close(internalCancel)
 			})
-		case <-internalCancel:
-		}
-	}()
-	wg.Wait()
+			cancelRun()
+			return nil
+		})()
+		defer a.lifecycle.OnShutdown(FlushRoles, func(shutdownCtx context.Context) error {
+			return waitOrDone(shutdownCtx, runDone)
+		})()
+		defer a.lifecycle.OnShutdown(Deregister, func(shutdownCtx context.Context) error {
+			stop(ErrCancelled)
+			// stop does nothing once retErr is set, for example by
+			// StopRoleChanges, but announce must stop regardless.
+			cancelRegistration()
+			return waitOrDone(shutdownCtx, announceDone)
+		})()
+	}
+	<-registrationCtx.Done()
+	stop(ErrCancelled)
+	<-announceDone
+	<-runDone
 	return
 }
 
-func (a *sharder) RegisterFrontends(cancel chan bool, address string, frontends []Frontend) (retErr error) {
-	var once sync.Once
-	versionChan := make(chan int64)
-	internalCancel := make(chan bool)
-	var wg sync.WaitGroup
-	wg.Add(3)
-	go func() {
-		defer wg.Done()
-		if err := a.announceFrontends(address, frontends, versionChan, internalCancel); err != nil {
-			once.Do(func() {
-				retErr = err
-				close(internalCancel)
-			})
-		}
-	}()
-	go func() {
-		defer wg.Done()
-		if err := a.runFrontends(address, frontends, versionChan, internalCancel); err != nil {
+// waitOrDone waits for done to be closed, or ctx to be done.
+func waitOrDone(ctx context.Context, done chan struct{}) error {
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// AssignRoles competes for the controller lock as address and, while it
+// holds it, assigns roles to the registered servers. It runs until ctx is
+// done, in which case it returns ErrCancelled.
+func (a *sharder) AssignRoles(ctx context.Context, address string) (retErr error) {
+	var stop chan struct{}
+	if a.lifecycle != nil {
+		stop = make(chan struct{})
+		finished := make(chan struct{})
+		defer close(finished)
+		var once sync.Once
+		defer a.lifecycle.OnShutdown(StopController, func(shutdownCtx context.Context) error {
 			once.Do(func() {
-				retErr = err
-				close(internalCancel)
+				close(stop)
 			})
-		}
-	}()
+			return waitOrDone(shutdownCtx, finished)
+		})()
+	}
+	ctx, cancelCtx := context.WithCancel(ctx)
+	defer cancelCtx()
 	go func() {
-		defer wg.Done()
 		select {
-		case <-cancel:
-			once.Do(func() {
-				retErr = ErrCancelled
-				close(internalCancel)
-			})
-		case <-internalCancel:
+		case <-stop:
+		case <-ctx.Done():
 		}
+		cancelCtx()
 	}()
-	wg.Wait()
-	return
-}
-
-func (a *sharder) AssignRoles(address string, cancel chan bool) (retErr error) {
-	var unsafeAssignRolesCancel chan bool
-	errChan := make(chan error)
-	// oldValue is the last value we wrote, if it's not "" it means we have the
-	// lock since we're the ones who set it last
-	oldValue := ""
 	for {
-		if err := a.discoveryClient.CheckAndSet("lock", address, holdTTL, oldValue); err != nil {
-			if oldValue != "" {
-				// lock lost
-				oldValue = ""
-				close(unsafeAssignRolesCancel)
-				protolion.Errorf("sharder.AssignRoles error from unsafeAssignRolesCancel: %+v", <-errChan)
-			}
-		} else {
-			if oldValue == "" {
-				// lock acquired
-				oldValue = address
-				unsafeAssignRolesCancel = make(chan bool)
-				go func() {
-					errChan <- a.unsafeAssignRoles(unsafeAssignRolesCancel)
-				}()
-			}
+		lease, err := discovery.AcquireLease(ctx, a.discoveryClient, a.lockKey(), address, a.holdTTL, discovery.LeaseOptions{Block: true})
+		if err != nil {
+			// A blocking AcquireLease only fails when ctx is done
+			return ErrCancelled
 		}
+		unsafeAssignRolesCtx, unsafeAssignRolesCancel := context.WithCancel(context.Background())
+		errChan := make(chan error, 1)
+		go func() {
+			errChan <- a.unsafeAssignRoles(unsafeAssignRolesCtx)
+		}()
 		select {
-		case <-cancel:
-			if oldValue != "" {
-				close(unsafeAssignRolesCancel)
-				return <-errChan
+		case <-lease.Lost():
+			unsafeAssignRolesCancel()
+			protolion.Errorf("sharder.AssignRoles error from unsafeAssignRolesCancel: %+v", <-errChan)
+		case <-ctx.Done():
+			// Stop assigning roles and release the lock so that another
+			// controller can take over without waiting for it to expire.
+			unsafeAssignRolesCancel()
+			err := <-errChan
+			if err := lease.Release(); err != nil {
+				protolion.Errorf("sharder.AssignRoles error releasing lock: %s", err.Error())
 			}
-		case <-time.After(time.Second * time.Duration(holdTTL/2)):
+			return err
 		}
 	}
 }
 
-// unsafeAssignRoles should be run
-func (a *sharder) unsafeAssignRoles(cancel chan bool) (retErr error) {
-	protolion.Info(&StartAssignRoles{})
-	defer func() {
-		protolion.Info(&FinishAssignRoles{errorToString(retErr)})
-	}()
+// AssignRolesOnce returns the roles AssignRoles would assign to the servers
+// described by serverStates, keyed by address, and the addresses it would
+// publish, without writing anything. The plan starts from the latest roles
+// in discovery, so it shows how shards would move if the servers changed to
+// serverStates.
+func (a *sharder) AssignRolesOnce(ctx context.Context, serverStates map[string]*ServerState) (*Addresses, map[string]*ServerRole, error) {
+	version, oldRoles, err := a.latestRoles(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(serverStates) == 0 {
+		return nil, nil, errorutil.New(errorutil.NotFound, "no servers to assign roles to")
+	}
+	numShards, err := a.currentNumShards()
+	if err != nil {
+		return nil, nil, err
+	}
+	oldShards := rolesToShards(oldRoles)
+	addresses, roles, err := planRoles(numShards, a.strategy, a.constraints, serverStates, oldShards, version, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	addresses, roles, _ = limitMoves(a.maxShardMoves, addresses, roles, oldShards, activeServers(serverStates))
+	return addresses, roles, nil
+}
+
+// latestRoles returns the latest role of every server in discovery, keyed by
+// address, and the version that comes after them.
+func (a *sharder) latestRoles(ctx context.Context) (int64, map[string]*ServerRole, error) {
 	var version int64
-	oldServers := make(map[string]bool)
-	oldRoles := make(map[string]*ServerRole)
-	oldShards := make(map[uint64]string)
-	var oldMinVersion int64
-	// Reconstruct state from a previous run
-	serverRoles, err := a.discoveryClient.GetAll(a.serverRoleDir())
+	roles := make(map[string]*ServerRole)
+	encodedServerRoles, err := a.discoveryClient.GetAllCtx(ctx, a.serverRoleDir())
 	if err != nil {
-		return err
+		return 0, nil, err
 	}
-	for _, encodedServerRole := range serverRoles {
+	for key, encodedServerRole := range encodedServerRoles {
 		serverRole, err := decodeServerRole(encodedServerRole)
 		if err != nil {
-			return err
+			a.corruptEntry(key, encodedServerRole, err)
+			continue
 		}
-		if oldServerRole, ok := oldRoles[serverRole.Address]; !ok || oldServerRole.Version < serverRole.Version {
-			oldRoles[serverRole.Address] = serverRole
-			oldServers[serverRole.Address] = true
+		if oldServerRole, ok := roles[serverRole.Address]; !ok || oldServerRole.Version < serverRole.Version {
+			roles[serverRole.Address] = serverRole
 		}
 		if version < serverRole.Version+1 {
 			version = serverRole.Version + 1
 		}
 	}
-	for _, oldServerRole := range oldRoles {
-		for shard := range oldServerRole.Shards {
-			oldShards[shard] = oldServerRole.Address
+	return version, roles, nil
+}
+
+// rolesToShards maps every shard in roles to the address of its server.
+func rolesToShards(roles map[string]*ServerRole) map[uint64]string {
+	result := make(map[uint64]string)
+	for _, serverRole := range roles {
+		for shard := range serverRole.Shards {
+			result[shard] = serverRole.Address
 		}
 	}
-	err = a.discoveryClient.WatchAll(a.serverStateDir(), cancel,
-		func(encodedServerStates map[string]string) error {
-			if len(encodedServerStates) == 0 {
-				return nil
-			}
-			newServerStates := make(map[string]*ServerState)
-			newRoles := make(map[string]*ServerRole)
-			newShards := make(map[uint64]string)
-			shardsPerServer := a.numShards / uint64(len(encodedServerStates))
-			shardsRemainder := a.numShards % uint64(len(encodedServerStates))
-			for _, encodedServerState := range encodedServerStates {
-				serverState, err := decodeServerState(encodedServerState)
-				if err != nil {
-					return err
-				}
-				newServerStates[serverState.Address] = serverState
-				newRoles[serverState.Address] = &ServerRole{
-					Address: serverState.Address,
-					Version: version,
-					Shards:  make(map[uint64]bool),
+	return result
+}
+
+// planRoles assigns numShards shards to the servers described by
+// serverStates, keyed by address, as version, following strategy. With
+// StrategyGreedy shards stay where oldShards says they are when that's
+// allowed, so that as few as possible move. It
+// returns the roles of every server, keyed by address, and the resulting
+// addresses. If no server satisfies the hard constraints the error is a
+// *ConstraintError.
+func planRoles(
+	numShards uint64,
+	strategy AssignmentStrategy,
+	constraints []Constraint,
+	serverStates map[string]*ServerState,
+	oldShards map[uint64]string,
+	version int64,
+	correlationID string,
+) (*Addresses, map[string]*ServerRole, error) {
+	roles := make(map[string]*ServerRole)
+	for address := range serverStates {
+		roles[address] = &ServerRole{
+			Address:       address,
+			Version:       version,
+			Shards:        make(map[uint64]bool),
+			CorrelationId: correlationID,
+			SchemaVersion: SchemaVersion,
+		}
+	}
+	// Only the servers which satisfy the hard constraints get shards, so
+	// they're what the shards are divided between.
+	placement := newPlacement(constraints, serverStates)
+	eligible, err := placement.eligible()
+	if err != nil {
+		return nil, nil, err
+	}
+	// Draining servers get no shards, unless every server is draining, in
+	// which case the shards are better where they are than unassigned.
+	var undrained []string
+	for _, address := range eligible {
+		if !serverStates[address].Draining {
+			undrained = append(undrained, address)
+		}
+	}
+	if len(undrained) > 0 {
+		eligible = undrained
+	}
+	eligibleServers := make(map[string]bool)
+	for _, address := range eligible {
+		eligibleServers[address] = true
+	}
+	shardsPerServer, shardsRemainder := shardQuotas(numShards, eligible, serverStates)
+	shards := make(map[uint64]string)
+Shard:
+	for shard := uint64(0); shard < numShards; shard++ {
+		var candidates []string
+		switch strategy {
+		case StrategyRendezvous:
+			candidates = rendezvousCandidates(shard, eligible)
+		default:
+			if address, ok := oldShards[shard]; ok && eligibleServers[address] {
+				if assignShard(roles, shards, address, shard, shardsPerServer[address], &shardsRemainder) {
+					placement.place(address)
+					continue Shard
 				}
 			}
-			// See if there's any roles we can delete
-			minVersion := int64(math.MaxInt64)
-			for _, serverState := range newServerStates {
-				if serverState.Version < minVersion {
-					minVersion = serverState.Version
-				}
+			candidates = placement.candidates(eligible)
+		}
+		for _, address := range candidates {
+			if assignShard(roles, shards, address, shard, shardsPerServer[address], &shardsRemainder) {
+				placement.place(address)
+				continue Shard
 			}
-			// Delete roles that no servers are using anymore
-			if minVersion > oldMinVersion {
-				oldMinVersion = minVersion
-				if err := a.discoveryClient.WatchAll(
-					a.frontendStateDir(),
-					cancel,
-					func(encodedFrontendStates map[string]string) error {
-						for _, encodedFrontendState := range encodedFrontendStates {
-							frontendState, err := decodeFrontendState(encodedFrontendState)
-							if err != nil {
-								return err
-							}
-							if frontendState.Version < minVersion {
-								return nil
-							}
+		}
+		return nil, nil, errorutil.New(errorutil.Internal, "could not assign shard").WithShard(shard)
+	}
+	addresses := &Addresses{
+		Version:       version,
+		Addresses:     shards,
+		CorrelationId: correlationID,
+		NumShards:     numShards,
+		SchemaVersion: SchemaVersion,
+	}
+	return addresses, roles, nil
+}
+
+// unsafeAssignRoles should be run
+func (a *sharder) unsafeAssignRoles(ctx context.Context) (retErr error) {
+	correlationID := uuid.NewWithoutDashes()
+	protolion.Info(&StartAssignRoles{correlationID})
+	defer func() {
+		protolion.Info(&FinishAssignRoles{errorutil.String(retErr), correlationID})
+	}()
+	var oldMinVersion int64
+	// Reconstruct state from a previous run
+	version, oldRoles, err := a.latestRoles(ctx)
+	if err != nil {
+		return err
+	}
+	oldServers := make(map[string]bool)
+	for address := range oldRoles {
+		oldServers[address] = true
+	}
+	oldShards := rolesToShards(oldRoles)
+	// deferredMoves is how many of the last version's moves were left for
+	// later by maxShardMoves.
+	deferredMoves := 0
+	numShards, err := a.currentNumShards()
+	if err != nil {
+		return err
+	}
+	assignRoles := func(serverStates serverStateCache) error {
+		if len(serverStates) == 0 {
+			return nil
+		}
+		// Everything logged about this version, by us and by the
+		// servers that pick up its roles, carries versionCorrelationID.
+		versionCorrelationID := fmt.Sprintf("%s-%d", correlationID, version)
+		newServerStates := make(map[string]*ServerState)
+		for _, serverState := range serverStates {
+			newServerStates[serverState.Address] = serverState
+		}
+		// See if there's any roles we can delete
+		minVersion := int64(math.MaxInt64)
+		for _, serverState := range newServerStates {
+			if serverState.Version < minVersion {
+				minVersion = serverState.Version
+			}
+		}
+		// Delete roles that no servers are using anymore
+		if minVersion > oldMinVersion {
+			oldMinVersion = minVersion
+			if err := a.discoveryClient.WatchAllCtx(
+				ctx,
+				a.frontendStateDir(),
+				func(encodedFrontendStates map[string]string) error {
+					for key, encodedFrontendState := range encodedFrontendStates {
+						frontendState, err := decodeFrontendState(encodedFrontendState)
+						if err != nil {
+							a.corruptEntry(key, encodedFrontendState, err)
+							continue
 						}
-						return errComplete
-					}); err != nil && err != errComplete {
-					return err
-				}
-				serverRoles, err := a.discoveryClient.GetAll(a.serverRoleDir())
-				if err != nil {
-					return err
-				}
-				for key, encodedServerRole := range serverRoles {
-					serverRole, err := decodeServerRole(encodedServerRole)
-					if err != nil {
-						return err
-					}
-					if serverRole.Version < minVersion {
-						if err := a.discoveryClient.Delete(key); err != nil {
-							return err
+						if frontendState.Version < minVersion {
+							return nil
 						}
-						protolion.Info(&DeleteServerRole{serverRole})
 					}
-				}
+					return errComplete
+				}); err != nil && err != errComplete {
+				return err
 			}
-			// if the servers are identical to last time then we know we'll
-			// assign shards the same way
-			if sameServers(oldServers, newServerStates) {
-				return nil
+			serverRoles, err := a.discoveryClient.GetAllCtx(ctx, a.serverRoleDir())
+			if err != nil {
+				return err
 			}
-		Shard:
-			for shard := uint64(0); shard < a.numShards; shard++ {
-				if address, ok := oldShards[shard]; ok {
-					if assignShard(newRoles, newShards, address, shard, shardsPerServer, &shardsRemainder) {
-						continue Shard
-					}
+			for key, encodedServerRole := range serverRoles {
+				serverRole, err := decodeServerRole(encodedServerRole)
+				if err != nil {
+					a.corruptEntry(key, encodedServerRole, err)
+					continue
 				}
-				for address := range newServerStates {
-					if assignShard(newRoles, newShards, address, shard, shardsPerServer, &shardsRemainder) {
-						continue Shard
+				if serverRole.Version < minVersion {
+					if err := a.delete(key); err != nil {
+						return err
 					}
+					protolion.Info(&DeleteServerRole{serverRole})
 				}
-				protolion.Error(&FailedToAssignRoles{
-					ServerStates: newServerStates,
-					NumShards:    a.numShards,
-				})
-				return nil
 			}
-			addresses := Addresses{
-				Version:   version,
-				Addresses: make(map[uint64]string),
+			if err := a.compactAddresses(ctx, minVersion); err != nil {
+				return err
 			}
+		}
+		// Shards are split, as Reshard asked, before they're moved, so
+		// that every shard's server is the one to split it.
+		activeServerStates := activeServers(newServerStates)
+		newNumShards, err := a.requestedNumShards(ctx)
+		if err != nil {
+			return err
+		}
+		if canSplit(numShards, newNumShards, oldShards, activeServerStates) {
+			addresses, newRoles := splitRoles(numShards, newNumShards, newServerStates, oldShards, version, versionCorrelationID)
+			if err := a.publishRoles(addresses, newRoles, time.Now()); err != nil {
+				return err
+			}
+			version++
+			numShards = newNumShards
+			// Servers without shards haven't been given their share,
+			// so they count as new.
+			oldServers = make(map[string]bool)
 			for address, serverRole := range newRoles {
-				encodedServerRole, err := marshaler.MarshalToString(serverRole)
-				if err != nil {
-					return err
-				}
-				if err := a.discoveryClient.Set(a.serverRoleKeyVersion(address, version), encodedServerRole, 0); err != nil {
-					return err
-				}
-				protolion.Info(&SetServerRole{serverRole})
-				address := newServerStates[address].Address
-				for shard := range serverRole.Shards {
-					addresses.Addresses[shard] = address
+				if len(serverRole.Shards) > 0 {
+					oldServers[address] = true
 				}
 			}
-			encodedAddresses, err := marshaler.MarshalToString(&addresses)
-			if err != nil {
+			oldShards = rolesToShards(newRoles)
+			return nil
+		}
+		// Then shards are moved, as SwapShardMaster asked.
+		swaps, err := a.requestedSwaps(ctx)
+		if err != nil {
+			return err
+		}
+		if addresses, newRoles, ok := swapRoles(numShards, swaps, newServerStates, activeServerStates, oldShards, version, versionCorrelationID); ok {
+			if err := a.publishRoles(addresses, newRoles, time.Now()); err != nil {
 				return err
 			}
-			if err := a.discoveryClient.Set(a.addressesKey(version), encodedAddresses, 0); err != nil {
+			version++
+			oldShards = rolesToShards(newRoles)
+			// The requests are done with, even if whoever made them
+			// isn't around to delete them.
+			for shard, address := range swaps {
+				if oldShards[shard] == address {
+					a.deleteSwapRequest(shard)
+				}
+			}
+			return nil
+		}
+		// if the servers are identical to last time then we know we'll
+		// assign shards the same way, draining servers count as gone
+		// since they'll have no shards.
+		if deferredMoves == 0 && sameServers(oldServers, activeServerStates) {
+			return nil
+		}
+		// Nor do we reassign them for changes too small to be worth
+		// moving shards for, but servers which are draining are always
+		// let go.
+		if deferredMoves == 0 && len(oldServers) > 0 && !startedDraining(oldServers, newServerStates) &&
+			serverChurn(oldServers, activeServerStates) < a.rebalanceThreshold {
+			a.debug.observeDeferredRebalance()
+			return nil
+		}
+		start := time.Now()
+		addresses, newRoles, err := planRoles(numShards, a.strategy, a.constraints, newServerStates, oldShards, version, versionCorrelationID)
+		if err != nil {
+			a.metrics.observeAssignmentFailure()
+			failedToAssignRoles := &FailedToAssignRoles{
+				ServerStates: newServerStates,
+				NumShards:    numShards,
+			}
+			if _, ok := err.(*ConstraintError); ok {
+				failedToAssignRoles.Unsatisfiable = err.Error()
+				a.debug.observePlacement(err)
+			}
+			protolion.Error(failedToAssignRoles)
+			return nil
+		}
+		a.debug.observePlacement(nil)
+		addresses, newRoles, deferredMoves = limitMoves(a.maxShardMoves, addresses, newRoles, oldShards, activeServerStates)
+		if deferredMoves > 0 {
+			protolion.Info(&DeferredShardMoves{
+				Version:       version,
+				Deferred:      uint64(deferredMoves),
+				CorrelationId: versionCorrelationID,
+			})
+		}
+		if err := a.publishRoles(addresses, newRoles, start); err != nil {
+			return err
+		}
+		version++
+		oldServers = make(map[string]bool)
+		for address := range activeServerStates {
+			oldServers[address] = true
+		}
+		oldShards = rolesToShards(newRoles)
+		return nil
+	}
+	if a.assignRolesDebounce > 0 {
+		watchCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		debouncer := newDebouncer(a.assignRolesDebounce, assignRoles, cancel)
+		err = a.watchServerStates(watchCtx, "AssignRoles", debouncer.call)
+		if debounceErr := debouncer.stop(); debounceErr != nil {
+			err = debounceErr
+		}
+	} else {
+		err = a.watchServerStates(ctx, "AssignRoles", assignRoles)
+	}
+	if err == context.Canceled {
+		return ErrCancelled
+	}
+	return err
+}
+
+// compactAddresses deletes the addresses of the versions before olderThan,
+// except for the latest addressRetention versions. The latest version is
+// always kept, GetVersion must always find it.
+func (a *sharder) compactAddresses(ctx context.Context, olderThan int64) error {
+	encodedAddresses, err := a.discoveryClient.GetAllCtx(ctx, a.addressesDir())
+	if err != nil {
+		return err
+	}
+	latest, err := a.latestVersion(encodedAddresses)
+	if err == ErrNoVersion {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if retained := latest - int64(a.addressRetention) + 1; retained < olderThan {
+		olderThan = retained
+	}
+	for key, encoded := range encodedAddresses {
+		var addresses Addresses
+		if err := decode(encoded, &addresses); err != nil {
+			a.corruptEntry(key, encoded, err)
+			continue
+		}
+		if addresses.Version < olderThan {
+			if err := a.delete(key); err != nil {
 				return err
 			}
-			protolion.Info(&SetAddresses{&addresses})
-			version++
-			oldServers = make(map[string]bool)
-			for address := range newServerStates {
-				oldServers[address] = true
-			}
-			oldRoles = newRoles
-			oldShards = newShards
-			return nil
-		})
-	if err == discovery.ErrCancelled {
-		return ErrCancelled
+			protolion.Info(&DeleteAddresses{&addresses})
+		}
 	}
-	return err
+	a.uncacheAddresses(olderThan)
+	return nil
+}
+
+// GetAddressVersionCount returns how many versions' addresses are in
+// discovery.
+func (a *sharder) GetAddressVersionCount() (int, error) {
+	encodedAddresses, err := a.discoveryClient.GetAll(a.addressesDir())
+	if err != nil {
+		return 0, err
+	}
+	return len(encodedAddresses), nil
+}
+
+// uncacheAddresses removes the versions before version, which have been
+// deleted, from the addresses cache.
+func (a *sharder) uncacheAddresses(version int64) {
+	a.addressesLock.Lock()
+	defer a.addressesLock.Unlock()
+	a.addresses.removeBefore(version)
+}
+
+// oldestVersion returns the lowest version in encodedAddresses, judging by
+// their keys, or false if there are none.
+func oldestVersion(encodedAddresses map[string]string) (int64, bool) {
+	result, ok := int64(math.MaxInt64), false
+	for key := range encodedAddresses {
+		version, err := strconv.ParseInt(path.Base(key), 10, 64)
+		if err != nil {
+			continue
+		}
+		if version < result {
+			result, ok = version, true
+		}
+	}
+	return result, ok
+}
+
+// publishRoles sets roles, keyed by address, and then publishes addresses, all
+// of them are for addresses.Version.
+func (a *sharder) publishRoles(addresses *Addresses, roles map[string]*ServerRole, start time.Time) error {
+	encodedServerRoles := make(map[string]string)
+	for address, serverRole := range roles {
+		encodedServerRole, err := a.encode(serverRole)
+		if err != nil {
+			return err
+		}
+		encodedServerRoles[a.serverRoleKeyVersion(address, addresses.Version)] = encodedServerRole
+	}
+	// The addresses are only published once every role has been set, a
+	// *discovery.SetMultiError tells us which roles weren't.
+	if err := a.setMulti(encodedServerRoles, 0); err != nil {
+		return err
+	}
+	for _, serverRole := range roles {
+		protolion.Info(&SetServerRole{serverRole})
+	}
+	encodedAddresses, err := a.encode(addresses)
+	if err != nil {
+		return err
+	}
+	if err := a.set(a.addressesKey(addresses.Version), encodedAddresses, 0); err != nil {
+		return err
+	}
+	protolion.Info(&SetAddresses{addresses})
+	a.debug.observeReassignment()
+	a.metrics.observeAssignment(addresses.Version, len(addresses.Addresses), start)
+	return nil
 }
 
-func (a *sharder) WaitForAvailability(frontendAddresses []string, serverAddresses []string) error {
+// WaitForAvailability waits until the servers at serverAddresses, and only
+// they, are on the same version, and then until the frontends at
+// frontendAddresses are on it too. If ctx is done first the error says how
+// many servers and frontends were available, lists the servers which hadn't
+// announced their state or had no version and the frontends which weren't on
+// the version, and wraps ctx.Err().
+func (a *sharder) WaitForAvailability(ctx context.Context, frontendAddresses []string, serverAddresses []string) error {
 	version := InvalidVersion
-	if err := a.discoveryClient.WatchAll(a.serverDir(), nil,
+	// What was last observed to be missing, the frontends are only looked
+	// at once the servers have converged.
+	var notAnnounced, noVersion []string
+	notConverged := frontendAddresses
+	unavailable := func(err error) error {
+		if ctx.Err() == nil {
+			return err
+		}
+		details := fmt.Sprintf("%d of %d servers announced and %d of %d frontends on version %d",
+			len(serverAddresses)-len(notAnnounced), len(serverAddresses),
+			len(frontendAddresses)-len(notConverged), len(frontendAddresses), version)
+		if len(notAnnounced) > 0 {
+			details += fmt.Sprintf("; servers not announced: %v", notAnnounced)
+		}
+		if len(noVersion) > 0 {
+			details += fmt.Sprintf("; servers with no version: %v", noVersion)
+		}
+		if len(notConverged) > 0 {
+			details += fmt.Sprintf("; frontends not on version: %v", notConverged)
+		}
+		return errorutil.Wrap(ctx.Err(), "%s", details)
+	}
+	if err := a.discoveryClient.WatchAllCtx(ctx, a.serverDir(),
 		func(encodedServerStatesAndRoles map[string]string) error {
 			serverStates := make(map[string]*ServerState)
 			serverRoles := make(map[string]map[int64]*ServerRole)
@@ -356,14 +1064,16 @@ func (a *sharder) WaitForAvailability(frontendAddresses []string, serverAddresse
 				if strings.HasPrefix(key, a.serverStateDir()) {
 					serverState, err := decodeServerState(encodedServerStateOrRole)
 					if err != nil {
-						return err
+						a.corruptEntry(key, encodedServerStateOrRole, err)
+						continue
 					}
 					serverStates[serverState.Address] = serverState
 				}
 				if strings.HasPrefix(key, a.serverRoleDir()) {
 					serverRole, err := decodeServerRole(encodedServerStateOrRole)
 					if err != nil {
-						return err
+						a.corruptEntry(key, encodedServerStateOrRole, err)
+						continue
 					}
 					if _, ok := serverRoles[serverRole.Address]; !ok {
 						serverRoles[serverRole.Address] = make(map[int64]*ServerRole)
@@ -371,6 +1081,15 @@ func (a *sharder) WaitForAvailability(frontendAddresses []string, serverAddresse
 					serverRoles[serverRole.Address][serverRole.Version] = serverRole
 				}
 			}
+			notAnnounced, noVersion = nil, nil
+			for _, address := range serverAddresses {
+				serverState, ok := serverStates[address]
+				if !ok {
+					notAnnounced = append(notAnnounced, address)
+				} else if serverState.Version == InvalidVersion {
+					noVersion = append(noVersion, address)
+				}
+			}
 			if len(serverStates) != len(serverAddresses) {
 				return nil
 			}
@@ -411,25 +1130,37 @@ func (a *sharder) WaitForAvailability(frontendAddresses []string, serverAddresse
 			}
 			return errComplete
 		}); err != errComplete {
-		return err
+		return unavailable(err)
 	}
 
-	if err := a.discoveryClient.WatchAll(
+	if err := a.discoveryClient.WatchAllCtx(
+		ctx,
 		a.frontendStateDir(),
-		nil,
 		func(encodedFrontendStates map[string]string) error {
 			frontendStates := make(map[string]*FrontendState)
-			for _, encodedFrontendState := range encodedFrontendStates {
+			converged := true
+			for key, encodedFrontendState := range encodedFrontendStates {
 				frontendState, err := decodeFrontendState(encodedFrontendState)
 				if err != nil {
-					return err
+					a.corruptEntry(key, encodedFrontendState, err)
+					continue
 				}
 
 				if frontendState.Version != version {
 					protolion.Printf("Wrong version: %d != %d", frontendState.Version, version)
-					return nil
+					converged = false
+					continue
+				}
+				frontendStates[frontendState.Id] = frontendState
+			}
+			notConverged = nil
+			for _, address := range frontendAddresses {
+				if _, ok := frontendStates[address]; !ok {
+					notConverged = append(notConverged, address)
 				}
-				frontendStates[frontendState.Address] = frontendState
+			}
+			if !converged {
+				return nil
 			}
 			protolion.Printf("frontendStates: %+v", frontendStates)
 			if len(frontendStates) != len(frontendAddresses) {
@@ -442,7 +1173,7 @@ func (a *sharder) WaitForAvailability(frontendAddresses []string, serverAddresse
 			}
 			return errComplete
 		}); err != nil && err != errComplete {
-		return err
+		return unavailable(err)
 	}
 	return nil
 }
@@ -459,6 +1190,10 @@ func newLocalSharder(addresses []string, numShards uint64) *localSharder {
 	return result
 }
 
+func (s *localSharder) Namespace() string {
+	return ""
+}
+
 func (s *localSharder) GetAddress(shard uint64, version int64) (string, bool, error) {
 	address, ok := s.shardToAddress[shard]
 	return address, ok, nil
@@ -468,24 +1203,73 @@ func (s *localSharder) GetShardToAddress(version int64) (map[uint64]string, erro
 	return s.shardToAddress, nil
 }
 
-func (s *localSharder) Register(cancel chan bool, address string, servers []Server) error {
+func (s *localSharder) GetVersion() (int64, error) {
+	return 0, nil
+}
+
+func (s *localSharder) GetCurrentVersion(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (s *localSharder) GetAddressCurrent(ctx context.Context, shard uint64) (string, bool, error) {
+	return s.GetAddress(shard, 0)
+}
+
+func (s *localSharder) WatchVersion(ctx context.Context, f func(version int64) error) error {
+	if err := f(0); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	return ErrCancelled
+}
+
+func (s *localSharder) Register(ctx context.Context, address string, servers []Server, options ...RegisterOption) error {
+	return nil
+}
+
+func (s *localSharder) Deregister(ctx context.Context, address string) error {
 	return nil
 }
 
-func (s *localSharder) RegisterFrontends(cancel chan bool, address string, frontends []Frontend) error {
+func (s *localSharder) RegisterFrontends(ctx context.Context, address string, frontends []Frontend, options ...FrontendOption) error {
 	return nil
 }
 
-func (s *localSharder) AssignRoles(string, chan bool) error {
+func (s *localSharder) AssignRoles(context.Context, string) error {
 	return nil
 }
 
-func (a *sharder) routeDir() string {
-	return fmt.Sprintf("%s/pfs/route", a.namespace)
+// AssignRolesOnce returns the local sharder's fixed assignment, whatever
+// serverStates is.
+func (s *localSharder) AssignRolesOnce(ctx context.Context, serverStates map[string]*ServerState) (*Addresses, map[string]*ServerRole, error) {
+	roles := make(map[string]*ServerRole)
+	for shard, address := range s.shardToAddress {
+		if _, ok := roles[address]; !ok {
+			roles[address] = &ServerRole{
+				Address:       address,
+				Shards:        make(map[uint64]bool),
+				SchemaVersion: SchemaVersion,
+			}
+		}
+		roles[address].Shards[shard] = true
+	}
+	return &Addresses{Addresses: s.shardToAddress, SchemaVersion: SchemaVersion}, roles, nil
+}
+
+func (s *localSharder) GetAddressVersionCount() (int, error) {
+	return 1, nil
+}
+
+func (s *localSharder) GetLiveness() (*Liveness, error) {
+	return newLiveness(), nil
+}
+
+func (a *sharder) lockKey() string {
+	return "lock"
 }
 
 func (a *sharder) serverDir() string {
-	return path.Join(a.routeDir(), "server")
+	return "server"
 }
 
 func (a *sharder) serverStateDir() string {
@@ -509,38 +1293,137 @@ func (a *sharder) serverRoleKeyVersion(address string, version int64) string {
 }
 
 func (a *sharder) frontendDir() string {
-	return path.Join(a.routeDir(), "frontend")
+	return "frontend"
 }
 
 func (a *sharder) frontendStateDir() string {
 	return path.Join(a.frontendDir(), "state")
 }
 
-func (a *sharder) frontendStateKey(address string) string {
-	return path.Join(a.frontendStateDir(), address)
+func (a *sharder) frontendStateKey(id string) string {
+	return path.Join(a.frontendStateDir(), id)
+}
+
+func (a *sharder) corruptKey(key string) string {
+	return path.Join("corrupt", key)
 }
 
 func (a *sharder) addressesDir() string {
-	return path.Join(a.routeDir(), "addresses")
+	return "addresses"
 }
 
 func (a *sharder) addressesKey(version int64) string {
 	return path.Join(a.addressesDir(), fmt.Sprint(version))
 }
 
+// writeContext returns the context used for a single write to discovery.
+// Writes are abandoned after half of holdTTL, by then we're due to make the
+// next announcement anyway and a hung etcd node shouldn't block us forever.
+func (a *sharder) writeContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), a.holdTTLDuration()/2)
+}
+
+func (a *sharder) set(key string, value string, ttl uint64) error {
+	ctx, cancel := a.writeContext()
+	defer cancel()
+	return a.discoveryClient.SetCtx(ctx, key, value, ttl)
+}
+
+// announce sets an announced state, waiting if it's been announced too
+// often.
+func (a *sharder) announce(key string, value string) error {
+	ctx, cancel := a.writeContext()
+	defer cancel()
+	return a.announceClient.SetCtx(ctx, key, value, a.holdTTL)
+}
+
+func (a *sharder) setMulti(kvs map[string]string, ttl uint64) error {
+	ctx, cancel := a.writeContext()
+	defer cancel()
+	return a.discoveryClient.SetMultiCtx(ctx, kvs, ttl)
+}
+
+func (a *sharder) delete(key string) error {
+	ctx, cancel := a.writeContext()
+	defer cancel()
+	return a.discoveryClient.DeleteCtx(ctx, key)
+}
+
 func decodeServerState(encodedServerState string) (*ServerState, error) {
 	var serverState ServerState
-	if err := jsonpb.UnmarshalString(encodedServerState, &serverState); err != nil {
+	if err := decode(encodedServerState, &serverState); err != nil {
 		return nil, err
 	}
 	return &serverState, nil
 }
 
+// serverStateCache holds the decoded contents of the server state directory,
+// keyed by discovery key, as reported by WatchAllDelta.
+type serverStateCache map[string]*ServerState
+
+// apply updates the cache with delta, only the keys in delta are decoded.
+// Entries which can't be decoded are dropped from the cache and passed to
+// corrupt.
+func (c serverStateCache) apply(delta *discovery.Delta, corrupt func(key string, value string, err error)) {
+	if delta.Snapshot {
+		// The watch has (re)started, anything we didn't see in the snapshot
+		// is gone.
+		for key := range c {
+			delete(c, key)
+		}
+	}
+	for _, key := range delta.Deleted {
+		delete(c, key)
+	}
+	for key, encodedServerState := range delta.Updated {
+		serverState, err := decodeServerState(encodedServerState)
+		if err != nil {
+			delete(c, key)
+			corrupt(key, encodedServerState, err)
+			continue
+		}
+		c[key] = serverState
+	}
+}
+
+// corruptEntry reports a discovery entry which couldn't be decoded, the
+// entry is skipped rather than failing the whole operation. If the sharder
+// repairs corrupt entries the entry is also moved under corrupt/.
+func (a *sharder) corruptEntry(key string, value string, err error) {
+	payload := value
+	if len(payload) > maxCorruptPayload {
+		payload = payload[:maxCorruptPayload] + "..."
+	}
+	quarantined := false
+	if a.repairCorrupt {
+		quarantined = a.quarantine(key, value)
+	}
+	protolion.Warn(&CorruptEntry{key, payload, err.Error(), quarantined})
+}
+
+// quarantine copies key to corrupt/key and deletes it, unless it has
+// been overwritten in the meantime.
+func (a *sharder) quarantine(key string, value string) bool {
+	if err := a.set(a.corruptKey(key), value, 0); err != nil {
+		protolion.Errorf("sharder error quarantining %s: %s", key, err.Error())
+		return false
+	}
+	if err := a.discoveryClient.CheckAndDelete(key, value); err != nil {
+		protolion.Errorf("sharder error quarantining %s: %s", key, err.Error())
+		return false
+	}
+	return true
+}
+
 func decodeFrontendState(encodedFrontendState string) (*FrontendState, error) {
 	var frontendState FrontendState
-	if err := jsonpb.UnmarshalString(encodedFrontendState, &frontendState); err != nil {
+	if err := decode(encodedFrontendState, &frontendState); err != nil {
 		return nil, err
 	}
+	// Frontends used to be identified by their address alone
+	if frontendState.Id == "" {
+		frontendState.Id = frontendState.Address
+	}
 	return &frontendState, nil
 }
 
@@ -550,10 +1433,11 @@ func (a *sharder) getServerStates() (map[string]*ServerState, error) {
 		return nil, err
 	}
 	result := make(map[string]*ServerState)
-	for _, encodedServerState := range encodedServerStates {
+	for key, encodedServerState := range encodedServerStates {
 		serverState, err := decodeServerState(encodedServerState)
 		if err != nil {
-			return nil, err
+			a.corruptEntry(key, encodedServerState, err)
+			continue
 		}
 		result[serverState.Address] = serverState
 	}
@@ -570,7 +1454,7 @@ func (a *sharder) getServerState(address string) (*ServerState, error) {
 
 func decodeServerRole(encodedServerRole string) (*ServerRole, error) {
 	var serverRole ServerRole
-	if err := jsonpb.UnmarshalString(encodedServerRole, &serverRole); err != nil {
+	if err := decode(encodedServerRole, &serverRole); err != nil {
 		return nil, err
 	}
 	return &serverRole, nil
@@ -582,10 +1466,11 @@ func (a *sharder) getServerRoles() (map[string]map[int64]*ServerRole, error) {
 		return nil, err
 	}
 	result := make(map[string]map[int64]*ServerRole)
-	for _, encodedServerRole := range encodedServerRoles {
+	for key, encodedServerRole := range encodedServerRoles {
 		serverRole, err := decodeServerRole(encodedServerRole)
 		if err != nil {
-			return nil, err
+			a.corruptEntry(key, encodedServerRole, err)
+			continue
 		}
 		if _, ok := result[serverRole.Address]; !ok {
 			result[serverRole.Address] = make(map[int64]*ServerRole)
@@ -601,10 +1486,11 @@ func (a *sharder) getServerRole(address string) (map[int64]*ServerRole, error) {
 		return nil, err
 	}
 	result := make(map[int64]*ServerRole)
-	for _, encodedServerRole := range encodedServerRoles {
+	for key, encodedServerRole := range encodedServerRoles {
 		serverRole, err := decodeServerRole(encodedServerRole)
 		if err != nil {
-			return nil, err
+			a.corruptEntry(key, encodedServerRole, err)
+			continue
 		}
 		result[serverRole.Version] = serverRole
 	}
@@ -613,32 +1499,89 @@ func (a *sharder) getServerRole(address string) (map[int64]*ServerRole, error) {
 
 func (a *sharder) getAddresses(version int64) (*Addresses, error) {
 	if version == InvalidVersion {
-		return nil, fmt.Errorf("invalid version")
+		return nil, ErrInvalidVersion
 	}
-	a.addressesLock.RLock()
-	if addresses, ok := a.addresses[version]; ok {
-		a.addressesLock.RUnlock()
-		return addresses, nil
-	}
-	a.addressesLock.RUnlock()
 	a.addressesLock.Lock()
 	defer a.addressesLock.Unlock()
+	if addresses, ok := a.addresses.get(version); ok {
+		a.metrics.observeCacheLookup(true)
+		return addresses, nil
+	}
+	a.metrics.observeCacheLookup(false)
 	encodedAddresses, err := a.discoveryClient.Get(a.addressesKey(version))
 	if err != nil {
-		return nil, err
+		return nil, errorutil.Wrap(err, "could not get addresses").WithVersion(version)
 	}
 	var addresses Addresses
-	if err := jsonpb.UnmarshalString(encodedAddresses, &addresses); err != nil {
-		return nil, err
+	if err := decode(encodedAddresses, &addresses); err != nil {
+		return nil, errorutil.Wrap(err, "could not decode addresses").WithVersion(version)
 	}
-	a.addresses[version] = &addresses
+	a.addresses.add(version, &addresses)
 	return &addresses, nil
 }
 
+// AddressCacheSize returns how many versions of the addresses are cached.
+func (a *sharder) AddressCacheSize() int {
+	a.addressesLock.Lock()
+	defer a.addressesLock.Unlock()
+	return a.addresses.len()
+}
+
 func hasShard(serverRole *ServerRole, shard uint64) bool {
 	return serverRole.Shards[shard]
 }
 
+// shardQuotas divides numShards between addresses in proportion to their
+// weights. It returns how many shards each address should get, rounded
+// down, and how many shards are left over once they have, each of which
+// goes to a different address.
+func shardQuotas(numShards uint64, addresses []string, serverStates map[string]*ServerState) (map[string]uint64, uint64) {
+	var totalWeight uint64
+	for _, address := range addresses {
+		totalWeight += serverWeight(serverStates[address])
+	}
+	quotas := make(map[string]uint64)
+	remainder := numShards
+	for _, address := range addresses {
+		quota := numShards * serverWeight(serverStates[address]) / totalWeight
+		quotas[address] = quota
+		remainder -= quota
+	}
+	return quotas, remainder
+}
+
+// weight returns the weight of a process serving servers, it's the largest
+// weight of any of them which is a WeightedServer, or 1.
+func weight(servers []Server) uint64 {
+	var result uint64 = 1
+	for _, server := range servers {
+		if weightedServer, ok := server.(WeightedServer); ok && weightedServer.Weight() > result {
+			result = weightedServer.Weight()
+		}
+	}
+	return result
+}
+
+// zone returns the zone of a process serving servers, it's the zone of the
+// first of them which is a ZonedServer and knows its zone.
+func zone(servers []Server) string {
+	for _, server := range servers {
+		if zonedServer, ok := server.(ZonedServer); ok && zonedServer.Zone() != "" {
+			return zonedServer.Zone()
+		}
+	}
+	return ""
+}
+
+// serverWeight returns serverState's weight, servers which didn't announce
+// one have a weight of 1.
+func serverWeight(serverState *ServerState) uint64 {
+	if serverState.Weight == 0 {
+		return 1
+	}
+	return serverState.Weight
+}
+
 func assignShard(
 	serverRoles map[string]*ServerRole,
 	shards map[uint64]string,
@@ -670,87 +1613,167 @@ func assignShard(
 }
 
 func (a *sharder) announceServers(
+	ctx context.Context,
 	address string,
 	servers []Server,
 	versionChan chan int64,
-	cancel chan bool,
+	drain <-chan struct{},
+	events chan<- RegisterEvent,
+	correlationID string,
+	options ...RegisterOption,
 ) error {
 	serverState := &ServerState{
-		Address: address,
-		Version: InvalidVersion,
+		Address:       address,
+		Version:       InvalidVersion,
+		Weight:        weight(servers),
+		Zone:          zone(servers),
+		SchemaVersion: SchemaVersion,
+	}
+	for _, option := range options {
+		option(serverState)
+	}
+	// Processes which start together, for example after a deploy, would
+	// otherwise announce in lockstep forever.
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-time.After(a.initialAnnounceDelay()):
 	}
+	announced := false
 	for {
-		encodedServerState, err := marshaler.MarshalToString(serverState)
+		serverState.LastRefreshed = a.now().UnixNano()
+		encodedServerState, err := a.encode(serverState)
 		if err != nil {
 			return err
 		}
-		if err := a.discoveryClient.Set(a.serverStateKey(address), encodedServerState, holdTTL); err != nil {
+		err = a.announce(a.serverStateKey(address), encodedServerState)
+		if err != nil {
 			protolion.Printf("Error setting server state: %s", err.Error())
+			sendRegisterEvent(events, RegisterEvent{Type: RegisterEventError, Err: err})
+		} else if !announced {
+			sendRegisterEvent(events, RegisterEvent{Type: RegisterEventAnnounced})
+			announced = true
 		}
-		protolion.Debug(&SetServerState{serverState})
+		a.debug.observeAnnounce(a.serverStateKey(address), err)
+		protolion.Debug(&SetServerState{serverState, correlationID})
 		select {
-		case <-cancel:
+		case <-ctx.Done():
+			a.deregister(a.serverStateKey(address))
 			return nil
 		case version := <-versionChan:
 			serverState.Version = version
-		case <-time.After(time.Second * time.Duration(holdTTL/2)):
+		case <-drain:
+			// Announce it right away, and only once.
+			serverState.Draining = true
+			drain = nil
+		case <-time.After(a.announceDelay()):
 		}
 	}
 }
 
 func (a *sharder) announceFrontends(
+	ctx context.Context,
 	address string,
 	frontends []Frontend,
 	versionChan chan int64,
-	cancel chan bool,
+	options ...FrontendOption,
 ) error {
 	frontendState := &FrontendState{
-		Address: address,
-		Version: InvalidVersion,
+		Address:       address,
+		Version:       InvalidVersion,
+		SchemaVersion: SchemaVersion,
+	}
+	for _, option := range options {
+		option(frontendState)
+	}
+	if frontendState.Id == "" {
+		frontendState.Id = address
+	}
+	// Processes which start together, for example after a deploy, would
+	// otherwise announce in lockstep forever.
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-time.After(a.initialAnnounceDelay()):
 	}
 	for {
-		encodedFrontendState, err := marshaler.MarshalToString(frontendState)
+		frontendState.LastRefreshed = a.now().UnixNano()
+		encodedFrontendState, err := a.encode(frontendState)
 		if err != nil {
 			return err
 		}
-		if err := a.discoveryClient.Set(a.frontendStateKey(address), encodedFrontendState, holdTTL); err != nil {
+		err = a.announce(a.frontendStateKey(frontendState.Id), encodedFrontendState)
+		if err != nil {
 			protolion.Printf("Error setting server state: %s", err.Error())
 		}
+		a.debug.observeAnnounce(a.frontendStateKey(frontendState.Id), err)
 		protolion.Debug(&SetFrontendState{frontendState})
 		select {
-		case <-cancel:
+		case <-ctx.Done():
+			a.deregister(a.frontendStateKey(frontendState.Id))
 			return nil
 		case version := <-versionChan:
 			frontendState.Version = version
-		case <-time.After(time.Second * time.Duration(holdTTL/2)):
+		case <-time.After(a.announceDelay()):
 		}
 	}
 }
 
+// announceDelay returns the time until the next announcement, it's
+// announceInterval randomized by ±announceJitter.
+func (a *sharder) announceDelay() time.Duration {
+	return a.announceInterval + time.Duration(a.announceJitter*(2*rand.Float64()-1)*float64(a.announceInterval))
+}
+
+// initialAnnounceDelay returns the time until the first announcement, it's
+// random between 0 and announceJitter of announceInterval.
+func (a *sharder) initialAnnounceDelay() time.Duration {
+	return time.Duration(a.announceJitter * rand.Float64() * float64(a.announceInterval))
+}
+
+// deregister deletes an announced key so that nobody has to wait for it to
+// expire.
+func (a *sharder) deregister(key string) {
+	err := a.delete(key)
+	if err != nil {
+		protolion.Printf("Error deleting state: %s", err.Error())
+	}
+	a.debug.observeAnnounce(key, err)
+}
+
 type int64Slice []int64
 
 func (s int64Slice) Len() int           { return len(s) }
 func (s int64Slice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
 func (s int64Slice) Less(i, j int) bool { return s[i] < s[j] }
 
+type uint64Slice []uint64
+
+func (s uint64Slice) Len() int           { return len(s) }
+func (s uint64Slice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s uint64Slice) Less(i, j int) bool { return s[i] < s[j] }
+
 func (a *sharder) fillRoles(
+	ctx context.Context,
 	address string,
 	servers []Server,
 	versionChan chan int64,
-	cancel chan bool,
+	drain <-chan struct{},
+	events chan<- RegisterEvent,
 ) error {
 	oldRoles := make(map[int64]ServerRole)
-	return a.discoveryClient.WatchAll(
+	return a.discoveryClient.WatchAllCtx(
+		ctx,
 		a.serverRoleKey(address),
-		cancel,
 		func(encodedServerRoles map[string]string) error {
 			roles := make(map[int64]ServerRole)
 			var versions int64Slice
 			// Decode the roles
-			for _, encodedServerRole := range encodedServerRoles {
+			for key, encodedServerRole := range encodedServerRoles {
 				var serverRole ServerRole
-				if err := jsonpb.UnmarshalString(encodedServerRole, &serverRole); err != nil {
-					return err
+				if err := decode(encodedServerRole, &serverRole); err != nil {
+					a.corruptEntry(key, encodedServerRole, err)
+					continue
 				}
 				roles[serverRole.Version] = serverRole
 				versions = append(versions, serverRole.Version)
@@ -766,91 +1789,211 @@ func (a *sharder) fillRoles(
 					continue
 				}
 				serverRole := roles[version]
-				var wg sync.WaitGroup
-				var addShardErr error
+				var addShards []uint64
+				// Shards split from one the server has are split rather
+				// than added, if it has just restarted it adds them.
+				splitShards := make(map[uint64]uint64)
 				for _, shard := range shards(serverRole) {
-					if !containsShard(oldRoles, shard) {
-						shard := shard
-						for _, server := range servers {
-							wg.Add(1)
-							server := server
-							go func() {
-								defer wg.Done()
-								if err := server.AddShard(shard); err != nil && addShardErr == nil {
-									addShardErr = err
-								}
-							}()
-						}
+					if containsShard(oldRoles, shard) {
+						continue
+					}
+					if oldShard, ok := serverRole.SplitFrom[shard]; ok && containsShard(oldRoles, oldShard) {
+						splitShards[shard] = oldShard
+					} else {
+						addShards = append(addShards, shard)
 					}
 				}
-				wg.Wait()
+				addShardErr := a.splitShards(servers, splitShards, version)
+				if addShardErr == nil {
+					addShardErr = a.callServers(servers, addShards, true)
+				}
 				if addShardErr != nil {
 					protolion.Info(&AddServerRole{&serverRole, addShardErr.Error()})
+					sendRegisterEvent(events, RegisterEvent{Type: RegisterEventError, Version: version, Err: addShardErr})
 					return addShardErr
 				}
 				protolion.Info(&AddServerRole{&serverRole, ""})
+				sendRegisterEvent(events, rolesReceived(serverRole))
 				oldRoles[version] = serverRole
-				versionChan <- version
+				select {
+				case versionChan <- version:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
 			}
 			// See if there are any old roles that aren't needed
 			for version, serverRole := range oldRoles {
-				var wg sync.WaitGroup
-				var removeShardErr error
 				if _, ok := roles[version]; ok {
 					// these roles haven't expired yet, so nothing to do
 					continue
 				}
+				var removeShards []uint64
 				for _, shard := range shards(serverRole) {
 					if !containsShard(roles, shard) {
-						shard := shard
-						for _, server := range servers {
-							server := server
-							wg.Add(1)
-							go func(shard uint64) {
-								defer wg.Done()
-								if err := server.DeleteShard(shard); err != nil && removeShardErr == nil {
-									removeShardErr = err
-								}
-							}(shard)
-						}
+						removeShards = append(removeShards, shard)
 					}
 				}
-				wg.Wait()
-				if removeShardErr != nil {
+				if removeShardErr := a.callServers(servers, removeShards, false); removeShardErr != nil {
 					protolion.Info(&RemoveServerRole{&serverRole, removeShardErr.Error()})
+					sendRegisterEvent(events, RegisterEvent{Type: RegisterEventError, Version: version, Err: removeShardErr})
 					return removeShardErr
 				}
 				protolion.Info(&RemoveServerRole{&serverRole, ""})
+				sendRegisterEvent(events, RegisterEvent{Type: RegisterEventRolesRemoved, Version: version})
 			}
 			oldRoles = make(map[int64]ServerRole)
 			for _, version := range versions {
 				oldRoles[version] = roles[version]
 			}
+			if drained(drain, oldRoles) {
+				return errDrained
+			}
 			return nil
 		},
 	)
 }
 
+// ShardErrors is returned by Register when its servers fail to add or
+// delete shards, the remaining shards were added or deleted successfully.
+type ShardErrors struct {
+	// Add is true if the shards were being added, false if they were being
+	// deleted.
+	Add bool
+	// Errors maps each shard that failed to the reason, if several servers
+	// failed the same shard it's the first of their errors.
+	Errors map[uint64]error
+}
+
+func (e *ShardErrors) Error() string {
+	var shards []int
+	for shard := range e.Errors {
+		shards = append(shards, int(shard))
+	}
+	sort.Ints(shards)
+	var errs []string
+	for _, shard := range shards {
+		errs = append(errs, fmt.Sprintf("%d: %s", shard, e.Errors[uint64(shard)].Error()))
+	}
+	op := "delete"
+	if e.Add {
+		op = "add"
+	}
+	return fmt.Sprintf("pachyderm: failed to %s %d shards: %s", op, len(shards), strings.Join(errs, "; "))
+}
+
+// callServers calls AddShard, if add is true, or DeleteShard for every shard
+// on every server, concurrently. Every failure is logged, and reported in a
+// *ShardErrors.
+func (a *sharder) callServers(servers []Server, shards []uint64, add bool) error {
+	return a.callEachServer(servers, shards, add, func(server Server, shard uint64) (string, func() error) {
+		if add {
+			return "AddShard", func() error { return server.AddShard(shard) }
+		}
+		return "DeleteShard", func() error { return server.DeleteShard(shard) }
+	})
+}
+
+// splitShards calls SplitShard for every shard in splitFrom, which maps them
+// to the shard they're split from, on every server. Servers which aren't
+// SplittingServers get AddShard instead. Failures are reported like
+// callServers reports them.
+func (a *sharder) splitShards(servers []Server, splitFrom map[uint64]uint64, version int64) error {
+	var shards []uint64
+	for shard := range splitFrom {
+		shards = append(shards, shard)
+	}
+	return a.callEachServer(servers, shards, true, func(server Server, shard uint64) (string, func() error) {
+		if splittingServer, ok := server.(SplittingServer); ok {
+			return "SplitShard", func() error { return splittingServer.SplitShard(splitFrom[shard], shard, version) }
+		}
+		return "AddShard", func() error { return server.AddShard(shard) }
+	})
+}
+
+// callEachServer calls the function call returns, along with the name of
+// the method it calls, for every shard on every server, concurrently.
+func (a *sharder) callEachServer(servers []Server, shards []uint64, add bool, call func(server Server, shard uint64) (string, func() error)) error {
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	errs := make(map[uint64]error)
+	for _, shard := range shards {
+		for _, server := range servers {
+			shard, server := shard, server
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				a.debug.observeShardCall(add)
+				method, run := call(server, shard)
+				err := a.watchShardCall(method, shard, run)
+				if err == nil {
+					return
+				}
+				protolion.Printf("Error from %s(%d): %s", method, shard, err.Error())
+				lock.Lock()
+				defer lock.Unlock()
+				if _, ok := errs[shard]; !ok {
+					errs[shard] = err
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		return &ShardErrors{Add: add, Errors: errs}
+	}
+	return nil
+}
+
+// watchShardCall runs call, logging a StuckShardCall every shardCallTimeout
+// until it returns. If failStuckShardCalls is set it stops waiting after the
+// first and returns an Unavailable error, the call keeps running.
+func (a *sharder) watchShardCall(method string, shard uint64, call func() error) error {
+	if a.shardCallTimeout == 0 {
+		return call()
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- call()
+	}()
+	start := time.Now()
+	ticker := time.NewTicker(a.shardCallTimeout)
+	defer ticker.Stop()
+	for {
+		select {
+		case err := <-done:
+			return err
+		case <-ticker.C:
+		}
+		elapsed := time.Since(start)
+		a.debug.observeStuckShardCall()
+		protolion.Warn(&StuckShardCall{
+			Method:         method,
+			Shard:          shard,
+			ElapsedSeconds: int64(elapsed / time.Second),
+			Abandoned:      a.failStuckShardCalls,
+		})
+		if a.failStuckShardCalls {
+			return errorutil.New(errorutil.Unavailable, "%s(%d) still running after %s", method, shard, elapsed).WithShard(shard)
+		}
+	}
+}
+
 func (a *sharder) runFrontends(
+	ctx context.Context,
 	address string,
 	frontends []Frontend,
 	versionChan chan int64,
-	cancel chan bool,
 ) error {
 	version := InvalidVersion
-	return a.discoveryClient.WatchAll(
-		a.serverStateDir(),
-		cancel,
-		func(encodedServerStates map[string]string) error {
-			if len(encodedServerStates) == 0 {
+	return a.watchServerStates(
+		ctx,
+		"RegisterFrontends",
+		func(serverStates serverStateCache) error {
+			if len(serverStates) == 0 {
 				return nil
 			}
 			minVersion := int64(math.MaxInt64)
-			for _, encodedServerState := range encodedServerStates {
-				serverState, err := decodeServerState(encodedServerState)
-				if err != nil {
-					return err
-				}
+			for _, serverState := range serverStates {
 				if serverState.Version < minVersion {
 					minVersion = serverState.Version
 				}
@@ -881,12 +2024,32 @@ func (a *sharder) runFrontends(
 				default:
 				}
 				version = minVersion
-				versionChan <- version
+				select {
+				case versionChan <- version:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
 			}
 			return nil
 		})
 }
 
+// drained returns true if the server is draining and none of its roles has
+// any shards left.
+func drained(drain <-chan struct{}, roles map[int64]ServerRole) bool {
+	select {
+	case <-drain:
+	default:
+		return false
+	}
+	for _, serverRole := range roles {
+		if len(serverRole.Shards) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
 func shards(serverRole ServerRole) []uint64 {
 	var result []uint64
 	for shard := range serverRole.Shards {
@@ -904,6 +2067,51 @@ func containsShard(roles map[int64]ServerRole, shard uint64) bool {
 	return false
 }
 
+// activeServers returns the servers in serverStates which aren't draining.
+func activeServers(serverStates map[string]*ServerState) map[string]*ServerState {
+	result := make(map[string]*ServerState)
+	for address, serverState := range serverStates {
+		if !serverState.Draining {
+			result[address] = serverState
+		}
+	}
+	return result
+}
+
+// startedDraining returns true if any of oldServers is draining in
+// newServerStates.
+func startedDraining(oldServers map[string]bool, newServerStates map[string]*ServerState) bool {
+	for address := range oldServers {
+		if serverState, ok := newServerStates[address]; ok && serverState.Draining {
+			return true
+		}
+	}
+	return false
+}
+
+// serverChurn returns the fraction of the servers in either oldServers or
+// newServerStates which aren't in both.
+func serverChurn(oldServers map[string]bool, newServerStates map[string]*ServerState) float64 {
+	all := make(map[string]bool)
+	changed := 0
+	for address := range oldServers {
+		all[address] = true
+		if _, ok := newServerStates[address]; !ok {
+			changed++
+		}
+	}
+	for address := range newServerStates {
+		all[address] = true
+		if !oldServers[address] {
+			changed++
+		}
+	}
+	if len(all) == 0 {
+		return 0
+	}
+	return float64(changed) / float64(len(all))
+}
+
 func sameServers(oldServers map[string]bool, newServerStates map[string]*ServerState) bool {
 	if len(oldServers) != len(newServerStates) {
 		return false
@@ -915,11 +2123,3 @@ func sameServers(oldServers map[string]bool, newServerStates map[string]*ServerS
 	}
 	return true
 }
-
-// TODO this code is duplicate elsewhere, we should put it somehwere.
-func errorToString(err error) string {
-	if err == nil {
-		return ""
-	}
-	return err.Error()
-}
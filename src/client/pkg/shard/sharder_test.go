@@ -0,0 +1,2100 @@
+package shard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/discovery"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errorutil"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"golang.org/x/net/context"
+)
+
+func TestServerStateCacheApply(t *testing.T) {
+	cache := make(serverStateCache)
+	noCorrupt := func(key string, value string, err error) {
+		t.Fatalf("unexpected corrupt entry %s", key)
+	}
+	encode := func(serverState *ServerState) string {
+		encodedServerState, err := marshaler.MarshalToString(serverState)
+		require.NoError(t, err)
+		return encodedServerState
+	}
+	cache.apply(&discovery.Delta{
+		Snapshot: true,
+		Updated: map[string]string{
+			"state/a": encode(&ServerState{Address: "a", Version: 1}),
+			"state/b": encode(&ServerState{Address: "b", Version: 1}),
+		},
+	}, noCorrupt)
+	require.Equal(t, 2, len(cache))
+	cache.apply(&discovery.Delta{
+		Updated: map[string]string{"state/a": encode(&ServerState{Address: "a", Version: 2})},
+		Deleted: []string{"state/b"},
+	}, noCorrupt)
+	require.Equal(t, 1, len(cache))
+	require.Equal(t, int64(2), cache["state/a"].Version)
+	cache.apply(&discovery.Delta{
+		Updated: map[string]string{"state/b": encode(&ServerState{Address: "b", Version: 2})},
+	}, noCorrupt)
+	require.Equal(t, 2, len(cache))
+	// A resumed watch delivers a new snapshot, b expired while the watch was
+	// down so it must not survive.
+	cache.apply(&discovery.Delta{
+		Snapshot: true,
+		Updated:  map[string]string{"state/a": encode(&ServerState{Address: "a", Version: 3})},
+	}, noCorrupt)
+	require.Equal(t, 1, len(cache))
+	require.Equal(t, int64(3), cache["state/a"].Version)
+	// A corrupt entry is dropped, and reported, without affecting the rest
+	var corruptKeys []string
+	cache.apply(&discovery.Delta{
+		Updated: map[string]string{
+			"state/a": "garbage",
+			"state/c": encode(&ServerState{Address: "c", Version: 3}),
+		},
+	}, func(key string, value string, err error) {
+		corruptKeys = append(corruptKeys, key)
+	})
+	require.Equal(t, []string{"state/a"}, corruptKeys)
+	require.Equal(t, 1, len(cache))
+	require.Equal(t, int64(3), cache["state/c"].Version)
+}
+
+func TestWaitForAvailability(t *testing.T) {
+	t.Parallel()
+	discoveryClient := discovery.NewMockClient()
+	sharder := newSharder(discoveryClient, 16, WithNamespace("TestWaitForAvailability"))
+	runCtx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+	var servers []*testServer
+	var serverAddresses []string
+	for i := 0; i < 3; i++ {
+		server := newTestServer()
+		servers = append(servers, server)
+		address := fmt.Sprintf("server%d", i)
+		serverAddresses = append(serverAddresses, address)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sharder.Register(runCtx, address, []Server{server})
+		}()
+	}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		sharder.RegisterFrontends(runCtx, "frontend", []Frontend{&testFrontend{}})
+	}()
+	go func() {
+		defer wg.Done()
+		sharder.AssignRoles(runCtx, "master")
+	}()
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelCtx()
+	require.NoError(t, sharder.WaitForAvailability(ctx, []string{"frontend"}, serverAddresses))
+	// Servers may not have dropped the shards from older versions yet, but
+	// every shard must be served.
+	for shard := uint64(0); shard < 16; shard++ {
+		served := false
+		for _, server := range servers {
+			served = server.hasShard(shard) || served
+		}
+		require.True(t, served, "shard %d not served", shard)
+	}
+	// Every role is tagged with the correlation ID of its version
+	serverRoles, err := sharder.getServerRoles()
+	require.NoError(t, err)
+	for _, versionToServerRole := range serverRoles {
+		for version, serverRole := range versionToServerRole {
+			require.True(t, strings.HasSuffix(serverRole.CorrelationId, fmt.Sprintf("-%d", version)))
+		}
+	}
+	// Nothing should have been written outside of the sharder's namespace
+	values, err := discoveryClient.GetAll("")
+	require.NoError(t, err)
+	require.True(t, len(values) > 0)
+	for key := range values {
+		require.True(t, strings.HasPrefix(key, "TestWaitForAvailability/pfs/route/"), "%s outside namespace", key)
+	}
+}
+
+func TestWaitForAvailabilityCancel(t *testing.T) {
+	t.Parallel()
+	sharder := newSharder(discovery.NewMockClient(), 16, WithNamespace("TestWaitForAvailabilityCancel"))
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := sharder.WaitForAvailability(ctx, nil, []string{"server"})
+	require.True(t, errorutil.Is(err, errorutil.Cancelled))
+	require.Equal(t, context.DeadlineExceeded, err.(*errorutil.Error).Cause)
+	require.Equal(t, "0 of 1 servers announced and 0 of 0 frontends on version -1; "+
+		"servers not announced: [server]: context deadline exceeded", err.Error())
+
+	// A server which has announced itself but never been given a role
+	encoded, err := sharder.encode(&ServerState{Address: "stuck", Version: InvalidVersion})
+	require.NoError(t, err)
+	require.NoError(t, sharder.set(sharder.serverStateKey("stuck"), encoded, 0))
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err = sharder.WaitForAvailability(ctx, []string{"frontend"}, []string{"server", "stuck"})
+	require.True(t, errorutil.Is(err, errorutil.Cancelled))
+	require.Equal(t, "1 of 2 servers announced and 0 of 1 frontends on version -1; "+
+		"servers not announced: [server]; servers with no version: [stuck]; "+
+		"frontends not on version: [frontend]: context deadline exceeded", err.Error())
+}
+
+func TestErrorsAs(t *testing.T) {
+	t.Parallel()
+	sharder := newSharder(discovery.NewMockClient(), 16, WithNamespace("TestErrorsAs"))
+	var shardErr *errorutil.Error
+	_, err := sharder.GetShardToAddress(InvalidVersion)
+	require.True(t, errors.Is(fmt.Errorf("routing: %w", err), ErrInvalidVersion))
+	require.True(t, errors.As(err, &shardErr))
+	require.Equal(t, errorutil.Unavailable, shardErr.Code)
+	_, _, err = sharder.GetAddress(0, InvalidVersion)
+	require.Equal(t, ErrInvalidVersion, err)
+
+	// A shard the version doesn't have isn't an error
+	encodedAddresses, err := sharder.encode(&Addresses{Version: 0, Addresses: map[uint64]string{0: "server"}})
+	require.NoError(t, err)
+	require.NoError(t, sharder.set(sharder.addressesKey(0), encodedAddresses, 0))
+	_, ok, err := sharder.GetAddress(1, 0)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	err = sharder.Deregister(context.Background(), "server")
+	require.True(t, errors.As(err, &shardErr))
+	require.Equal(t, errorutil.NotFound, shardErr.Code)
+
+	_, err = encode(Encoding(99), 0, &Addresses{})
+	require.True(t, errors.As(err, &shardErr))
+	require.Equal(t, errorutil.Internal, shardErr.Code)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err = sharder.WaitForAvailability(ctx, nil, []string{"server"})
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestCorruptEntries(t *testing.T) {
+	t.Parallel()
+	for _, repair := range []bool{false, true} {
+		namespace := fmt.Sprintf("TestCorruptEntries%t", repair)
+		sharder := newSharder(discovery.NewMockClient(), 16, WithNamespace(namespace), WithRepairCorruptEntries(repair))
+		random := rand.New(rand.NewSource(int64(len(namespace))))
+		payloads := []string{
+			"garbage",
+			`{"address": 5}`,
+			`{"version": "x"}`,
+			`{"address": "server0", "version": true, "shards": {"a": true}}`,
+			`{"address": "server0"`,
+		}
+		for i := 0; i < 10; i++ {
+			garbage := make([]byte, random.Intn(64)+1)
+			random.Read(garbage)
+			payloads = append(payloads, string(garbage))
+		}
+		corruptKeys := make(map[string]bool)
+		for i, payload := range payloads {
+			for _, key := range []string{
+				sharder.serverStateKey(fmt.Sprintf("corrupt%d", i)),
+				sharder.serverRoleKeyVersion("server0", int64(100+i)),
+				sharder.frontendStateKey(fmt.Sprintf("corrupt%d", i)),
+			} {
+				require.NoError(t, sharder.discoveryClient.Set(key, payload, 0))
+				corruptKeys[key] = true
+			}
+		}
+
+		runCtx, cancel := context.WithCancel(context.Background())
+		var wg sync.WaitGroup
+		var serverAddresses []string
+		for i := 0; i < 2; i++ {
+			address := fmt.Sprintf("server%d", i)
+			serverAddresses = append(serverAddresses, address)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				sharder.Register(runCtx, address, []Server{newTestServer()})
+			}()
+		}
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			sharder.RegisterFrontends(runCtx, "frontend", []Frontend{&testFrontend{}})
+		}()
+		go func() {
+			defer wg.Done()
+			sharder.AssignRoles(runCtx, "master")
+		}()
+		ctx, cancelCtx := context.WithTimeout(context.Background(), 10*time.Second)
+		// The control loop keeps making progress despite the corrupt entries
+		err := sharder.WaitForAvailability(ctx, []string{"frontend"}, serverAddresses)
+		cancelCtx()
+		cancel()
+		wg.Wait()
+		require.NoError(t, err)
+
+		values, err := sharder.discoveryClient.GetAll("")
+		require.NoError(t, err)
+		for key := range corruptKeys {
+			_, ok := values[key]
+			require.Equal(t, !repair, ok, "%s", key)
+			_, ok = values[sharder.corruptKey(key)]
+			require.Equal(t, repair, ok, "%s", key)
+		}
+	}
+}
+
+func TestDebugHandler(t *testing.T) {
+	t.Parallel()
+	sharder := newSharder(discovery.NewMockClient(), 16, WithNamespace("TestDebugHandler"))
+	sharder.addresses.add(3, &Addresses{Version: 3})
+	sharder.debug.observeServerStates(serverStateCache{"server/state/a": &ServerState{Address: "a", Version: 3}})
+	sharder.debug.observeAnnounce("server/state/a", nil)
+	sharder.debug.observeAnnounce("frontend/state/a", fmt.Errorf("etcd is down"))
+	server := httptest.NewServer(NewDebugHandler(sharder))
+	defer server.Close()
+
+	response, err := http.Get(server.URL + DebugPath)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	var state DebugState
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&state))
+	require.Equal(t, []int64{3}, state.AddressesVersions)
+	require.Equal(t, int64(3), state.ServerStates["server/state/a"].Version)
+	require.False(t, state.Announces["server/state/a"].LastSuccess.IsZero())
+	require.Equal(t, "etcd is down", state.Announces["frontend/state/a"].LastError)
+	require.Equal(t, 0, len(state.Liveness.Servers))
+
+	postResponse, err := http.Post(server.URL+DebugPath, "application/json", nil)
+	require.NoError(t, err)
+	postResponse.Body.Close()
+	require.Equal(t, http.StatusMethodNotAllowed, postResponse.StatusCode)
+}
+
+func TestLiveness(t *testing.T) {
+	t.Parallel()
+	sharder := newSharder(discovery.NewMockClient(), 16, WithNamespace("TestLiveness"), WithAnnounceJitter(0))
+	clock := discovery.NewFakeClock(time.Unix(1000, 0))
+	sharder.now = clock.Now
+	// A state written before last_refreshed existed
+	encoded, err := sharder.encode(&ServerState{Address: "old", Version: 2})
+	require.NoError(t, err)
+	require.NoError(t, sharder.discoveryClient.Set(sharder.serverStateKey("old"), encoded, 0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		sharder.announceServers(ctx, "server", nil, nil, nil, nil, "")
+	}()
+	go func() {
+		defer wg.Done()
+		sharder.announceFrontends(ctx, "frontend", nil, nil)
+	}()
+	var liveness *Liveness
+	for deadline := time.Now().Add(10 * time.Second); ; {
+		liveness, err = sharder.GetLiveness()
+		require.NoError(t, err)
+		if len(liveness.Servers) == 2 && len(liveness.Frontends) == 1 {
+			break
+		}
+		require.True(t, time.Now().Before(deadline), "not announced")
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.Equal(t, LivenessUnknown, liveness.Servers["old"].Status)
+	require.Equal(t, int64(2), liveness.Servers["old"].Version)
+	require.Equal(t, time.Unix(1000, 0), liveness.Servers["server"].LastRefreshed)
+	require.Equal(t, InvalidVersion, liveness.Servers["server"].Version)
+
+	for _, test := range []struct {
+		advance time.Duration
+		status  LivenessStatus
+	}{
+		{0, LivenessHealthy},
+		{sharder.announceInterval, LivenessHealthy},
+		{time.Second, LivenessLagging},
+		{4 * time.Second, LivenessNearExpiry},
+	} {
+		clock.Advance(test.advance)
+		liveness, err := sharder.GetLiveness()
+		require.NoError(t, err)
+		require.Equal(t, test.status, liveness.Servers["server"].Status)
+		require.Equal(t, test.status, liveness.Frontends["frontend"].Status)
+	}
+}
+
+func TestShardHealth(t *testing.T) {
+	t.Parallel()
+	sharder := newSharder(discovery.NewMockClient(), 4, WithNamespace("TestShardHealth"), WithAnnounceJitter(0))
+	clock := time.Unix(1000, 0)
+	sharder.now = func() time.Time { return clock }
+	sharder.addresses.add(0, &Addresses{Version: 0, Addresses: map[uint64]string{0: "live", 1: "lagging", 2: "gone"}})
+	for address, lastRefreshed := range map[string]time.Time{
+		"live":    clock,
+		"lagging": clock.Add(-2 * sharder.announceInterval),
+	} {
+		encodedServerState, err := sharder.encode(&ServerState{Address: address, LastRefreshed: lastRefreshed.UnixNano()})
+		require.NoError(t, err)
+		require.NoError(t, sharder.set(sharder.serverStateKey(address), encodedServerState, 0))
+	}
+	shardHealths, err := sharder.GetShardHealth(0)
+	require.NoError(t, err)
+	require.Equal(t, 4, len(shardHealths))
+	for shard, status := range []ShardHealthStatus{ShardHealthy, ShardOwnerLagging, ShardNoOwner, ShardNoOwner} {
+		require.Equal(t, uint64(shard), shardHealths[shard].Shard)
+		require.Equal(t, status, shardHealths[shard].Status)
+	}
+	require.Equal(t, "", shardHealths[3].Address)
+	summary, err := sharder.GetClusterHealthSummary(0)
+	require.NoError(t, err)
+	require.Equal(t, &ClusterHealthSummary{Version: 0, Healthy: 1, Degraded: 1, Critical: 2}, summary)
+}
+
+func TestAnnounceJitter(t *testing.T) {
+	t.Parallel()
+	discoveryClient := &timingClient{Client: discovery.NewMockClient(), writes: make(map[string][]time.Time)}
+	sharder := newSharder(discoveryClient, 16, WithNamespace("TestAnnounceJitter"), WithAnnounceJitter(0.2))
+	sharder.announceInterval = 500 * time.Millisecond
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), 1300*time.Millisecond)
+	defer cancel()
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		address := fmt.Sprintf("server%d", i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, sharder.announceServers(ctx, address, nil, nil, nil, nil, ""))
+		}()
+	}
+	wg.Wait()
+	var firstWrites []time.Duration
+	var intervals []time.Duration
+	for _, writes := range discoveryClient.get() {
+		require.True(t, len(writes) >= 2)
+		firstWrites = append(firstWrites, writes[0].Sub(start))
+		intervals = append(intervals, writes[1].Sub(writes[0]))
+	}
+	require.Equal(t, 50, len(firstWrites))
+	// Initial writes are spread over the first 20% of the interval and
+	// refreshes over ±20% of it, rather than all landing together.
+	min, max := spread(firstWrites)
+	require.True(t, max < 150*time.Millisecond, "first write at %s", max)
+	require.True(t, max-min > 30*time.Millisecond, "first writes within %s", max-min)
+	min, max = spread(intervals)
+	require.True(t, min > 350*time.Millisecond && max < 650*time.Millisecond, "intervals between %s and %s", min, max)
+	require.True(t, max-min > 60*time.Millisecond, "intervals within %s", max-min)
+}
+
+func TestHoldTTL(t *testing.T) {
+	t.Parallel()
+	discoveryClient := &timingClient{
+		Client: discovery.NewMockClient(),
+		writes: make(map[string][]time.Time),
+		ttls:   make(map[string]uint64),
+	}
+	sharder := newSharder(discoveryClient, 16, WithNamespace("TestHoldTTL"), WithHoldTTL(2), WithAnnounceJitter(0))
+	ctx, cancel := context.WithTimeout(context.Background(), 3500*time.Millisecond)
+	defer cancel()
+	require.NoError(t, sharder.announceServers(ctx, "server", nil, nil, nil, nil, ""))
+	key := "TestHoldTTL/pfs/route/server/state/server"
+	writes := discoveryClient.get()[key]
+	require.True(t, len(writes) >= 3)
+	for i := 1; i < len(writes); i++ {
+		interval := writes[i].Sub(writes[i-1])
+		require.True(t, interval > 800*time.Millisecond && interval < 1300*time.Millisecond, "announced after %s", interval)
+	}
+	discoveryClient.lock.Lock()
+	defer discoveryClient.lock.Unlock()
+	require.Equal(t, uint64(2), discoveryClient.ttls[key])
+}
+
+func BenchmarkGetAddress(b *testing.B) {
+	sharder := newSharder(discovery.NewMockClient(), 16, WithNamespace("BenchmarkGetAddress"))
+	sharder.addresses.add(1, &Addresses{Version: 1, Addresses: map[uint64]string{0: "server"}})
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := sharder.GetAddress(0, 1); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestShardDistribution(t *testing.T) {
+	t.Parallel()
+	sharder := newSharder(discovery.NewMockClient(), 16, WithNamespace("TestShardDistribution"))
+	_, err := sharder.GetShardDistribution(0)
+	require.YesError(t, err)
+	shardToAddress := make(map[uint64]string)
+	for shard := uint64(0); shard < 16; shard++ {
+		shardToAddress[shard] = []string{"a", "a", "b", "c"}[shard%4]
+	}
+	sharder.addresses.add(0, &Addresses{Version: 0, Addresses: shardToAddress})
+	distribution, err := sharder.GetShardDistribution(0)
+	require.NoError(t, err)
+	require.Equal(t, map[string]uint64{"a": 8, "b": 4, "c": 4}, distribution.Servers)
+	require.Equal(t, uint64(4), distribution.Imbalance)
+	require.Equal(t, uint64(0), shardDistribution(nil).Imbalance)
+}
+
+func BenchmarkGetShardDistribution(b *testing.B) {
+	sharder := newSharder(discovery.NewMockClient(), 1024, WithNamespace("BenchmarkGetShardDistribution"))
+	shardToAddress := make(map[uint64]string)
+	for shard := uint64(0); shard < 1024; shard++ {
+		shardToAddress[shard] = fmt.Sprintf("server-%d", shard%32)
+	}
+	sharder.addresses.add(1, &Addresses{Version: 1, Addresses: shardToAddress})
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := sharder.GetShardDistribution(1); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func spread(durations []time.Duration) (time.Duration, time.Duration) {
+	min, max := durations[0], durations[0]
+	for _, duration := range durations {
+		if duration < min {
+			min = duration
+		}
+		if duration > max {
+			max = duration
+		}
+	}
+	return min, max
+}
+
+// TestAnnounceRetry checks that an announcement which fails is retried at
+// the next interval.
+func TestAnnounceRetry(t *testing.T) {
+	t.Parallel()
+	discoveryClient := discovery.NewFaultyClient(discovery.NewMockClient(), discovery.FaultOptions{})
+	sharder := newSharder(discoveryClient, 16, WithNamespace("TestAnnounceRetry"), WithAnnounceJitter(0))
+	sharder.announceInterval = 20 * time.Millisecond
+	discoveryClient.FailNext(discovery.OpSet, 2, fmt.Errorf("etcd is down"))
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	require.NoError(t, sharder.announceServers(ctx, "server", nil, nil, nil, nil, ""))
+	faults := discoveryClient.Faults()
+	require.Equal(t, 2, len(faults))
+	require.Equal(t, "TestAnnounceRetry/pfs/route/server/state/server", faults[0].Key)
+	state, err := sharder.debugState()
+	require.NoError(t, err)
+	health := state.Announces["server/state/server"]
+	require.Equal(t, "etcd is down", health.LastError)
+	require.True(t, health.LastSuccess.After(health.LastErrorTime))
+}
+
+// TestWatchResume drops watch notifications while a cluster is starting,
+// the sharder must catch up when the watches resume.
+func TestWatchResume(t *testing.T) {
+	t.Parallel()
+	discoveryClient := discovery.NewFaultyClient(discovery.NewMockClient(), discovery.FaultOptions{})
+	sharder := newSharder(discoveryClient, 16, WithNamespace("TestWatchResume"))
+	sharder.announceInterval = 100 * time.Millisecond
+	discoveryClient.DropNotifications(5)
+	runCtx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+	var serverAddresses []string
+	for i := 0; i < 2; i++ {
+		address := fmt.Sprintf("server%d", i)
+		serverAddresses = append(serverAddresses, address)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sharder.Register(runCtx, address, []Server{newTestServer()})
+		}()
+	}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		sharder.RegisterFrontends(runCtx, "frontend", []Frontend{&testFrontend{}})
+	}()
+	go func() {
+		defer wg.Done()
+		sharder.AssignRoles(runCtx, "master")
+	}()
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelCtx()
+	require.NoError(t, sharder.WaitForAvailability(ctx, []string{"frontend"}, serverAddresses))
+	require.Equal(t, 5, len(discoveryClient.Faults()))
+}
+
+// TestWatchStaleness black-holes watch notifications, the sharder must
+// report its watch as stale and then re-establish it.
+func TestWatchStaleness(t *testing.T) {
+	t.Parallel()
+	discoveryClient := discovery.NewFaultyClient(discovery.NewMockClient(), discovery.FaultOptions{})
+	sharder := newSharder(discoveryClient, 16, WithNamespace("TestWatchStaleness"), WithWatchStaleness(300*time.Millisecond, 600*time.Millisecond))
+	sharder.announceInterval = 50 * time.Millisecond
+	runCtx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		sharder.Register(runCtx, "server", []Server{newTestServer()})
+	}()
+	go func() {
+		defer wg.Done()
+		sharder.AssignRoles(runCtx, "master")
+	}()
+	waitForWatch := func(condition func(health *WatchHealth) bool) *WatchHealth {
+		for deadline := time.Now().Add(5 * time.Second); ; {
+			state, err := sharder.debugState()
+			require.NoError(t, err)
+			if health, ok := state.Watches["AssignRoles"]; ok && condition(health) {
+				return health
+			}
+			require.True(t, time.Now().Before(deadline), "timed out waiting for watch")
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelCtx()
+	require.NoError(t, sharder.WaitForAvailability(ctx, nil, []string{"server"}))
+
+	discoveryClient.DropNotifications(math.MaxInt32)
+	health := waitForWatch(func(health *WatchHealth) bool { return health.Stale })
+	require.Equal(t, uint64(1), health.StaleCount)
+	require.True(t, health.Lag >= 300*time.Millisecond)
+	waitForWatch(func(health *WatchHealth) bool { return health.Resumes > 0 })
+
+	discoveryClient.DropNotifications(0)
+	health = waitForWatch(func(health *WatchHealth) bool { return !health.Stale })
+	require.True(t, health.Lag < 300*time.Millisecond)
+}
+
+// TestAnnounceRateLimit announces a server whose version changes as fast as
+// it can, like a flapping component.
+func TestAnnounceRateLimit(t *testing.T) {
+	t.Parallel()
+	discoveryClient := &timingClient{Client: discovery.NewMockClient(), writes: make(map[string][]time.Time)}
+	sharder := newSharder(discoveryClient, 16, WithNamespace("TestAnnounceRateLimit"), WithAnnounceJitter(0), WithAnnounceRateLimit(discovery.RateLimit{Rate: 10, Burst: 2}))
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	versionChan := make(chan int64)
+	go func() {
+		for version := int64(0); ; version++ {
+			select {
+			case versionChan <- version:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	require.NoError(t, sharder.announceServers(ctx, "server", nil, versionChan, nil, nil, ""))
+	writes := discoveryClient.get()["TestAnnounceRateLimit/pfs/route/server/state/server"]
+	require.True(t, len(writes) >= 3 && len(writes) <= 8, "%d writes", len(writes))
+	state, err := sharder.debugState()
+	require.NoError(t, err)
+	require.True(t, state.Throttled["server/state/server"] > 0)
+}
+
+// timingClient records when each key is set, and the TTL it was last set
+// with.
+type timingClient struct {
+	discovery.Client
+	writes map[string][]time.Time
+	ttls   map[string]uint64
+	lock   sync.Mutex
+}
+
+func (c *timingClient) SetCtx(ctx context.Context, key string, value string, ttl uint64) error {
+	c.lock.Lock()
+	c.writes[key] = append(c.writes[key], time.Now())
+	if c.ttls != nil {
+		c.ttls[key] = ttl
+	}
+	c.lock.Unlock()
+	return c.Client.SetCtx(ctx, key, value, ttl)
+}
+
+func (c *timingClient) get() map[string][]time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	result := make(map[string][]time.Time)
+	for key, writes := range c.writes {
+		result[key] = append([]time.Time(nil), writes...)
+	}
+	return result
+}
+
+type testServer struct {
+	shards map[uint64]bool
+	lock   sync.Mutex
+}
+
+func newTestServer() *testServer {
+	return &testServer{shards: make(map[uint64]bool)}
+}
+
+func (s *testServer) AddShard(shard uint64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.shards[shard] = true
+	return nil
+}
+
+func (s *testServer) DeleteShard(shard uint64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.shards, shard)
+	return nil
+}
+
+func (s *testServer) numShards() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.shards)
+}
+
+func (s *testServer) hasShard(shard uint64) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.shards[shard]
+}
+
+type testFrontend struct{}
+
+func (f *testFrontend) Version(version int64) error {
+	return nil
+}
+
+func TestAssignRolesFailover(t *testing.T) {
+	t.Parallel()
+	client := discovery.NewMockClient()
+	sharder := newSharder(client, 16, WithNamespace("TestAssignRolesFailover"))
+	ctxs := make([]context.Context, 2)
+	cancels := make([]context.CancelFunc, 2)
+	for i := range ctxs {
+		ctxs[i], cancels[i] = context.WithCancel(context.Background())
+	}
+	errs := make(chan error, 2)
+	go func() {
+		errs <- sharder.AssignRoles(ctxs[0], "master0")
+	}()
+	waitForHolder := func(holder string) {
+		for i := 0; i < 100; i++ {
+			if value, err := sharder.discoveryClient.Get(sharder.lockKey()); err == nil && value == holder {
+				return
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+		t.Fatalf("%s never acquired the lock", holder)
+	}
+	waitForHolder("master0")
+	go func() {
+		errs <- sharder.AssignRoles(ctxs[1], "master1")
+	}()
+	// The standby takes over as soon as the leader releases the lock, without
+	// waiting for its retry interval
+	cancels[0]()
+	require.Equal(t, ErrCancelled, <-errs)
+	waitForHolder("master1")
+	cancels[1]()
+	require.Equal(t, ErrCancelled, <-errs)
+	_, err := sharder.discoveryClient.Get(sharder.lockKey())
+	require.YesError(t, err)
+}
+
+func TestGetVersion(t *testing.T) {
+	t.Parallel()
+	sharder := newSharder(discovery.NewMockClient(), 16, WithNamespace("TestGetVersion"))
+	version, err := sharder.GetVersion()
+	require.Equal(t, ErrNoVersion, err)
+	require.Equal(t, InvalidVersion, version)
+	versions := make(chan int64)
+	runCtx, cancel := context.WithCancel(context.Background())
+	watchErr := make(chan error, 1)
+	go func() {
+		watchErr <- sharder.WatchVersion(runCtx, func(version int64) error {
+			versions <- version
+			return nil
+		})
+	}()
+	publish := func(version int64) {
+		encodedAddresses, err := sharder.encode(&Addresses{Version: version, Addresses: map[uint64]string{0: "server"}})
+		require.NoError(t, err)
+		require.NoError(t, sharder.set(sharder.addressesKey(version), encodedAddresses, 0))
+	}
+	for version := int64(0); version <= 3; version++ {
+		publish(version)
+		require.Equal(t, version, <-versions)
+	}
+	// Corrupt entries and older versions don't change the version
+	require.NoError(t, sharder.set(sharder.addressesKey(4), "garbage", 0))
+	publish(1)
+	version, err = sharder.GetVersion()
+	require.NoError(t, err)
+	require.Equal(t, int64(3), version)
+	publish(5)
+	require.Equal(t, int64(5), <-versions)
+	cancel()
+	require.Equal(t, ErrCancelled, <-watchErr)
+}
+
+func TestGetCurrentVersion(t *testing.T) {
+	t.Parallel()
+	sharder := newSharder(discovery.NewMockClient(), 16, WithNamespace("TestGetCurrentVersion"))
+	clock := time.Unix(1000, 0)
+	sharder.now = func() time.Time { return clock }
+	ctx := context.Background()
+	_, err := sharder.GetCurrentVersion(ctx)
+	require.Equal(t, ErrNoVersion, err)
+	publish := func(version int64) {
+		encodedAddresses, err := sharder.encode(&Addresses{Version: version, Addresses: map[uint64]string{0: fmt.Sprint(version)}})
+		require.NoError(t, err)
+		require.NoError(t, sharder.set(sharder.addressesKey(version), encodedAddresses, 0))
+	}
+	current := func(expected int64) {
+		version, err := sharder.GetCurrentVersion(ctx)
+		require.NoError(t, err)
+		require.Equal(t, expected, version)
+		address, ok, err := sharder.GetAddressCurrent(ctx, 0)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, fmt.Sprint(expected), address)
+	}
+	publish(0)
+	current(0)
+	// The version is cached for holdTTL
+	publish(1)
+	current(0)
+	clock = clock.Add(time.Second * time.Duration(sharder.holdTTL))
+	current(1)
+	// Unless WatchVersion sees a newer one
+	publish(2)
+	current(1)
+	watchCtx, cancel := context.WithCancel(ctx)
+	require.Equal(t, errComplete, sharder.WatchVersion(watchCtx, func(version int64) error {
+		if version == 2 {
+			return errComplete
+		}
+		return nil
+	}))
+	cancel()
+	current(2)
+}
+
+func TestAddressCache(t *testing.T) {
+	t.Parallel()
+	sharder := newSharder(discovery.NewMockClient(), 16, WithNamespace("TestAddressCache"), WithAddressCacheSize(2))
+	for version := int64(0); version < 4; version++ {
+		encodedAddresses, err := sharder.encode(&Addresses{Version: version, Addresses: map[uint64]string{0: fmt.Sprint(version)}})
+		require.NoError(t, err)
+		require.NoError(t, sharder.set(sharder.addressesKey(version), encodedAddresses, 0))
+	}
+	get := func(version int64) {
+		address, ok, err := sharder.GetAddress(0, version)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, fmt.Sprint(version), address)
+	}
+	get(0)
+	get(1)
+	get(0)
+	// 1 is the least recently used
+	get(2)
+	require.Equal(t, 2, sharder.AddressCacheSize())
+	state, err := sharder.debugState()
+	require.NoError(t, err)
+	require.Equal(t, []int64{0, 2}, state.AddressesVersions)
+	require.Equal(t, uint64(1), state.AddressCacheEvictions)
+	// Evicted versions are fetched again
+	get(1)
+	get(3)
+	require.Equal(t, 2, sharder.AddressCacheSize())
+	state, err = sharder.debugState()
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 3}, state.AddressesVersions)
+	require.Equal(t, uint64(3), state.AddressCacheEvictions)
+}
+
+func TestAddressCacheBounded(t *testing.T) {
+	t.Parallel()
+	sharder := newSharder(discovery.NewMockClient(), 16, WithNamespace("TestAddressCacheBounded"))
+	for version := int64(0); version < 1000; version++ {
+		encodedAddresses, err := sharder.encode(&Addresses{Version: version, Addresses: map[uint64]string{0: fmt.Sprint(version)}})
+		require.NoError(t, err)
+		require.NoError(t, sharder.set(sharder.addressesKey(version), encodedAddresses, 0))
+		address, ok, err := sharder.GetAddress(0, version)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, fmt.Sprint(version), address)
+		require.True(t, sharder.AddressCacheSize() <= defaultAddressCacheSize)
+	}
+	// Evicted versions are still fetched from discovery
+	for version := int64(0); version < 1000; version += 100 {
+		address, ok, err := sharder.GetAddress(0, version)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, fmt.Sprint(version), address)
+	}
+	require.Equal(t, defaultAddressCacheSize, sharder.AddressCacheSize())
+}
+
+func TestDeleteOldAddresses(t *testing.T) {
+	t.Parallel()
+	sharder := newSharder(discovery.NewMockClient(), 16, WithNamespace("TestDeleteOldAddresses"),
+		WithAnnounceInterval(100*time.Millisecond))
+	runCtx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		sharder.RegisterFrontends(runCtx, "frontend", []Frontend{&testFrontend{}})
+	}()
+	go func() {
+		defer wg.Done()
+		sharder.AssignRoles(runCtx, "master")
+	}()
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelCtx()
+	// Each server that registers bumps the version, and we cache every
+	// version's addresses
+	var addresses []string
+	for _, address := range []string{"a", "b", "c", "d"} {
+		address := address
+		addresses = append(addresses, address)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sharder.Register(runCtx, address, []Server{newTestServer()})
+		}()
+		require.NoError(t, sharder.WaitForAvailability(ctx, []string{"frontend"}, addresses))
+		version, err := sharder.GetVersion()
+		require.NoError(t, err)
+		_, err = sharder.GetShardToAddress(version)
+		require.NoError(t, err)
+	}
+	version, err := sharder.GetVersion()
+	require.NoError(t, err)
+	require.True(t, version >= 3)
+	// Once the servers and the frontend are all on the latest version only
+	// its addresses are left, in discovery and in the cache
+	for {
+		encodedAddresses, err := sharder.discoveryClient.GetAll(sharder.addressesDir())
+		require.NoError(t, err)
+		sharder.addressesLock.Lock()
+		cached := sharder.addresses.versions()
+		sharder.addressesLock.Unlock()
+		if len(encodedAddresses) == 1 && len(cached) == 1 {
+			_, ok := encodedAddresses[sharder.addressesKey(version)]
+			require.True(t, ok)
+			require.Equal(t, []int64{version}, cached)
+			break
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatalf("%d versions of addresses are left, %d cached", len(encodedAddresses), len(cached))
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	_, err = sharder.GetShardToAddress(version - 1)
+	require.YesError(t, err)
+}
+
+func TestAddressRetention(t *testing.T) {
+	t.Parallel()
+	sharder := newSharder(discovery.NewMockClient(), 4, WithNamespace("TestAddressRetention"),
+		WithAddressRetentionVersions(5))
+	ctx := context.Background()
+	// Every cycle publishes a version and, as if every server and frontend
+	// had moved to it, compacts the ones before it.
+	for version := int64(0); version < 100; version++ {
+		roles := map[string]*ServerRole{
+			"a": {Address: "a", Version: version, Shards: map[uint64]bool{0: true, 1: true, 2: true, 3: true}},
+		}
+		addresses := &Addresses{Version: version, Addresses: map[uint64]string{0: "a", 1: "a", 2: "a", 3: "a"}}
+		require.NoError(t, sharder.publishRoles(addresses, roles, time.Now()))
+		require.NoError(t, sharder.compactAddresses(ctx, version))
+	}
+	count, err := sharder.GetAddressVersionCount()
+	require.NoError(t, err)
+	require.Equal(t, 5, count)
+	for version := int64(95); version < 100; version++ {
+		_, err := sharder.GetShardToAddress(version)
+		require.NoError(t, err)
+	}
+	_, err = sharder.GetShardToAddress(94)
+	require.YesError(t, err)
+	sharder.addressesLock.Lock()
+	cached := sharder.addresses.versions()
+	sharder.addressesLock.Unlock()
+	sort.Sort(int64Slice(cached))
+	require.Equal(t, []int64{95, 96, 97, 98, 99}, cached)
+
+	// Versions still in use are kept whatever the retention.
+	require.NoError(t, sharder.compactAddresses(ctx, 90))
+	count, err = sharder.GetAddressVersionCount()
+	require.NoError(t, err)
+	require.Equal(t, 5, count)
+}
+
+type weightedTestServer struct {
+	*testServer
+	weight uint64
+}
+
+func (s *weightedTestServer) Weight() uint64 {
+	return s.weight
+}
+
+func TestShardQuotas(t *testing.T) {
+	t.Parallel()
+	serverStates := map[string]*ServerState{
+		"a": {Address: "a"},
+		"b": {Address: "b", Weight: 1},
+		"c": {Address: "c", Weight: 3},
+	}
+	// Equal weights split the shards evenly, as they always have
+	quotas, remainder := shardQuotas(16, []string{"a", "b"}, serverStates)
+	require.Equal(t, map[string]uint64{"a": 8, "b": 8}, quotas)
+	require.Equal(t, uint64(0), remainder)
+	quotas, remainder = shardQuotas(16, []string{"a", "b", "c"}, map[string]*ServerState{
+		"a": {Address: "a"}, "b": {Address: "b"}, "c": {Address: "c"},
+	})
+	require.Equal(t, map[string]uint64{"a": 5, "b": 5, "c": 5}, quotas)
+	require.Equal(t, uint64(1), remainder)
+	quotas, remainder = shardQuotas(16, []string{"b", "c"}, serverStates)
+	require.Equal(t, map[string]uint64{"b": 4, "c": 12}, quotas)
+	require.Equal(t, uint64(0), remainder)
+	quotas, remainder = shardQuotas(16, []string{"a", "b", "c"}, serverStates)
+	require.Equal(t, map[string]uint64{"a": 3, "b": 3, "c": 9}, quotas)
+	require.Equal(t, uint64(1), remainder)
+}
+
+func TestWeightedServers(t *testing.T) {
+	t.Parallel()
+	sharder := newSharder(discovery.NewMockClient(), 16, WithNamespace("TestWeightedServers"))
+	runCtx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+	servers := map[string]*weightedTestServer{
+		"heavy": {newTestServer(), 3},
+		"light": {newTestServer(), 1},
+	}
+	for address, server := range servers {
+		address, server := address, server
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sharder.Register(runCtx, address, []Server{server})
+		}()
+	}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		sharder.RegisterFrontends(runCtx, "frontend", []Frontend{&testFrontend{}})
+	}()
+	go func() {
+		defer wg.Done()
+		sharder.AssignRoles(runCtx, "master")
+	}()
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelCtx()
+	require.NoError(t, sharder.WaitForAvailability(ctx, []string{"frontend"}, []string{"heavy", "light"}))
+	version, err := sharder.GetVersion()
+	require.NoError(t, err)
+	shardToAddress, err := sharder.GetShardToAddress(version)
+	require.NoError(t, err)
+	counts := make(map[string]int)
+	for _, address := range shardToAddress {
+		counts[address]++
+	}
+	require.Equal(t, map[string]int{"heavy": 12, "light": 4}, counts)
+}
+
+func TestWeightRatio(t *testing.T) {
+	t.Parallel()
+	serverStates := map[string]*ServerState{
+		"small":  {Address: "small"},
+		"medium": {Address: "medium", Weight: 2},
+		"large":  {Address: "large", Weight: 4},
+	}
+	// No weight counts as 1
+	addresses, _, err := planRoles(70, StrategyGreedy, nil, serverStates, nil, 0, "")
+	require.NoError(t, err)
+	counts := make(map[string]int)
+	for _, address := range addresses.Addresses {
+		counts[address]++
+	}
+	require.Equal(t, map[string]int{"small": 10, "medium": 20, "large": 40}, counts)
+}
+
+func TestPlanRoles(t *testing.T) {
+	t.Parallel()
+	serverStates := map[string]*ServerState{
+		"a": {Address: "a"},
+		"b": {Address: "b"},
+	}
+	addresses, roles, err := planRoles(16, StrategyGreedy, nil, serverStates, nil, 3, "plan")
+	require.NoError(t, err)
+	require.Equal(t, int64(3), addresses.Version)
+	require.Equal(t, 16, len(addresses.Addresses))
+	require.Equal(t, 2, len(roles))
+	for address, role := range roles {
+		require.Equal(t, address, role.Address)
+		require.Equal(t, int64(3), role.Version)
+		require.Equal(t, "plan", role.CorrelationId)
+		require.Equal(t, 8, len(role.Shards))
+		for shard := range role.Shards {
+			require.Equal(t, address, addresses.Addresses[shard])
+		}
+	}
+	// Planning is deterministic, so a plan matches what's later assigned
+	serverStates["c"] = &ServerState{Address: "c"}
+	newAddresses, newRoles, err := planRoles(16, StrategyGreedy, nil, serverStates, addresses.Addresses, 4, "plan")
+	require.NoError(t, err)
+	require.Equal(t, 3, len(newRoles))
+	for i := 0; i < 10; i++ {
+		againAddresses, _, err := planRoles(16, StrategyGreedy, nil, serverStates, addresses.Addresses, 4, "plan")
+		require.NoError(t, err)
+		require.Equal(t, newAddresses.Addresses, againAddresses.Addresses)
+	}
+	_, _, err = planRoles(16, StrategyGreedy, []Constraint{RequireLabel("zone", "east")}, serverStates, nil, 5, "plan")
+	_, ok := err.(*ConstraintError)
+	require.True(t, ok)
+}
+
+func TestAssignRolesOnce(t *testing.T) {
+	t.Parallel()
+	sharder := newSharder(discovery.NewMockClient(), 16, WithNamespace("TestAssignRolesOnce"))
+	runCtx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+	register := func(address string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sharder.Register(runCtx, address, []Server{newTestServer()})
+		}()
+	}
+	register("a")
+	register("b")
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		sharder.RegisterFrontends(runCtx, "frontend", []Frontend{&testFrontend{}})
+	}()
+	go func() {
+		defer wg.Done()
+		sharder.AssignRoles(runCtx, "master")
+	}()
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelCtx()
+	require.NoError(t, sharder.WaitForAvailability(ctx, []string{"frontend"}, []string{"a", "b"}))
+	version, err := sharder.GetVersion()
+	require.NoError(t, err)
+	roles, err := sharder.discoveryClient.GetAll(sharder.serverRoleDir())
+	require.NoError(t, err)
+
+	addresses, plannedRoles, err := sharder.AssignRolesOnce(ctx, map[string]*ServerState{
+		"a": {Address: "a"},
+		"b": {Address: "b"},
+		"c": {Address: "c"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, version+1, addresses.Version)
+	require.Equal(t, 3, len(plannedRoles))
+	// Nothing was written
+	newVersion, err := sharder.GetVersion()
+	require.NoError(t, err)
+	require.Equal(t, version, newVersion)
+	newRoles, err := sharder.discoveryClient.GetAll(sharder.serverRoleDir())
+	require.NoError(t, err)
+	require.Equal(t, roles, newRoles)
+
+	// AssignRoles makes the same assignment once the server really joins
+	register("c")
+	require.NoError(t, sharder.WaitForAvailability(ctx, []string{"frontend"}, []string{"a", "b", "c"}))
+	newVersion, err = sharder.GetVersion()
+	require.NoError(t, err)
+	require.Equal(t, addresses.Version, newVersion)
+	shardToAddress, err := sharder.GetShardToAddress(newVersion)
+	require.NoError(t, err)
+	require.Equal(t, addresses.Addresses, shardToAddress)
+}
+
+func TestSharderOptions(t *testing.T) {
+	t.Parallel()
+	client := discovery.NewMockClient()
+	sharder := newSharder(client, 16)
+	require.Equal(t, defaultHoldTTL, sharder.holdTTL)
+	require.Equal(t, 10*time.Second, sharder.announceInterval)
+	require.Equal(t, 20*time.Second, sharder.watchStaleAfter)
+	require.Equal(t, 0.0, sharder.rebalanceThreshold)
+	require.Equal(t, 1, sharder.addressRetention)
+	require.Equal(t, time.Minute, sharder.shardCallTimeout)
+	require.False(t, sharder.failStuckShardCalls)
+	require.Equal(t, time.Duration(0), sharder.assignRolesDebounce)
+	require.Equal(t, 0, sharder.maxShardMoves)
+
+	sharder = newSharder(client, 16, WithHoldTTL(0), WithAddressCacheSize(0), WithRebalanceThreshold(-1),
+		WithAddressRetentionVersions(0))
+	require.Equal(t, defaultHoldTTL, sharder.holdTTL)
+	require.Equal(t, 1, sharder.addressRetention)
+	require.Equal(t, 1, sharder.addresses.size)
+	require.Equal(t, 0.0, sharder.rebalanceThreshold)
+
+	sharder = newSharder(client, 16, WithHoldTTL(1), WithRebalanceThreshold(2))
+	require.Equal(t, uint64(1), sharder.holdTTL)
+	require.Equal(t, 500*time.Millisecond, sharder.announceInterval)
+	require.Equal(t, time.Second, sharder.watchStaleAfter)
+	require.Equal(t, 1.0, sharder.rebalanceThreshold)
+
+	sharder = newSharder(client, 16, WithHoldTTL(2), WithAnnounceInterval(0))
+	require.Equal(t, time.Second, sharder.announceInterval)
+	sharder = newSharder(client, 16, WithAnnounceInterval(time.Millisecond))
+	require.Equal(t, time.Millisecond, sharder.announceInterval)
+
+	for _, namespace := range []string{"", "TestSharderOptions"} {
+		sharder = newSharder(client, 16, WithNamespace(namespace))
+		require.NoError(t, sharder.set(sharder.addressesKey(0), namespace, 0))
+		value, err := client.Get(path.Join(namespace, "pfs", "route", sharder.addressesKey(0)))
+		require.NoError(t, err)
+		require.Equal(t, namespace, value)
+	}
+}
+
+func TestAssignRoleDebounce(t *testing.T) {
+	t.Parallel()
+	// publications returns how many versions AssignRoles publishes for 10
+	// servers arriving within 50ms.
+	publications := func(namespace string, options ...SharderOption) int64 {
+		sharder := newSharder(discovery.NewMockClient(), 16, append(options, WithNamespace(namespace))...)
+		runCtx, cancel := context.WithCancel(context.Background())
+		var wg sync.WaitGroup
+		defer wg.Wait()
+		defer cancel()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sharder.AssignRoles(runCtx, "master")
+		}()
+		// Let AssignRoles start watching
+		time.Sleep(100 * time.Millisecond)
+		for i := 0; i < 10; i++ {
+			address := fmt.Sprintf("server%d", i)
+			encoded, err := sharder.encode(&ServerState{Address: address, Version: InvalidVersion})
+			require.NoError(t, err)
+			require.NoError(t, sharder.set(sharder.serverStateKey(address), encoded, 0))
+			time.Sleep(5 * time.Millisecond)
+		}
+		time.Sleep(time.Second)
+		version, err := sharder.GetVersion()
+		require.NoError(t, err)
+		shardToAddress, err := sharder.GetShardToAddress(version)
+		require.NoError(t, err)
+		require.Equal(t, 10, len(shardDistribution(shardToAddress).Servers))
+		return version + 1
+	}
+	require.True(t, publications("TestAssignRoleDebounceOff") > 1)
+	require.Equal(t, int64(1), publications("TestAssignRoleDebounce", WithAssignRoleDebounce(200*time.Millisecond)))
+}
+
+func TestServerChurn(t *testing.T) {
+	t.Parallel()
+	oldServers := map[string]bool{"a": true, "b": true, "c": true, "d": true}
+	require.Equal(t, 0.0, serverChurn(oldServers, map[string]*ServerState{"a": {}, "b": {}, "c": {}, "d": {}}))
+	require.Equal(t, 0.25, serverChurn(oldServers, map[string]*ServerState{"a": {}, "b": {}, "c": {}}))
+	require.Equal(t, 0.4, serverChurn(oldServers, map[string]*ServerState{"a": {}, "b": {}, "c": {}, "e": {}}))
+	require.Equal(t, 0.0, serverChurn(nil, nil))
+}
+
+// handoffTestServer checks that every shard it deletes has already been
+// added to one of others.
+type handoffTestServer struct {
+	*testServer
+	others  []*testServer
+	dropped []uint64
+}
+
+func (s *handoffTestServer) DeleteShard(shard uint64) error {
+	handedOff := false
+	for _, other := range s.others {
+		if other.hasShard(shard) {
+			handedOff = true
+		}
+	}
+	if !handedOff {
+		s.lock.Lock()
+		s.dropped = append(s.dropped, shard)
+		s.lock.Unlock()
+	}
+	return s.testServer.DeleteShard(shard)
+}
+
+func TestDeregister(t *testing.T) {
+	t.Parallel()
+	sharder := newSharder(discovery.NewMockClient(), 16, WithNamespace("TestDeregister"))
+	runCtx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+	a, b := newTestServer(), newTestServer()
+	c := &handoffTestServer{testServer: newTestServer(), others: []*testServer{a, b}}
+	registerErrs := make(map[string]chan error)
+	for address, server := range map[string]Server{"a": a, "b": b, "c": c} {
+		address, server, registerErr := address, server, make(chan error, 1)
+		registerErrs[address] = registerErr
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			registerErr <- sharder.Register(runCtx, address, []Server{server})
+		}()
+	}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		sharder.RegisterFrontends(runCtx, "frontend", []Frontend{&testFrontend{}})
+	}()
+	go func() {
+		defer wg.Done()
+		sharder.AssignRoles(runCtx, "master")
+	}()
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelCtx()
+	require.NoError(t, sharder.WaitForAvailability(ctx, []string{"frontend"}, []string{"a", "b", "c"}))
+	require.True(t, c.numShards() > 0)
+
+	require.NoError(t, sharder.Deregister(ctx, "c"))
+	require.NoError(t, <-registerErrs["c"])
+	require.Equal(t, 0, c.numShards())
+	require.Equal(t, 0, len(c.dropped))
+	for shard := uint64(0); shard < 16; shard++ {
+		require.True(t, a.hasShard(shard) || b.hasShard(shard))
+	}
+	_, err := sharder.getServerState("c")
+	require.YesError(t, err)
+	// c's gone, so there's nothing left to drain
+	require.YesError(t, sharder.Deregister(ctx, "c"))
+}
+
+func TestRebalanceThreshold(t *testing.T) {
+	t.Parallel()
+	sharder := newSharder(discovery.NewMockClient(), 64, WithNamespace("TestRebalanceThreshold"),
+		WithAnnounceJitter(0), WithRebalanceThreshold(0.1))
+	runCtx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+	var addresses []string
+	cancels := make(map[string]context.CancelFunc)
+	registerErrs := make(map[string]chan error)
+	register := func(address string) {
+		ctx, cancel := context.WithCancel(runCtx)
+		registerErr := make(chan error, 1)
+		cancels[address], registerErrs[address] = cancel, registerErr
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			registerErr <- sharder.Register(ctx, address, []Server{newTestServer()})
+		}()
+	}
+	deregister := func(address string) {
+		cancels[address]()
+		require.Equal(t, ErrCancelled, <-registerErrs[address])
+	}
+	for i := 0; i < 20; i++ {
+		address := fmt.Sprintf("server-%d", i)
+		addresses = append(addresses, address)
+		register(address)
+	}
+	// AssignRoles starts once every server is up, so that they all get
+	// shards
+	for serverStates, err := sharder.getServerStates(); len(serverStates) < 20; serverStates, err = sharder.getServerStates() {
+		require.NoError(t, err)
+		time.Sleep(10 * time.Millisecond)
+	}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		sharder.RegisterFrontends(runCtx, "frontend", []Frontend{&testFrontend{}})
+	}()
+	go func() {
+		defer wg.Done()
+		sharder.AssignRoles(runCtx, "master")
+	}()
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancelCtx()
+	require.NoError(t, sharder.WaitForAvailability(ctx, []string{"frontend"}, addresses))
+	version, err := sharder.GetVersion()
+	require.NoError(t, err)
+	deferredRebalances := func() uint64 {
+		state, err := sharder.debugState()
+		require.NoError(t, err)
+		return state.DeferredRebalances
+	}
+
+	// One server flapping is 5% of them, which isn't enough to move shards
+	deregister("server-0")
+	for deferredRebalances() == 0 {
+		require.NoError(t, ctx.Err())
+		time.Sleep(10 * time.Millisecond)
+	}
+	register("server-0")
+	require.NoError(t, sharder.WaitForAvailability(ctx, []string{"frontend"}, addresses))
+	newVersion, err := sharder.GetVersion()
+	require.NoError(t, err)
+	require.Equal(t, version, newVersion)
+
+	// Two servers leaving is 10%, which is enough
+	deregister("server-1")
+	deregister("server-2")
+	require.NoError(t, sharder.WaitForAvailability(ctx, []string{"frontend"}, append([]string{"server-0"}, addresses[3:]...)))
+	newVersion, err = sharder.GetVersion()
+	require.NoError(t, err)
+	require.True(t, newVersion > version)
+	shardToAddress, err := sharder.GetShardToAddress(newVersion)
+	require.NoError(t, err)
+	for _, address := range shardToAddress {
+		require.True(t, address != "server-1" && address != "server-2")
+	}
+}
+
+func TestStats(t *testing.T) {
+	t.Parallel()
+	sharder := newSharder(discovery.NewMockClient(), 16, WithNamespace("TestStats"))
+	stats, err := sharder.Stats()
+	require.NoError(t, err)
+	require.Equal(t, InvalidVersion, stats.Version)
+	require.Equal(t, 0, stats.Servers)
+	runCtx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+	for _, address := range []string{"a", "b"} {
+		address := address
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sharder.Register(runCtx, address, []Server{newTestServer()})
+		}()
+	}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		sharder.RegisterFrontends(runCtx, "frontend", []Frontend{&testFrontend{}})
+	}()
+	go func() {
+		defer wg.Done()
+		sharder.AssignRoles(runCtx, "master")
+	}()
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelCtx()
+	require.NoError(t, sharder.WaitForAvailability(ctx, []string{"frontend"}, []string{"a", "b"}))
+	stats, err = sharder.Stats()
+	require.NoError(t, err)
+	version, err := sharder.GetVersion()
+	require.NoError(t, err)
+	require.Equal(t, version, stats.Version)
+	require.Equal(t, 2, stats.Servers)
+	require.Equal(t, map[uint64]int{8: 2}, stats.ShardsPerServer)
+	// Every version AssignRoles published is counted, the first may have
+	// had only one of the servers
+	require.Equal(t, uint64(version+1), stats.Reassignments)
+	require.True(t, stats.AddShards >= 16)
+	require.True(t, stats.RemoveShards <= stats.AddShards-16)
+}
+
+// failingServer fails to add the shards in fail.
+type failingServer struct {
+	*testServer
+	fail map[uint64]bool
+}
+
+func (s *failingServer) AddShard(shard uint64) error {
+	if s.fail[shard] {
+		return fmt.Errorf("disk full")
+	}
+	return s.testServer.AddShard(shard)
+}
+
+func TestShardErrors(t *testing.T) {
+	t.Parallel()
+	sharder := newSharder(discovery.NewMockClient(), 16, WithNamespace("TestShardErrors"))
+	runCtx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sharder.AssignRoles(runCtx, "master")
+	}()
+	server := &failingServer{newTestServer(), map[uint64]bool{3: true, 7: true}}
+	err := sharder.Register(runCtx, "server", []Server{server, newTestServer()})
+	shardErrs, ok := err.(*ShardErrors)
+	require.True(t, ok, "%v", err)
+	require.True(t, shardErrs.Add)
+	require.Equal(t, 2, len(shardErrs.Errors))
+	require.Equal(t, "pachyderm: failed to add 2 shards: 3: disk full; 7: disk full", err.Error())
+	require.Equal(t, 14, server.numShards())
+}
+
+type splittingTestServer struct {
+	*testServer
+	// splitFrom maps the shards the server has split to the shard they were
+	// split from.
+	splitFrom map[uint64]uint64
+}
+
+func newSplittingTestServer() *splittingTestServer {
+	return &splittingTestServer{testServer: newTestServer(), splitFrom: make(map[uint64]uint64)}
+}
+
+func (s *splittingTestServer) SplitShard(oldShard uint64, newShard uint64, version int64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if !s.shards[oldShard] {
+		return fmt.Errorf("can't split %d from %d, which we don't have", newShard, oldShard)
+	}
+	s.shards[newShard] = true
+	s.splitFrom[newShard] = oldShard
+	return nil
+}
+
+func (s *splittingTestServer) splitFromShard(shard uint64) (uint64, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	oldShard, ok := s.splitFrom[shard]
+	return oldShard, ok
+}
+
+func TestReshard(t *testing.T) {
+	t.Parallel()
+	sharder := newSharder(discovery.NewMockClient(), 8, WithNamespace("TestReshard"),
+		WithAnnounceInterval(100*time.Millisecond))
+	runCtx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+	a, b := newSplittingTestServer(), newSplittingTestServer()
+	// c isn't a SplittingServer, so it's given the new shards with AddShard
+	c := newTestServer()
+	servers := map[string]*testServer{"a": a.testServer, "b": b.testServer, "c": c}
+	for address, server := range map[string]Server{"a": a, "b": b, "c": c} {
+		address, server := address, server
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sharder.Register(runCtx, address, []Server{server})
+		}()
+	}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		sharder.RegisterFrontends(runCtx, "frontend", []Frontend{&testFrontend{}})
+	}()
+	go func() {
+		defer wg.Done()
+		sharder.AssignRoles(runCtx, "master")
+	}()
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelCtx()
+	require.NoError(t, sharder.WaitForAvailability(ctx, []string{"frontend"}, []string{"a", "b", "c"}))
+	oldVersion, err := sharder.GetVersion()
+	require.NoError(t, err)
+	oldShardToAddress, err := sharder.GetShardToAddress(oldVersion)
+	require.NoError(t, err)
+
+	require.True(t, errorutil.Is(sharder.Reshard(ctx, 8, 12), errorutil.Conflict))
+	require.True(t, errorutil.Is(sharder.Reshard(ctx, 4, 8), errorutil.Conflict))
+	require.NoError(t, sharder.Reshard(ctx, 8, 16))
+	version, err := sharder.GetVersion()
+	require.NoError(t, err)
+	shardToAddress, err := sharder.GetShardToAddress(version)
+	require.NoError(t, err)
+	require.Equal(t, 16, len(shardToAddress))
+	for shard := uint64(0); shard < 8; shard++ {
+		address := oldShardToAddress[shard]
+		require.Equal(t, address, shardToAddress[shard])
+		require.Equal(t, address, shardToAddress[shard+8])
+		require.True(t, servers[address].hasShard(shard+8))
+		for splittingAddress, server := range map[string]*splittingTestServer{"a": a, "b": b} {
+			oldShard, ok := server.splitFromShard(shard + 8)
+			require.Equal(t, address == splittingAddress, ok)
+			if ok {
+				require.Equal(t, shard, oldShard)
+			}
+		}
+	}
+	shardHealths, err := sharder.GetShardHealth(version)
+	require.NoError(t, err)
+	require.Equal(t, 16, len(shardHealths))
+	// The shards have already been split
+	require.True(t, errorutil.Is(sharder.Reshard(ctx, 8, 16), errorutil.Conflict))
+	require.NoError(t, sharder.Reshard(ctx, 16, 16))
+}
+
+func TestGracefulDrainServer(t *testing.T) {
+	t.Parallel()
+	// c is registered by a different sharder from the one that drains it,
+	// as it would be in a different process.
+	discoveryClient := discovery.NewMockClient()
+	sharder := newSharder(discoveryClient, 16, WithNamespace("TestGracefulDrainServer"),
+		WithAnnounceInterval(100*time.Millisecond))
+	otherSharder := newSharder(discoveryClient, 16, WithNamespace("TestGracefulDrainServer"),
+		WithAnnounceInterval(100*time.Millisecond))
+	runCtx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+	a, b, c := newTestServer(), newTestServer(), newTestServer()
+	registerErrs := make(map[string]chan error)
+	for address, server := range map[string]*testServer{"a": a, "b": b, "c": c} {
+		address, server, registerErr := address, server, make(chan error, 1)
+		registerErrs[address] = registerErr
+		registeringSharder := sharder
+		if address == "c" {
+			registeringSharder = otherSharder
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			registerErr <- registeringSharder.Register(runCtx, address, []Server{server})
+		}()
+	}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		sharder.RegisterFrontends(runCtx, "frontend", []Frontend{&testFrontend{}})
+	}()
+	go func() {
+		defer wg.Done()
+		sharder.AssignRoles(runCtx, "master")
+	}()
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelCtx()
+	require.NoError(t, sharder.WaitForAvailability(ctx, []string{"frontend"}, []string{"a", "b", "c"}))
+	require.True(t, c.numShards() > 0)
+	require.True(t, errorutil.Is(sharder.GracefulDrainServer(ctx, "d"), errorutil.NotFound))
+
+	require.NoError(t, sharder.GracefulDrainServer(ctx, "c"))
+	require.NoError(t, <-registerErrs["c"])
+	require.Equal(t, 0, c.numShards())
+	for shard := uint64(0); shard < 16; shard++ {
+		require.True(t, a.hasShard(shard) || b.hasShard(shard))
+	}
+	_, err := sharder.getServerState("c")
+	require.YesError(t, err)
+	drainRequests, err := sharder.discoveryClient.GetAll(sharder.serverDrainKey("c"))
+	require.NoError(t, err)
+	require.Equal(t, 0, len(drainRequests))
+
+	// Once b has drained a is the only server left, so it can't hand off
+	// its shards.
+	require.NoError(t, sharder.GracefulDrainServer(ctx, "b"))
+	require.NoError(t, <-registerErrs["b"])
+	drainCtx, cancelDrain := context.WithTimeout(ctx, time.Second)
+	defer cancelDrain()
+	err = sharder.GracefulDrainServer(drainCtx, "a")
+	drainErr, ok := err.(*DrainError)
+	require.True(t, ok, "%v is not a *DrainError", err)
+	require.Equal(t, "a", drainErr.Address)
+	require.Equal(t, 16, len(drainErr.Shards))
+	require.Equal(t, context.DeadlineExceeded, drainErr.Err)
+}
+
+func TestDiffShardAssignments(t *testing.T) {
+	t.Parallel()
+	oldShards := map[uint64]string{0: "a", 1: "a", 2: "b"}
+	newShards := map[uint64]string{0: "a", 1: "b", 2: "b"}
+	// Moving shard 1 from a to b is two events
+	require.Equal(t, []ShardAssignmentEvent{
+		{Version: 3, Shard: 1, Role: ShardRoleMaster, OldAddress: "a"},
+		{Version: 3, Shard: 1, Role: ShardRoleMaster, NewAddress: "b"},
+	}, diffShardAssignments(3, oldShards, newShards))
+	require.Equal(t, 0, len(diffShardAssignments(3, oldShards, oldShards)))
+	require.Equal(t, []ShardAssignmentEvent{
+		{Version: 0, Shard: 0, Role: ShardRoleMaster, NewAddress: "a"},
+	}, diffShardAssignments(0, nil, map[uint64]string{0: "a"}))
+}
+
+func TestWatchShardAssignments(t *testing.T) {
+	t.Parallel()
+	sharder := newSharder(discovery.NewMockClient(), 16, WithNamespace("TestWatchShardAssignments"))
+	runCtx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+	register := func(address string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sharder.Register(runCtx, address, []Server{newTestServer()})
+		}()
+	}
+	register("a")
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		sharder.RegisterFrontends(runCtx, "frontend", []Frontend{&testFrontend{}})
+	}()
+	go func() {
+		defer wg.Done()
+		sharder.AssignRoles(runCtx, "master")
+	}()
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelCtx()
+	require.NoError(t, sharder.WaitForAvailability(ctx, []string{"frontend"}, []string{"a"}))
+	events, err := sharder.WatchShardAssignments(ctx)
+	require.NoError(t, err)
+
+	// b takes half of a's shards
+	register("b")
+	require.NoError(t, sharder.WaitForAvailability(ctx, []string{"frontend"}, []string{"a", "b"}))
+	version, err := sharder.GetVersion()
+	require.NoError(t, err)
+	shardToAddress, err := sharder.GetShardToAddress(version)
+	require.NoError(t, err)
+	shardEvents := make(map[uint64][]ShardAssignmentEvent)
+	for i := 0; i < 16; i++ {
+		event := <-events
+		require.Equal(t, version, event.Version)
+		shardEvents[event.Shard] = append(shardEvents[event.Shard], event)
+	}
+	require.Equal(t, 8, len(shardEvents))
+	for shard, address := range shardToAddress {
+		if address != "b" {
+			continue
+		}
+		require.Equal(t, []ShardAssignmentEvent{
+			{Version: version, Shard: shard, Role: ShardRoleMaster, OldAddress: "a"},
+			{Version: version, Shard: shard, Role: ShardRoleMaster, NewAddress: "b"},
+		}, shardEvents[shard])
+	}
+	select {
+	case event := <-events:
+		t.Fatalf("unexpected event %+v", event)
+	default:
+	}
+	cancelCtx()
+	for range events {
+	}
+}
+
+func TestServerExpiry(t *testing.T) {
+	t.Parallel()
+	// Only the ghost's state has a ttl short enough to expire when the clock
+	// is advanced, the registered servers' states last for holdTTL.
+	clock := discovery.NewFakeClock(time.Unix(1000, 0))
+	sharder := newSharder(discovery.NewMockClientWithClock(clock), 16, WithNamespace("TestServerExpiry"),
+		WithAnnounceInterval(100*time.Millisecond))
+	encoded, err := sharder.encode(&ServerState{Address: "ghost", Version: InvalidVersion, SchemaVersion: SchemaVersion})
+	require.NoError(t, err)
+	require.NoError(t, sharder.discoveryClient.Set(sharder.serverStateKey("ghost"), encoded, 1))
+	runCtx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+	a, b := newTestServer(), newTestServer()
+	for address, server := range map[string]*testServer{"a": a, "b": b} {
+		address, server := address, server
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sharder.Register(runCtx, address, []Server{server})
+		}()
+	}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		sharder.RegisterFrontends(runCtx, "frontend", []Frontend{&testFrontend{}})
+	}()
+	go func() {
+		defer wg.Done()
+		sharder.AssignRoles(runCtx, "master")
+	}()
+	ghostShards := func() int {
+		version, err := sharder.GetVersion()
+		if err == ErrNoVersion {
+			return 0
+		}
+		require.NoError(t, err)
+		addresses, err := sharder.getAddresses(version)
+		require.NoError(t, err)
+		result := 0
+		for _, address := range addresses.Addresses {
+			if address == "ghost" {
+				result++
+			}
+		}
+		return result
+	}
+	for deadline := time.Now().Add(10 * time.Second); ghostShards() == 0; {
+		require.True(t, time.Now().Before(deadline), "ghost never assigned shards")
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	clock.Advance(2 * time.Second)
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelCtx()
+	for deadline := time.Now().Add(10 * time.Second); ghostShards() > 0; {
+		require.True(t, time.Now().Before(deadline), "ghost's shards never reassigned")
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.NoError(t, sharder.WaitForAvailability(ctx, []string{"frontend"}, []string{"a", "b"}))
+	for shard := uint64(0); shard < 16; shard++ {
+		require.True(t, a.hasShard(shard) || b.hasShard(shard))
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	t.Parallel()
+	sharder := newSharder(discovery.NewMockClient(), 4, WithNamespace("TestSnapshot"))
+	_, err := sharder.Snapshot(0)
+	require.YesError(t, err)
+	publish := func(version int64, shardToAddress map[uint64]string) {
+		roles := map[string]*ServerRole{
+			"a": {Address: "a", Version: version, Shards: make(map[uint64]bool)},
+			"b": {Address: "b", Version: version, Shards: make(map[uint64]bool)},
+		}
+		for shard, address := range shardToAddress {
+			roles[address].Shards[shard] = true
+		}
+		addresses := &Addresses{Version: version, Addresses: shardToAddress}
+		require.NoError(t, sharder.publishRoles(addresses, roles, time.Now()))
+	}
+	publish(0, map[uint64]string{0: "a", 1: "a", 2: "a", 3: "a"})
+	publish(1, map[uint64]string{0: "a", 1: "b", 2: "a", 3: "b"})
+
+	snapshot, err := sharder.Snapshot(0)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), snapshot.Version)
+	require.Equal(t, map[uint64]string{0: "a", 1: "a", 2: "a", 3: "a"}, snapshot.ShardToMaster)
+	require.Equal(t, 4, len(snapshot.ServerRoles["a"].Shards))
+	require.Equal(t, 0, len(snapshot.ServerRoles["b"].Shards))
+	snapshot, err = sharder.Snapshot(1)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), snapshot.Addresses.Version)
+	require.Equal(t, map[uint64]string{0: "a", 1: "b", 2: "a", 3: "b"}, snapshot.ShardToMaster)
+	require.Equal(t, map[uint64]bool{1: true, 3: true}, snapshot.ServerRoles["b"].Shards)
+	_, err = sharder.Snapshot(2)
+	require.YesError(t, err)
+
+	// The same version always encodes the same way, different versions
+	// don't.
+	encoded, err := MarshalSnapshotJSON(snapshot)
+	require.NoError(t, err)
+	snapshot, err = sharder.Snapshot(1)
+	require.NoError(t, err)
+	reencoded, err := MarshalSnapshotJSON(snapshot)
+	require.NoError(t, err)
+	require.Equal(t, string(encoded), string(reencoded))
+	snapshot, err = sharder.Snapshot(0)
+	require.NoError(t, err)
+	other, err := MarshalSnapshotJSON(snapshot)
+	require.NoError(t, err)
+	require.NotEqual(t, string(encoded), string(other))
+}
+
+func TestServerInspection(t *testing.T) {
+	t.Parallel()
+	sharder := newSharder(discovery.NewMockClient(), 2, WithNamespace("TestServerInspection"))
+	ctx := context.Background()
+	addresses, err := sharder.ListServerAddresses(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(addresses))
+	_, err = sharder.GetServerState(ctx, "a")
+	require.True(t, errorutil.Is(err, errorutil.NotFound))
+	for _, address := range []string{"b", "a"} {
+		encoded, err := sharder.encode(&ServerState{Address: address, Version: 3})
+		require.NoError(t, err)
+		require.NoError(t, sharder.set(sharder.serverStateKey(address), encoded, 0))
+	}
+	roles := map[string]*ServerRole{
+		"a": {Address: "a", Version: 3, Shards: map[uint64]bool{0: true, 1: true}},
+		"b": {Address: "b", Version: 3, Shards: make(map[uint64]bool)},
+	}
+	require.NoError(t, sharder.publishRoles(&Addresses{Version: 3, Addresses: map[uint64]string{0: "a", 1: "a"}}, roles, time.Now()))
+
+	addresses, err = sharder.ListServerAddresses(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, addresses)
+	serverState, err := sharder.GetServerState(ctx, "a")
+	require.NoError(t, err)
+	require.Equal(t, int64(3), serverState.Version)
+	serverRoles, err := sharder.GetServerRole(ctx, "a")
+	require.NoError(t, err)
+	require.Equal(t, map[uint64]bool{0: true, 1: true}, serverRoles[3].Shards)
+	serverRoles, err = sharder.GetServerRole(ctx, "c")
+	require.NoError(t, err)
+	require.Equal(t, 0, len(serverRoles))
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = sharder.ListServerAddresses(cancelled)
+	require.YesError(t, err)
+}
+
+func TestNamespace(t *testing.T) {
+	t.Parallel()
+	discoveryClient := discovery.NewMockClient()
+	for _, namespace := range []string{"/", "/prod", "prod//a", "prod/../other", "./prod"} {
+		_, err := NewSharder(discoveryClient, 2, WithNamespace(namespace))
+		require.YesError(t, err, "namespace %q", namespace)
+	}
+	namespaced := func(namespace string) Sharder {
+		sharder, err := NewSharder(discoveryClient, 2, WithNamespace(namespace))
+		require.NoError(t, err)
+		return sharder
+	}
+	prod := namespaced("TestNamespace/prod/")
+	require.Equal(t, "TestNamespace/prod", prod.Namespace())
+	staging := namespaced("TestNamespace/staging")
+	encoded, err := prod.(*sharder).encode(&ServerState{Address: "a"})
+	require.NoError(t, err)
+	require.NoError(t, prod.(*sharder).set(prod.(*sharder).serverStateKey("a"), encoded, 0))
+
+	ctx := context.Background()
+	addresses, err := prod.ListServerAddresses(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a"}, addresses)
+	addresses, err = namespaced("TestNamespace/prod").ListServerAddresses(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a"}, addresses)
+	addresses, err = staging.ListServerAddresses(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(addresses))
+}
+
+func TestFrontendIDs(t *testing.T) {
+	t.Parallel()
+	sharder := newSharder(discovery.NewMockClient(), 16, WithNamespace("TestFrontendIDs"),
+		WithAnnounceInterval(100*time.Millisecond))
+	runCtx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+	wg.Add(4)
+	go func() {
+		defer wg.Done()
+		sharder.Register(runCtx, "server", []Server{newTestServer()})
+	}()
+	// Two frontends behind one address
+	for _, id := range []string{"frontend-0", "frontend-1"} {
+		id := id
+		go func() {
+			defer wg.Done()
+			sharder.RegisterFrontends(runCtx, "balancer", []Frontend{&testFrontend{}}, WithFrontendID(id))
+		}()
+	}
+	go func() {
+		defer wg.Done()
+		sharder.AssignRoles(runCtx, "master")
+	}()
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelCtx()
+	require.NoError(t, sharder.WaitForAvailability(ctx, []string{"frontend-0", "frontend-1"}, []string{"server"}))
+	liveness, err := sharder.GetLiveness()
+	require.NoError(t, err)
+	require.Equal(t, 2, len(liveness.Frontends))
+
+	// States written before frontends had IDs are identified by address
+	encoded, err := sharder.encode(&FrontendState{Address: "old"})
+	require.NoError(t, err)
+	frontendState, err := decodeFrontendState(encoded)
+	require.NoError(t, err)
+	require.Equal(t, "old", frontendState.Id)
+}
+
+func TestRegisterWithEvents(t *testing.T) {
+	t.Parallel()
+	sharder := newSharder(discovery.NewMockClient(), 16, WithNamespace("TestRegisterWithEvents"),
+		WithAnnounceInterval(100*time.Millisecond))
+	runCtx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+	a, b := newTestServer(), newTestServer()
+	events := make(chan RegisterEvent, 64)
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		sharder.RegisterWithEvents(runCtx, "a", []Server{a}, events)
+	}()
+	go func() {
+		defer wg.Done()
+		sharder.RegisterFrontends(runCtx, "frontend", []Frontend{&testFrontend{}})
+	}()
+	go func() {
+		defer wg.Done()
+		sharder.AssignRoles(runCtx, "master")
+	}()
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelCtx()
+	require.NoError(t, sharder.WaitForAvailability(ctx, []string{"frontend"}, []string{"a"}))
+	require.Equal(t, RegisterEventAnnounced, (<-events).Type)
+	event := <-events
+	require.Equal(t, RegisterEventRolesReceived, event.Type)
+	require.Equal(t, 16, len(event.Shards))
+	for i, shard := range event.Shards {
+		require.Equal(t, uint64(i), shard)
+	}
+
+	// b joining gives a a new role without some of its shards, and then
+	// they're removed.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sharder.Register(runCtx, "b", []Server{b})
+	}()
+	require.NoError(t, sharder.WaitForAvailability(ctx, []string{"frontend"}, []string{"a", "b"}))
+	var received RegisterEvent
+	for event := range events {
+		if event.Type == RegisterEventRolesReceived {
+			received = event
+		}
+		if event.Type == RegisterEventRolesRemoved {
+			require.Equal(t, received.Version-1, event.Version)
+			break
+		}
+	}
+	require.Equal(t, a.numShards(), len(received.Shards))
+	for _, shard := range received.Shards {
+		require.True(t, a.hasShard(shard))
+	}
+}
+
+func TestSwapShardMaster(t *testing.T) {
+	t.Parallel()
+	sharder := newSharder(discovery.NewMockClient(), 16, WithNamespace("TestSwapShardMaster"),
+		WithAnnounceInterval(100*time.Millisecond))
+	runCtx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+	servers := map[string]*testServer{"a": newTestServer(), "b": newTestServer(), "c": newTestServer()}
+	for address, server := range servers {
+		address, server := address, server
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sharder.Register(runCtx, address, []Server{server})
+		}()
+	}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		sharder.RegisterFrontends(runCtx, "frontend", []Frontend{&testFrontend{}})
+	}()
+	go func() {
+		defer wg.Done()
+		sharder.AssignRoles(runCtx, "master")
+	}()
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelCtx()
+	require.NoError(t, sharder.WaitForAvailability(ctx, []string{"frontend"}, []string{"a", "b", "c"}))
+
+	// 16 shards between 3 servers is 6, 5 and 5, so one shard can move from
+	// the server with 6 to one with 5, but no more.
+	version, err := sharder.GetVersion()
+	require.NoError(t, err)
+	distribution, err := sharder.GetShardDistribution(version)
+	require.NoError(t, err)
+	var from, to string
+	for address, count := range distribution.Servers {
+		if count == 6 {
+			from = address
+		} else if to == "" {
+			to = address
+		}
+	}
+	require.True(t, from != "" && to != "", "unexpected distribution %v", distribution.Servers)
+	shardToAddress, err := sharder.GetShardToAddress(version)
+	require.NoError(t, err)
+	var shard uint64
+	for shard = 0; shardToAddress[shard] != from; shard++ {
+	}
+	require.True(t, errorutil.Is(sharder.SwapShardMaster(ctx, shard, to, from), errorutil.Conflict))
+	require.True(t, errorutil.Is(sharder.SwapShardMaster(ctx, shard, from, "d"), errorutil.NotFound))
+	require.True(t, errorutil.Is(sharder.SwapShardMaster(ctx, 16, from, to), errorutil.NotFound))
+
+	require.NoError(t, sharder.SwapShardMaster(ctx, shard, from, to))
+	newVersion, err := sharder.GetVersion()
+	require.NoError(t, err)
+	require.True(t, newVersion > version)
+	address, ok, err := sharder.GetAddress(shard, newVersion)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, to, address)
+	require.True(t, servers[to].hasShard(shard))
+	swaps, err := sharder.discoveryClient.GetAll(sharder.swapDir())
+	require.NoError(t, err)
+	require.Equal(t, 0, len(swaps))
+
+	// to now has its 6
+	var other uint64
+	for other = 0; shardToAddress[other] != from || other == shard; other++ {
+	}
+	require.True(t, errorutil.Is(sharder.SwapShardMaster(ctx, other, from, to), errorutil.Conflict))
+}
+
+// blockingTestServer's AddShard blocks until unblock is closed.
+type blockingTestServer struct {
+	*testServer
+	unblock chan struct{}
+}
+
+func (s *blockingTestServer) AddShard(shard uint64) error {
+	<-s.unblock
+	return s.testServer.AddShard(shard)
+}
+
+func TestStuckShardCalls(t *testing.T) {
+	t.Parallel()
+	stuckShardCalls := func(sharder *sharder) uint64 {
+		state, err := sharder.debugState()
+		require.NoError(t, err)
+		return state.StuckShardCalls
+	}
+	// Stuck calls are reported while they run, and still succeed
+	sharder := newSharder(discovery.NewMockClient(), 16, WithShardCallTimeout(10*time.Millisecond))
+	server := &blockingTestServer{newTestServer(), make(chan struct{})}
+	time.AfterFunc(100*time.Millisecond, func() { close(server.unblock) })
+	require.NoError(t, sharder.callServers([]Server{server}, []uint64{3}, true))
+	require.True(t, server.hasShard(3))
+	require.True(t, stuckShardCalls(sharder) >= 2)
+
+	// Or fail, if the sharder is told to give up on them
+	sharder = newSharder(discovery.NewMockClient(), 16, WithShardCallTimeout(10*time.Millisecond), WithFailStuckShardCalls())
+	server = &blockingTestServer{newTestServer(), make(chan struct{})}
+	defer close(server.unblock)
+	err := sharder.callServers([]Server{server, newTestServer()}, []uint64{3}, true)
+	shardErrors, ok := err.(*ShardErrors)
+	require.True(t, ok, "%v is not a *ShardErrors", err)
+	require.True(t, errorutil.Is(shardErrors.Errors[3], errorutil.Unavailable))
+	require.Equal(t, uint64(1), stuckShardCalls(sharder))
+	require.False(t, server.hasShard(3))
+}
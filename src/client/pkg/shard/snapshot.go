@@ -0,0 +1,73 @@
+package shard
+
+import (
+	"encoding/json"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errorutil"
+	"golang.org/x/net/context"
+)
+
+// ClusterSnapshot is the assignment published as one version.
+type ClusterSnapshot struct {
+	Version int64 `json:"version"`
+	// ShardToMaster is the server each shard is assigned to.
+	ShardToMaster map[uint64]string `json:"shard_to_master"`
+	// ServerRoles are the roles of the servers the version was assigned
+	// to, keyed by address. Roles are deleted once every server has moved
+	// past them, so an old version's may be missing.
+	ServerRoles map[string]*ServerRole `json:"server_roles"`
+	Addresses   *Addresses             `json:"addresses"`
+}
+
+// Snapshot returns the assignment published as version, as it was
+// published, without assigning roles again.
+func (a *sharder) Snapshot(version int64) (*ClusterSnapshot, error) {
+	// A version's roles are all set before its addresses and neither
+	// changes afterwards, so once the addresses are found the roles are
+	// the ones that go with them.
+	addresses, err := a.getAddresses(version)
+	if err != nil {
+		return nil, err
+	}
+	serverRoles, err := a.getServerRoles()
+	if err != nil {
+		return nil, errorutil.Wrap(err, "could not get server roles").WithVersion(version)
+	}
+	result := &ClusterSnapshot{
+		Version:       version,
+		ShardToMaster: make(map[uint64]string),
+		ServerRoles:   make(map[string]*ServerRole),
+		Addresses:     addresses,
+	}
+	for shard, address := range addresses.Addresses {
+		result.ShardToMaster[shard] = address
+	}
+	for address, roles := range serverRoles {
+		if serverRole, ok := roles[version]; ok {
+			result.ServerRoles[address] = serverRole
+		}
+	}
+	return result, nil
+}
+
+func (s *localSharder) Snapshot(version int64) (*ClusterSnapshot, error) {
+	addresses, roles, err := s.AssignRolesOnce(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	result := &ClusterSnapshot{
+		ShardToMaster: make(map[uint64]string),
+		ServerRoles:   roles,
+		Addresses:     addresses,
+	}
+	for shard, address := range s.shardToAddress {
+		result.ShardToMaster[shard] = address
+	}
+	return result, nil
+}
+
+// MarshalSnapshotJSON encodes s as JSON. Maps are encoded in key order, so
+// two snapshots of the same assignment encode to the same bytes.
+func MarshalSnapshotJSON(s *ClusterSnapshot) ([]byte, error) {
+	return json.MarshalIndent(s, "", "  ")
+}
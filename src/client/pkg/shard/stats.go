@@ -0,0 +1,66 @@
+package shard
+
+// Stats are numbers describing a sharder's assignment and how much it's been
+// churning, meant to be exported to monitoring.
+type Stats struct {
+	// Version is the current version, or InvalidVersion if no roles have
+	// been assigned yet.
+	Version int64
+	// Servers is the number of registered servers.
+	Servers int
+	// ShardsPerServer is a histogram of how many shards the registered
+	// servers hold at Version: the number of servers holding each number of
+	// shards.
+	ShardsPerServer map[uint64]int
+	// Reassignments counts the versions AssignRoles has published in this
+	// process.
+	Reassignments uint64
+	// AddShards and RemoveShards count the calls to Server.AddShard and
+	// Server.DeleteShard made for servers registered in this process.
+	AddShards    uint64
+	RemoveShards uint64
+}
+
+// Stats returns the sharder's Stats.
+func (a *sharder) Stats() (*Stats, error) {
+	result := &Stats{ShardsPerServer: make(map[uint64]int)}
+	a.debug.lock.Lock()
+	result.Reassignments = a.debug.reassignments
+	result.AddShards = a.debug.addShards
+	result.RemoveShards = a.debug.removeShards
+	a.debug.lock.Unlock()
+	serverStates, err := a.getServerStates()
+	if err != nil {
+		return nil, err
+	}
+	result.Servers = len(serverStates)
+	version, err := a.GetVersion()
+	if err == ErrNoVersion {
+		result.Version = InvalidVersion
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.Version = version
+	distribution, err := a.GetShardDistribution(version)
+	if err != nil {
+		return nil, err
+	}
+	for address := range serverStates {
+		result.ShardsPerServer[distribution.Servers[address]]++
+	}
+	return result, nil
+}
+
+func (s *localSharder) Stats() (*Stats, error) {
+	distribution := shardDistribution(s.shardToAddress)
+	result := &Stats{
+		Servers:         len(distribution.Servers),
+		ShardsPerServer: make(map[uint64]int),
+	}
+	for _, count := range distribution.Servers {
+		result.ShardsPerServer[count]++
+	}
+	return result, nil
+}
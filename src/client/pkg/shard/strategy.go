@@ -0,0 +1,80 @@
+package shard
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"sort"
+)
+
+// AssignmentStrategy is how AssignRoles chooses a server for each shard.
+type AssignmentStrategy int
+
+const (
+	// StrategyGreedy leaves shards where they are when it can, and places
+	// the rest on the servers with the fewest shards alongside them,
+	// following the soft constraints. It's the default.
+	StrategyGreedy AssignmentStrategy = iota
+	// StrategyRendezvous places each shard on the server that scores
+	// highest for it, by rendezvous hashing of the shard and the server's
+	// address, that still has room for it. The assignment depends only on
+	// the servers, not on where the shards were, and a server joining or
+	// leaving mostly moves only the shards it scores highest for. Soft
+	// constraints aren't followed.
+	StrategyRendezvous
+)
+
+func (s AssignmentStrategy) String() string {
+	switch s {
+	case StrategyGreedy:
+		return "Greedy"
+	case StrategyRendezvous:
+		return "Rendezvous"
+	}
+	return "Unknown"
+}
+
+// rendezvousCandidates returns addresses ordered from the highest to the
+// lowest rendezvous score for shard.
+func rendezvousCandidates(shard uint64, addresses []string) []string {
+	candidates := &byScore{addresses: append([]string(nil), addresses...)}
+	for _, address := range candidates.addresses {
+		candidates.scores = append(candidates.scores, rendezvousScore(shard, address))
+	}
+	sort.Sort(candidates)
+	return candidates.addresses
+}
+
+// rendezvousScore hashes shard and address together.
+func rendezvousScore(shard uint64, address string) uint64 {
+	hash := fnv.New64a()
+	var encodedShard [8]byte
+	binary.BigEndian.PutUint64(encodedShard[:], shard)
+	hash.Write(encodedShard[:])
+	hash.Write([]byte(address))
+	// fnv's low bits barely depend on the last bytes hashed, so the sum is
+	// mixed before it's compared.
+	result := hash.Sum64()
+	result ^= result >> 33
+	result *= 0xff51afd7ed558ccd
+	result ^= result >> 33
+	result *= 0xc4ceb9fe1a85ec53
+	result ^= result >> 33
+	return result
+}
+
+type byScore struct {
+	addresses []string
+	scores    []uint64
+}
+
+func (s *byScore) Len() int { return len(s.addresses) }
+func (s *byScore) Less(i, j int) bool {
+	if s.scores[i] != s.scores[j] {
+		return s.scores[i] > s.scores[j]
+	}
+	return s.addresses[i] < s.addresses[j]
+}
+func (s *byScore) Swap(i, j int) {
+	s.addresses[i], s.addresses[j] = s.addresses[j], s.addresses[i]
+	s.scores[i], s.scores[j] = s.scores[j], s.scores[i]
+}
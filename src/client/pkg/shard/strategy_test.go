@@ -0,0 +1,76 @@
+package shard
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/discovery"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestRendezvousCandidates(t *testing.T) {
+	t.Parallel()
+	candidates := rendezvousCandidates(7, []string{"a", "b", "c", "d"})
+	require.Equal(t, 4, len(candidates))
+	require.Equal(t, candidates, rendezvousCandidates(7, []string{"d", "c", "b", "a"}))
+	// Each server comes first for some shards
+	first := make(map[string]bool)
+	for shard := uint64(0); shard < 64; shard++ {
+		first[rendezvousCandidates(shard, candidates)[0]] = true
+	}
+	require.Equal(t, 4, len(first))
+}
+
+func TestRendezvousStrategy(t *testing.T) {
+	t.Parallel()
+	serverStates := make(map[string]*ServerState)
+	for i := 0; i < 4; i++ {
+		address := fmt.Sprintf("server%d", i)
+		serverStates[address] = &ServerState{Address: address}
+	}
+	addresses, roles, err := planRoles(64, StrategyRendezvous, nil, serverStates, nil, 0, "")
+	require.NoError(t, err)
+	require.Equal(t, 64, len(addresses.Addresses))
+	for _, role := range roles {
+		require.Equal(t, 16, len(role.Shards))
+	}
+	// Where the shards were doesn't matter
+	oldShards := make(map[uint64]string)
+	for shard := uint64(0); shard < 64; shard++ {
+		oldShards[shard] = "server0"
+	}
+	again, _, err := planRoles(64, StrategyRendezvous, nil, serverStates, oldShards, 1, "")
+	require.NoError(t, err)
+	require.Equal(t, addresses.Addresses, again.Addresses)
+
+	// A server joining takes about its share of the shards, and few others
+	// move.
+	serverStates["server4"] = &ServerState{Address: "server4"}
+	joined, _, err := planRoles(64, StrategyRendezvous, nil, serverStates, nil, 2, "")
+	require.NoError(t, err)
+	moved := 0
+	for shard, address := range joined.Addresses {
+		if address != addresses.Addresses[shard] {
+			moved++
+		}
+	}
+	require.True(t, moved < 32, "%d of 64 shards moved", moved)
+	require.Equal(t, uint64(1), shardDistribution(joined.Addresses).Imbalance)
+
+	// Hard constraints and draining are still followed
+	serverStates["server0"].Labels = map[string]string{"disk": "hdd"}
+	serverStates["server1"].Draining = true
+	constrained, _, err := planRoles(64, StrategyRendezvous, []Constraint{AvoidLabel("disk", "hdd")}, serverStates, nil, 3, "")
+	require.NoError(t, err)
+	for _, address := range constrained.Addresses {
+		require.True(t, address != "server0" && address != "server1", "shard placed on %s", address)
+	}
+}
+
+func TestAssignmentStrategyString(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "Greedy", StrategyGreedy.String())
+	require.Equal(t, "Rendezvous", StrategyRendezvous.String())
+	sharder := newSharder(discovery.NewMockClient(), 16, WithAssignmentStrategy(StrategyRendezvous))
+	require.Equal(t, StrategyRendezvous, sharder.strategy)
+}
@@ -0,0 +1,222 @@
+package shard
+
+import (
+	"path"
+	"sort"
+	"strconv"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/errorutil"
+	"go.pedge.io/lion/proto"
+	"golang.org/x/net/context"
+)
+
+// SwapShardMaster asks AssignRoles to move shard from the server at
+// fromAddress to the one at toAddress, by setting the shard's swapKey, and
+// waits for both servers to pick up the version that moves it. The servers
+// must both have roles at the latest version, where the shard must be on
+// fromAddress, and toAddress must have fewer shards than its share of them.
+// AssignRoles leaves the shard on toAddress until the servers change.
+func (a *sharder) SwapShardMaster(ctx context.Context, shard uint64, fromAddress string, toAddress string) error {
+	version, err := a.GetVersion()
+	if err != nil {
+		return err
+	}
+	addresses, err := a.getAddresses(version)
+	if err != nil {
+		return err
+	}
+	address, ok := addresses.Addresses[shard]
+	if !ok {
+		return errorutil.New(errorutil.NotFound, "no shard %d", shard).WithVersion(version)
+	}
+	if address != fromAddress {
+		return errorutil.New(errorutil.Conflict, "shard %d is on %s, not %s", shard, address, fromAddress).WithVersion(version)
+	}
+	serverStates, err := a.getServerStates()
+	if err != nil {
+		return err
+	}
+	for _, serverAddress := range []string{fromAddress, toAddress} {
+		if _, ok := serverStates[serverAddress]; !ok {
+			return errorutil.New(errorutil.NotFound, "no server registered at %s", serverAddress)
+		}
+		roles, err := a.getServerRole(serverAddress)
+		if err != nil {
+			return err
+		}
+		if _, ok := roles[version]; !ok {
+			return errorutil.New(errorutil.NotFound, "%s has no role", serverAddress).WithVersion(version)
+		}
+	}
+	if serverStates[toAddress].Draining {
+		return errorutil.New(errorutil.Conflict, "%s is draining", toAddress)
+	}
+	numShards := a.addressesNumShards(addresses)
+	if shards, quota := shardsAndQuota(numShards, addresses.Addresses, serverStates, toAddress); shards >= quota {
+		return errorutil.New(errorutil.Conflict, "%s already has %d shards, its share of the %d", toAddress, shards, numShards).WithVersion(version)
+	}
+	if err := a.discoveryClient.Create(a.swapKey(shard), toAddress, 0); err != nil {
+		return errorutil.Wrap(err, "another move of shard %d is pending", shard)
+	}
+	// The request is done with once the shard has moved, AssignRoles
+	// mustn't move it back if it's later moved elsewhere. AssignRoles
+	// deletes it too, in case we don't get to.
+	defer a.deleteSwapRequest(shard)
+	swapped := InvalidVersion
+	if err := a.WatchVersion(ctx, func(latest int64) error {
+		if latest <= version {
+			return nil
+		}
+		addresses, err := a.getAddresses(latest)
+		if err != nil {
+			return err
+		}
+		if addresses.Addresses[shard] == toAddress {
+			swapped = latest
+			return errComplete
+		}
+		return nil
+	}); err != errComplete {
+		return err
+	}
+	err = a.watchServerStates(ctx, "SwapShardMaster", func(serverStates serverStateCache) error {
+		caughtUp := 0
+		for _, serverState := range serverStates {
+			if (serverState.Address == fromAddress || serverState.Address == toAddress) && serverState.Version >= swapped {
+				caughtUp++
+			}
+		}
+		if caughtUp < 2 {
+			return nil
+		}
+		return errComplete
+	})
+	if ctx.Err() != nil {
+		return ErrCancelled
+	}
+	if err != errComplete {
+		return err
+	}
+	return nil
+}
+
+func (s *localSharder) SwapShardMaster(ctx context.Context, shard uint64, fromAddress string, toAddress string) error {
+	return errorutil.New(errorutil.Conflict, "a local sharder's shards can't be moved")
+}
+
+// shardsAndQuota returns how many of shardToAddress's shards the server at
+// address has, and the most AssignRoles would give it.
+func shardsAndQuota(numShards uint64, shardToAddress map[uint64]string, serverStates map[string]*ServerState, address string) (uint64, uint64) {
+	var addresses []string
+	for address := range activeServers(serverStates) {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+	quotas, remainder := shardQuotas(numShards, addresses, serverStates)
+	quota := quotas[address]
+	if remainder > 0 {
+		quota++
+	}
+	var shards uint64
+	for _, shardAddress := range shardToAddress {
+		if shardAddress == address {
+			shards++
+		}
+	}
+	return shards, quota
+}
+
+// requestedSwaps returns the server SwapShardMaster has asked for each shard
+// to be moved to.
+func (a *sharder) requestedSwaps(ctx context.Context) (map[uint64]string, error) {
+	encodedSwaps, err := a.discoveryClient.GetAllCtx(ctx, a.swapDir())
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[uint64]string)
+	for key, address := range encodedSwaps {
+		shard, err := strconv.ParseUint(path.Base(key), 10, 64)
+		if err != nil {
+			a.corruptEntry(key, address, err)
+			continue
+		}
+		result[shard] = address
+	}
+	return result, nil
+}
+
+// swapRoles moves each of numShards shards that swaps has a server for to
+// it, if that server is in activeServerStates, and leaves the rest where
+// oldShards says they are, as version. It returns the roles of every server
+// in serverStates, keyed by address, and the resulting addresses, or false
+// if no shard moves or a shard's server is gone.
+func swapRoles(
+	numShards uint64,
+	swaps map[uint64]string,
+	serverStates map[string]*ServerState,
+	activeServerStates map[string]*ServerState,
+	oldShards map[uint64]string,
+	version int64,
+	correlationID string,
+) (*Addresses, map[string]*ServerRole, bool) {
+	roles := make(map[string]*ServerRole)
+	for address := range serverStates {
+		roles[address] = &ServerRole{
+			Address:       address,
+			Version:       version,
+			Shards:        make(map[uint64]bool),
+			CorrelationId: correlationID,
+			SchemaVersion: SchemaVersion,
+		}
+	}
+	moved := false
+	shards := make(map[uint64]string)
+	for shard := uint64(0); shard < numShards; shard++ {
+		address := oldShards[shard]
+		if toAddress, ok := swaps[shard]; ok && toAddress != address {
+			if _, ok := activeServerStates[toAddress]; ok {
+				address = toAddress
+				moved = true
+			}
+		}
+		serverRole, ok := roles[address]
+		if !ok {
+			return nil, nil, false
+		}
+		serverRole.Shards[shard] = true
+		shards[shard] = address
+	}
+	if !moved {
+		return nil, nil, false
+	}
+	addresses := &Addresses{
+		Version:       version,
+		Addresses:     shards,
+		CorrelationId: correlationID,
+		NumShards:     numShards,
+		SchemaVersion: SchemaVersion,
+	}
+	return addresses, roles, true
+}
+
+// deleteSwapRequest deletes the request to move shard, if it's still there.
+func (a *sharder) deleteSwapRequest(shard uint64) {
+	swaps, err := a.discoveryClient.GetAll(a.swapKey(shard))
+	if err == nil && len(swaps) == 0 {
+		return
+	}
+	if err == nil {
+		err = a.delete(a.swapKey(shard))
+	}
+	if err != nil {
+		protolion.Errorf("sharder error deleting request to move shard %d: %s", shard, err.Error())
+	}
+}
+
+func (a *sharder) swapDir() string {
+	return "swap"
+}
+
+func (a *sharder) swapKey(shard uint64) string {
+	return path.Join(a.swapDir(), strconv.FormatUint(shard, 10))
+}
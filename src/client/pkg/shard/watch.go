@@ -0,0 +1,151 @@
+package shard
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/discovery"
+	"go.pedge.io/lion/proto"
+	"golang.org/x/net/context"
+)
+
+// WatchHealth describes how recently a watch received a notification.
+type WatchHealth struct {
+	LastNotification time.Time
+	// Lag is how long it's been since the last notification, it's 0 when no
+	// notifications are expected because there are no servers.
+	Lag time.Duration
+	// Stale is true if the watch hasn't received a notification for longer
+	// than expected, StaleCount counts how many times that has happened.
+	Stale      bool
+	StaleCount uint64
+	// Resumes counts how many times the watch was re-established because it
+	// was stale.
+	Resumes uint64
+}
+
+// watchMonitor tracks a watch's notifications.
+type watchMonitor struct {
+	health WatchHealth
+	// expecting is true when servers are registered, their announcements
+	// should produce a notification every announce interval.
+	expecting bool
+}
+
+// watchServerStates watches the server states and calls f with all of them
+// every time they change. Servers refresh their states constantly, so if
+// the watch goes quiet for watchStaleAfter while there are servers it's
+// reported as stale, and if it stays quiet for watchResumeAfter it's
+// re-established.
+func (a *sharder) watchServerStates(ctx context.Context, name string, f func(serverStates serverStateCache) error) error {
+	serverStates := make(serverStateCache)
+	a.debug.startWatch(name, a.now())
+	for {
+		watchCtx, cancel := context.WithCancel(ctx)
+		var wg sync.WaitGroup
+		resumed := make(chan struct{})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if a.monitorWatch(watchCtx, name) {
+				close(resumed)
+				cancel()
+			}
+		}()
+		err := a.discoveryClient.WatchAllDeltaCtx(watchCtx, a.serverStateDir(),
+			func(delta *discovery.Delta) error {
+				serverStates.apply(delta, a.corruptEntry)
+				a.debug.observeServerStates(serverStates)
+				a.debug.observeNotification(name, a.now(), len(serverStates) > 0)
+				return f(serverStates)
+			})
+		cancel()
+		wg.Wait()
+		select {
+		case <-resumed:
+			if ctx.Err() == nil {
+				continue
+			}
+		default:
+		}
+		return err
+	}
+}
+
+// monitorWatch checks the watch called name until ctx is done, it returns
+// true if the watch should be resumed.
+func (a *sharder) monitorWatch(ctx context.Context, name string) bool {
+	interval := a.watchStaleAfter / 4
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(interval):
+		}
+		becameStale, resume, lag := a.debug.checkWatch(name, a.now(), a.watchStaleAfter, a.watchResumeAfter)
+		if becameStale || resume {
+			protolion.Warn(&StaleWatch{
+				Watch:      name,
+				Key:        a.serverStateDir(),
+				LagSeconds: int64(lag / time.Second),
+				Resumed:    resume,
+			})
+		}
+		if resume {
+			return true
+		}
+	}
+}
+
+func (r *debugRecorder) startWatch(name string, now time.Time) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.watches[name] = &watchMonitor{health: WatchHealth{LastNotification: now}}
+}
+
+func (r *debugRecorder) observeNotification(name string, now time.Time, expecting bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	monitor := r.watches[name]
+	monitor.health.LastNotification = now
+	monitor.health.Stale = false
+	monitor.expecting = expecting
+}
+
+// checkWatch updates the health of the watch called name, it returns
+// whether the watch just became stale, whether it should be resumed, and
+// its lag.
+func (r *debugRecorder) checkWatch(name string, now time.Time, staleAfter time.Duration, resumeAfter time.Duration) (bool, bool, time.Duration) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	monitor := r.watches[name]
+	if !monitor.expecting {
+		return false, false, 0
+	}
+	lag := now.Sub(monitor.health.LastNotification)
+	becameStale := false
+	if lag >= staleAfter && !monitor.health.Stale {
+		monitor.health.Stale = true
+		monitor.health.StaleCount++
+		becameStale = true
+	}
+	if resumeAfter > 0 && lag >= resumeAfter {
+		monitor.health.Resumes++
+		// Give the resumed watch a full resumeAfter to deliver its snapshot
+		monitor.health.LastNotification = now
+		return becameStale, true, lag
+	}
+	return becameStale, false, lag
+}
+
+func (r *debugRecorder) watchHealth(now time.Time) map[string]*WatchHealth {
+	result := make(map[string]*WatchHealth)
+	for name, monitor := range r.watches {
+		health := monitor.health
+		if monitor.expecting {
+			health.Lag = now.Sub(health.LastNotification)
+		}
+		result[name] = &health
+	}
+	return result
+}
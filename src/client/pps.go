@@ -150,11 +150,29 @@ func (c APIClient) ListJob(pipelineName string, inputCommit []*pfs.Commit) ([]*p
 func (c APIClient) GetLogs(
 	jobID string,
 	writer io.Writer,
+) error {
+	return c.getLogs(jobID, false, writer)
+}
+
+// FollowLogs is like GetLogs but keeps writing new logs as the job produces
+// them, until it's done.
+func (c APIClient) FollowLogs(
+	jobID string,
+	writer io.Writer,
+) error {
+	return c.getLogs(jobID, true, writer)
+}
+
+func (c APIClient) getLogs(
+	jobID string,
+	follow bool,
+	writer io.Writer,
 ) error {
 	getLogsClient, err := c.PpsAPIClient.GetLogs(
 		context.Background(),
 		&pps.GetLogsRequest{
-			Job: NewJob(jobID),
+			Job:    NewJob(jobID),
+			Follow: follow,
 		},
 	)
 	if err != nil {
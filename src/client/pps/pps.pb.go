@@ -260,6 +260,8 @@ func (m *JobInfo) GetOutputCommit() *pfs.Commit {
 
 type JobInfos struct {
 	JobInfo []*JobInfo `protobuf:"bytes,1,rep,name=job_info,json=jobInfo" json:"job_info,omitempty"`
+	// next_page_token is set when a request with page_size has more jobs
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken" json:"next_page_token,omitempty"`
 }
 
 func (m *JobInfos) Reset()                    { *m = JobInfos{} }
@@ -436,6 +438,11 @@ func (m *InspectJobRequest) GetJob() *Job {
 type ListJobRequest struct {
 	Pipeline    *Pipeline     `protobuf:"bytes,1,opt,name=pipeline" json:"pipeline,omitempty"`
 	InputCommit []*pfs.Commit `protobuf:"bytes,2,rep,name=input_commit,json=inputCommit" json:"input_commit,omitempty"`
+	// page_size limits how many jobs are returned, 0 means all of them.
+	// Paging requires pipeline to be set.
+	PageSize int64 `protobuf:"varint,3,opt,name=page_size,json=pageSize" json:"page_size,omitempty"`
+	// page_token is the next_page_token of the previous page
+	PageToken string `protobuf:"bytes,4,opt,name=page_token,json=pageToken" json:"page_token,omitempty"`
 }
 
 func (m *ListJobRequest) Reset()                    { *m = ListJobRequest{} }
@@ -459,6 +466,8 @@ func (m *ListJobRequest) GetInputCommit() []*pfs.Commit {
 
 type GetLogsRequest struct {
 	Job *Job `protobuf:"bytes,1,opt,name=job" json:"job,omitempty"`
+	// follow keeps sending new lines until the job's pods exit
+	Follow bool `protobuf:"varint,2,opt,name=follow" json:"follow,omitempty"`
 }
 
 func (m *GetLogsRequest) Reset()                    { *m = GetLogsRequest{} }
@@ -901,76 +910,81 @@ var _API_serviceDesc = grpc.ServiceDesc{
 }
 
 var fileDescriptor0 = []byte{
-	// 1135 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xcc, 0x57, 0xef, 0x6e, 0x1a, 0x47,
-	0x10, 0x37, 0x1c, 0xff, 0x6e, 0xc0, 0x36, 0x59, 0xdb, 0x31, 0xc2, 0x49, 0xec, 0x5e, 0x53, 0xc9,
-	0xb2, 0x5a, 0x9c, 0x3a, 0x55, 0xa4, 0x7e, 0xa8, 0x54, 0x9b, 0x90, 0x94, 0x94, 0xd8, 0x74, 0x6d,
-	0xb7, 0x52, 0xa4, 0x16, 0x1d, 0xc7, 0xe2, 0x9c, 0xcb, 0x71, 0xd7, 0xbb, 0x45, 0x91, 0x1f, 0xa3,
-	0x9f, 0xfb, 0x2e, 0x7d, 0x92, 0x3e, 0x46, 0x1f, 0xa0, 0xb3, 0xbb, 0x77, 0x70, 0x1c, 0x60, 0xd9,
-	0x34, 0x1f, 0xf2, 0x01, 0x69, 0x77, 0x66, 0x76, 0x76, 0xe6, 0x37, 0xbf, 0x99, 0x3d, 0x60, 0xd3,
-	0x1a, 0xd8, 0x6c, 0xc8, 0x0f, 0x3d, 0x2f, 0x10, 0xbf, 0x9a, 0xe7, 0xbb, 0xdc, 0x25, 0xab, 0x9e,
-	0x69, 0xbd, 0xbf, 0xe9, 0x31, 0xdf, 0xa9, 0xa1, 0xb0, 0xba, 0x73, 0xe5, 0xba, 0x57, 0x03, 0x76,
-	0x28, 0x95, 0xdd, 0x51, 0xff, 0x90, 0x39, 0x1e, 0xbf, 0x51, 0xb6, 0xd5, 0xdd, 0xa4, 0x92, 0xdb,
-	0x0e, 0x0b, 0xb8, 0xe9, 0x78, 0xa1, 0xc1, 0x93, 0xa4, 0xc1, 0x07, 0xdf, 0xf4, 0x3c, 0xe6, 0x87,
-	0x97, 0x55, 0xc7, 0x21, 0xf4, 0x03, 0xf1, 0x53, 0x52, 0xe3, 0x03, 0xe8, 0x17, 0xbe, 0x39, 0x0c,
-	0xfa, 0xae, 0xef, 0x90, 0x4d, 0xc8, 0xda, 0x8e, 0x79, 0xc5, 0x2a, 0xa9, 0xbd, 0xd4, 0xbe, 0x4e,
-	0xd5, 0x86, 0x94, 0x41, 0xb3, 0x9c, 0x5e, 0x25, 0xbd, 0xa7, 0xa1, 0x4c, 0x2c, 0x85, 0x5d, 0xc0,
-	0x7b, 0xf6, 0xb0, 0xa2, 0x49, 0x99, 0xda, 0x90, 0x2f, 0x81, 0x98, 0x96, 0xc5, 0x3c, 0xde, 0xf1,
-	0x19, 0x1f, 0xf9, 0xc3, 0x8e, 0xe5, 0xf6, 0x58, 0x25, 0x83, 0x26, 0x1a, 0x2d, 0x2b, 0x0d, 0x95,
-	0x8a, 0x3a, 0xca, 0x8d, 0x2d, 0xd0, 0xde, 0xb8, 0x5d, 0xb2, 0x06, 0x69, 0xbb, 0x17, 0xde, 0x87,
-	0x2b, 0xa3, 0x0b, 0xb9, 0xb7, 0x8c, 0xbf, 0x77, 0x7b, 0xe4, 0x05, 0xe8, 0x9e, 0xe9, 0x73, 0x9b,
-	0xdb, 0xee, 0x50, 0x1a, 0xac, 0x1d, 0x55, 0x6a, 0x53, 0x80, 0xd5, 0xda, 0x91, 0x9e, 0x4e, 0x4c,
-	0xc9, 0x1e, 0x14, 0xed, 0xa1, 0xe5, 0x33, 0x07, 0x93, 0x35, 0x07, 0x18, 0x76, 0x6a, 0xbf, 0x40,
-	0xe3, 0x22, 0xe3, 0x37, 0x28, 0xe0, 0xd5, 0xcd, 0xa1, 0x37, 0xe2, 0xe4, 0x73, 0xc8, 0x59, 0xae,
-	0xe3, 0xd8, 0x5c, 0x5e, 0x51, 0x3c, 0x2a, 0xd6, 0x04, 0x36, 0x75, 0x29, 0xa2, 0xa1, 0x8a, 0x7c,
-	0x05, 0x39, 0x47, 0x06, 0x25, 0xbd, 0x15, 0x8f, 0xb6, 0x12, 0x71, 0xa8, 0x88, 0x69, 0x68, 0x64,
-	0xfc, 0xad, 0x41, 0x5e, 0x5e, 0xd0, 0x77, 0xc9, 0x53, 0xd0, 0xae, 0xdd, 0x6e, 0xe8, 0x9c, 0x24,
-	0xce, 0xa1, 0x11, 0x15, 0x6a, 0x91, 0x2b, 0x8f, 0xaa, 0x10, 0xde, 0x91, 0xcc, 0x75, 0x5c, 0x25,
-	0x3a, 0x31, 0x25, 0xcf, 0xa1, 0xe0, 0xd9, 0x1e, 0x1b, 0xd8, 0x43, 0x86, 0xb5, 0x10, 0xc7, 0xb6,
-	0x93, 0x10, 0x85, 0x6a, 0x3a, 0x36, 0x14, 0x00, 0x21, 0x5a, 0xe6, 0x60, 0x80, 0xdb, 0xc0, 0xc1,
-	0x02, 0xa5, 0xf6, 0x33, 0x34, 0x2e, 0x22, 0x87, 0x90, 0xb3, 0x05, 0x3a, 0x41, 0x25, 0x8b, 0xd5,
-	0x9b, 0x75, 0x1a, 0xa1, 0x47, 0x43, 0x33, 0xf2, 0x35, 0x00, 0x9e, 0x47, 0x74, 0x3b, 0x22, 0xd9,
-	0xdc, 0xc2, 0x64, 0x75, 0x65, 0x25, 0x0a, 0xff, 0x2d, 0x00, 0x56, 0xc4, 0xe4, 0xac, 0xd7, 0x31,
-	0x79, 0x25, 0x2f, 0x8f, 0x54, 0x6b, 0x8a, 0xc3, 0xb5, 0x88, 0xc3, 0xb5, 0x8b, 0x88, 0xe4, 0x54,
-	0x0f, 0xad, 0x8f, 0x39, 0x79, 0x06, 0xab, 0xee, 0x88, 0xe3, 0xc5, 0x9d, 0xb0, 0x74, 0x85, 0xd9,
-	0xd2, 0x95, 0x94, 0x45, 0x3d, 0x2a, 0x20, 0x72, 0x14, 0x4f, 0x57, 0x74, 0xc9, 0xa3, 0x39, 0xf9,
-	0x9c, 0x0b, 0x35, 0x55, 0x56, 0xc6, 0x77, 0x21, 0x41, 0xfa, 0xae, 0x48, 0xad, 0x80, 0x39, 0x75,
-	0x6c, 0xdc, 0x60, 0x15, 0x05, 0x1a, 0x0f, 0xe7, 0xa1, 0xd1, 0x77, 0x69, 0xfe, 0x5a, 0x2d, 0x8c,
-	0x27, 0x50, 0x88, 0x60, 0x27, 0x04, 0x32, 0x43, 0xd3, 0x89, 0x3a, 0x4a, 0xae, 0x8d, 0x5f, 0x61,
-	0x35, 0xd2, 0x2b, 0x12, 0x3e, 0x86, 0x8c, 0xcf, 0x3c, 0x37, 0x64, 0x89, 0x2e, 0xf3, 0xa0, 0x28,
-	0xa0, 0x52, 0x7c, 0x5f, 0xfa, 0xfd, 0xa9, 0x41, 0x69, 0xe2, 0x1f, 0x39, 0x18, 0x67, 0x49, 0xea,
-	0xae, 0x2c, 0x59, 0x96, 0x92, 0x09, 0x76, 0x69, 0xb3, 0xec, 0xfa, 0x66, 0xcc, 0xae, 0x8c, 0xc4,
-	0xf3, 0xd1, 0x82, 0x60, 0xa6, 0x29, 0x76, 0x00, 0xc5, 0xb0, 0xe8, 0x12, 0xaa, 0x6c, 0x12, 0x2a,
-	0x50, 0x5a, 0xb1, 0x4e, 0x70, 0x2b, 0x77, 0x1f, 0x6e, 0x1d, 0x45, 0x4c, 0xc9, 0x4b, 0xa6, 0x2c,
-	0x8a, 0x2d, 0x4e, 0x17, 0xf2, 0x19, 0x94, 0x7c, 0x66, 0x09, 0xf6, 0x33, 0xdf, 0x77, 0x7d, 0x49,
-	0x47, 0x9d, 0x16, 0x95, 0xac, 0x21, 0x44, 0xc6, 0x4f, 0xf1, 0x92, 0x0b, 0x5a, 0x7d, 0x0f, 0xab,
-	0x11, 0xd4, 0x71, 0x6e, 0xed, 0x2c, 0xc4, 0x02, 0x09, 0x56, 0xf2, 0x62, 0x3b, 0xe3, 0xaf, 0x34,
-	0x94, 0xeb, 0x32, 0x6e, 0xd1, 0x59, 0xec, 0x8f, 0x11, 0x26, 0x33, 0x5d, 0xb5, 0xd4, 0x72, 0x83,
-	0x24, 0xbd, 0xe4, 0x20, 0xd1, 0x6e, 0x1b, 0x24, 0x99, 0x65, 0x06, 0x49, 0xf6, 0x2e, 0x83, 0x04,
-	0x1f, 0x23, 0x4c, 0xc1, 0x62, 0xb2, 0xce, 0x05, 0xaa, 0x36, 0xc6, 0x3b, 0x78, 0xd0, 0x1c, 0x06,
-	0x1e, 0xb3, 0x78, 0x0c, 0x9d, 0xbb, 0x0d, 0xe3, 0x5d, 0x28, 0x76, 0x07, 0xae, 0xf5, 0x7b, 0x47,
-	0x11, 0x41, 0x3d, 0x20, 0x20, 0x45, 0xb2, 0xec, 0xc6, 0x08, 0xd6, 0x5a, 0x76, 0x10, 0x77, 0xbc,
-	0x54, 0x87, 0xd5, 0xa0, 0x24, 0xb3, 0x8e, 0xa6, 0x58, 0x5a, 0x42, 0x34, 0x35, 0xc5, 0x8a, 0xd2,
-	0x40, 0x6d, 0x8c, 0x17, 0xb0, 0xf6, 0x9a, 0xf1, 0x96, 0x7b, 0x15, 0xdc, 0x2b, 0x1f, 0xe3, 0x9f,
-	0x14, 0x6c, 0x29, 0xa2, 0x8c, 0x83, 0xf8, 0x3f, 0x61, 0x7f, 0x62, 0x83, 0xc1, 0x78, 0x0b, 0x0f,
-	0xc3, 0x4a, 0x7f, 0x8c, 0xf4, 0xf0, 0xbb, 0x64, 0x43, 0x14, 0x37, 0xe1, 0xcb, 0x68, 0xc1, 0xd6,
-	0x4b, 0x36, 0x60, 0x1f, 0x07, 0xc3, 0x83, 0x53, 0xf9, 0xc0, 0x48, 0x36, 0x91, 0x75, 0x28, 0xbe,
-	0x39, 0x3b, 0xe9, 0xb4, 0x2f, 0x5b, 0xad, 0xe6, 0xe9, 0xeb, 0xf2, 0x4a, 0x24, 0xa0, 0x97, 0xa7,
-	0xa7, 0x42, 0x90, 0x8a, 0x04, 0xaf, 0x8e, 0x9b, 0xad, 0x4b, 0xda, 0x28, 0xa7, 0x23, 0xc1, 0xf9,
-	0x65, 0xbd, 0xde, 0x38, 0x3f, 0x2f, 0x6b, 0x07, 0x07, 0xa0, 0x8f, 0xbf, 0x85, 0x88, 0x0e, 0xd9,
-	0x93, 0xd6, 0x59, 0xfd, 0x47, 0x74, 0x55, 0x80, 0xcc, 0xab, 0x66, 0xab, 0x81, 0x3e, 0x70, 0x45,
-	0x1b, 0xed, 0xb3, 0x72, 0xfa, 0xe0, 0x7a, 0x32, 0x8a, 0x54, 0x00, 0x5b, 0xf0, 0xa0, 0xdd, 0x6c,
-	0x37, 0xf0, 0xf6, 0x46, 0xe7, 0xfc, 0xe2, 0x98, 0x5e, 0xa8, 0x30, 0x36, 0xa1, 0x3c, 0x16, 0x4f,
-	0x62, 0xd9, 0x86, 0x8d, 0x89, 0xb4, 0x31, 0x36, 0x4f, 0x93, 0x0d, 0x58, 0x1f, 0x2b, 0x44, 0xa4,
-	0x8d, 0x97, 0x65, 0xed, 0xe8, 0xdf, 0x0c, 0x68, 0xc7, 0xed, 0x26, 0x39, 0x01, 0x7d, 0x3c, 0xaa,
-	0xc8, 0x6e, 0x02, 0x9f, 0xe4, 0x10, 0xab, 0xce, 0x61, 0xb2, 0xb1, 0x42, 0x7e, 0x00, 0x98, 0x74,
-	0x34, 0xd9, 0x4b, 0xd8, 0xcc, 0x34, 0x7b, 0x75, 0xc1, 0x33, 0x8d, 0x9e, 0xea, 0x90, 0x0f, 0xfb,
-	0x97, 0x3c, 0x4e, 0x18, 0x4d, 0xf7, 0x75, 0x75, 0x7b, 0xbe, 0x8f, 0x00, 0x9d, 0x34, 0x21, 0x1f,
-	0x76, 0xe3, 0x8c, 0x93, 0xe9, 0x2e, 0xad, 0xee, 0xcc, 0xbc, 0x3c, 0x27, 0x37, 0x9c, 0x05, 0x3f,
-	0x9b, 0x83, 0x11, 0x33, 0x56, 0x9e, 0xa5, 0x48, 0x1b, 0xd6, 0xa6, 0xfb, 0x93, 0x3c, 0x9d, 0x0b,
-	0x51, 0x82, 0x7a, 0x98, 0x61, 0xd2, 0x71, 0x43, 0xfc, 0x61, 0xc0, 0xe0, 0x7e, 0x81, 0xf5, 0x44,
-	0x4f, 0x90, 0x2f, 0xe6, 0x03, 0x96, 0xf4, 0x79, 0xdb, 0x03, 0x84, 0x8e, 0x29, 0x94, 0xe2, 0xdd,
-	0x41, 0x8c, 0x39, 0xf8, 0x25, 0x5d, 0x3e, 0xba, 0xc5, 0xa5, 0x40, 0x12, 0xd3, 0x9f, 0x6e, 0xad,
-	0x99, 0xf4, 0xe7, 0x76, 0xde, 0xe2, 0xf4, 0x4f, 0xb2, 0xef, 0x34, 0x3c, 0xd6, 0xcd, 0x49, 0xc5,
-	0xf3, 0xff, 0x02, 0x00, 0x00, 0xff, 0xff, 0x70, 0x8e, 0xe8, 0xc8, 0x7d, 0x0d, 0x00, 0x00,
+	// 1213 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x57, 0xdb, 0x6e, 0xdb, 0x46,
+	0x13, 0x16, 0x45, 0x9d, 0x38, 0x92, 0x65, 0x65, 0x63, 0x39, 0x84, 0x9c, 0x83, 0x7e, 0xfe, 0x69,
+	0x61, 0x18, 0xad, 0x9c, 0x3a, 0x45, 0x81, 0xde, 0xd5, 0x56, 0x94, 0x54, 0xae, 0xa2, 0xa8, 0x2b,
+	0xbb, 0x05, 0x02, 0xb4, 0x04, 0x45, 0xad, 0x14, 0x3a, 0x24, 0x77, 0x4b, 0xae, 0x90, 0x26, 0x6f,
+	0xd1, 0xeb, 0x3e, 0x45, 0x5f, 0xa0, 0x4f, 0xd2, 0xc7, 0xe8, 0x03, 0x14, 0xbb, 0x24, 0x75, 0xa0,
+	0xa4, 0x20, 0x76, 0x73, 0xd1, 0x0b, 0x03, 0xdc, 0x99, 0xe1, 0x70, 0xe6, 0x9b, 0x6f, 0xbe, 0x95,
+	0x61, 0xcf, 0x76, 0x1d, 0xe2, 0xf3, 0x63, 0xc6, 0x42, 0xf1, 0xd7, 0x62, 0x01, 0xe5, 0x14, 0xed,
+	0x30, 0xcb, 0x7e, 0xf5, 0x76, 0x4c, 0x02, 0xaf, 0xc5, 0x58, 0xd8, 0x38, 0x98, 0x52, 0x3a, 0x75,
+	0xc9, 0xb1, 0x74, 0x8e, 0x66, 0x93, 0x63, 0xe2, 0x31, 0xfe, 0x36, 0x8a, 0x6d, 0x3c, 0x48, 0x3b,
+	0xb9, 0xe3, 0x91, 0x90, 0x5b, 0x1e, 0x8b, 0x03, 0xee, 0xa7, 0x03, 0xde, 0x04, 0x16, 0x63, 0x24,
+	0x88, 0x3f, 0xd6, 0x98, 0x97, 0x30, 0x09, 0xc5, 0x5f, 0x64, 0x35, 0xde, 0x80, 0x76, 0x11, 0x58,
+	0x7e, 0x38, 0xa1, 0x81, 0x87, 0xf6, 0x20, 0xef, 0x78, 0xd6, 0x94, 0xe8, 0x4a, 0x53, 0x39, 0xd4,
+	0x70, 0x74, 0x40, 0x35, 0x50, 0x6d, 0x6f, 0xac, 0x67, 0x9b, 0xea, 0xa1, 0x86, 0xc5, 0xa3, 0x88,
+	0x0b, 0xf9, 0xd8, 0xf1, 0x75, 0x55, 0xda, 0xa2, 0x03, 0xfa, 0x0c, 0x90, 0x65, 0xdb, 0x84, 0x71,
+	0x33, 0x20, 0x7c, 0x16, 0xf8, 0xa6, 0x4d, 0xc7, 0x44, 0xcf, 0x35, 0xd5, 0x43, 0x15, 0xd7, 0x22,
+	0x0f, 0x96, 0x8e, 0x36, 0x1d, 0x13, 0xa3, 0x0e, 0xea, 0x39, 0x1d, 0xa1, 0x2a, 0x64, 0x9d, 0x71,
+	0xfc, 0xbd, 0xac, 0x33, 0x36, 0x46, 0x50, 0x78, 0x4e, 0xf8, 0x2b, 0x3a, 0x46, 0x5f, 0x81, 0xc6,
+	0xac, 0x80, 0x3b, 0xdc, 0xa1, 0xbe, 0x0c, 0xa8, 0x9e, 0xe8, 0xad, 0x15, 0xc0, 0x5a, 0x83, 0xc4,
+	0x8f, 0x17, 0xa1, 0xa8, 0x09, 0x65, 0xc7, 0xb7, 0x03, 0xe2, 0x11, 0x9f, 0x5b, 0xae, 0x9e, 0x6d,
+	0x2a, 0x87, 0x25, 0xbc, 0x6c, 0x32, 0x7e, 0x86, 0xd2, 0x39, 0x1d, 0x75, 0x7d, 0x36, 0xe3, 0xe8,
+	0xff, 0x50, 0xb0, 0xa9, 0xe7, 0x39, 0x5c, 0x7e, 0xa2, 0x7c, 0x52, 0x6e, 0x09, 0x6c, 0xda, 0xd2,
+	0x84, 0x63, 0x17, 0xfa, 0x1c, 0x0a, 0x9e, 0x2c, 0x4a, 0x66, 0x2b, 0x9f, 0xd4, 0x53, 0x75, 0x44,
+	0x15, 0xe3, 0x38, 0xc8, 0xf8, 0x53, 0x85, 0xa2, 0xfc, 0xc0, 0x84, 0xa2, 0x87, 0xa0, 0x5e, 0xd1,
+	0x51, 0x9c, 0x1c, 0xa5, 0xde, 0x3b, 0xa7, 0x23, 0x2c, 0xdc, 0xa2, 0x57, 0x9e, 0x4c, 0x21, 0xfe,
+	0x46, 0xba, 0xd7, 0xf9, 0x94, 0xf0, 0x22, 0x14, 0x3d, 0x86, 0x12, 0x73, 0x18, 0x71, 0x1d, 0x9f,
+	0xe8, 0xaa, 0x7c, 0xed, 0x4e, 0x1a, 0xa2, 0xd8, 0x8d, 0xe7, 0x81, 0x02, 0x20, 0x66, 0x05, 0x96,
+	0xeb, 0x12, 0xd7, 0x09, 0x3d, 0x3d, 0xd7, 0x54, 0x0e, 0x73, 0x78, 0xd9, 0x84, 0x8e, 0xa1, 0xe0,
+	0x08, 0x74, 0x42, 0x3d, 0xdf, 0x54, 0x37, 0x24, 0x4d, 0xd0, 0xc3, 0x71, 0x18, 0xfa, 0x02, 0x80,
+	0x59, 0x01, 0xf1, 0xb9, 0x29, 0x9a, 0x2d, 0x6c, 0x6d, 0x56, 0x8b, 0xa2, 0xc4, 0xe0, 0xbf, 0x06,
+	0xb0, 0x03, 0x62, 0x71, 0x32, 0x36, 0x2d, 0xae, 0x17, 0xe5, 0x2b, 0x8d, 0x56, 0xc4, 0xe1, 0x56,
+	0xc2, 0xe1, 0xd6, 0x45, 0x42, 0x72, 0xac, 0xc5, 0xd1, 0xa7, 0x1c, 0x3d, 0x82, 0x1d, 0x3a, 0xe3,
+	0x6c, 0xc6, 0xcd, 0x78, 0x74, 0xa5, 0xf5, 0xd1, 0x55, 0xa2, 0x88, 0x76, 0x32, 0xc0, 0x7c, 0xc8,
+	0x2d, 0x4e, 0x74, 0x4d, 0xf2, 0x68, 0x43, 0x3f, 0x43, 0xe1, 0xc6, 0x51, 0x94, 0x41, 0x62, 0x82,
+	0x4c, 0xa8, 0x68, 0xad, 0x74, 0x45, 0x47, 0xa6, 0xe3, 0x4f, 0xa8, 0xae, 0x48, 0x34, 0xf6, 0x37,
+	0xa1, 0x31, 0xa1, 0xb8, 0x78, 0x15, 0x3d, 0xa0, 0x4f, 0x61, 0xd7, 0x27, 0xbf, 0x72, 0x93, 0x59,
+	0x53, 0x62, 0x72, 0xfa, 0x9a, 0xf8, 0x72, 0xa6, 0x1a, 0xde, 0x11, 0xe6, 0x81, 0x35, 0x25, 0x17,
+	0xc2, 0x68, 0xdc, 0x87, 0x52, 0x32, 0x1e, 0x84, 0x20, 0xe7, 0x5b, 0x5e, 0xb2, 0x79, 0xf2, 0xd9,
+	0xf8, 0x09, 0x76, 0x12, 0x7f, 0x44, 0xd6, 0x7b, 0x90, 0x0b, 0x08, 0xa3, 0x31, 0x9b, 0x34, 0xd9,
+	0x2f, 0x26, 0x8c, 0x62, 0x69, 0xbe, 0x2e, 0x4d, 0x7f, 0x53, 0xa1, 0xb2, 0xc8, 0x3f, 0xa1, 0x2b,
+	0x6c, 0x52, 0x3e, 0x94, 0x4d, 0x37, 0xa5, 0x6e, 0x8a, 0x85, 0xea, 0x3a, 0x0b, 0xbf, 0x9c, 0xb3,
+	0x30, 0x27, 0x71, 0xbf, 0xbb, 0xa5, 0x98, 0x55, 0x2a, 0x1e, 0x41, 0x39, 0x26, 0x87, 0x84, 0x2a,
+	0x9f, 0x86, 0x0a, 0x22, 0xaf, 0x78, 0x4e, 0x71, 0xb0, 0x70, 0x1d, 0x0e, 0x9e, 0x24, 0x8c, 0x2a,
+	0x4a, 0x46, 0x6d, 0xab, 0x6d, 0x99, 0x56, 0xe8, 0x7f, 0x50, 0x09, 0x88, 0x2d, 0xb6, 0x84, 0x04,
+	0x01, 0x0d, 0x24, 0x6d, 0x35, 0x5c, 0x8e, 0x6c, 0x1d, 0x61, 0x32, 0xbe, 0x5f, 0x1e, 0xb9, 0xa0,
+	0xdf, 0x37, 0xb0, 0x93, 0x40, 0xbd, 0xcc, 0xc1, 0x83, 0xad, 0x58, 0x4c, 0x28, 0xae, 0xb0, 0xa5,
+	0x93, 0xf1, 0x7b, 0x16, 0x6a, 0x6d, 0x59, 0xb7, 0xd8, 0x40, 0xf2, 0xcb, 0x8c, 0x84, 0x7c, 0x75,
+	0x6a, 0xca, 0xcd, 0x04, 0x27, 0x7b, 0x43, 0xc1, 0x51, 0xdf, 0x27, 0x38, 0xb9, 0x9b, 0x08, 0x4e,
+	0xfe, 0x43, 0x04, 0x67, 0x0f, 0xf2, 0x13, 0x1a, 0xd8, 0x44, 0xce, 0xb9, 0x84, 0xa3, 0x83, 0xf1,
+	0x12, 0x6e, 0x75, 0xfd, 0x90, 0x11, 0x9b, 0x2f, 0xa1, 0xf3, 0x61, 0xa2, 0xfd, 0x00, 0xca, 0x23,
+	0x97, 0xda, 0xaf, 0xcd, 0x88, 0x08, 0xd1, 0x45, 0x03, 0xd2, 0x24, 0xc7, 0x6e, 0xfc, 0xa1, 0x40,
+	0xb5, 0xe7, 0x84, 0xcb, 0x99, 0x6f, 0xb4, 0x62, 0x2d, 0xa8, 0x38, 0xfe, 0x92, 0xdc, 0x65, 0x9b,
+	0x6a, 0x5a, 0xee, 0xca, 0x32, 0x20, 0x3a, 0xa0, 0x03, 0x71, 0x73, 0x4e, 0x89, 0x19, 0x3a, 0xef,
+	0xa2, 0x6b, 0x41, 0xc5, 0x25, 0x61, 0x18, 0x3a, 0xef, 0x08, 0xba, 0x07, 0xb0, 0xa4, 0x4b, 0x39,
+	0x49, 0x41, 0x8d, 0xcd, 0x35, 0xa9, 0x0f, 0xd5, 0x67, 0x84, 0xf7, 0xe8, 0x34, 0xbc, 0x1e, 0x18,
+	0xfb, 0x50, 0x98, 0x50, 0xd7, 0xa5, 0x6f, 0x62, 0x1c, 0xe2, 0x93, 0xf1, 0x97, 0x02, 0xf5, 0x88,
+	0x7d, 0xf3, 0xc6, 0xfe, 0x0d, 0x14, 0xff, 0x31, 0xb5, 0x31, 0x9e, 0xc3, 0x7e, 0x4c, 0x9f, 0x8f,
+	0xd1, 0x9e, 0x51, 0x87, 0xdb, 0x82, 0x30, 0xa9, 0x5c, 0x46, 0x0f, 0xea, 0x4f, 0x88, 0x4b, 0x3e,
+	0x0e, 0x86, 0x47, 0x7d, 0x79, 0xbb, 0x49, 0x8a, 0xa2, 0x5d, 0x28, 0x9f, 0xbf, 0x38, 0x33, 0x07,
+	0x97, 0xbd, 0x5e, 0xb7, 0xff, 0xac, 0x96, 0x49, 0x0c, 0xf8, 0xb2, 0xdf, 0x17, 0x06, 0x25, 0x31,
+	0x3c, 0x3d, 0xed, 0xf6, 0x2e, 0x71, 0xa7, 0x96, 0x4d, 0x0c, 0xc3, 0xcb, 0x76, 0xbb, 0x33, 0x1c,
+	0xd6, 0xd4, 0xa3, 0x23, 0xd0, 0xe6, 0x3f, 0xc4, 0x90, 0x06, 0xf9, 0xb3, 0xde, 0x8b, 0xf6, 0x77,
+	0xb5, 0x0c, 0x2a, 0x41, 0xee, 0x69, 0xb7, 0xd7, 0xa9, 0x29, 0xe2, 0x09, 0x77, 0x06, 0x2f, 0x6a,
+	0xd9, 0xa3, 0xab, 0x85, 0xbe, 0x45, 0x05, 0xd4, 0xe1, 0xd6, 0xa0, 0x3b, 0xe8, 0xf4, 0xba, 0xfd,
+	0x8e, 0x39, 0xbc, 0x38, 0xc5, 0x17, 0x51, 0x19, 0x7b, 0x50, 0x9b, 0x9b, 0x17, 0xb5, 0xdc, 0x81,
+	0xdb, 0x0b, 0x6b, 0x67, 0x1e, 0x9e, 0x45, 0xb7, 0x61, 0x77, 0xee, 0x10, 0x95, 0x76, 0x9e, 0xd4,
+	0xd4, 0x93, 0xbf, 0x73, 0xa0, 0x9e, 0x0e, 0xba, 0xe8, 0x0c, 0xb4, 0xb9, 0xfe, 0xa1, 0x07, 0x29,
+	0x7c, 0xd2, 0xca, 0xd8, 0xd8, 0xc0, 0x70, 0x23, 0x83, 0xbe, 0x05, 0x58, 0xc8, 0x04, 0x6a, 0xa6,
+	0x62, 0xd6, 0x14, 0xa4, 0xb1, 0xe5, 0x37, 0x82, 0x91, 0x41, 0x6d, 0x28, 0xc6, 0x9a, 0x80, 0xee,
+	0xa5, 0x82, 0x56, 0xb5, 0xa2, 0x71, 0x67, 0x73, 0x8e, 0xd0, 0xc8, 0xa0, 0x2e, 0x14, 0xe3, 0x2d,
+	0x5d, 0x4b, 0xb2, 0xba, 0xbd, 0x8d, 0x83, 0xb5, 0xeb, 0xec, 0xec, 0x2d, 0x27, 0xe1, 0x0f, 0x96,
+	0x3b, 0x23, 0x46, 0xe6, 0x91, 0x82, 0x06, 0x50, 0x5d, 0xdd, 0x4f, 0xf4, 0x70, 0x23, 0x44, 0x29,
+	0xea, 0x35, 0xf6, 0xd7, 0x12, 0x77, 0xc4, 0x7f, 0x2b, 0x46, 0x06, 0xfd, 0x08, 0xbb, 0xa9, 0x9d,
+	0x40, 0x9f, 0x6c, 0x06, 0x2c, 0x9d, 0xf3, 0x7d, 0xb7, 0x9a, 0x91, 0x41, 0x18, 0x2a, 0xcb, 0xdb,
+	0x81, 0x8c, 0x0d, 0xf8, 0xa5, 0x53, 0xde, 0x7d, 0x4f, 0x4a, 0x81, 0xe4, 0x00, 0xaa, 0xab, 0xab,
+	0xb5, 0xd6, 0xfe, 0xc6, 0xcd, 0xdb, 0xde, 0xfe, 0x59, 0xfe, 0xa5, 0xca, 0x58, 0x38, 0x2a, 0x48,
+	0xc7, 0xe3, 0x7f, 0x06, 0x00, 0x37, 0x6e, 0xc3, 0xca, 0xfa, 0x0d, 0x00, 0x00,
 }
@@ -1,9 +1,13 @@
 package main
 
 import (
+	"encoding/base64"
 	"fmt"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/pachyderm/pachyderm/src/client"
 	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
@@ -27,6 +31,7 @@ import (
 	"go.pedge.io/env"
 	"go.pedge.io/lion/proto"
 	"go.pedge.io/proto/server"
+	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"k8s.io/kubernetes/pkg/api"
 	kube_client "k8s.io/kubernetes/pkg/client/restclient"
@@ -47,34 +52,46 @@ type appEnv struct {
 	StorageBackend  string `env:"STORAGE_BACKEND,default="`
 	DatabaseAddress string `env:"RETHINK_PORT_28015_TCP_ADDR,required"`
 	DatabaseName    string `env:"DATABASE_NAME,default=pachyderm"`
+	// DatabaseMaxOpen and DatabaseMaxIdle size the pool of connections to
+	// rethink, 0 leaves the driver's defaults.
+	DatabaseMaxOpen int    `env:"DATABASE_MAX_OPEN,default=0"`
+	DatabaseMaxIdle int    `env:"DATABASE_MAX_IDLE,default=0"`
 	KubeAddress     string `env:"KUBERNETES_PORT_443_TCP_ADDR,required"`
 	EtcdAddress     string `env:"ETCD_PORT_2379_TCP_ADDR,required"`
 	Namespace       string `env:"NAMESPACE,default=default"`
 	Metrics         bool   `env:"METRICS,default=true"`
 	Init            bool   `env:"INIT,default=false"`
+	DebugAddress    string `env:"DEBUG_ADDRESS,default="`
+	ShutdownTimeout uint64 `env:"SHUTDOWN_TIMEOUT_SECONDS,default=30"`
+	// EncryptionKey, if set, is the base64 encoded key used to encrypt the
+	// values pachd writes to etcd. DecryptionKeys are comma separated,
+	// base64 encoded keys that values may also have been encrypted with,
+	// during a key rotation.
+	EncryptionKey  string `env:"DISCOVERY_ENCRYPTION_KEY,default="`
+	DecryptionKeys string `env:"DISCOVERY_DECRYPTION_KEYS,default="`
 }
 
 func main() {
 	env.Main(do, &appEnv{})
 }
 
-// isDBCreated is used to tell when we are trying to initialize a database,
-// whether we are getting an error because the database has already been
-// initialized.
-func isDBCreated(err error) bool {
-	return strings.Contains(err.Error(), "Database") && strings.Contains(err.Error(), "already exists")
-}
-
 func do(appEnvObj interface{}) error {
 	appEnv := appEnvObj.(*appEnv)
-	etcdClient := getEtcdClient(appEnv)
+	etcdClient, err := getEtcdClient(appEnv)
+	if err != nil {
+		return err
+	}
 	if appEnv.Init {
 		if err := setClusterID(etcdClient); err != nil {
 			return err
 		}
-		if err := persist_server.InitDBs(fmt.Sprintf("%s:28015", appEnv.DatabaseAddress), appEnv.DatabaseName); err != nil && !isDBCreated(err) {
+		alreadyExisted, err := persist_server.InitDBs(fmt.Sprintf("%s:28015", appEnv.DatabaseAddress), appEnv.DatabaseName)
+		if err != nil {
 			return err
 		}
+		if alreadyExisted {
+			protolion.Printf("Database %s was already initialized", appEnv.DatabaseName)
+		}
 		return nil
 	}
 	if readinessCheck {
@@ -114,13 +131,26 @@ func do(appEnvObj interface{}) error {
 		return err
 	}
 	address = fmt.Sprintf("%s:%d", address, appEnv.Port)
-	sharder := shard.NewSharder(
+	lifecycle := shard.NewLifecycle()
+	go shutdownOnSignal(lifecycle, time.Duration(appEnv.ShutdownTimeout)*time.Second)
+	sharder, err := shard.NewSharder(
 		etcdClient,
 		appEnv.NumShards,
-		appEnv.Namespace,
+		shard.WithNamespace(appEnv.Namespace),
+		shard.WithLifecycle(lifecycle),
 	)
+	if err != nil {
+		return err
+	}
+	if appEnv.DebugAddress != "" {
+		go func() {
+			if err := shard.ServeDebug(sharder, appEnv.DebugAddress); err != nil {
+				protolion.Printf("Error from shard.ServeDebug: %s", err.Error())
+			}
+		}()
+	}
 	go func() {
-		if err := sharder.AssignRoles(address, nil); err != nil {
+		if err := sharder.AssignRoles(context.Background(), address); err != nil {
 			protolion.Printf("Error from sharder.AssignRoles: %s", err.Error())
 		}
 	}()
@@ -142,7 +172,7 @@ func do(appEnvObj interface{}) error {
 		),
 	)
 	go func() {
-		if err := sharder.RegisterFrontends(nil, address, []shard.Frontend{apiServer}); err != nil {
+		if err := sharder.RegisterFrontends(context.Background(), address, []shard.Frontend{apiServer}); err != nil {
 			protolion.Printf("Error from sharder.RegisterFrontend %s", err.Error())
 		}
 	}()
@@ -167,7 +197,7 @@ func do(appEnvObj interface{}) error {
 		getNamespace(),
 	)
 	go func() {
-		if err := sharder.Register(nil, address, []shard.Server{internalAPIServer, ppsAPIServer}); err != nil {
+		if err := sharder.Register(context.Background(), address, []shard.Server{internalAPIServer, ppsAPIServer}); err != nil {
 			protolion.Printf("Error from sharder.Register %s", err.Error())
 		}
 	}()
@@ -193,8 +223,42 @@ func do(appEnvObj interface{}) error {
 	)
 }
 
-func getEtcdClient(env *appEnv) discovery.Client {
-	return discovery.NewEtcdClient(fmt.Sprintf("http://%s:2379", env.EtcdAddress))
+// shutdownOnSignal shuts lifecycle down, and then exits, when we're asked
+// to terminate.
+func shutdownOnSignal(lifecycle *shard.Lifecycle, timeout time.Duration) {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	<-sigChan
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := lifecycle.Shutdown(ctx); err != nil {
+		protolion.Errorf("Error shutting down: %s", err.Error())
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func getEtcdClient(env *appEnv) (discovery.Client, error) {
+	client := discovery.NewEtcdClient(fmt.Sprintf("http://%s:2379", env.EtcdAddress))
+	if env.EncryptionKey == "" {
+		return client, nil
+	}
+	encryptionKey, err := base64.StdEncoding.DecodeString(env.EncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DISCOVERY_ENCRYPTION_KEY: %s", err.Error())
+	}
+	var decryptionKeys [][]byte
+	for _, encoded := range strings.Split(env.DecryptionKeys, ",") {
+		if encoded == "" {
+			continue
+		}
+		decryptionKey, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DISCOVERY_DECRYPTION_KEYS: %s", err.Error())
+		}
+		decryptionKeys = append(decryptionKeys, decryptionKey)
+	}
+	return discovery.NewEncryptedClient(client, encryptionKey, decryptionKeys...)
 }
 
 const clusterIDKey = "cluster-id"
@@ -232,7 +296,14 @@ func getRethinkAPIServer(env *appEnv) (persist.APIServer, error) {
 	if err := persist_server.CheckDBs(fmt.Sprintf("%s:28015", env.DatabaseAddress), env.DatabaseName); err != nil {
 		return nil, err
 	}
-	return persist_server.NewRethinkAPIServer(fmt.Sprintf("%s:28015", env.DatabaseAddress), env.DatabaseName)
+	return persist_server.NewRethinkAPIServer(
+		fmt.Sprintf("%s:28015", env.DatabaseAddress),
+		env.DatabaseName,
+		persist_server.WithRethinkOptions(persist_server.RethinkOptions{
+			MaxOpen: env.DatabaseMaxOpen,
+			MaxIdle: env.DatabaseMaxIdle,
+		}),
+	)
 }
 
 // getNamespace returns the kubernetes namespace that this pachd pod runs in
@@ -448,6 +448,7 @@ Files can be read from finished commits with get-file.`,
 		}),
 	}
 
+	var debugAddress string
 	mount := &cobra.Command{
 		Use:   "mount path/to/mount/point",
 		Short: "Mount pfs locally.",
@@ -458,6 +459,13 @@ Files can be read from finished commits with get-file.`,
 				return err
 			}
 			mounter := fuse.NewMounter(address, client.PfsAPIClient)
+			if debugAddress != "" {
+				go func() {
+					if err := fuse.ServeDebug(mounter, debugAddress); err != nil {
+						fmt.Fprintf(os.Stderr, "Error serving debug state: %s\n", err.Error())
+					}
+				}()
+			}
 			mountPoint := args[0]
 			err = mounter.Mount(mountPoint, shard(), nil, nil)
 			if err != nil {
@@ -467,6 +475,7 @@ Files can be read from finished commits with get-file.`,
 		}),
 	}
 	addShardFlags(mount)
+	mount.Flags().StringVar(&debugAddress, "debug-address", "", "serve the mount's internal state as JSON at "+fuse.DebugPath+" on this address, such as :6060; hosts default to localhost")
 
 	var result []*cobra.Command
 	result = append(result, repo)
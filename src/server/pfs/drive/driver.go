@@ -655,6 +655,39 @@ func (d *driver) ListFile(file *pfs.File, filterShard *pfs.Shard, from *pfs.Comm
 	return result, nil
 }
 
+// ListFileStream is like ListFile but calls f with each info as soon as it's
+// built instead of returning them all. The lock is only held while each info
+// is built, so a slow f doesn't hold up writers.
+func (d *driver) ListFileStream(file *pfs.File, filterShard *pfs.Shard, from *pfs.Commit, shard uint64, recurse bool, unsafe bool, handle string, f func(*pfs.FileInfo) error) error {
+	d.lock.RLock()
+	fileInfo, _, err := d.inspectFile(file, filterShard, shard, from, false, unsafe, handle)
+	d.lock.RUnlock()
+	if err != nil {
+		return err
+	}
+	if fileInfo.FileType == pfs.FileType_FILE_TYPE_REGULAR {
+		return f(fileInfo)
+	}
+	for i, child := range fileInfo.Children {
+		// let the children already sent be collected
+		fileInfo.Children[i] = nil
+		d.lock.RLock()
+		childInfo, _, err := d.inspectFile(child, filterShard, shard, from, recurse, unsafe, handle)
+		d.lock.RUnlock()
+		if _, ok := err.(*pfsserver.ErrFileNotFound); ok {
+			// see ListFile
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := f(childInfo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (d *driver) DeleteFile(file *pfs.File, shard uint64, unsafe bool, handle string) error {
 	d.lock.RLock()
 	// We don't want to be able to delete files that are only added in the current
@@ -703,6 +736,8 @@ func (d *driver) deleteFile(file *pfs.File, shard uint64, unsafe bool, handle st
 	}
 
 	cleanPath := path.Clean(file.Path)
+	// gone is true if nothing written to the file in this commit is left
+	gone := true
 	if _append, ok := diffInfo.Appends[cleanPath]; !ok {
 		// we have no append for this file, we create on so that we can set the
 		// Delete flag in it
@@ -713,14 +748,22 @@ func (d *driver) deleteFile(file *pfs.File, shard uint64, unsafe bool, handle st
 			diffInfo.Appends[cleanPath] = newAppend(pfs.FileType_FILE_TYPE_NONE)
 		} else {
 			delete(_append.Handles, handle)
+			if isEmptyAppend(_append) {
+				_append.FileType = pfs.FileType_FILE_TYPE_NONE
+				_append.Children = nil
+			} else {
+				gone = false
+			}
 		}
+	} else {
+		gone = false
 	}
 	if !unsafe || handle == "" {
 		diffInfo.Appends[cleanPath].Delete = true
 	} else {
 		diffInfo.Appends[cleanPath].HandleDeletes[handle] = true
 	}
-	d.deleteFromDir(diffInfo, file, shard)
+	d.deleteFromDir(diffInfo, file, shard, gone)
 
 	return nil
 }
@@ -1174,6 +1217,10 @@ func (d *driver) addDirs(diffInfo *pfs.DiffInfo, child *pfs.File, shard uint64)
 		if !ok {
 			_append = newAppend(pfs.FileType_FILE_TYPE_DIR)
 			diffInfo.Appends[dirPath] = _append
+		} else if _append.FileType == pfs.FileType_FILE_TYPE_NONE {
+			// the directory was deleted in this commit and is being
+			// recreated
+			_append.FileType = pfs.FileType_FILE_TYPE_DIR
 		}
 		if _append.Children == nil {
 			_append.Children = make(map[string]bool)
@@ -1193,7 +1240,9 @@ func (d *driver) addDirs(diffInfo *pfs.DiffInfo, child *pfs.File, shard uint64)
 	}
 }
 
-func (d *driver) deleteFromDir(diffInfo *pfs.DiffInfo, child *pfs.File, shard uint64) {
+// deleteFromDir removes child from its directory, gone is true if nothing
+// written to child in this commit is left.
+func (d *driver) deleteFromDir(diffInfo *pfs.DiffInfo, child *pfs.File, shard uint64, gone bool) {
 	childPath := child.Path
 	dirPath := path.Dir(childPath)
 
@@ -1208,8 +1257,9 @@ func (d *driver) deleteFromDir(diffInfo *pfs.DiffInfo, child *pfs.File, shard ui
 	// Basically, we only set the entry to false if it's not been
 	// set to true.  If it's been set to true, that means that there
 	// is a PutFile operation in this commit for this very same file,
-	// so we don't want to remove the file from the directory.
-	if !_append.Children[childPath] {
+	// so we don't want to remove the file from the directory, unless
+	// what it put has been deleted too.
+	if gone || !_append.Children[childPath] {
 		_append.Children[childPath] = false
 		if diffInfo.ParentCommit != nil {
 			_append.LastRef = d.lastRef(
@@ -1361,6 +1411,17 @@ func repoSetFromCommits(commits []*pfs.Commit) map[string]bool {
 	return result
 }
 
+// isEmptyAppend returns true if nothing written in _append's commit is left
+// in it.
+func isEmptyAppend(_append *pfs.Append) bool {
+	for _, add := range _append.Children {
+		if add {
+			return false
+		}
+	}
+	return len(_append.Handles) == 0 && len(_append.BlockRefs) == 0
+}
+
 func newAppend(filetype pfs.FileType) *pfs.Append {
 	return &pfs.Append{
 		Handles:       make(map[string]*pfs.BlockRefs),
@@ -0,0 +1,94 @@
+package fuse
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/debugutil"
+)
+
+// DebugPath is the path at which ServeDebug serves a Mounter's DebugState.
+const DebugPath = "/debug/fuse"
+
+// DebugState is a snapshot of the internal state of a Mounter's mounts, it's
+// only meant for humans debugging a mount.
+type DebugState struct {
+	// Mounts are keyed by mount point.
+	Mounts map[string]*MountDebugState
+}
+
+// MountDebugState is the internal state of a single mount.
+type MountDebugState struct {
+	OpenHandles int64
+	// Ops are keyed by operation, such as "DirectoryLookup".
+	Ops map[string]*OpStats
+}
+
+// OpStats counts the calls to a fuse operation.
+type OpStats struct {
+	Count        int64
+	Errors       int64
+	TotalLatency time.Duration
+}
+
+// NewDebugHandler returns a read-only http.Handler which serves m's
+// DebugState as JSON.
+func NewDebugHandler(m Mounter) http.Handler {
+	return debugutil.NewJSONHandler(func() (interface{}, error) {
+		debugMounter, ok := m.(*mounter)
+		if !ok {
+			return nil, fmt.Errorf("pachyderm: %T doesn't expose debug state", m)
+		}
+		return debugMounter.debugState(), nil
+	})
+}
+
+// ServeDebug serves m's DebugState at DebugPath on address, if address has
+// no host it's bound to localhost.
+func ServeDebug(m Mounter, address string) error {
+	return debugutil.Serve(address, DebugPath, NewDebugHandler(m))
+}
+
+func (m *mounter) debugState() *DebugState {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	result := &DebugState{Mounts: make(map[string]*MountDebugState)}
+	for mountPoint, filesystem := range m.filesystems {
+		result.Mounts[mountPoint] = filesystem.debugState()
+	}
+	return result
+}
+
+func (f *filesystem) debugState() *MountDebugState {
+	result := &MountDebugState{
+		OpenHandles: atomic.LoadInt64(&f.openHandles),
+		Ops:         make(map[string]*OpStats),
+	}
+	f.opStatsLock.Lock()
+	defer f.opStatsLock.Unlock()
+	for op, stats := range f.opStats {
+		statsCopy := *stats
+		result.Ops[op] = &statsCopy
+	}
+	return result
+}
+
+// observe records a call to op which started at start, it's meant to be
+// deferred with a pointer to the op's named error result.
+func (f *filesystem) observe(op string, start time.Time, err *error) {
+	latency := time.Since(start)
+	f.opStatsLock.Lock()
+	defer f.opStatsLock.Unlock()
+	stats, ok := f.opStats[op]
+	if !ok {
+		stats = &OpStats{}
+		f.opStats[op] = stats
+	}
+	stats.Count++
+	stats.TotalLatency += latency
+	if *err != nil {
+		stats.Errors++
+	}
+}
@@ -0,0 +1,62 @@
+package fuse
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bazil.org/fuse"
+	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"golang.org/x/net/context"
+)
+
+func TestDebugHandler(t *testing.T) {
+	m := newMounter("", nil).(*mounter)
+	filesystem := newFilesystem(nil, nil, nil)
+	m.filesystems["/pfs"] = filesystem
+	f := &file{directory: directory{fs: filesystem}}
+	f.newHandle(0)
+	var err error
+	filesystem.observe("FileOpen", time.Now(), &err)
+	err = fmt.Errorf("not found")
+	filesystem.observe("FileOpen", time.Now(), &err)
+	server := httptest.NewServer(NewDebugHandler(m))
+	defer server.Close()
+
+	response, err := http.Get(server.URL + DebugPath)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	var state DebugState
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&state))
+	mount := state.Mounts["/pfs"]
+	require.NotNil(t, mount)
+	require.Equal(t, int64(1), mount.OpenHandles)
+	require.Equal(t, int64(2), mount.Ops["FileOpen"].Count)
+	require.Equal(t, int64(1), mount.Ops["FileOpen"].Errors)
+
+	postResponse, err := http.Post(server.URL+DebugPath, "application/json", nil)
+	require.NoError(t, err)
+	postResponse.Body.Close()
+	require.Equal(t, http.StatusMethodNotAllowed, postResponse.StatusCode)
+}
+
+func BenchmarkDirectoryAttr(b *testing.B) {
+	d := &directory{
+		fs:   newFilesystem(nil, nil, nil),
+		Node: Node{File: &pfsclient.File{Commit: &pfsclient.Commit{Repo: &pfsclient.Repo{Name: "repo"}, ID: "commit"}}},
+	}
+	ctx := context.Background()
+	var attr fuse.Attr
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := d.Attr(ctx, &attr); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package fuse
+
+import (
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"bazil.org/fuse"
+	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"go.pedge.io/proto/time"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+const dirCacheEntries = 1000
+
+// listFileAPIClient counts ListFile and ListFileStream calls and answers
+// them with dirCacheEntries files, PutFile drops what's written like
+// putFileAPIClient.
+type listFileAPIClient struct {
+	putFileAPIClient
+	listFiles   int
+	listStreams int
+}
+
+func (c *listFileAPIClient) ListFile(ctx context.Context, request *pfsclient.ListFileRequest, opts ...grpc.CallOption) (*pfsclient.FileInfos, error) {
+	c.listFiles++
+	stream := &listFileStreamClient{dir: request.File}
+	fileInfos := &pfsclient.FileInfos{}
+	for {
+		fileInfo, err := stream.Recv()
+		if err == io.EOF {
+			return fileInfos, nil
+		}
+		fileInfos.FileInfo = append(fileInfos.FileInfo, fileInfo)
+	}
+}
+
+func (c *listFileAPIClient) ListFileStream(ctx context.Context, request *pfsclient.ListFileRequest, opts ...grpc.CallOption) (pfsclient.API_ListFileStreamClient, error) {
+	c.listFiles++
+	c.listStreams++
+	return &listFileStreamClient{dir: request.File}, nil
+}
+
+type listFileStreamClient struct {
+	grpc.ClientStream
+	dir  *pfsclient.File
+	sent int
+}
+
+func (c *listFileStreamClient) Recv() (*pfsclient.FileInfo, error) {
+	if c.sent == dirCacheEntries {
+		return nil, io.EOF
+	}
+	c.sent++
+	return &pfsclient.FileInfo{
+		File:     &pfsclient.File{Commit: c.dir.Commit, Path: fmt.Sprintf("%s/file-%d", c.dir.Path, c.sent)},
+		FileType: pfsclient.FileType_FILE_TYPE_REGULAR,
+	}, nil
+}
+
+func newDirCacheTestDirectory(apiClient pfsclient.APIClient, dirCacheTTL time.Duration) *directory {
+	filesystem := newFilesystem(apiClient, nil, nil)
+	filesystem.DirCacheTtl = prototime.DurationToProto(dirCacheTTL)
+	return &directory{
+		fs:   filesystem,
+		Node: Node{File: &pfsclient.File{Commit: &pfsclient.Commit{Repo: &pfsclient.Repo{Name: "repo"}, ID: "commit"}, Path: "/dir"}, Write: true},
+	}
+}
+
+func countReadDirAllRPCs(t *testing.T, dirCacheTTL time.Duration) int {
+	apiClient := &listFileAPIClient{}
+	d := newDirCacheTestDirectory(apiClient, dirCacheTTL)
+	for i := 0; i < 100; i++ {
+		dirents, err := d.ReadDirAll(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, dirCacheEntries, len(dirents))
+	}
+	return apiClient.listFiles
+}
+
+func TestDirCache(t *testing.T) {
+	require.Equal(t, 100, countReadDirAllRPCs(t, 0))
+	require.Equal(t, 1, countReadDirAllRPCs(t, time.Minute))
+}
+
+func TestDirCacheInvalidatedOnCreate(t *testing.T) {
+	apiClient := &listFileAPIClient{}
+	d := newDirCacheTestDirectory(apiClient, time.Minute)
+	ctx := context.Background()
+	_, err := d.ReadDirAll(ctx)
+	require.NoError(t, err)
+	_, _, err = d.Create(ctx, &fuse.CreateRequest{Name: "new"}, &fuse.CreateResponse{})
+	require.NoError(t, err)
+	_, err = d.ReadDirAll(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, apiClient.listFiles)
+}
+
+func TestStreamThreshold(t *testing.T) {
+	// Directories are streamed until they're known to be small
+	apiClient := &listFileAPIClient{}
+	d := newDirCacheTestDirectory(apiClient, 0)
+	for i := 0; i < 10; i++ {
+		dirents, err := d.ReadDirAll(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, dirCacheEntries, len(dirents))
+	}
+	require.Equal(t, 10, apiClient.listFiles)
+	require.Equal(t, 1, apiClient.listStreams)
+
+	// Larger ones are always streamed
+	apiClient = &listFileAPIClient{}
+	d = newDirCacheTestDirectory(apiClient, 0)
+	d.fs.StreamThreshold = dirCacheEntries - 1
+	for i := 0; i < 10; i++ {
+		_, err := d.ReadDirAll(context.Background())
+		require.NoError(t, err)
+	}
+	require.Equal(t, 10, apiClient.listStreams)
+}
+
+func benchmarkReadDirAll(b *testing.B, dirCacheTTL time.Duration) {
+	apiClient := &listFileAPIClient{}
+	d := newDirCacheTestDirectory(apiClient, dirCacheTTL)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < 100; j++ {
+			if _, err := d.ReadDirAll(context.Background()); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+	b.Logf("%d ListFile RPCs for %d listings", apiClient.listFiles, 100*b.N)
+}
+
+func BenchmarkReadDirAllUncached(b *testing.B) {
+	benchmarkReadDirAll(b, 0)
+}
+
+func BenchmarkReadDirAllCached(b *testing.B) {
+	benchmarkReadDirAll(b, time.Minute)
+}
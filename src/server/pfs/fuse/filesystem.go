@@ -1,14 +1,17 @@
 package fuse
 
 import (
+	"bufio"
 	"bytes"
 	"fmt"
+	"hash/fnv"
 	"io"
 	"os"
 	"path"
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -16,6 +19,8 @@ import (
 	"bazil.org/fuse/fs"
 	"github.com/pachyderm/pachyderm/src/client"
 	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errorutil"
+	"github.com/pachyderm/pachyderm/src/client/pkg/loglevel"
 	"github.com/pachyderm/pachyderm/src/client/pkg/uuid"
 	"go.pedge.io/lion/proto"
 	"go.pedge.io/proto/time"
@@ -27,9 +32,65 @@ import (
 type filesystem struct {
 	apiClient client.APIClient
 	Filesystem
-	inodes   map[string]uint64
-	lock     sync.RWMutex
-	handleID string
+	handleID    string
+	openHandles int64
+	opStats     map[string]*OpStats
+	opStatsLock sync.Mutex
+	// negativeEntries are the keys of files Lookup recently found missing,
+	// mapped to when they were found missing. They're trusted for
+	// negativeEntryTTL so probes for absent files don't each need an RPC.
+	negativeEntries   map[string]time.Time
+	negativeEntryTTL  time.Duration
+	negativeEntryLock sync.Mutex
+	// dirCache holds the dirents readFiles read for each directory, keyed by
+	// key(file), so listing a directory again within Filesystem.DirCacheTtl
+	// doesn't need an RPC.
+	dirCache     map[string]dirCacheEntry
+	dirCacheLock sync.Mutex
+	// dirSizes holds how many entries each directory, keyed by key(file),
+	// had when readFiles last listed it. Directories known to have at most
+	// Filesystem.StreamThreshold entries are listed with ListFile, others,
+	// including ones that haven't been listed, are streamed.
+	dirSizes     map[string]int
+	dirSizesLock sync.Mutex
+	// writeHandles counts, for each commit, the handles which are open for
+	// writing to it. See CommitMount.AutoFinish and MaxWriteHandles.
+	writeHandles     map[string]int
+	writeHandlesLock sync.Mutex
+	// nodes are the nodes in commits open for writing which have been
+	// handed to the kernel. It keeps using the node it got for a file's old
+	// name after Rename, so Rename moves them along with the file.
+	nodes     map[*Node]bool
+	nodesLock sync.Mutex
+	statsCollector
+}
+
+// defaultNegativeEntryTTL is how long Lookup trusts that a file is missing.
+const defaultNegativeEntryTTL = time.Second
+
+// maxNegativeEntries is how many negative entries there can be before
+// expired ones are dropped.
+const maxNegativeEntries = 1024
+
+// defaultDirCacheTTL is how long a listing is reused when
+// Filesystem.DirCacheTtl isn't set.
+const defaultDirCacheTTL = 100 * time.Millisecond
+
+// maxDirCacheEntries is how many listings there can be in the dir cache
+// before expired ones are dropped.
+const maxDirCacheEntries = 128
+
+// defaultStreamThreshold is how many entries a directory can have before its
+// listing is streamed, when Filesystem.StreamThreshold isn't set.
+const defaultStreamThreshold = 10000
+
+// maxDirSizes is how many directories' sizes are remembered before they're
+// all forgotten.
+const maxDirSizes = 1024
+
+type dirCacheEntry struct {
+	dirents []fuse.Dirent
+	readAt  time.Time
 }
 
 func newFilesystem(
@@ -40,21 +101,27 @@ func newFilesystem(
 	return &filesystem{
 		apiClient: client.APIClient{PfsAPIClient: pfsAPIClient},
 		Filesystem: Filesystem{
-			shard,
-			commitMounts,
+			Shard:        shard,
+			CommitMounts: commitMounts,
 		},
-		inodes:   make(map[string]uint64),
-		lock:     sync.RWMutex{},
-		handleID: uuid.NewWithoutDashes(),
+		handleID:         uuid.NewWithoutDashes(),
+		opStats:          make(map[string]*OpStats),
+		negativeEntries:  make(map[string]time.Time),
+		negativeEntryTTL: defaultNegativeEntryTTL,
+		dirCache:         make(map[string]dirCacheEntry),
+		dirSizes:         make(map[string]int),
+		writeHandles:     make(map[string]int),
+		nodes:            make(map[*Node]bool),
 	}
 }
 
 func (f *filesystem) Root() (result fs.Node, retErr error) {
+	defer f.observe("Root", time.Now(), &retErr)
 	defer func() {
-		if retErr == nil {
-			protolion.Debug(&Root{&f.Filesystem, getNode(result), errorToString(retErr)})
-		} else {
-			protolion.Error(&Root{&f.Filesystem, getNode(result), errorToString(retErr)})
+		if retErr != nil {
+			protolion.Error(&Root{&f.Filesystem, getNode(result), errorutil.String(retErr)})
+		} else if loglevel.DebugEnabled() {
+			protolion.Debug(&Root{&f.Filesystem, getNode(result), errorutil.String(retErr)})
 		}
 	}()
 	return &directory{
@@ -69,17 +136,55 @@ func (f *filesystem) Root() (result fs.Node, retErr error) {
 	}, nil
 }
 
+// statfsBlockSize is the block size Statfs reports sizes in.
+const statfsBlockSize = 4096
+
+// statfsUnlimited is reported as the free blocks and inodes, since PFS has
+// no quota.
+const statfsUnlimited = 1 << 40
+
+func (f *filesystem) Statfs(ctx context.Context, request *fuse.StatfsRequest, response *fuse.StatfsResponse) (retErr error) {
+	defer f.observe("Statfs", time.Now(), &retErr)
+	defer func() {
+		if retErr != nil {
+			protolion.Error(&Statfs{&f.Filesystem, response.Blocks, errorutil.String(retErr)})
+		} else if loglevel.DebugEnabled() {
+			protolion.Debug(&Statfs{&f.Filesystem, response.Blocks, errorutil.String(retErr)})
+		}
+	}()
+	repoInfos, err := f.apiClient.ListRepo(nil)
+	if err != nil {
+		return rpcError(err, "ListRepo", client.NewFile("", "", ""))
+	}
+	var sizeBytes uint64
+	for _, repoInfo := range repoInfos {
+		if f.getCommitMount(repoInfo.Repo.Name) == nil {
+			continue
+		}
+		sizeBytes += repoInfo.SizeBytes
+	}
+	response.Bsize = statfsBlockSize
+	response.Frsize = statfsBlockSize
+	response.Blocks = (sizeBytes + statfsBlockSize - 1) / statfsBlockSize
+	response.Bfree = statfsUnlimited
+	response.Bavail = statfsUnlimited
+	response.Files = statfsUnlimited
+	response.Ffree = statfsUnlimited
+	return nil
+}
+
 type directory struct {
 	fs *filesystem
 	Node
 }
 
 func (d *directory) Attr(ctx context.Context, a *fuse.Attr) (retErr error) {
+	defer d.fs.observe("DirectoryAttr", time.Now(), &retErr)
 	defer func() {
-		if retErr == nil {
-			protolion.Debug(&DirectoryAttr{&d.Node, &Attr{uint32(a.Mode)}, errorToString(retErr)})
-		} else {
-			protolion.Error(&DirectoryAttr{&d.Node, &Attr{uint32(a.Mode)}, errorToString(retErr)})
+		if retErr != nil {
+			protolion.Error(&DirectoryAttr{&d.Node, &Attr{uint32(a.Mode)}, errorutil.String(retErr)})
+		} else if loglevel.DebugEnabled() {
+			protolion.Debug(&DirectoryAttr{&d.Node, &Attr{uint32(a.Mode)}, errorutil.String(retErr)})
 		}
 	}()
 
@@ -95,13 +200,15 @@ func (d *directory) Attr(ctx context.Context, a *fuse.Attr) (retErr error) {
 }
 
 func (d *directory) Lookup(ctx context.Context, name string) (result fs.Node, retErr error) {
+	defer d.fs.observe("DirectoryLookup", time.Now(), &retErr)
 	defer func() {
-		if retErr == nil {
-			protolion.Debug(&DirectoryLookup{&d.Node, name, getNode(result), errorToString(retErr)})
-		} else {
-			protolion.Error(&DirectoryLookup{&d.Node, name, getNode(result), errorToString(retErr)})
+		if retErr != nil {
+			protolion.Error(&DirectoryLookup{&d.Node, name, getNode(result), errorutil.String(retErr)})
+		} else if loglevel.DebugEnabled() {
+			protolion.Debug(&DirectoryLookup{&d.Node, name, getNode(result), errorutil.String(retErr)})
 		}
 	}()
+	d.fs.addLookup()
 	if d.File.Commit.Repo.Name == "" {
 		return d.lookUpRepo(ctx, name)
 	}
@@ -112,15 +219,16 @@ func (d *directory) Lookup(ctx context.Context, name string) (result fs.Node, re
 }
 
 func (d *directory) ReadDirAll(ctx context.Context) (result []fuse.Dirent, retErr error) {
+	defer d.fs.observe("DirectoryReadDirAll", time.Now(), &retErr)
 	defer func() {
 		var dirents []*Dirent
 		for _, dirent := range result {
 			dirents = append(dirents, &Dirent{dirent.Inode, dirent.Name})
 		}
-		if retErr == nil {
-			protolion.Debug(&DirectoryReadDirAll{&d.Node, dirents, errorToString(retErr)})
-		} else {
-			protolion.Error(&DirectoryReadDirAll{&d.Node, dirents, errorToString(retErr)})
+		if retErr != nil {
+			protolion.Error(&DirectoryReadDirAll{&d.Node, dirents, errorutil.String(retErr)})
+		} else if loglevel.DebugEnabled() {
+			protolion.Debug(&DirectoryReadDirAll{&d.Node, dirents, errorutil.String(retErr)})
 		}
 	}()
 	if d.File.Commit.Repo.Name == "" {
@@ -139,11 +247,12 @@ func (d *directory) ReadDirAll(ctx context.Context) (result []fuse.Dirent, retEr
 }
 
 func (d *directory) Create(ctx context.Context, request *fuse.CreateRequest, response *fuse.CreateResponse) (result fs.Node, _ fs.Handle, retErr error) {
+	defer d.fs.observe("DirectoryCreate", time.Now(), &retErr)
 	defer func() {
-		if retErr == nil {
-			protolion.Debug(&DirectoryCreate{&d.Node, getNode(result), errorToString(retErr)})
-		} else {
-			protolion.Error(&DirectoryCreate{&d.Node, getNode(result), errorToString(retErr)})
+		if retErr != nil {
+			protolion.Error(&DirectoryCreate{&d.Node, getNode(result), errorutil.String(retErr)})
+		} else if loglevel.DebugEnabled() {
+			protolion.Debug(&DirectoryCreate{&d.Node, getNode(result), errorutil.String(retErr)})
 		}
 	}()
 	if d.File.Commit.ID == "" {
@@ -151,61 +260,179 @@ func (d *directory) Create(ctx context.Context, request *fuse.CreateRequest, res
 	}
 	directory := d.copy()
 	directory.File.Path = path.Join(directory.File.Path, request.Name)
+	if !d.fs.addWriteHandle(directory.File.Commit, d.fs.maxWriteHandles(d.getRepoOrAliasName())) {
+		return nil, 0, fuse.Errno(syscall.EMFILE)
+	}
+	d.fs.removeNegativeEntry(directory.File)
+	d.fs.removeDirCacheEntry(d.File)
 	localResult := &file{
 		directory: *directory,
 		size:      0,
 	}
 	if err := localResult.touch(); err != nil {
+		d.fs.releaseWriteHandle(directory.File.Commit)
 		return nil, 0, err
 	}
 	response.Flags |= fuse.OpenDirectIO | fuse.OpenNonSeekable
 	handle := localResult.newHandle(0)
+	handle.writing = true
+	d.fs.addNode(&localResult.Node)
 	return localResult, handle, nil
 }
 
 func (d *directory) Mkdir(ctx context.Context, request *fuse.MkdirRequest) (result fs.Node, retErr error) {
+	defer d.fs.observe("DirectoryMkdir", time.Now(), &retErr)
 	defer func() {
-		if retErr == nil {
-			protolion.Debug(&DirectoryMkdir{&d.Node, getNode(result), errorToString(retErr)})
-		} else {
-			protolion.Error(&DirectoryMkdir{&d.Node, getNode(result), errorToString(retErr)})
+		if retErr != nil {
+			protolion.Error(&DirectoryMkdir{&d.Node, getNode(result), errorutil.String(retErr)})
+		} else if loglevel.DebugEnabled() {
+			protolion.Debug(&DirectoryMkdir{&d.Node, getNode(result), errorutil.String(retErr)})
 		}
 	}()
 	if d.File.Commit.ID == "" {
 		return nil, fuse.EPERM
 	}
-	if err := d.fs.apiClient.MakeDirectory(d.File.Commit.Repo.Name, d.File.Commit.ID, path.Join(d.File.Path, request.Name)); err != nil {
-		return nil, err
-	}
 	localResult := d.copy()
 	localResult.File.Path = path.Join(localResult.File.Path, request.Name)
+	d.fs.removeNegativeEntry(localResult.File)
+	d.fs.removeDirCacheEntry(d.File)
+	if err := d.fs.apiClient.MakeDirectory(d.File.Commit.Repo.Name, d.File.Commit.ID, localResult.File.Path); err != nil {
+		return nil, rpcError(err, "MakeDirectory", localResult.File)
+	}
+	d.fs.addNode(&localResult.Node)
 	return localResult, nil
 }
 
+func (d *directory) Forget() {
+	d.fs.removeNode(&d.Node)
+}
+
 func (d *directory) Remove(ctx context.Context, req *fuse.RemoveRequest) (retErr error) {
+	defer d.fs.observe("FileRemove", time.Now(), &retErr)
 	defer func() {
-		if retErr == nil {
-			protolion.Debug(&FileRemove{&d.Node, req.Name, req.Dir, errorToString(retErr)})
-		} else {
-			protolion.Error(&FileRemove{&d.Node, req.Name, req.Dir, errorToString(retErr)})
+		if retErr != nil {
+			protolion.Error(&FileRemove{&d.Node, req.Name, req.Dir, errorutil.String(retErr)})
+		} else if loglevel.DebugEnabled() {
+			protolion.Debug(&FileRemove{&d.Node, req.Name, req.Dir, errorutil.String(retErr)})
 		}
 	}()
-	return d.fs.apiClient.DeleteFile(d.Node.File.Commit.Repo.Name,
-		d.Node.File.Commit.ID, filepath.Join(d.Node.File.Path, req.Name), true, d.fs.handleID)
+	removed := client.NewFile(d.Node.File.Commit.Repo.Name, d.Node.File.Commit.ID, filepath.Join(d.Node.File.Path, req.Name))
+	d.fs.removeDirCacheEntry(d.File)
+	if err := d.fs.apiClient.DeleteFile(removed.Commit.Repo.Name, removed.Commit.ID, removed.Path, true, d.fs.handleID); err != nil {
+		if req.Dir || grpc.Code(err) != codes.NotFound {
+			return rpcError(err, "DeleteFile", removed)
+		}
+		// There's no file called req.Name, it may be a symlink.
+		if symlinkErr := d.fs.apiClient.DeleteFile(removed.Commit.Repo.Name, removed.Commit.ID, removed.Path+symlinkSuffix, true, d.fs.handleID); symlinkErr != nil {
+			if grpc.Code(symlinkErr) != codes.NotFound {
+				err = symlinkErr
+			}
+			return rpcError(err, "DeleteFile", removed)
+		}
+	}
+	return nil
+}
+
+func (d *directory) Rename(ctx context.Context, request *fuse.RenameRequest, newDir fs.Node) (retErr error) {
+	defer d.fs.observe("DirectoryRename", time.Now(), &retErr)
+	defer func() {
+		if retErr != nil {
+			protolion.Error(&DirectoryRename{&d.Node, request.OldName, getNode(newDir), request.NewName, errorutil.String(retErr)})
+		} else if loglevel.DebugEnabled() {
+			protolion.Debug(&DirectoryRename{&d.Node, request.OldName, getNode(newDir), request.NewName, errorutil.String(retErr)})
+		}
+	}()
+	if d.File.Commit.ID == "" || !d.Write {
+		return fuse.EPERM
+	}
+	newDirectory, ok := newDir.(*directory)
+	if !ok || newDirectory.File.Commit.Repo.Name != d.File.Commit.Repo.Name ||
+		newDirectory.File.Commit.ID != d.File.Commit.ID {
+		// Moving between commits would need a copy, which mv does itself
+		// when rename(2) fails with EXDEV.
+		return fuse.Errno(syscall.EXDEV)
+	}
+	oldFile := client.NewFile(d.File.Commit.Repo.Name, d.File.Commit.ID, path.Join(d.File.Path, request.OldName))
+	newPath := path.Join(newDirectory.File.Path, request.NewName)
+	d.fs.removeNegativeEntry(client.NewFile(oldFile.Commit.Repo.Name, oldFile.Commit.ID, newPath))
+	d.fs.removeDirCacheEntry(d.File)
+	d.fs.removeDirCacheEntry(newDirectory.File)
+	if err := d.fs.apiClient.MoveFile(oldFile.Commit.Repo.Name, oldFile.Commit.ID, oldFile.Path, newPath, d.fs.handleID); err != nil {
+		if grpc.Code(err) != codes.NotFound {
+			return rpcError(err, "MoveFile", oldFile)
+		}
+		// There's no file called request.OldName, it may be a symlink.
+		if symlinkErr := d.fs.apiClient.MoveFile(oldFile.Commit.Repo.Name, oldFile.Commit.ID, oldFile.Path+symlinkSuffix, newPath+symlinkSuffix, d.fs.handleID); symlinkErr != nil {
+			if grpc.Code(symlinkErr) != codes.NotFound {
+				err = symlinkErr
+			}
+			return rpcError(err, "MoveFile", oldFile)
+		}
+		oldFile.Path += symlinkSuffix
+		newPath += symlinkSuffix
+	}
+	d.fs.moveNodes(oldFile, newPath)
+	return nil
+}
+
+// syncFile is the file whose presence at the root of a commit makes fsync on
+// one of the commit's directories finish the commit. This is how a process
+// that doesn't own the mount can signal that the commit is done, the other
+// way to finish commits is CommitMount.AutoFinish.
+const syncFile = ".pfs_sync"
+
+func (d *directory) Fsync(ctx context.Context, request *fuse.FsyncRequest) (retErr error) {
+	defer d.fs.observe("DirectoryFsync", time.Now(), &retErr)
+	var finished bool
+	defer func() {
+		if retErr != nil {
+			protolion.Error(&DirectoryFsync{&d.Node, finished, errorutil.String(retErr)})
+		} else if loglevel.DebugEnabled() {
+			protolion.Debug(&DirectoryFsync{&d.Node, finished, errorutil.String(retErr)})
+		}
+	}()
+	if d.File.Commit.ID == "" || !d.Write {
+		return nil
+	}
+	syncFileInfo, err := d.fs.apiClient.InspectFileUnsafe(
+		d.File.Commit.Repo.Name,
+		d.File.Commit.ID,
+		syncFile,
+		d.fs.getFromCommitID(d.getRepoOrAliasName()),
+		d.Shard,
+		d.fs.handleID,
+	)
+	if err != nil {
+		if grpc.Code(err) == codes.NotFound {
+			return nil
+		}
+		return rpcError(err, "InspectFile", d.File)
+	}
+	if syncFileInfo.FileType != pfsclient.FileType_FILE_TYPE_REGULAR {
+		return nil
+	}
+	if err := d.fs.apiClient.FinishCommit(d.File.Commit.Repo.Name, d.File.Commit.ID); err != nil {
+		return rpcError(err, "FinishCommit", d.File)
+	}
+	finished = true
+	return nil
 }
 
 type file struct {
 	directory
 	size    int64
 	handles []*handle
+	// symlink is set for files holding a symlink, see Symlink.
+	symlink bool
 }
 
 func (f *file) Attr(ctx context.Context, a *fuse.Attr) (retErr error) {
+	defer f.fs.observe("FileAttr", time.Now(), &retErr)
 	defer func() {
-		if retErr == nil {
-			protolion.Debug(&FileAttr{&f.Node, &Attr{uint32(a.Mode)}, errorToString(retErr)})
-		} else {
-			protolion.Error(&FileAttr{&f.Node, &Attr{uint32(a.Mode)}, errorToString(retErr)})
+		if retErr != nil {
+			protolion.Error(&FileAttr{&f.Node, &Attr{uint32(a.Mode)}, errorutil.String(retErr)})
+		} else if loglevel.DebugEnabled() {
+			protolion.Debug(&FileAttr{&f.Node, &Attr{uint32(a.Mode)}, errorutil.String(retErr)})
 		}
 	}()
 	fileInfo, err := f.fs.apiClient.InspectFileUnsafe(
@@ -217,50 +444,143 @@ func (f *file) Attr(ctx context.Context, a *fuse.Attr) (retErr error) {
 		f.fs.handleID,
 	)
 	if err != nil {
-		return err
+		return rpcError(err, "InspectFile", f.File)
 	}
 	if fileInfo != nil {
 		a.Size = fileInfo.SizeBytes
+		if f.symlink && !isSymlinkPath(f.File.Path) && isLegacySymlinkSize(a.Size) {
+			a.Size -= uint64(len(legacySymlinkPrefix))
+		}
 		a.Mtime = prototime.TimestampToTime(fileInfo.Modified)
 	}
 	a.Mode = 0666
+	if f.symlink {
+		a.Mode = os.ModeSymlink | 0777
+	}
 	a.Inode = f.fs.inode(f.File)
 	return nil
 }
 
 func (f *file) Setattr(ctx context.Context, req *fuse.SetattrRequest, resp *fuse.SetattrResponse) (retErr error) {
+	defer f.fs.observe("FileSetAttr", time.Now(), &retErr)
 	defer func() {
-		if retErr == nil {
-			protolion.Debug(&FileSetAttr{&f.Node, errorToString(retErr)})
-		} else {
-			protolion.Error(&FileSetAttr{&f.Node, errorToString(retErr)})
+		if retErr != nil {
+			protolion.Error(&FileSetAttr{&f.Node, errorutil.String(retErr)})
+		} else if loglevel.DebugEnabled() {
+			protolion.Debug(&FileSetAttr{&f.Node, errorutil.String(retErr)})
 		}
 	}()
-	if req.Size == 0 {
-		err := f.fs.apiClient.DeleteFile(f.Node.File.Commit.Repo.Name,
-			f.Node.File.Commit.ID, f.Node.File.Path, true, f.fs.handleID)
-		if err != nil {
+	if !req.Valid.Size() {
+		return nil
+	}
+	if f.File.Commit.ID == "" || !f.Write {
+		return fuse.EPERM
+	}
+	// Writes still buffered in handles have to reach PFS before the file
+	// is read and deleted.
+	for _, h := range f.handles {
+		if err := h.closeWriter(); err != nil {
 			return err
 		}
-		if err := f.touch(); err != nil {
-			return err
+	}
+	return f.truncate(int64(req.Size))
+}
+
+// truncate resizes the file to size. Growing it appends zeros, but PFS can't
+// shrink a file in place, so it's deleted and its first size bytes are
+// streamed back in.
+func (f *file) truncate(size int64) error {
+	current, err := f.stat()
+	if err != nil {
+		return err
+	}
+	if size == current {
+		return nil
+	}
+	var kept *bufio.Reader
+	if size < current {
+		if size > 0 {
+			r, w := io.Pipe()
+			defer r.Close()
+			go func() {
+				w.CloseWithError(f.fs.apiClient.GetFileUnsafe(
+					f.File.Commit.Repo.Name,
+					f.File.Commit.ID,
+					f.File.Path,
+					0,
+					size,
+					f.fs.getFromCommitID(f.getRepoOrAliasName()),
+					f.Shard,
+					f.fs.handleID,
+					w,
+				))
+			}()
+			// PFS finds the blocks it's going to read before sending
+			// any of them, so once the first byte is here deleting the
+			// file doesn't change what's read.
+			kept = bufio.NewReader(r)
+			if _, err := kept.Peek(1); err != nil {
+				return rpcError(err, "GetFile", f.File)
+			}
 		}
-		for _, handle := range f.handles {
-			handle.cursor = 0
+		if err := f.fs.apiClient.DeleteFile(f.File.Commit.Repo.Name, f.File.Commit.ID, f.File.Path, true, f.fs.handleID); err != nil {
+			return rpcError(err, "DeleteFile", f.File)
 		}
 	}
+	w, err := f.fs.apiClient.PutFileWriter(
+		f.File.Commit.Repo.Name,
+		f.File.Commit.ID,
+		f.File.Path,
+		pfsclient.Delimiter_LINE,
+		f.fs.handleID,
+	)
+	if err != nil {
+		return rpcError(err, "PutFile", f.File)
+	}
+	if kept != nil {
+		if _, err := io.Copy(w, kept); err != nil {
+			w.Close()
+			return rpcError(err, "PutFile", f.File)
+		}
+	}
+	if size > current {
+		if _, err := io.CopyN(w, zeroReader{}, size-current); err != nil {
+			w.Close()
+			return rpcError(err, "PutFile", f.File)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return rpcError(err, "PutFile", f.File)
+	}
+	f.size = size
+	for _, handle := range f.handles {
+		handle.cursor = int(size)
+	}
 	return nil
 }
 
+// zeroReader reads an endless run of zeros.
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
+
 func (f *file) Open(ctx context.Context, request *fuse.OpenRequest, response *fuse.OpenResponse) (_ fs.Handle, retErr error) {
+	defer f.fs.observe("FileOpen", time.Now(), &retErr)
 	defer func() {
-		if retErr == nil {
-			protolion.Debug(&FileOpen{&f.Node, errorToString(retErr)})
-		} else {
-			protolion.Error(&FileOpen{&f.Node, errorToString(retErr)})
+		if retErr != nil {
+			protolion.Error(&FileOpen{&f.Node, errorutil.String(retErr)})
+		} else if loglevel.DebugEnabled() {
+			protolion.Debug(&FileOpen{&f.Node, errorutil.String(retErr)})
 		}
 	}()
-	response.Flags |= fuse.OpenDirectIO | fuse.OpenNonSeekable
+	// Reads go straight to GetFile at the requested offset, so files
+	// opened for reading can be seeked, writes still have to append.
+	response.Flags |= fuse.OpenDirectIO
 	fileInfo, err := f.fs.apiClient.InspectFileUnsafe(
 		f.File.Commit.Repo.Name,
 		f.File.Commit.ID,
@@ -270,19 +590,41 @@ func (f *file) Open(ctx context.Context, request *fuse.OpenRequest, response *fu
 		f.fs.handleID,
 	)
 	if err != nil {
-		return nil, err
+		return nil, rpcError(err, "InspectFile", f.File)
 	}
-	return f.newHandle(int(fileInfo.SizeBytes)), nil
+	writing := !request.Flags.IsReadOnly()
+	if writing && !f.fs.addWriteHandle(f.File.Commit, f.fs.maxWriteHandles(f.getRepoOrAliasName())) {
+		return nil, fuse.Errno(syscall.EMFILE)
+	}
+	h := f.newHandle(int(fileInfo.SizeBytes))
+	h.writing = writing
+	return h, nil
+}
+
+// stat returns the file's current size and stores it in f.size.
+func (f *file) stat() (int64, error) {
+	fileInfo, err := f.fs.apiClient.InspectFileUnsafe(
+		f.File.Commit.Repo.Name,
+		f.File.Commit.ID,
+		f.File.Path,
+		f.fs.getFromCommitID(f.getRepoOrAliasName()),
+		f.Shard,
+		f.fs.handleID,
+	)
+	if err != nil {
+		return 0, rpcError(err, "InspectFile", f.File)
+	}
+	if fileInfo == nil {
+		return 0, fuse.ENOENT
+	}
+	f.size = int64(fileInfo.SizeBytes)
+	return f.size, nil
 }
 
 func (f *file) Fsync(ctx context.Context, req *fuse.FsyncRequest) error {
 	for _, h := range f.handles {
-		if h.w != nil {
-			w := h.w
-			h.w = nil
-			if err := w.Close(); err != nil {
-				return err
-			}
+		if err := h.closeWriter(); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -297,29 +639,160 @@ func (f *file) touch() error {
 		f.fs.handleID,
 	)
 	if err != nil {
-		return err
+		return rpcError(err, "PutFile", f.File)
 	}
 	if err := w.Close(); err != nil {
-		return err
+		return rpcError(err, "PutFile", f.File)
 	}
 	return nil
 }
 
+// inode returns the inode number for file. It's a hash of the file's key,
+// rather than a counter, so that the same file has the same inode across
+// mounts, the top bit is cleared to stay out of the range the kernel reserves.
 func (f *filesystem) inode(file *pfsclient.File) uint64 {
-	f.lock.RLock()
-	inode, ok := f.inodes[key(file)]
-	f.lock.RUnlock()
-	if ok {
-		return inode
+	hash := fnv.New64a()
+	hash.Write([]byte(key(file)))
+	return hash.Sum64() &^ (1 << 63)
+}
+
+func (f *filesystem) isNegativeEntry(file *pfsclient.File) bool {
+	f.negativeEntryLock.Lock()
+	defer f.negativeEntryLock.Unlock()
+	missingAt, ok := f.negativeEntries[key(file)]
+	if !ok {
+		return false
+	}
+	if time.Since(missingAt) >= f.negativeEntryTTL {
+		delete(f.negativeEntries, key(file))
+		return false
+	}
+	f.addCacheHit()
+	return true
+}
+
+func (f *filesystem) addNegativeEntry(file *pfsclient.File) {
+	if f.negativeEntryTTL <= 0 {
+		return
+	}
+	f.negativeEntryLock.Lock()
+	defer f.negativeEntryLock.Unlock()
+	if len(f.negativeEntries) >= maxNegativeEntries {
+		// Most entries are never looked up again, so they're only
+		// removed here.
+		for fileKey, missingAt := range f.negativeEntries {
+			if time.Since(missingAt) >= f.negativeEntryTTL {
+				delete(f.negativeEntries, fileKey)
+			}
+		}
+	}
+	f.negativeEntries[key(file)] = time.Now()
+}
+
+// removeNegativeEntry is called when file is created, so Lookup finds it
+// right away.
+func (f *filesystem) removeNegativeEntry(file *pfsclient.File) {
+	f.negativeEntryLock.Lock()
+	defer f.negativeEntryLock.Unlock()
+	delete(f.negativeEntries, key(file))
+}
+
+func (f *filesystem) dirCacheTTL() time.Duration {
+	if f.DirCacheTtl == nil {
+		return defaultDirCacheTTL
+	}
+	return prototime.DurationFromProto(f.DirCacheTtl)
+}
+
+func (f *filesystem) getDirCacheEntry(dir *pfsclient.File) ([]fuse.Dirent, bool) {
+	f.dirCacheLock.Lock()
+	defer f.dirCacheLock.Unlock()
+	entry, ok := f.dirCache[key(dir)]
+	if !ok || time.Since(entry.readAt) >= f.dirCacheTTL() {
+		return nil, false
 	}
-	f.lock.Lock()
-	defer f.lock.Unlock()
-	if inode, ok := f.inodes[key(file)]; ok {
-		return inode
+	f.addCacheHit()
+	return entry.dirents, true
+}
+
+func (f *filesystem) addDirCacheEntry(dir *pfsclient.File, dirents []fuse.Dirent) {
+	ttl := f.dirCacheTTL()
+	if ttl <= 0 {
+		return
+	}
+	f.dirCacheLock.Lock()
+	defer f.dirCacheLock.Unlock()
+	if len(f.dirCache) >= maxDirCacheEntries {
+		for dirKey, entry := range f.dirCache {
+			if time.Since(entry.readAt) >= ttl {
+				delete(f.dirCache, dirKey)
+			}
+		}
+	}
+	f.dirCache[key(dir)] = dirCacheEntry{dirents, time.Now()}
+}
+
+// removeDirCacheEntry is called when a child of dir is created or removed,
+// so the next listing reads it again.
+func (f *filesystem) removeDirCacheEntry(dir *pfsclient.File) {
+	f.dirCacheLock.Lock()
+	defer f.dirCacheLock.Unlock()
+	delete(f.dirCache, key(dir))
+}
+
+// addNode is called when node, in a commit open for writing, is handed to the
+// kernel.
+func (f *filesystem) addNode(node *Node) {
+	f.nodesLock.Lock()
+	defer f.nodesLock.Unlock()
+	f.nodes[node] = true
+}
+
+func (f *filesystem) removeNode(node *Node) {
+	f.nodesLock.Lock()
+	defer f.nodesLock.Unlock()
+	delete(f.nodes, node)
+}
+
+// moveNodes is called when the file at oldFile, and everything under it, is
+// moved to newPath.
+func (f *filesystem) moveNodes(oldFile *pfsclient.File, newPath string) {
+	f.nodesLock.Lock()
+	defer f.nodesLock.Unlock()
+	for node := range f.nodes {
+		file := node.File
+		if file.Commit.Repo.Name != oldFile.Commit.Repo.Name || file.Commit.ID != oldFile.Commit.ID {
+			continue
+		}
+		if file.Path == oldFile.Path || strings.HasPrefix(file.Path, oldFile.Path+"/") {
+			node.File = client.NewFile(file.Commit.Repo.Name, file.Commit.ID, newPath+strings.TrimPrefix(file.Path, oldFile.Path))
+		}
 	}
-	newInode := uint64(len(f.inodes))
-	f.inodes[key(file)] = newInode
-	return newInode
+}
+
+func (f *filesystem) streamThreshold() int {
+	if f.StreamThreshold == 0 {
+		return defaultStreamThreshold
+	}
+	return int(f.StreamThreshold)
+}
+
+// isSmallDir returns true if dir had at most streamThreshold entries when it
+// was last listed.
+func (f *filesystem) isSmallDir(dir *pfsclient.File) bool {
+	f.dirSizesLock.Lock()
+	defer f.dirSizesLock.Unlock()
+	size, ok := f.dirSizes[key(dir)]
+	return ok && size <= f.streamThreshold()
+}
+
+func (f *filesystem) setDirSize(dir *pfsclient.File, size int) {
+	f.dirSizesLock.Lock()
+	defer f.dirSizesLock.Unlock()
+	if len(f.dirSizes) >= maxDirSizes {
+		f.dirSizes = make(map[string]int)
+	}
+	f.dirSizes[key(dir)] = size
 }
 
 func (f *file) newHandle(cursor int) *handle {
@@ -329,22 +802,78 @@ func (f *file) newHandle(cursor int) *handle {
 	}
 
 	f.handles = append(f.handles, h)
+	atomic.AddInt64(&f.fs.openHandles, 1)
 
 	return h
 }
 
+func (f *file) removeHandle(h *handle) {
+	for i, handle := range f.handles {
+		if handle == h {
+			f.handles = append(f.handles[:i], f.handles[i+1:]...)
+			return
+		}
+	}
+}
+
+// addWriteHandle counts a handle open for writing to commit and returns
+// true, unless commit already has max of them, 0 means there's no limit.
+func (f *filesystem) addWriteHandle(commit *pfsclient.Commit, max int) bool {
+	f.writeHandlesLock.Lock()
+	defer f.writeHandlesLock.Unlock()
+	k := commitKey(commit)
+	if max > 0 && f.writeHandles[k] >= max {
+		return false
+	}
+	f.writeHandles[k]++
+	return true
+}
+
+func (f *filesystem) openWriteHandleCount(commit *pfsclient.Commit) int {
+	f.writeHandlesLock.Lock()
+	defer f.writeHandlesLock.Unlock()
+	return f.writeHandles[commitKey(commit)]
+}
+
+// releaseWriteHandle returns true if the released handle was the last one
+// writing to commit.
+func (f *filesystem) releaseWriteHandle(commit *pfsclient.Commit) bool {
+	f.writeHandlesLock.Lock()
+	defer f.writeHandlesLock.Unlock()
+	k := commitKey(commit)
+	f.writeHandles[k]--
+	if f.writeHandles[k] > 0 {
+		return false
+	}
+	delete(f.writeHandles, k)
+	return true
+}
+
+// defaultWriteBufferSize is how many bytes a handle buffers when its
+// CommitMount doesn't set WriteBufferSize.
+const defaultWriteBufferSize = 64 * 1024
+
 type handle struct {
-	f      *file
-	w      io.WriteCloser
+	f *file
+	w io.WriteCloser
+	// buffer wraps w so many small writes become fewer PutFile requests.
+	buffer *bufio.Writer
+	// cursor is where the next write appends, reads are at the offset
+	// they ask for.
 	cursor int
+	// writing is set for handles opened for writing and once a handle
+	// writes, from then until it's released it's counted in
+	// filesystem.writeHandles.
+	writing bool
 }
 
 func (h *handle) Read(ctx context.Context, request *fuse.ReadRequest, response *fuse.ReadResponse) (retErr error) {
+	defer h.f.fs.observe("FileRead", time.Now(), &retErr)
 	defer func() {
-		if retErr == nil {
-			protolion.Debug(&FileRead{&h.f.Node, string(response.Data), errorToString(retErr)})
-		} else {
-			protolion.Error(&FileRead{&h.f.Node, string(response.Data), errorToString(retErr)})
+		if retErr != nil {
+			protolion.Error(&FileRead{&h.f.Node, string(response.Data), errorutil.String(retErr)})
+		} else if loglevel.DebugEnabled() {
+			protolion.Debug(&FileRead{&h.f.Node, string(response.Data), errorutil.String(retErr)})
 		}
 	}()
 	var buffer bytes.Buffer
@@ -360,31 +889,61 @@ func (h *handle) Read(ctx context.Context, request *fuse.ReadRequest, response *
 		&buffer,
 	); err != nil {
 		if grpc.Code(err) == codes.NotFound {
+			// Reading past the end of the file reads nothing.
+			if size, err := h.f.stat(); err == nil && request.Offset >= size {
+				return nil
+			}
 			// ENOENT from read(2) is weird, let's call this EINVAL
 			// instead.
 			return fuse.Errno(syscall.EINVAL)
 		}
-		return err
+		return rpcError(err, "GetFile", h.f.File)
+	}
+	data := buffer.Bytes()
+	if len(data) > request.Size {
+		data = data[:request.Size]
 	}
-	response.Data = buffer.Bytes()
+	if len(data) < request.Size {
+		// A short read reached the end of the file, the size is read again
+		// so data past the end the kernel now sees can't be returned.
+		if size, err := h.f.stat(); err == nil {
+			switch {
+			case request.Offset >= size:
+				data = nil
+			case request.Offset+int64(len(data)) > size:
+				data = data[:size-request.Offset]
+			}
+		}
+	}
+	response.Data = data
+	h.f.fs.addRead(len(response.Data))
 	return nil
 }
 
 func (h *handle) Write(ctx context.Context, request *fuse.WriteRequest, response *fuse.WriteResponse) (retErr error) {
+	defer h.f.fs.observe("FileWrite", time.Now(), &retErr)
 	defer func() {
-		if retErr == nil {
-			protolion.Debug(&FileWrite{&h.f.Node, string(request.Data), request.Offset, errorToString(retErr)})
-		} else {
-			protolion.Error(&FileWrite{&h.f.Node, string(request.Data), request.Offset, errorToString(retErr)})
+		if retErr != nil {
+			protolion.Error(&FileWrite{&h.f.Node, string(request.Data), request.Offset, errorutil.String(retErr)})
+		} else if loglevel.DebugEnabled() {
+			protolion.Debug(&FileWrite{&h.f.Node, string(request.Data), request.Offset, errorutil.String(retErr)})
 		}
 	}()
 	if h.w == nil {
+		h.f.fs.removeNegativeEntry(h.f.File)
+		if !h.writing {
+			if !h.f.fs.addWriteHandle(h.f.File.Commit, h.f.fs.maxWriteHandles(h.f.getRepoOrAliasName())) {
+				return fuse.Errno(syscall.EMFILE)
+			}
+			h.writing = true
+		}
 		w, err := h.f.fs.apiClient.PutFileWriter(
 			h.f.File.Commit.Repo.Name, h.f.File.Commit.ID, h.f.File.Path, pfsclient.Delimiter_LINE, h.f.fs.handleID)
 		if err != nil {
-			return err
+			return rpcError(err, "PutFile", h.f.File)
 		}
 		h.w = w
+		h.buffer = bufio.NewWriterSize(w, h.f.fs.writeBufferSize(h.f.getRepoOrAliasName()))
 	}
 	// repeated is how many bytes in this write have already been sent in
 	// previous call to Write. Why does the OS send us the same data twice in
@@ -392,13 +951,17 @@ func (h *handle) Write(ctx context.Context, request *fuse.WriteRequest, response
 	// observed on osx, not on linux.
 	repeated := h.cursor - int(request.Offset)
 	if repeated < 0 {
-		return fmt.Errorf("gap in bytes written, (OpenNonSeekable should make this impossible)")
+		return fmt.Errorf("gap in bytes written, writes must append to the file")
 	}
-	written, err := h.w.Write(request.Data[repeated:])
+	if repeated > len(request.Data) {
+		return fmt.Errorf("write at offset %d before the end of the file, writes must append to the file", request.Offset)
+	}
+	written, err := h.buffer.Write(request.Data[repeated:])
 	if err != nil {
-		return err
+		return rpcError(err, "PutFile", h.f.File)
 	}
 	response.Size = written + repeated
+	h.f.fs.addWrite(written)
 	h.cursor += written
 	if h.f.size < request.Offset+int64(written) {
 		h.f.size = request.Offset + int64(written)
@@ -407,17 +970,44 @@ func (h *handle) Write(ctx context.Context, request *fuse.WriteRequest, response
 }
 
 func (h *handle) Flush(ctx context.Context, req *fuse.FlushRequest) error {
-	if h.w != nil {
-		w := h.w
-		h.w = nil
-		if err := w.Close(); err != nil {
-			return err
-		}
+	return h.closeWriter()
+}
+
+// Release finishes the handle's commit if its CommitMount sets AutoFinish and
+// this was the last handle writing to it. The commit isn't finished if
+// sending this handle's writes failed.
+func (h *handle) Release(ctx context.Context, req *fuse.ReleaseRequest) error {
+	atomic.AddInt64(&h.f.fs.openHandles, -1)
+	h.f.removeHandle(h)
+	err := h.closeWriter()
+	if !h.writing || !h.f.fs.releaseWriteHandle(h.f.File.Commit) || err != nil {
+		return err
+	}
+	commitMount := h.f.fs.getCommitMount(h.f.getRepoOrAliasName())
+	if commitMount == nil || !commitMount.AutoFinish {
+		return nil
+	}
+	if err := h.f.fs.apiClient.FinishCommit(h.f.File.Commit.Repo.Name, h.f.File.Commit.ID); err != nil {
+		return rpcError(err, "FinishCommit", h.f.File)
 	}
 	return nil
 }
 
-func (h *handle) Release(ctx context.Context, req *fuse.ReleaseRequest) error {
+// closeWriter sends what's buffered and finishes the PutFile, the next write
+// starts a new one.
+func (h *handle) closeWriter() error {
+	if h.w == nil {
+		return nil
+	}
+	w, buffer := h.w, h.buffer
+	h.w, h.buffer = nil, nil
+	if err := buffer.Flush(); err != nil {
+		w.Close()
+		return rpcError(err, "PutFile", h.f.File)
+	}
+	if err := w.Close(); err != nil {
+		return rpcError(err, "PutFile", h.f.File)
+	}
 	return nil
 }
 
@@ -473,6 +1063,22 @@ func (f *filesystem) getCommitMount(nameOrAlias string) *CommitMount {
 	return nil
 }
 
+func (f *filesystem) writeBufferSize(nameOrAlias string) int {
+	commitMount := f.getCommitMount(nameOrAlias)
+	if commitMount == nil || commitMount.WriteBufferSize == 0 {
+		return defaultWriteBufferSize
+	}
+	return int(commitMount.WriteBufferSize)
+}
+
+func (f *filesystem) maxWriteHandles(nameOrAlias string) int {
+	commitMount := f.getCommitMount(nameOrAlias)
+	if commitMount == nil {
+		return 0
+	}
+	return int(commitMount.MaxWriteHandles)
+}
+
 func (f *filesystem) getFromCommitID(nameOrAlias string) string {
 	commitMount := f.getCommitMount(nameOrAlias)
 	if commitMount == nil || commitMount.FromCommit == nil {
@@ -482,13 +1088,16 @@ func (f *filesystem) getFromCommitID(nameOrAlias string) string {
 }
 
 func (d *directory) lookUpRepo(ctx context.Context, name string) (fs.Node, error) {
+	if name == statsFile {
+		return &statsNode{d.fs}, nil
+	}
 	commitMount := d.fs.getCommitMount(name)
 	if commitMount == nil {
 		return nil, fuse.EPERM
 	}
 	repoInfo, err := d.fs.apiClient.InspectRepo(commitMount.Commit.Repo.Name)
 	if err != nil {
-		return nil, err
+		return nil, rpcError(err, "InspectRepo", client.NewFile(commitMount.Commit.Repo.Name, "", ""))
 	}
 	if repoInfo == nil {
 		return nil, fuse.ENOENT
@@ -504,7 +1113,7 @@ func (d *directory) lookUpRepo(ctx context.Context, name string) (fs.Node, error
 		commitMount.Commit.ID,
 	)
 	if err != nil {
-		return nil, err
+		return nil, rpcError(err, "InspectCommit", client.NewFile(commitMount.Commit.Repo.Name, commitMount.Commit.ID, ""))
 	}
 	if commitInfo.CommitType == pfsclient.CommitType_COMMIT_TYPE_READ {
 		result.Write = false
@@ -522,7 +1131,7 @@ func (d *directory) lookUpCommit(ctx context.Context, name string) (fs.Node, err
 		name,
 	)
 	if err != nil {
-		return nil, err
+		return nil, rpcError(err, "InspectCommit", client.NewFile(d.File.Commit.Repo.Name, name, ""))
 	}
 	if commitInfo == nil {
 		return nil, fuse.ENOENT
@@ -539,21 +1148,39 @@ func (d *directory) lookUpCommit(ctx context.Context, name string) (fs.Node, err
 }
 
 func (d *directory) lookUpFile(ctx context.Context, name string) (fs.Node, error) {
-	var fileInfo *pfsclient.FileInfo
-	var err error
-
-	fileInfo, err = d.fs.apiClient.InspectFileUnsafe(
-		d.File.Commit.Repo.Name,
-		d.File.Commit.ID,
-		path.Join(d.File.Path, name),
-		d.fs.getFromCommitID(d.getRepoOrAliasName()),
-		d.Shard,
-		d.fs.handleID,
-	)
-	if err != nil {
+	lookedUp := client.NewFile(d.File.Commit.Repo.Name, d.File.Commit.ID, path.Join(d.File.Path, name))
+	if isSymlinkPath(name) || d.fs.isNegativeEntry(lookedUp) {
 		return nil, fuse.ENOENT
 	}
-	if d.Node.Write {
+	fileInfo, err := d.inspectFile(lookedUp.Path)
+	symlink := false
+	if err != nil {
+		if grpc.Code(err) != codes.NotFound {
+			return nil, rpcError(err, "InspectFile", lookedUp)
+		}
+		// Symlinks are only looked for once there's no regular file, so they
+		// cost an extra call on misses, which the negative entry then caches.
+		if fileInfo, err = d.inspectFile(lookedUp.Path + symlinkSuffix); err != nil {
+			if grpc.Code(err) != codes.NotFound {
+				return nil, rpcError(err, "InspectFile", lookedUp)
+			}
+			d.fs.addNegativeEntry(lookedUp)
+			return nil, fuse.ENOENT
+		}
+		symlink = true
+	} else if fileInfo.FileType == pfsclient.FileType_FILE_TYPE_REGULAR && isLegacySymlinkSize(fileInfo.SizeBytes) {
+		legacy := d.copy()
+		legacy.File.Path = fileInfo.File.Path
+		target, ok, err := legacy.readLegacySymlink()
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			symlink = true
+			fileInfo.SizeBytes = uint64(len(target))
+		}
+	}
+	if d.Node.Write && !symlink {
 		fileInfo.SizeBytes = 0
 	}
 
@@ -563,23 +1190,53 @@ func (d *directory) lookUpFile(ctx context.Context, name string) (fs.Node, error
 	directory.File.Path = fileInfo.File.Path
 	switch fileInfo.FileType {
 	case pfsclient.FileType_FILE_TYPE_REGULAR:
-		return &file{
+		result := &file{
 			directory: *directory,
 			size:      int64(fileInfo.SizeBytes),
-		}, nil
+			symlink:   symlink,
+		}
+		if d.Write {
+			d.fs.addNode(&result.Node)
+		}
+		return result, nil
 	case pfsclient.FileType_FILE_TYPE_DIR:
+		if symlink {
+			return nil, fuse.ENOENT
+		}
+		if d.Write {
+			d.fs.addNode(&directory.Node)
+		}
 		return directory, nil
 	default:
 		return nil, fmt.Errorf("Unrecognized FileType.")
 	}
 }
 
+// inspectFile inspects the file at filePath in d's commit. Its errors keep
+// their gRPC code, so a missing file can be told from a failed call.
+func (d *directory) inspectFile(filePath string) (*pfsclient.FileInfo, error) {
+	var fromCommit *pfsclient.Commit
+	if fromCommitID := d.fs.getFromCommitID(d.getRepoOrAliasName()); fromCommitID != "" {
+		fromCommit = client.NewCommit(d.File.Commit.Repo.Name, fromCommitID)
+	}
+	return d.fs.apiClient.PfsAPIClient.InspectFile(
+		context.Background(),
+		&pfsclient.InspectFileRequest{
+			File:       client.NewFile(d.File.Commit.Repo.Name, d.File.Commit.ID, filePath),
+			Shard:      d.Shard,
+			FromCommit: fromCommit,
+			Unsafe:     true,
+			Handle:     d.fs.handleID,
+		},
+	)
+}
+
 func (d *directory) readRepos(ctx context.Context) ([]fuse.Dirent, error) {
 	var result []fuse.Dirent
 	if len(d.fs.CommitMounts) == 0 {
 		repoInfos, err := d.fs.apiClient.ListRepo(nil)
 		if err != nil {
-			return nil, err
+			return nil, rpcError(err, "ListRepo", d.File)
 		}
 		for _, repoInfo := range repoInfos {
 			result = append(result, fuse.Dirent{Name: repoInfo.Repo.Name, Type: fuse.DT_Dir})
@@ -593,6 +1250,7 @@ func (d *directory) readRepos(ctx context.Context) ([]fuse.Dirent, error) {
 			result = append(result, fuse.Dirent{Name: name, Type: fuse.DT_Dir})
 		}
 	}
+	result = append(result, fuse.Dirent{Name: statsFile, Type: fuse.DT_File})
 	return result, nil
 }
 
@@ -600,7 +1258,7 @@ func (d *directory) readCommits(ctx context.Context) ([]fuse.Dirent, error) {
 	commitInfos, err := d.fs.apiClient.ListCommit([]string{d.File.Commit.Repo.Name},
 		nil, client.CommitTypeNone, false, false, nil)
 	if err != nil {
-		return nil, err
+		return nil, rpcError(err, "ListCommit", d.File)
 	}
 	var result []fuse.Dirent
 	for _, commitInfo := range commitInfos {
@@ -610,44 +1268,104 @@ func (d *directory) readCommits(ctx context.Context) ([]fuse.Dirent, error) {
 }
 
 func (d *directory) readFiles(ctx context.Context) ([]fuse.Dirent, error) {
-	fileInfos, err := d.fs.apiClient.ListFileUnsafe(
-		d.File.Commit.Repo.Name,
-		d.File.Commit.ID,
-		d.File.Path,
-		d.fs.getFromCommitID(d.getRepoOrAliasName()),
-		d.Shard,
-		// setting recurse to false for performance reasons
-		// it does however means that we won't know the correct sizes of directories
-		false,
-		d.fs.handleID,
-	)
-	if err != nil {
-		return nil, err
+	if dirents, ok := d.fs.getDirCacheEntry(d.File); ok {
+		return dirents, nil
 	}
 	var result []fuse.Dirent
-	for _, fileInfo := range fileInfos {
-		shortPath := strings.TrimPrefix(fileInfo.File.Path, d.File.Path)
-		if shortPath[0] == '/' {
-			shortPath = shortPath[1:]
-		}
-		switch fileInfo.FileType {
-		case pfsclient.FileType_FILE_TYPE_REGULAR:
-			result = append(result, fuse.Dirent{Name: shortPath, Type: fuse.DT_File})
-		case pfsclient.FileType_FILE_TYPE_DIR:
-			result = append(result, fuse.Dirent{Name: shortPath, Type: fuse.DT_Dir})
-		default:
-			continue
+	if d.fs.isSmallDir(d.File) {
+		fileInfos, err := d.fs.apiClient.ListFileUnsafe(
+			d.File.Commit.Repo.Name,
+			d.File.Commit.ID,
+			d.File.Path,
+			d.fs.getFromCommitID(d.getRepoOrAliasName()),
+			d.Shard,
+			// setting recurse to false for performance reasons
+			// it does however means that we won't know the correct sizes of directories
+			false,
+			d.fs.handleID,
+		)
+		if err != nil {
+			return nil, rpcError(err, "ListFile", d.File)
+		}
+		for _, fileInfo := range fileInfos {
+			if dirent, ok := fileInfoDirent(d.File.Path, fileInfo); ok {
+				result = append(result, dirent)
+			}
+		}
+	} else {
+		// The infos are streamed so only the dirents, which are much smaller,
+		// are held for large directories.
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		fileInfos, errCh := d.fs.apiClient.ListFileStreamUnsafe(
+			ctx,
+			d.File.Commit.Repo.Name,
+			d.File.Commit.ID,
+			d.File.Path,
+			d.fs.getFromCommitID(d.getRepoOrAliasName()),
+			d.Shard,
+			false,
+			d.fs.handleID,
+		)
+		result = readDirents(d.File.Path, fileInfos)
+		if err := <-errCh; err != nil {
+			return nil, rpcError(err, "ListFile", d.File)
 		}
 	}
+	d.fs.setDirSize(d.File, len(result))
+	d.fs.addDirCacheEntry(d.File, result)
 	return result, nil
 }
 
-// TODO this code is duplicate elsewhere, we should put it somehwere.
-func errorToString(err error) string {
-	if err == nil {
-		return ""
+// readDirents returns the dirents for the fileInfos of the children of the
+// directory at dirPath, as they're received.
+func readDirents(dirPath string, fileInfos <-chan *pfsclient.FileInfo) []fuse.Dirent {
+	var result []fuse.Dirent
+	for fileInfo := range fileInfos {
+		if dirent, ok := fileInfoDirent(dirPath, fileInfo); ok {
+			result = append(result, dirent)
+		}
+	}
+	return result
+}
+
+// fileInfoDirent returns the dirent for fileInfo, a child of the directory at
+// dirPath, ok is false if it isn't a regular file or directory.
+func fileInfoDirent(dirPath string, fileInfo *pfsclient.FileInfo) (dirent fuse.Dirent, ok bool) {
+	shortPath := strings.TrimPrefix(fileInfo.File.Path, dirPath)
+	if shortPath[0] == '/' {
+		shortPath = shortPath[1:]
 	}
-	return err.Error()
+	switch fileInfo.FileType {
+	case pfsclient.FileType_FILE_TYPE_REGULAR:
+		if isSymlinkPath(shortPath) {
+			return fuse.Dirent{Name: strings.TrimSuffix(shortPath, symlinkSuffix), Type: fuse.DT_Link}, true
+		}
+		return fuse.Dirent{Name: shortPath, Type: fuse.DT_File}, true
+	case pfsclient.FileType_FILE_TYPE_DIR:
+		return fuse.Dirent{Name: shortPath, Type: fuse.DT_Dir}, true
+	default:
+		return fuse.Dirent{}, false
+	}
+}
+
+// fuseError is an errorutil.Error which also tells fuse which errno to send
+// to the kernel.
+type fuseError struct {
+	err *errorutil.Error
+}
+
+func (e *fuseError) Error() string {
+	return e.err.Error()
+}
+
+func (e *fuseError) Errno() fuse.Errno {
+	return fuse.Errno(errorutil.Errno(e.err.Code))
+}
+
+// rpcError wraps err, returned by a call to rpc about file.
+func rpcError(err error, rpc string, file *pfsclient.File) error {
+	return &fuseError{errorutil.Wrap(err, "%s failed", rpc).WithFile(file.Commit.Repo.Name, file.Commit.ID, file.Path)}
 }
 
 func getNode(node fs.Node) *Node {
@@ -664,3 +1382,7 @@ func getNode(node fs.Node) *Node {
 func key(file *pfsclient.File) string {
 	return fmt.Sprintf("%s/%s/%s", file.Commit.Repo.Name, file.Commit.ID, file.Path)
 }
+
+func commitKey(commit *pfsclient.Commit) string {
+	return fmt.Sprintf("%s/%s", commit.Repo.Name, commit.ID)
+}
@@ -4,6 +4,7 @@ package fuse_test
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
@@ -47,24 +48,43 @@ func TestSeekRead(t *testing.T) {
 		fmt.Printf("==== %v - Read word len %v : %v\n", time.Now(), n1, string(word1))
 
 		offset, err := file.Seek(6, 0)
-		fmt.Printf("==== %v - err (%v)\n", time.Now(), err)
-
-		fmt.Printf("==== %v - offset (%v)\n", time.Now(), offset)
-		require.YesError(t, err)
-		require.Equal(t, int64(0), offset)
-
-		fmt.Printf("==== Seeked to %v\n", offset)
-
-		/* Leaving in place so the test's intention is clear / for repro'ing manually for mac
+		require.NoError(t, err)
+		require.Equal(t, int64(6), offset)
 
 		word2 := make([]byte, 3)
 		n2, err := file.Read(word2)
 		require.NoError(t, err)
 		require.Equal(t, 3, n2)
 		require.Equal(t, "baz", string(word2))
+	})
+}
 
-		fmt.Printf("==== Read word len %v : %v\n", n2, string(word2)) */
+func TestSeekReadLargeFile(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	testFuse(t, func(c client.APIClient, mountpoint string) {
+		repo := "test"
+		require.NoError(t, c.CreateRepo(repo))
+		commit, err := c.StartCommit(repo, "", "")
+		require.NoError(t, err)
+		path := filepath.Join(mountpoint, repo, commit.ID, "file")
+		data := make([]byte, 1024*1024)
+		for i := range data {
+			data[i] = byte('a' + i%26)
+		}
+		require.NoError(t, ioutil.WriteFile(path, data, 0666))
+		require.NoError(t, c.FinishCommit(repo, commit.ID))
 
+		file, err := os.Open(path)
+		require.NoError(t, err)
+		defer file.Close()
+		buffer := make([]byte, 512)
+		n, err := file.ReadAt(buffer, 512000)
+		require.NoError(t, err)
+		require.Equal(t, 512, n)
+		require.Equal(t, string(data[512000:512512]), string(buffer))
 	})
 }
 
@@ -155,3 +175,41 @@ func TestSeekWriteBackwards(t *testing.T) {
 		require.NoError(t, c.FinishCommit(repo, commit.ID)) */
 	})
 }
+
+func TestReadUnalignedOffsets(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	testFuse(t, func(c client.APIClient, mountpoint string) {
+		repo := "test"
+		require.NoError(t, c.CreateRepo(repo))
+		commit, err := c.StartCommit(repo, "", "")
+		require.NoError(t, err)
+		path := filepath.Join(mountpoint, repo, commit.ID, "file")
+		data := make([]byte, 10000)
+		for i := range data {
+			data[i] = byte('a' + i%26)
+		}
+		require.NoError(t, ioutil.WriteFile(path, data, 0666))
+		require.NoError(t, c.FinishCommit(repo, commit.ID))
+
+		file, err := os.Open(path)
+		require.NoError(t, err)
+		defer file.Close()
+		for _, offset := range []int{0, 1, 7, 4095, 4097, 9999, 10000, 12345} {
+			for _, size := range []int{1, 3, 100, 4097, 20000} {
+				buffer := make([]byte, size)
+				n, _ := file.ReadAt(buffer, int64(offset))
+				start, end := offset, offset+size
+				if start > len(data) {
+					start = len(data)
+				}
+				if end > len(data) {
+					end = len(data)
+				}
+				require.Equal(t, string(data[start:end]), string(buffer[:n]))
+			}
+		}
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"syscall"
 	"testing"
 
 	"bazil.org/fuse/fs/fstestutil"
@@ -65,6 +66,12 @@ func TestRootReadDir(t *testing.T) {
 				// }
 				return nil
 			},
+			".pfs_stats": func(fi os.FileInfo) error {
+				if g, e := fi.Mode(), os.FileMode(0444); g != e {
+					return fmt.Errorf("wrong mode: %v != %v", g, e)
+				}
+				return nil
+			},
 		}))
 	})
 }
@@ -455,6 +462,129 @@ func TestCreateFileInDir(t *testing.T) {
 	})
 }
 
+func TestRenameFile(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+	testFuse(t, func(c client.APIClient, mountpoint string) {
+		require.NoError(t, c.CreateRepo("repo"))
+		commit, err := c.StartCommit("repo", "", "")
+		require.NoError(t, err)
+		dir := filepath.Join(mountpoint, "repo", commit.ID)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "old"), []byte("foo\n"), 0644))
+		require.NoError(t, os.Rename(filepath.Join(dir, "old"), filepath.Join(dir, "new")))
+		_, err = os.Stat(filepath.Join(dir, "old"))
+		require.True(t, os.IsNotExist(err))
+		require.NoError(t, c.FinishCommit("repo", commit.ID))
+		var buffer bytes.Buffer
+		require.NoError(t, c.GetFile("repo", commit.ID, "new", 0, 0, "", nil, &buffer))
+		require.Equal(t, "foo\n", buffer.String())
+		_, err = os.Stat(filepath.Join(dir, "old"))
+		require.True(t, os.IsNotExist(err))
+		require.NoError(t, fstestutil.CheckDir(dir, map[string]fstestutil.FileInfoCheck{"new": nil}))
+
+		// Finished commits can't be changed
+		require.YesError(t, os.Rename(filepath.Join(dir, "new"), filepath.Join(dir, "newer")))
+	})
+}
+
+func TestRenameDirectory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+	testFuse(t, func(c client.APIClient, mountpoint string) {
+		require.NoError(t, c.CreateRepo("repo"))
+		commit, err := c.StartCommit("repo", "", "")
+		require.NoError(t, err)
+		dir := filepath.Join(mountpoint, "repo", commit.ID)
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "old", "sub"), 0700))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "old", "a"), []byte("a\n"), 0644))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "old", "sub", "b"), []byte("b\n"), 0644))
+		require.NoError(t, os.Rename(filepath.Join(dir, "old"), filepath.Join(dir, "new")))
+		require.NoError(t, c.FinishCommit("repo", commit.ID))
+		for path, content := range map[string]string{"new/a": "a\n", "new/sub/b": "b\n"} {
+			var buffer bytes.Buffer
+			require.NoError(t, c.GetFile("repo", commit.ID, path, 0, 0, "", nil, &buffer))
+			require.Equal(t, content, buffer.String())
+		}
+		for _, path := range []string{"old", "old/a", "old/sub/b"} {
+			_, err := os.Stat(filepath.Join(dir, path))
+			require.True(t, os.IsNotExist(err))
+		}
+		require.NoError(t, fstestutil.CheckDir(dir, map[string]fstestutil.FileInfoCheck{"new": nil}))
+	})
+}
+
+func TestRenameAcrossDirectories(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+	testFuse(t, func(c client.APIClient, mountpoint string) {
+		require.NoError(t, c.CreateRepo("repo"))
+		commit, err := c.StartCommit("repo", "", "")
+		require.NoError(t, err)
+		dir := filepath.Join(mountpoint, "repo", commit.ID)
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "left"), 0700))
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "right"), 0700))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "left", "file"), []byte("foo\n"), 0644))
+		require.NoError(t, os.Rename(filepath.Join(dir, "left", "file"), filepath.Join(dir, "right", "file")))
+		require.NoError(t, c.FinishCommit("repo", commit.ID))
+		var buffer bytes.Buffer
+		require.NoError(t, c.GetFile("repo", commit.ID, "right/file", 0, 0, "", nil, &buffer))
+		require.Equal(t, "foo\n", buffer.String())
+		_, err = os.Stat(filepath.Join(dir, "left", "file"))
+		require.True(t, os.IsNotExist(err))
+		require.NoError(t, fstestutil.CheckDir(filepath.Join(dir, "left"), nil))
+	})
+}
+
+func TestSymlink(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+	testFuse(t, func(c client.APIClient, mountpoint string) {
+		require.NoError(t, c.CreateRepo("repo"))
+		commit, err := c.StartCommit("repo", "", "")
+		require.NoError(t, err)
+		dir := filepath.Join(mountpoint, "repo", commit.ID)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "file"), []byte("foo\n"), 0644))
+		require.NoError(t, os.Symlink("file", filepath.Join(dir, "link")))
+		require.NoError(t, c.FinishCommit("repo", commit.ID))
+
+		target, err := os.Readlink(filepath.Join(dir, "link"))
+		require.NoError(t, err)
+		require.Equal(t, "file", target)
+		fileInfo, err := os.Lstat(filepath.Join(dir, "link"))
+		require.NoError(t, err)
+		require.True(t, fileInfo.Mode()&os.ModeSymlink != 0)
+		data, err := ioutil.ReadFile(filepath.Join(dir, "link"))
+		require.NoError(t, err)
+		require.Equal(t, "foo\n", string(data))
+
+		// Regular files aren't links
+		_, err = os.Readlink(filepath.Join(dir, "file"))
+		require.YesError(t, err)
+	})
+}
+
+func TestStatfs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+	testFuse(t, func(c client.APIClient, mountpoint string) {
+		require.NoError(t, c.CreateRepo("repo"))
+		commit, err := c.StartCommit("repo", "", "")
+		require.NoError(t, err)
+		path := filepath.Join(mountpoint, "repo", commit.ID, "file")
+		require.NoError(t, ioutil.WriteFile(path, bytes.Repeat([]byte("a"), 1024), 0644))
+		require.NoError(t, c.FinishCommit("repo", commit.ID))
+		var stat syscall.Statfs_t
+		require.NoError(t, syscall.Statfs(mountpoint, &stat))
+		require.True(t, stat.Blocks > 0)
+		require.True(t, stat.Bfree > 0)
+	})
+}
+
 func TestOverwriteFile(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipped because of short mode")
@@ -492,12 +622,13 @@ func testFuse(
 		_ = os.RemoveAll(tmp)
 	}()
 
-	// closed on successful termination
+	// closed on successful termination, before the listener is closed so
+	// that Serve's error isn't reported
 	quit := make(chan struct{})
-	defer close(quit)
 	listener, err := net.Listen("tcp", "localhost:0")
 	require.NoError(t, err)
 	defer func() {
+		close(quit)
 		_ = listener.Close()
 	}()
 
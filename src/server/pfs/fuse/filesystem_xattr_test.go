@@ -0,0 +1,50 @@
+// +build linux
+
+package fuse_test
+
+import (
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func getxattr(t *testing.T, path string, name string) string {
+	value := make([]byte, 1024)
+	n, err := syscall.Getxattr(path, name, value)
+	require.NoError(t, err)
+	return string(value[:n])
+}
+
+func TestXattr(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+	testFuse(t, func(c client.APIClient, mountpoint string) {
+		require.NoError(t, c.CreateRepo("repo"))
+		commit, err := c.StartCommit("repo", "", "")
+		require.NoError(t, err)
+		_, err = c.PutFile("repo", commit.ID, "file", strings.NewReader("foo\n"))
+		require.NoError(t, err)
+		require.NoError(t, c.FinishCommit("repo", commit.ID))
+
+		path := filepath.Join(mountpoint, "repo", commit.ID, "file")
+		require.Equal(t, commit.ID, getxattr(t, path, "pfs.commit.id"))
+		require.Equal(t, "repo", getxattr(t, path, "pfs.repo.name"))
+		require.Equal(t, "4", getxattr(t, path, "pfs.file.size_bytes"))
+		require.NotEqual(t, "", getxattr(t, path, "pfs.commit.finished"))
+
+		names := make([]byte, 1024)
+		n, err := syscall.Listxattr(path, names)
+		require.NoError(t, err)
+		require.Equal(t, "pfs.commit.id\x00pfs.repo.name\x00pfs.file.size_bytes\x00pfs.commit.finished\x00", string(names[:n]))
+
+		// The attributes are read only
+		require.YesError(t, syscall.Setxattr(path, "pfs.commit.id", []byte("foo"), 0))
+		_, err = syscall.Getxattr(path, "pfs.unknown", make([]byte, 1024))
+		require.YesError(t, err)
+	})
+}
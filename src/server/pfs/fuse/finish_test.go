@@ -0,0 +1,78 @@
+package fuse
+
+import (
+	"testing"
+
+	"bazil.org/fuse"
+	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"go.pedge.io/pb/go/google/protobuf"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// finishCommitAPIClient records the commits it's asked to finish, and drops
+// what's written to it like putFileAPIClient.
+type finishCommitAPIClient struct {
+	putFileAPIClient
+	finished []*pfsclient.Commit
+}
+
+func (c *finishCommitAPIClient) FinishCommit(ctx context.Context, request *pfsclient.FinishCommitRequest, opts ...grpc.CallOption) (*google_protobuf.Empty, error) {
+	c.finished = append(c.finished, request.Commit)
+	return google_protobuf.EmptyInstance, nil
+}
+
+func newFinishTestFile(filesystem *filesystem, path string) *file {
+	return &file{
+		directory: directory{
+			fs: filesystem,
+			Node: Node{
+				File:  &pfsclient.File{Commit: &pfsclient.Commit{Repo: &pfsclient.Repo{Name: "repo"}, ID: "commit"}, Path: path},
+				Write: true,
+			},
+		},
+	}
+}
+
+func writeLine(t *testing.T, h *handle) {
+	require.NoError(t, h.Write(context.Background(), &fuse.WriteRequest{Data: []byte("line\n"), Offset: int64(h.cursor)}, &fuse.WriteResponse{}))
+}
+
+func TestAutoFinish(t *testing.T) {
+	apiClient := &finishCommitAPIClient{}
+	commitMounts := []*CommitMount{{Commit: &pfsclient.Commit{Repo: &pfsclient.Repo{Name: "repo"}, ID: "commit"}, AutoFinish: true}}
+	filesystem := newFilesystem(apiClient, nil, commitMounts)
+	ctx := context.Background()
+
+	h1 := newFinishTestFile(filesystem, "file1").newHandle(0)
+	h2 := newFinishTestFile(filesystem, "file2").newHandle(0)
+	reader := newFinishTestFile(filesystem, "file3").newHandle(0)
+	writeLine(t, h1)
+	writeLine(t, h2)
+	// Flushing doesn't release the handle, it can still write.
+	require.NoError(t, h1.Flush(ctx, &fuse.FlushRequest{}))
+	writeLine(t, h1)
+
+	require.NoError(t, h1.Release(ctx, &fuse.ReleaseRequest{}))
+	require.Equal(t, 0, len(apiClient.finished))
+	require.NoError(t, h2.Release(ctx, &fuse.ReleaseRequest{}))
+	require.Equal(t, 1, len(apiClient.finished))
+	require.Equal(t, "commit", apiClient.finished[0].ID)
+	// Handles that never wrote don't finish the commit.
+	require.NoError(t, reader.Release(ctx, &fuse.ReleaseRequest{}))
+	require.Equal(t, 1, len(apiClient.finished))
+	require.Equal(t, 0, len(filesystem.writeHandles))
+}
+
+func TestNoAutoFinish(t *testing.T) {
+	apiClient := &finishCommitAPIClient{}
+	commitMounts := []*CommitMount{{Commit: &pfsclient.Commit{Repo: &pfsclient.Repo{Name: "repo"}, ID: "commit"}}}
+	filesystem := newFilesystem(apiClient, nil, commitMounts)
+	f := newFinishTestFile(filesystem, "file")
+	h := f.newHandle(0)
+	writeLine(t, h)
+	require.NoError(t, h.Release(context.Background(), &fuse.ReleaseRequest{}))
+	require.Equal(t, 0, len(apiClient.finished))
+	require.Equal(t, 0, len(f.handles))
+}
@@ -26,6 +26,14 @@ It has these top-level messages:
 	FileOpen
 	FileWrite
 	FileRemove
+	DirectoryRename
+	Statfs
+	DirectoryGetxattr
+	DirectoryListxattr
+	DirectorySetxattr
+	DirectorySymlink
+	FileReadlink
+	DirectoryFsync
 */
 package fuse
 
@@ -34,6 +42,7 @@ import fmt "fmt"
 import math "math"
 import pfs "github.com/pachyderm/pachyderm/src/client/pfs"
 import google_protobuf2 "go.pedge.io/pb/go/google/protobuf"
+import google_protobuf3 "go.pedge.io/pb/go/google/protobuf"
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
@@ -49,6 +58,15 @@ type CommitMount struct {
 	FromCommit *pfs.Commit `protobuf:"bytes,2,opt,name=from_commit,json=fromCommit" json:"from_commit,omitempty"`
 	Alias      string      `protobuf:"bytes,3,opt,name=alias" json:"alias,omitempty"`
 	Shard      *pfs.Shard  `protobuf:"bytes,4,opt,name=shard" json:"shard,omitempty"`
+	// write_buffer_size is how many bytes a handle buffers before sending
+	// them to PFS, 0 means 64KiB.
+	WriteBufferSize uint64 `protobuf:"varint,5,opt,name=write_buffer_size,json=writeBufferSize" json:"write_buffer_size,omitempty"`
+	// auto_finish finishes the commit once the last handle that wrote to
+	// it is released.
+	AutoFinish bool `protobuf:"varint,6,opt,name=auto_finish,json=autoFinish" json:"auto_finish,omitempty"`
+	// max_write_handles is how many handles can be open for writing to
+	// the commit at once, 0 means there's no limit.
+	MaxWriteHandles uint64 `protobuf:"varint,7,opt,name=max_write_handles,json=maxWriteHandles" json:"max_write_handles,omitempty"`
 }
 
 func (m *CommitMount) Reset()                    { *m = CommitMount{} }
@@ -80,6 +98,12 @@ func (m *CommitMount) GetShard() *pfs.Shard {
 type Filesystem struct {
 	Shard        *pfs.Shard     `protobuf:"bytes,1,opt,name=shard" json:"shard,omitempty"`
 	CommitMounts []*CommitMount `protobuf:"bytes,2,rep,name=commit_mounts,json=commitMounts" json:"commit_mounts,omitempty"`
+	// dir_cache_ttl is how long a directory's listing is reused for, unset
+	// means 100ms and 0 turns the cache off.
+	DirCacheTtl *google_protobuf3.Duration `protobuf:"bytes,3,opt,name=dir_cache_ttl,json=dirCacheTtl" json:"dir_cache_ttl,omitempty"`
+	// stream_threshold is how many entries a directory can have before its
+	// listing is streamed, unset means 10000.
+	StreamThreshold uint64 `protobuf:"varint,4,opt,name=stream_threshold,json=streamThreshold" json:"stream_threshold,omitempty"`
 }
 
 func (m *Filesystem) Reset()                    { *m = Filesystem{} }
@@ -101,6 +125,13 @@ func (m *Filesystem) GetCommitMounts() []*CommitMount {
 	return nil
 }
 
+func (m *Filesystem) GetDirCacheTtl() *google_protobuf3.Duration {
+	if m != nil {
+		return m.DirCacheTtl
+	}
+	return nil
+}
+
 type Node struct {
 	File      *pfs.File                   `protobuf:"bytes,1,opt,name=file" json:"file,omitempty"`
 	RepoAlias string                      `protobuf:"bytes,2,opt,name=repo_alias,json=repoAlias" json:"repo_alias,omitempty"`
@@ -420,6 +451,168 @@ func (m *FileRemove) GetFile() *Node {
 	return nil
 }
 
+type DirectoryRename struct {
+	Directory    *Node  `protobuf:"bytes,1,opt,name=directory" json:"directory,omitempty"`
+	OldName      string `protobuf:"bytes,2,opt,name=old_name,json=oldName" json:"old_name,omitempty"`
+	NewDirectory *Node  `protobuf:"bytes,3,opt,name=new_directory,json=newDirectory" json:"new_directory,omitempty"`
+	NewName      string `protobuf:"bytes,4,opt,name=new_name,json=newName" json:"new_name,omitempty"`
+	Error        string `protobuf:"bytes,5,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *DirectoryRename) Reset()                    { *m = DirectoryRename{} }
+func (m *DirectoryRename) String() string            { return proto.CompactTextString(m) }
+func (*DirectoryRename) ProtoMessage()               {}
+func (*DirectoryRename) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+
+func (m *DirectoryRename) GetDirectory() *Node {
+	if m != nil {
+		return m.Directory
+	}
+	return nil
+}
+
+func (m *DirectoryRename) GetNewDirectory() *Node {
+	if m != nil {
+		return m.NewDirectory
+	}
+	return nil
+}
+
+type Statfs struct {
+	Filesystem *Filesystem `protobuf:"bytes,1,opt,name=filesystem" json:"filesystem,omitempty"`
+	Blocks     uint64      `protobuf:"varint,2,opt,name=blocks" json:"blocks,omitempty"`
+	Error      string      `protobuf:"bytes,3,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *Statfs) Reset()                    { *m = Statfs{} }
+func (m *Statfs) String() string            { return proto.CompactTextString(m) }
+func (*Statfs) ProtoMessage()               {}
+func (*Statfs) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+
+func (m *Statfs) GetFilesystem() *Filesystem {
+	if m != nil {
+		return m.Filesystem
+	}
+	return nil
+}
+
+type DirectoryGetxattr struct {
+	Directory *Node  `protobuf:"bytes,1,opt,name=directory" json:"directory,omitempty"`
+	Name      string `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	Error     string `protobuf:"bytes,3,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *DirectoryGetxattr) Reset()                    { *m = DirectoryGetxattr{} }
+func (m *DirectoryGetxattr) String() string            { return proto.CompactTextString(m) }
+func (*DirectoryGetxattr) ProtoMessage()               {}
+func (*DirectoryGetxattr) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+func (m *DirectoryGetxattr) GetDirectory() *Node {
+	if m != nil {
+		return m.Directory
+	}
+	return nil
+}
+
+type DirectoryListxattr struct {
+	Directory *Node    `protobuf:"bytes,1,opt,name=directory" json:"directory,omitempty"`
+	Names     []string `protobuf:"bytes,2,rep,name=names" json:"names,omitempty"`
+	Error     string   `protobuf:"bytes,3,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *DirectoryListxattr) Reset()                    { *m = DirectoryListxattr{} }
+func (m *DirectoryListxattr) String() string            { return proto.CompactTextString(m) }
+func (*DirectoryListxattr) ProtoMessage()               {}
+func (*DirectoryListxattr) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+
+func (m *DirectoryListxattr) GetDirectory() *Node {
+	if m != nil {
+		return m.Directory
+	}
+	return nil
+}
+
+type DirectorySetxattr struct {
+	Directory *Node  `protobuf:"bytes,1,opt,name=directory" json:"directory,omitempty"`
+	Name      string `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	Error     string `protobuf:"bytes,3,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *DirectorySetxattr) Reset()                    { *m = DirectorySetxattr{} }
+func (m *DirectorySetxattr) String() string            { return proto.CompactTextString(m) }
+func (*DirectorySetxattr) ProtoMessage()               {}
+func (*DirectorySetxattr) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+
+func (m *DirectorySetxattr) GetDirectory() *Node {
+	if m != nil {
+		return m.Directory
+	}
+	return nil
+}
+
+type DirectorySymlink struct {
+	Directory *Node  `protobuf:"bytes,1,opt,name=directory" json:"directory,omitempty"`
+	NewName   string `protobuf:"bytes,2,opt,name=new_name,json=newName" json:"new_name,omitempty"`
+	Target    string `protobuf:"bytes,3,opt,name=target" json:"target,omitempty"`
+	Result    *Node  `protobuf:"bytes,4,opt,name=result" json:"result,omitempty"`
+	Error     string `protobuf:"bytes,5,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *DirectorySymlink) Reset()                    { *m = DirectorySymlink{} }
+func (m *DirectorySymlink) String() string            { return proto.CompactTextString(m) }
+func (*DirectorySymlink) ProtoMessage()               {}
+func (*DirectorySymlink) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+
+func (m *DirectorySymlink) GetDirectory() *Node {
+	if m != nil {
+		return m.Directory
+	}
+	return nil
+}
+
+func (m *DirectorySymlink) GetResult() *Node {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+type FileReadlink struct {
+	File   *Node  `protobuf:"bytes,1,opt,name=file" json:"file,omitempty"`
+	Target string `protobuf:"bytes,2,opt,name=target" json:"target,omitempty"`
+	Error  string `protobuf:"bytes,3,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *FileReadlink) Reset()                    { *m = FileReadlink{} }
+func (m *FileReadlink) String() string            { return proto.CompactTextString(m) }
+func (*FileReadlink) ProtoMessage()               {}
+func (*FileReadlink) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+
+func (m *FileReadlink) GetFile() *Node {
+	if m != nil {
+		return m.File
+	}
+	return nil
+}
+
+type DirectoryFsync struct {
+	Directory *Node  `protobuf:"bytes,1,opt,name=directory" json:"directory,omitempty"`
+	Finished  bool   `protobuf:"varint,2,opt,name=finished" json:"finished,omitempty"`
+	Error     string `protobuf:"bytes,3,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *DirectoryFsync) Reset()                    { *m = DirectoryFsync{} }
+func (m *DirectoryFsync) String() string            { return proto.CompactTextString(m) }
+func (*DirectoryFsync) ProtoMessage()               {}
+func (*DirectoryFsync) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+
+func (m *DirectoryFsync) GetDirectory() *Node {
+	if m != nil {
+		return m.Directory
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*CommitMount)(nil), "fuse.CommitMount")
 	proto.RegisterType((*Filesystem)(nil), "fuse.Filesystem")
@@ -438,48 +631,78 @@ func init() {
 	proto.RegisterType((*FileOpen)(nil), "fuse.FileOpen")
 	proto.RegisterType((*FileWrite)(nil), "fuse.FileWrite")
 	proto.RegisterType((*FileRemove)(nil), "fuse.FileRemove")
+	proto.RegisterType((*DirectoryRename)(nil), "fuse.DirectoryRename")
+	proto.RegisterType((*Statfs)(nil), "fuse.Statfs")
+	proto.RegisterType((*DirectoryGetxattr)(nil), "fuse.DirectoryGetxattr")
+	proto.RegisterType((*DirectoryListxattr)(nil), "fuse.DirectoryListxattr")
+	proto.RegisterType((*DirectorySetxattr)(nil), "fuse.DirectorySetxattr")
+	proto.RegisterType((*DirectorySymlink)(nil), "fuse.DirectorySymlink")
+	proto.RegisterType((*FileReadlink)(nil), "fuse.FileReadlink")
+	proto.RegisterType((*DirectoryFsync)(nil), "fuse.DirectoryFsync")
 }
 
 var fileDescriptor0 = []byte{
-	// 630 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xbc, 0x54, 0x4d, 0x6f, 0xd3, 0x40,
-	0x10, 0x95, 0x13, 0x27, 0x8a, 0x27, 0x0d, 0x14, 0x53, 0xa1, 0x28, 0x12, 0x50, 0x19, 0x0e, 0x3d,
-	0x20, 0x07, 0x15, 0x89, 0x33, 0x51, 0x10, 0x27, 0x02, 0xd2, 0xb6, 0x12, 0xc7, 0xc8, 0x8d, 0xc7,
-	0xad, 0x55, 0x6f, 0xd6, 0xda, 0x5d, 0x17, 0x55, 0x9c, 0xf9, 0x0f, 0xfc, 0x10, 0x7e, 0x20, 0xfb,
-	0xe1, 0x2f, 0xd4, 0x46, 0x49, 0x83, 0xc4, 0x21, 0xd1, 0xce, 0xce, 0xf3, 0xbc, 0x37, 0xb3, 0x6f,
-	0x17, 0x26, 0x02, 0xf9, 0x0d, 0xf2, 0x69, 0x9e, 0x88, 0x69, 0x52, 0x08, 0x34, 0x7f, 0x61, 0xce,
-	0x99, 0x64, 0xbe, 0xab, 0xd7, 0x93, 0xa3, 0x55, 0x96, 0xe2, 0x5a, 0x1a, 0x84, 0xfa, 0xd9, 0xdc,
-	0xe4, 0xe5, 0x25, 0x63, 0x97, 0x19, 0x4e, 0x4d, 0x74, 0x51, 0x24, 0x53, 0x99, 0x52, 0x14, 0x32,
-	0xa2, 0xb9, 0x05, 0x04, 0xbf, 0x1c, 0x18, 0xce, 0x19, 0xa5, 0xa9, 0x5c, 0xb0, 0x62, 0x2d, 0xfd,
-	0x57, 0xd0, 0x5f, 0x99, 0x70, 0xec, 0x1c, 0x3b, 0x27, 0xc3, 0xd3, 0x61, 0xa8, 0x8b, 0x59, 0x04,
-	0x29, 0x53, 0xfe, 0x1b, 0x18, 0x26, 0x9c, 0xd1, 0x65, 0x89, 0xec, 0xdc, 0x45, 0x82, 0xce, 0xdb,
-	0xb5, 0x7f, 0x04, 0xbd, 0x28, 0x4b, 0x23, 0x31, 0xee, 0x2a, 0x9c, 0x47, 0x6c, 0xe0, 0x1f, 0x43,
-	0x4f, 0x5c, 0x45, 0x3c, 0x1e, 0xbb, 0xe6, 0x6b, 0x30, 0x5f, 0x9f, 0xe9, 0x1d, 0x62, 0x13, 0x41,
-	0x02, 0xf0, 0x29, 0xcd, 0x50, 0xdc, 0x0a, 0x89, 0xb4, 0xc1, 0x3b, 0x1b, 0xf0, 0xfe, 0x7b, 0x18,
-	0x59, 0x41, 0x4b, 0xaa, 0x5b, 0x11, 0x4a, 0x57, 0x57, 0x21, 0x9f, 0x84, 0x66, 0x56, 0xad, 0x26,
-	0xc9, 0xc1, 0xaa, 0x09, 0x44, 0xf0, 0xdb, 0x01, 0xf7, 0x0b, 0x8b, 0xd1, 0x7f, 0x0e, 0x6e, 0xa2,
-	0x08, 0x4b, 0x06, 0xcf, 0x30, 0x68, 0x05, 0xc4, 0x6c, 0xab, 0x34, 0x70, 0xcc, 0xd9, 0xd2, 0x36,
-	0xd3, 0x31, 0xcd, 0x78, 0x7a, 0x67, 0x66, 0x1a, 0x52, 0x6d, 0x7e, 0xe7, 0xa9, 0x44, 0xd3, 0xe6,
-	0x80, 0xd8, 0x60, 0x7b, 0x9b, 0x4a, 0xf6, 0x80, 0xb2, 0x38, 0x4d, 0x52, 0x8c, 0xc7, 0x3d, 0x03,
-	0x9a, 0x84, 0xf6, 0xd4, 0xc2, 0xea, 0xd4, 0xc2, 0xf3, 0xea, 0xd4, 0x48, 0x8d, 0x0d, 0x26, 0xe0,
-	0xce, 0xa4, 0xe4, 0xbe, 0x0f, 0xee, 0x42, 0xa9, 0x37, 0xaa, 0x47, 0xc4, 0x55, 0x79, 0x0c, 0x4e,
-	0xa1, 0xff, 0x31, 0xe5, 0xca, 0x0e, 0x5a, 0x55, 0xba, 0xae, 0xd2, 0x2e, 0xb1, 0x81, 0xfe, 0x66,
-	0x1d, 0x51, 0x2c, 0x9b, 0x30, 0xeb, 0x80, 0x83, 0x4b, 0x18, 0x93, 0xfe, 0x5b, 0x80, 0xa4, 0x1e,
-	0x7b, 0x39, 0x8b, 0x43, 0x3b, 0xc3, 0xe6, 0x38, 0x48, 0x0b, 0xe3, 0x07, 0xd0, 0xe7, 0x28, 0x8a,
-	0xac, 0x72, 0x02, 0x58, 0xb4, 0x9e, 0x29, 0x29, 0x33, 0x5a, 0x07, 0x72, 0xce, 0x78, 0x65, 0x02,
-	0x13, 0x04, 0x02, 0x46, 0x5a, 0xe7, 0x4a, 0x32, 0x7e, 0x6b, 0x9a, 0x39, 0x01, 0x2f, 0xae, 0x36,
-	0xea, 0x93, 0x6e, 0xaa, 0x35, 0xc9, 0x4d, 0xa4, 0xba, 0xca, 0x16, 0xd2, 0x9f, 0x0e, 0x3c, 0xae,
-	0x59, 0x3f, 0x33, 0x76, 0x5d, 0xe4, 0x0f, 0xe0, 0xbd, 0x67, 0x74, 0x2d, 0x2d, 0xdd, 0x8d, 0x03,
-	0x38, 0x84, 0xae, 0xa2, 0x37, 0x36, 0xf0, 0x88, 0x5e, 0x06, 0x3f, 0xe0, 0x69, 0x2d, 0x83, 0x60,
-	0x14, 0xab, 0x60, 0x96, 0x65, 0x0f, 0x90, 0xf2, 0xba, 0x35, 0x02, 0xed, 0xf4, 0x03, 0x0b, 0xb3,
-	0x27, 0xbf, 0x65, 0x08, 0x45, 0x6b, 0x06, 0x73, 0x8e, 0x91, 0xb2, 0xea, 0x3f, 0xcf, 0x7e, 0x87,
-	0x03, 0x97, 0xf0, 0xa8, 0xa6, 0x5d, 0x5c, 0xab, 0x8a, 0xff, 0x85, 0x35, 0x86, 0x81, 0xb6, 0xae,
-	0x71, 0xd8, 0x8b, 0xbf, 0x2e, 0x79, 0xbb, 0x86, 0xbd, 0xe5, 0xfb, 0xfb, 0x6a, 0x0e, 0x43, 0xcd,
-	0x72, 0x86, 0x72, 0x27, 0xa2, 0xba, 0x48, 0xa7, 0x5d, 0xe4, 0xdc, 0x4a, 0xd5, 0x7e, 0xd8, 0x5a,
-	0x41, 0x59, 0x31, 0x8e, 0x64, 0x54, 0x59, 0x51, 0xaf, 0x37, 0x48, 0xfb, 0x60, 0xab, 0x7e, 0xcd,
-	0x71, 0xbd, 0xa7, 0x2e, 0x0a, 0x9e, 0xae, 0xf0, 0xcd, 0x3c, 0x6a, 0xfb, 0x08, 0x7b, 0x06, 0x7d,
-	0x96, 0x24, 0x02, 0xed, 0x1d, 0xe9, 0x92, 0x32, 0x6a, 0xe8, 0xdc, 0x36, 0xdd, 0x95, 0x7d, 0xfb,
-	0x09, 0x52, 0x76, 0xb3, 0x13, 0xdf, 0x9d, 0x3b, 0xa9, 0xee, 0x9b, 0xb2, 0x4e, 0xf9, 0x18, 0xeb,
-	0xe5, 0xfd, 0x4c, 0x17, 0x7d, 0xf3, 0xc8, 0xbe, 0xfb, 0x13, 0x00, 0x00, 0xff, 0xff, 0x24, 0xe2,
-	0x1d, 0x0c, 0x62, 0x07, 0x00, 0x00,
+	// 981 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x56, 0x51, 0x6f, 0x23, 0x35,
+	0x10, 0xd6, 0x26, 0x9b, 0x34, 0x99, 0x6d, 0x69, 0xbb, 0x54, 0x55, 0x2e, 0xd2, 0xdd, 0x45, 0x81,
+	0x87, 0x80, 0x50, 0x8a, 0x82, 0x74, 0x6f, 0x48, 0x94, 0x56, 0x85, 0x07, 0x7a, 0x48, 0x4e, 0x25,
+	0x5e, 0x90, 0x56, 0x6e, 0x3c, 0xdb, 0x98, 0xee, 0xae, 0x23, 0xdb, 0xb9, 0xb6, 0xc7, 0x33, 0x3f,
+	0x80, 0x9f, 0xc1, 0x3b, 0x0f, 0xfc, 0x0c, 0x7e, 0x12, 0xb2, 0xbd, 0xd9, 0x6c, 0x48, 0xa2, 0x24,
+	0x3d, 0xc1, 0x4b, 0xe4, 0x19, 0x8f, 0xe7, 0xfb, 0xfc, 0xcd, 0x78, 0xb2, 0xd0, 0x56, 0x28, 0xdf,
+	0xa1, 0x3c, 0x9b, 0xc4, 0xea, 0x2c, 0x9e, 0x2a, 0xb4, 0x3f, 0xfd, 0x89, 0x14, 0x5a, 0x84, 0xbe,
+	0x59, 0xb7, 0x4f, 0x46, 0x09, 0xc7, 0x4c, 0xdb, 0x88, 0x49, 0xac, 0xdc, 0x5e, 0xfb, 0xf5, 0x9d,
+	0x10, 0x77, 0x09, 0x9e, 0x59, 0xeb, 0x76, 0x1a, 0x9f, 0x69, 0x9e, 0xa2, 0xd2, 0x34, 0x9d, 0xe4,
+	0x01, 0xaf, 0xfe, 0x1d, 0xc0, 0xa6, 0x92, 0x6a, 0x2e, 0x32, 0xb7, 0xdf, 0xfd, 0xbd, 0x02, 0xc1,
+	0x85, 0x48, 0x53, 0xae, 0xaf, 0xc5, 0x34, 0xd3, 0xe1, 0x27, 0x50, 0x1f, 0x59, 0xb3, 0xe5, 0x75,
+	0xbc, 0x5e, 0x30, 0x08, 0xfa, 0x06, 0xcc, 0x45, 0x90, 0x7c, 0x2b, 0xfc, 0x02, 0x82, 0x58, 0x8a,
+	0x34, 0xca, 0x23, 0x2b, 0xcb, 0x91, 0x60, 0xf6, 0xdd, 0x3a, 0x3c, 0x81, 0x1a, 0x4d, 0x38, 0x55,
+	0xad, 0x6a, 0xc7, 0xeb, 0x35, 0x89, 0x33, 0xc2, 0x0e, 0xd4, 0xd4, 0x98, 0x4a, 0xd6, 0xf2, 0xed,
+	0x69, 0xb0, 0xa7, 0x87, 0xc6, 0x43, 0xdc, 0x46, 0xf8, 0x39, 0x1c, 0x3f, 0x48, 0xae, 0x31, 0xba,
+	0x9d, 0xc6, 0x31, 0xca, 0x48, 0xf1, 0xf7, 0xd8, 0xaa, 0x75, 0xbc, 0x9e, 0x4f, 0x0e, 0xed, 0xc6,
+	0xb7, 0xd6, 0x3f, 0xe4, 0xef, 0x31, 0x7c, 0x0d, 0x01, 0x9d, 0x6a, 0x11, 0xc5, 0x3c, 0xe3, 0x6a,
+	0xdc, 0xaa, 0x77, 0xbc, 0x5e, 0x83, 0x80, 0x71, 0x5d, 0x59, 0x8f, 0x49, 0x96, 0xd2, 0xc7, 0xc8,
+	0x25, 0x1c, 0xd3, 0x8c, 0x25, 0xa8, 0x5a, 0x7b, 0x2e, 0x59, 0x4a, 0x1f, 0x7f, 0x32, 0xfe, 0xef,
+	0x9d, 0xbb, 0xfb, 0xb7, 0x07, 0x70, 0xc5, 0x13, 0x54, 0x4f, 0x4a, 0x63, 0x3a, 0x67, 0xea, 0xad,
+	0x63, 0xfa, 0x06, 0x0e, 0x9c, 0x14, 0x51, 0x6a, 0x44, 0x54, 0xad, 0x4a, 0xa7, 0xda, 0x0b, 0x06,
+	0xc7, 0x7d, 0x5b, 0xc5, 0x92, 0xbc, 0x64, 0x7f, 0x34, 0x37, 0x54, 0xf8, 0x35, 0x1c, 0x30, 0x2e,
+	0xa3, 0x11, 0x1d, 0x8d, 0x31, 0xd2, 0x3a, 0xb1, 0x0a, 0x05, 0x83, 0x17, 0x7d, 0x57, 0xb4, 0xfe,
+	0xac, 0x68, 0xfd, 0xcb, 0xbc, 0x68, 0x24, 0x60, 0x5c, 0x5e, 0x98, 0xf0, 0x1b, 0x9d, 0x84, 0x9f,
+	0xc1, 0x91, 0xd2, 0x12, 0x69, 0x1a, 0xe9, 0xb1, 0x44, 0x35, 0x16, 0x89, 0x53, 0xd3, 0x27, 0x87,
+	0xce, 0x7f, 0x33, 0x73, 0x77, 0xff, 0xf4, 0xc0, 0x7f, 0x2b, 0x18, 0x86, 0x2f, 0xc1, 0x8f, 0x79,
+	0x82, 0xf9, 0x5d, 0x9a, 0xf6, 0x2e, 0xe6, 0xae, 0xc4, 0xba, 0xc3, 0x97, 0x00, 0x12, 0x27, 0x22,
+	0x72, 0x05, 0xab, 0xd8, 0x82, 0x35, 0x8d, 0xe7, 0xdc, 0x16, 0xed, 0x04, 0x6a, 0x56, 0x41, 0x4b,
+	0xb4, 0x41, 0x9c, 0xb1, 0x45, 0x29, 0xdf, 0x40, 0x23, 0x15, 0x8c, 0xc7, 0x1c, 0x99, 0xad, 0x60,
+	0x30, 0x68, 0x2f, 0xdd, 0xf1, 0x66, 0xd6, 0xb9, 0xa4, 0x88, 0xed, 0xb6, 0xc1, 0x3f, 0xd7, 0x5a,
+	0x86, 0x21, 0xf8, 0xd7, 0x82, 0x39, 0xd6, 0x07, 0xc4, 0x4f, 0x05, 0xc3, 0xee, 0x00, 0xea, 0x97,
+	0x5c, 0x62, 0x66, 0x1b, 0x8c, 0x67, 0xb3, 0x6d, 0x9f, 0x38, 0xc3, 0x9c, 0xc9, 0x68, 0x8a, 0xf9,
+	0x25, 0xec, 0xba, 0x2b, 0xc1, 0x27, 0x42, 0xe8, 0xf0, 0x4b, 0x80, 0xb8, 0x28, 0x70, 0xae, 0xc5,
+	0x91, 0xab, 0xd6, 0xbc, 0xf0, 0xa4, 0x14, 0x13, 0x76, 0xa1, 0x2e, 0x51, 0x4d, 0x93, 0x59, 0xb7,
+	0x83, 0x8b, 0x36, 0x9a, 0x92, 0x7c, 0xc7, 0xf0, 0x40, 0x29, 0x85, 0x9c, 0x35, 0xba, 0x35, 0xba,
+	0x0a, 0x0e, 0x0c, 0xcf, 0x91, 0x16, 0xf2, 0xc9, 0x5e, 0xa6, 0x07, 0x4d, 0x36, 0x73, 0xb4, 0xbc,
+	0xa5, 0x6c, 0xf3, 0xcd, 0x75, 0xa0, 0x26, 0xcb, 0x06, 0xd0, 0xdf, 0x3c, 0x38, 0x2c, 0x50, 0x7f,
+	0x10, 0xe2, 0x7e, 0x3a, 0xd9, 0x01, 0x77, 0x85, 0x74, 0x25, 0x2e, 0xd5, 0xb5, 0x02, 0x1c, 0x41,
+	0x15, 0xa5, 0xb4, 0x6d, 0xd0, 0x24, 0x66, 0xd9, 0xfd, 0x15, 0x3e, 0x2e, 0x68, 0x10, 0xa4, 0xec,
+	0x92, 0xcb, 0xf3, 0x24, 0xd9, 0x81, 0xca, 0xa7, 0x25, 0x09, 0xcc, 0x9b, 0xda, 0x77, 0x61, 0xae,
+	0xf2, 0x1b, 0x44, 0x98, 0x96, 0x34, 0xb8, 0x90, 0x48, 0x35, 0x7e, 0xb8, 0xf6, 0x5b, 0x14, 0x5c,
+	0xc3, 0x47, 0x05, 0xec, 0xf5, 0x3d, 0xe3, 0xf2, 0x7f, 0x41, 0x65, 0xd0, 0x30, 0xad, 0x6b, 0x3b,
+	0xec, 0xd5, 0xc2, 0x23, 0x2f, 0xe7, 0xb0, 0xfe, 0x0f, 0xe8, 0xab, 0x0b, 0x08, 0x0c, 0xca, 0x10,
+	0xf5, 0x56, 0x40, 0x45, 0x92, 0x4a, 0x39, 0xc9, 0x8d, 0xa3, 0x6a, 0xfa, 0x61, 0x63, 0x86, 0x10,
+	0x7c, 0x46, 0x35, 0x9d, 0xb5, 0xa2, 0x59, 0xaf, 0xa1, 0xf6, 0x8d, 0xcb, 0xfa, 0xe3, 0x04, 0xb3,
+	0x67, 0xf2, 0x4a, 0xa1, 0x69, 0x32, 0xd8, 0xff, 0x82, 0x67, 0x11, 0x3b, 0x85, 0xba, 0x88, 0x63,
+	0x85, 0xee, 0x8d, 0x54, 0x49, 0x6e, 0xcd, 0xe1, 0xfc, 0x32, 0xdc, 0xd8, 0xfd, 0xcb, 0x10, 0x4c,
+	0xc5, 0xbb, 0xad, 0xf0, 0x96, 0xde, 0xe4, 0x11, 0x54, 0x19, 0x97, 0xf9, 0x30, 0x36, 0xcb, 0x35,
+	0x48, 0x7f, 0x95, 0xa7, 0x01, 0x41, 0x7b, 0x76, 0xfb, 0x9e, 0x7c, 0x01, 0x0d, 0x91, 0xb0, 0xa8,
+	0x84, 0xbe, 0x27, 0x12, 0xf6, 0xd6, 0x24, 0x39, 0x83, 0x83, 0x0c, 0x1f, 0xa2, 0x79, 0xa2, 0xe5,
+	0xd9, 0xb0, 0x9f, 0xe1, 0xc3, 0x65, 0x39, 0x97, 0x39, 0x60, 0x73, 0x39, 0x8a, 0x7b, 0x19, 0x3e,
+	0xd8, 0x5c, 0x05, 0xf5, 0xda, 0xa2, 0x48, 0xf5, 0xa1, 0xa6, 0x3a, 0x56, 0xcf, 0x98, 0xd9, 0xa7,
+	0x50, 0xbf, 0x4d, 0xc4, 0xe8, 0xde, 0xfd, 0x91, 0xf9, 0x24, 0xb7, 0xd6, 0xf4, 0xcf, 0x1d, 0x1c,
+	0x17, 0x3c, 0xbf, 0x43, 0xfd, 0x48, 0x77, 0x9b, 0xd5, 0xab, 0xea, 0xb3, 0x1a, 0xe8, 0x17, 0x08,
+	0xe7, 0xa3, 0x99, 0xab, 0x9d, 0x91, 0x4e, 0xa0, 0x66, 0xb2, 0xbb, 0xaf, 0x8c, 0x26, 0x71, 0xc6,
+	0x16, 0x97, 0x1a, 0xfe, 0x97, 0x97, 0xfa, 0xc3, 0x83, 0xa3, 0x39, 0xd2, 0x53, 0x9a, 0xf0, 0xec,
+	0x7e, 0xb7, 0x1e, 0x2b, 0xfa, 0xa2, 0xb2, 0xd8, 0x17, 0xa7, 0x50, 0xd7, 0x54, 0xde, 0xe5, 0x8f,
+	0xaa, 0x49, 0x72, 0xab, 0x34, 0xc4, 0xfc, 0xcd, 0xa3, 0x72, 0xa1, 0xa7, 0x7e, 0x86, 0xfd, 0xd9,
+	0xfc, 0xb1, 0x34, 0x37, 0x3d, 0xbd, 0x39, 0x83, 0xca, 0x02, 0x83, 0xd5, 0x4a, 0x24, 0xa5, 0xf1,
+	0x7f, 0xa5, 0x9e, 0xb2, 0xd1, 0x0e, 0x32, 0xb4, 0xa1, 0xe1, 0xbe, 0x60, 0x91, 0x59, 0xac, 0x06,
+	0x29, 0xec, 0xd5, 0x68, 0xb7, 0x75, 0xfb, 0xfd, 0xf4, 0xd5, 0x3f, 0x03, 0x00, 0x79, 0x55, 0x27,
+	0x89, 0x41, 0x0c, 0x00, 0x00,
 }
@@ -0,0 +1,55 @@
+package fuse
+
+import (
+	"fmt"
+	"syscall"
+	"testing"
+
+	"bazil.org/fuse"
+	"bazil.org/fuse/fs"
+	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"golang.org/x/net/context"
+)
+
+func TestMaxWriteHandles(t *testing.T) {
+	commit := &pfsclient.Commit{Repo: &pfsclient.Repo{Name: "repo"}, ID: "commit"}
+	commitMounts := []*CommitMount{{Commit: commit, MaxWriteHandles: 3}}
+	filesystem := newFilesystem(putFileAPIClient{}, nil, commitMounts)
+	d := &directory{
+		fs:   filesystem,
+		Node: Node{File: &pfsclient.File{Commit: commit}, Write: true},
+	}
+	ctx := context.Background()
+	create := func(i int) (fs.Handle, error) {
+		_, h, err := d.Create(ctx, &fuse.CreateRequest{Name: fmt.Sprintf("file%d", i)}, &fuse.CreateResponse{})
+		return h, err
+	}
+
+	var handles []fs.Handle
+	for i := 0; i < 3; i++ {
+		h, err := create(i)
+		require.NoError(t, err)
+		handles = append(handles, h)
+	}
+	require.Equal(t, 3, filesystem.openWriteHandleCount(commit))
+	for i := 3; i < 5; i++ {
+		_, err := create(i)
+		require.Equal(t, fuse.Errno(syscall.EMFILE), err)
+	}
+	require.Equal(t, 3, filesystem.openWriteHandleCount(commit))
+
+	// Releasing a handle makes room for another.
+	require.NoError(t, handles[0].(*handle).Release(ctx, &fuse.ReleaseRequest{}))
+	require.Equal(t, 2, filesystem.openWriteHandleCount(commit))
+	_, err := create(4)
+	require.NoError(t, err)
+	require.Equal(t, 3, filesystem.openWriteHandleCount(commit))
+
+	// So does a handle opened for reading which starts writing
+	f := &file{directory: *d.copy()}
+	f.File.Path = "file0"
+	err = f.newHandle(0).Write(ctx, &fuse.WriteRequest{Data: []byte("foo\n")}, &fuse.WriteResponse{})
+	require.Equal(t, fuse.Errno(syscall.EMFILE), err)
+	require.Equal(t, 3, filesystem.openWriteHandleCount(commit))
+}
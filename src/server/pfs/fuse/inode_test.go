@@ -0,0 +1,43 @@
+package fuse
+
+import (
+	"testing"
+
+	"bazil.org/fuse"
+	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// inspectFileAPIClient answers InspectFile with a regular file at the
+// requested path, the rest of its methods panic.
+type inspectFileAPIClient struct {
+	pfsclient.APIClient
+}
+
+func (c inspectFileAPIClient) InspectFile(ctx context.Context, request *pfsclient.InspectFileRequest, opts ...grpc.CallOption) (*pfsclient.FileInfo, error) {
+	return &pfsclient.FileInfo{
+		File:     request.File,
+		FileType: pfsclient.FileType_FILE_TYPE_REGULAR,
+	}, nil
+}
+
+func lookUpInode(t *testing.T, filesystem *filesystem) uint64 {
+	d := &directory{
+		fs:   filesystem,
+		Node: Node{File: &pfsclient.File{Commit: &pfsclient.Commit{Repo: &pfsclient.Repo{Name: "repo"}, ID: "commit"}}},
+	}
+	node, err := d.Lookup(context.Background(), "file")
+	require.NoError(t, err)
+	var attr fuse.Attr
+	require.NoError(t, node.Attr(context.Background(), &attr))
+	return attr.Inode
+}
+
+func TestInodeStableAcrossMounts(t *testing.T) {
+	commitMounts := []*CommitMount{{Commit: &pfsclient.Commit{Repo: &pfsclient.Repo{Name: "repo"}, ID: "commit"}}}
+	inode := lookUpInode(t, newFilesystem(inspectFileAPIClient{}, nil, commitMounts))
+	require.Equal(t, inode, lookUpInode(t, newFilesystem(inspectFileAPIClient{}, nil, commitMounts)))
+	require.Equal(t, uint64(0), inode>>63)
+}
@@ -0,0 +1,82 @@
+package fuse
+
+import (
+	"testing"
+	"time"
+
+	"bazil.org/fuse"
+	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// notFoundAPIClient counts InspectFile calls and answers them with
+// NotFound, the rest of its methods panic.
+type notFoundAPIClient struct {
+	pfsclient.APIClient
+	inspectFiles int
+}
+
+func (c *notFoundAPIClient) InspectFile(ctx context.Context, request *pfsclient.InspectFileRequest, opts ...grpc.CallOption) (*pfsclient.FileInfo, error) {
+	c.inspectFiles++
+	return nil, grpc.Errorf(codes.NotFound, "file %s not found", request.File.Path)
+}
+
+func countLookupRPCs(t *testing.T, negativeEntryTTL time.Duration) int {
+	apiClient := &notFoundAPIClient{}
+	filesystem := newFilesystem(apiClient, nil, nil)
+	filesystem.negativeEntryTTL = negativeEntryTTL
+	d := &directory{
+		fs:   filesystem,
+		Node: Node{File: &pfsclient.File{Commit: &pfsclient.Commit{Repo: &pfsclient.Repo{Name: "repo"}, ID: "commit"}}},
+	}
+	for i := 0; i < 100; i++ {
+		_, err := d.Lookup(context.Background(), "absent.pyc")
+		require.Equal(t, fuse.ENOENT, err)
+	}
+	return apiClient.inspectFiles
+}
+
+func TestNegativeEntryCache(t *testing.T) {
+	// Each miss inspects the name and the name of a symlink called it
+	require.Equal(t, 200, countLookupRPCs(t, 0))
+	require.Equal(t, 2, countLookupRPCs(t, time.Minute))
+}
+
+func TestNegativeEntryRemovedOnCreate(t *testing.T) {
+	filesystem := newFilesystem(&notFoundAPIClient{}, nil, nil)
+	file := &pfsclient.File{Commit: &pfsclient.Commit{Repo: &pfsclient.Repo{Name: "repo"}, ID: "commit"}, Path: "file"}
+	filesystem.addNegativeEntry(file)
+	require.True(t, filesystem.isNegativeEntry(file))
+	filesystem.removeNegativeEntry(file)
+	require.False(t, filesystem.isNegativeEntry(file))
+}
+
+// unavailableAPIClient counts InspectFile calls and fails them with
+// Unavailable, the rest of its methods panic.
+type unavailableAPIClient struct {
+	pfsclient.APIClient
+	inspectFiles int
+}
+
+func (c *unavailableAPIClient) InspectFile(ctx context.Context, request *pfsclient.InspectFileRequest, opts ...grpc.CallOption) (*pfsclient.FileInfo, error) {
+	c.inspectFiles++
+	return nil, grpc.Errorf(codes.Unavailable, "pachd unavailable")
+}
+
+func TestLookupFailure(t *testing.T) {
+	apiClient := &unavailableAPIClient{}
+	filesystem := newFilesystem(apiClient, nil, nil)
+	d := &directory{
+		fs:   filesystem,
+		Node: Node{File: &pfsclient.File{Commit: &pfsclient.Commit{Repo: &pfsclient.Repo{Name: "repo"}, ID: "commit"}}},
+	}
+	// Only a missing file is looked for as a symlink, or cached as missing
+	_, err := d.Lookup(context.Background(), "file")
+	require.YesError(t, err)
+	require.NotEqual(t, fuse.ENOENT, err)
+	require.Equal(t, 1, apiClient.inspectFiles)
+	require.False(t, filesystem.isNegativeEntry(&pfsclient.File{Commit: d.File.Commit, Path: "file"}))
+}
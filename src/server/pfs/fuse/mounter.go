@@ -17,14 +17,18 @@ const (
 )
 
 type mounter struct {
-	address   string
-	apiClient pfsclient.APIClient
+	address     string
+	apiClient   pfsclient.APIClient
+	filesystems map[string]*filesystem
+	lock        sync.Mutex
 }
 
 func newMounter(address string, apiClient pfsclient.APIClient) Mounter {
 	return &mounter{
 		address,
 		apiClient,
+		make(map[string]*filesystem),
+		sync.Mutex{},
 	}
 }
 
@@ -83,8 +87,17 @@ func (m *mounter) Mount(
 			close(ready)
 		}
 	})
+	filesystem := newFilesystem(m.apiClient, shard, commitMounts)
+	m.lock.Lock()
+	m.filesystems[mountPoint] = filesystem
+	m.lock.Unlock()
+	defer func() {
+		m.lock.Lock()
+		defer m.lock.Unlock()
+		delete(m.filesystems, mountPoint)
+	}()
 	config := &fs.Config{}
-	if err := fs.New(conn, config).Serve(newFilesystem(m.apiClient, shard, commitMounts)); err != nil {
+	if err := fs.New(conn, config).Serve(filesystem); err != nil {
 		return err
 	}
 	<-conn.Ready
@@ -0,0 +1,81 @@
+package fuse
+
+import (
+	"testing"
+
+	"bazil.org/fuse"
+	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+const readContent = "hello\nworld\n"
+
+// paddedAPIClient's GetFile returns the rest of the file followed by
+// padding, whatever size is asked for, like a server with a stale view of
+// the file.
+type paddedAPIClient struct {
+	*memoryAPIClient
+	padding []byte
+}
+
+func (c *paddedAPIClient) GetFile(ctx context.Context, request *pfsclient.GetFileRequest, opts ...grpc.CallOption) (pfsclient.API_GetFileClient, error) {
+	data := c.files[request.File.Path]
+	if request.OffsetBytes > int64(len(data)) {
+		request.OffsetBytes = int64(len(data))
+	}
+	data = append(append([]byte{}, data[request.OffsetBytes:]...), c.padding...)
+	return &memoryGetFileClient{data: data}, nil
+}
+
+func newReadTestHandle(apiClient pfsclient.APIClient) *handle {
+	f := &file{
+		directory: directory{
+			fs: newFilesystem(apiClient, nil, nil),
+			Node: Node{
+				File: &pfsclient.File{Commit: &pfsclient.Commit{Repo: &pfsclient.Repo{Name: "repo"}, ID: "commit"}, Path: "file"},
+			},
+		},
+	}
+	return f.newHandle(0)
+}
+
+func read(t *testing.T, h *handle, offset int64, size int) string {
+	response := &fuse.ReadResponse{}
+	require.NoError(t, h.Read(context.Background(), &fuse.ReadRequest{Offset: offset, Size: size}, response))
+	return string(response.Data)
+}
+
+func TestReadUnaligned(t *testing.T) {
+	apiClient := newMemoryAPIClient()
+	apiClient.files["file"] = []byte(readContent)
+	h := newReadTestHandle(apiClient)
+	for offset := 0; offset <= len(readContent); offset++ {
+		for size := 1; size <= len(readContent)+3; size++ {
+			end := offset + size
+			if end > len(readContent) {
+				end = len(readContent)
+			}
+			require.Equal(t, readContent[offset:end], read(t, h, int64(offset), size))
+		}
+	}
+}
+
+func TestReadPastEnd(t *testing.T) {
+	apiClient := newMemoryAPIClient()
+	apiClient.files["file"] = []byte(readContent)
+	h := newReadTestHandle(apiClient)
+	require.Equal(t, "", read(t, h, int64(len(readContent)+5), 10))
+	require.Equal(t, int64(len(readContent)), h.f.size)
+}
+
+func TestReadClamped(t *testing.T) {
+	apiClient := &paddedAPIClient{newMemoryAPIClient(), []byte("padding")}
+	apiClient.files["file"] = []byte(readContent)
+	h := newReadTestHandle(apiClient)
+	// Never more than was asked for.
+	require.Equal(t, "hel", read(t, h, 0, 3))
+	// Never past the end of the file.
+	require.Equal(t, "world\n", read(t, h, 6, 100))
+}
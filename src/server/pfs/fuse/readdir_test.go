@@ -0,0 +1,121 @@
+package fuse
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
+)
+
+const benchmarkDirEntries = 100000
+
+func benchmarkFileInfo(i int) *pfsclient.FileInfo {
+	return &pfsclient.FileInfo{
+		File:     &pfsclient.File{Path: fmt.Sprintf("/dir/file-%d", i)},
+		FileType: pfsclient.FileType_FILE_TYPE_REGULAR,
+	}
+}
+
+// sendFileInfoSlice is how readFiles worked before streaming, every info is
+// held at once before the dirents are built. sample is called before each
+// info is sent.
+func sendFileInfoSlice(fileInfoCh chan<- *pfsclient.FileInfo, sample func()) {
+	var fileInfos []*pfsclient.FileInfo
+	for j := 0; j < benchmarkDirEntries; j++ {
+		fileInfos = append(fileInfos, benchmarkFileInfo(j))
+	}
+	for _, fileInfo := range fileInfos {
+		sample()
+		fileInfoCh <- fileInfo
+	}
+}
+
+// sendFileInfoStream is how readFiles works now, each info can be collected
+// as soon as its dirent is built.
+func sendFileInfoStream(fileInfoCh chan<- *pfsclient.FileInfo, sample func()) {
+	for j := 0; j < benchmarkDirEntries; j++ {
+		sample()
+		fileInfoCh <- benchmarkFileInfo(j)
+	}
+}
+
+// readDirentsFrom reads the dirents for what send sends.
+func readDirentsFrom(send func(chan<- *pfsclient.FileInfo, func()), sample func()) error {
+	fileInfoCh := make(chan *pfsclient.FileInfo)
+	go func() {
+		defer close(fileInfoCh)
+		send(fileInfoCh, sample)
+	}()
+	if dirents := readDirents("/dir", fileInfoCh); len(dirents) != benchmarkDirEntries {
+		return fmt.Errorf("wrong number of dirents: %d", len(dirents))
+	}
+	return nil
+}
+
+// livePeak keeps the most heap that was live, above what was live when it
+// was created, every 1000th time sample is called.
+type livePeak struct {
+	base    uint64
+	peak    uint64
+	samples int
+}
+
+func newLivePeak() *livePeak {
+	return &livePeak{base: liveHeap()}
+}
+
+func (p *livePeak) sample() {
+	p.samples++
+	if p.samples%1000 != 0 {
+		return
+	}
+	if live := liveHeap(); live > p.base && live-p.base > p.peak {
+		p.peak = live - p.base
+	}
+}
+
+// liveHeap collects garbage first, so that only what's still referenced is
+// counted.
+func liveHeap() uint64 {
+	runtime.GC()
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	return memStats.HeapAlloc
+}
+
+func TestReadDirentsPeakHeap(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+	slice := newLivePeak()
+	if err := readDirentsFrom(sendFileInfoSlice, slice.sample); err != nil {
+		t.Fatal(err)
+	}
+	stream := newLivePeak()
+	if err := readDirentsFrom(sendFileInfoStream, stream.sample); err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("peak live heap for %d entries: slice %d bytes, stream %d bytes", benchmarkDirEntries, slice.peak, stream.peak)
+	if stream.peak >= slice.peak {
+		t.Errorf("streaming held %d bytes, no less than the %d bytes the slice held", stream.peak, slice.peak)
+	}
+}
+
+func BenchmarkReadDirentsSlice(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := readDirentsFrom(sendFileInfoSlice, func() {}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReadDirentsStream(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := readDirentsFrom(sendFileInfoStream, func() {}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package fuse
+
+import (
+	"io"
+	"testing"
+
+	"bazil.org/fuse"
+	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"go.pedge.io/pb/go/google/protobuf"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// memoryAPIClient keeps files in memory by path, it answers PutFile, GetFile,
+// InspectFile and DeleteFile, the rest of its methods panic.
+type memoryAPIClient struct {
+	pfsclient.APIClient
+	files       map[string][]byte
+	deleteFiles int
+}
+
+func newMemoryAPIClient() *memoryAPIClient {
+	return &memoryAPIClient{files: make(map[string][]byte)}
+}
+
+func (c *memoryAPIClient) PutFile(ctx context.Context, opts ...grpc.CallOption) (pfsclient.API_PutFileClient, error) {
+	return &memoryPutFileClient{c: c}, nil
+}
+
+func (c *memoryAPIClient) GetFile(ctx context.Context, request *pfsclient.GetFileRequest, opts ...grpc.CallOption) (pfsclient.API_GetFileClient, error) {
+	data, ok := c.files[request.File.Path]
+	if !ok {
+		return nil, grpc.Errorf(codes.NotFound, "file %s not found", request.File.Path)
+	}
+	if request.OffsetBytes > int64(len(data)) {
+		request.OffsetBytes = int64(len(data))
+	}
+	data = data[request.OffsetBytes:]
+	if request.SizeBytes > 0 && request.SizeBytes < int64(len(data)) {
+		data = data[:request.SizeBytes]
+	}
+	return &memoryGetFileClient{data: data}, nil
+}
+
+func (c *memoryAPIClient) InspectFile(ctx context.Context, request *pfsclient.InspectFileRequest, opts ...grpc.CallOption) (*pfsclient.FileInfo, error) {
+	data, ok := c.files[request.File.Path]
+	if !ok {
+		return nil, grpc.Errorf(codes.NotFound, "file %s not found", request.File.Path)
+	}
+	return &pfsclient.FileInfo{
+		File:      request.File,
+		FileType:  pfsclient.FileType_FILE_TYPE_REGULAR,
+		SizeBytes: uint64(len(data)),
+	}, nil
+}
+
+func (c *memoryAPIClient) DeleteFile(ctx context.Context, request *pfsclient.DeleteFileRequest, opts ...grpc.CallOption) (*google_protobuf.Empty, error) {
+	c.deleteFiles++
+	delete(c.files, request.File.Path)
+	return google_protobuf.EmptyInstance, nil
+}
+
+type memoryPutFileClient struct {
+	grpc.ClientStream
+	c    *memoryAPIClient
+	path string
+}
+
+func (w *memoryPutFileClient) Send(request *pfsclient.PutFileRequest) error {
+	// File is only set on the first request.
+	if request.File != nil {
+		w.path = request.File.Path
+	}
+	w.c.files[w.path] = append(w.c.files[w.path], request.Value...)
+	return nil
+}
+
+func (w *memoryPutFileClient) CloseAndRecv() (*google_protobuf.Empty, error) {
+	return google_protobuf.EmptyInstance, nil
+}
+
+type memoryGetFileClient struct {
+	grpc.ClientStream
+	data []byte
+	sent bool
+}
+
+func (r *memoryGetFileClient) Recv() (*google_protobuf.BytesValue, error) {
+	if r.sent {
+		return nil, io.EOF
+	}
+	r.sent = true
+	return &google_protobuf.BytesValue{Value: r.data}, nil
+}
+
+const truncateContent = "hello\nworld\n"
+
+func newTruncateTestFile(apiClient *memoryAPIClient, write bool) *file {
+	apiClient.files["file"] = []byte(truncateContent)
+	return &file{
+		directory: directory{
+			fs: newFilesystem(apiClient, nil, nil),
+			Node: Node{
+				File:  &pfsclient.File{Commit: &pfsclient.Commit{Repo: &pfsclient.Repo{Name: "repo"}, ID: "commit"}, Path: "file"},
+				Write: write,
+			},
+		},
+	}
+}
+
+func truncateFile(f *file, size uint64) error {
+	return f.Setattr(context.Background(), &fuse.SetattrRequest{Valid: fuse.SetattrSize, Size: size}, &fuse.SetattrResponse{})
+}
+
+func TestTruncateToZero(t *testing.T) {
+	apiClient := newMemoryAPIClient()
+	f := newTruncateTestFile(apiClient, true)
+	require.NoError(t, truncateFile(f, 0))
+	require.Equal(t, "", string(apiClient.files["file"]))
+	require.Equal(t, int64(0), f.size)
+}
+
+func TestTruncateToSmaller(t *testing.T) {
+	apiClient := newMemoryAPIClient()
+	f := newTruncateTestFile(apiClient, true)
+	h := f.newHandle(len(truncateContent))
+	require.NoError(t, truncateFile(f, 5))
+	require.Equal(t, "hello", string(apiClient.files["file"]))
+	require.Equal(t, int64(5), f.size)
+	require.Equal(t, 5, h.cursor)
+}
+
+func TestTruncateToSame(t *testing.T) {
+	apiClient := newMemoryAPIClient()
+	f := newTruncateTestFile(apiClient, true)
+	require.NoError(t, truncateFile(f, uint64(len(truncateContent))))
+	require.Equal(t, truncateContent, string(apiClient.files["file"]))
+	require.Equal(t, 0, apiClient.deleteFiles)
+}
+
+func TestTruncateToLarger(t *testing.T) {
+	apiClient := newMemoryAPIClient()
+	f := newTruncateTestFile(apiClient, true)
+	require.NoError(t, truncateFile(f, uint64(len(truncateContent)+3)))
+	require.Equal(t, truncateContent+"\x00\x00\x00", string(apiClient.files["file"]))
+	// Growing only appends
+	require.Equal(t, 0, apiClient.deleteFiles)
+}
+
+func TestTruncateReadOnly(t *testing.T) {
+	apiClient := newMemoryAPIClient()
+	f := newTruncateTestFile(apiClient, false)
+	require.Equal(t, fuse.EPERM, truncateFile(f, 0))
+	require.Equal(t, truncateContent, string(apiClient.files["file"]))
+}
+
+func TestSetattrWithoutSize(t *testing.T) {
+	apiClient := newMemoryAPIClient()
+	f := newTruncateTestFile(apiClient, true)
+	require.NoError(t, f.Setattr(context.Background(), &fuse.SetattrRequest{Valid: fuse.SetattrMode, Mode: 0644}, &fuse.SetattrResponse{}))
+	require.Equal(t, truncateContent, string(apiClient.files["file"]))
+}
@@ -0,0 +1,99 @@
+package fuse
+
+import (
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	"bazil.org/fuse"
+	"bazil.org/fuse/fs"
+	"github.com/pachyderm/pachyderm/src/client"
+	"golang.org/x/net/context"
+)
+
+// statsFile is the name of the virtual file in the root of a mount which
+// holds a JSON snapshot of the mount's Stats.
+const statsFile = ".pfs_stats"
+
+// Stats counts the reads, writes and lookups a mount has served.
+type Stats struct {
+	Reads        int64 `json:"reads"`
+	Writes       int64 `json:"writes"`
+	BytesRead    int64 `json:"bytes_read"`
+	BytesWritten int64 `json:"bytes_written"`
+	Lookups      int64 `json:"lookups"`
+	// CacheHits counts the lookups and listings answered from the negative
+	// entry and dir caches rather than by an RPC.
+	CacheHits int64 `json:"cache_hits"`
+}
+
+// statsCollector is embedded in filesystem, its counters are only accessed
+// atomically.
+type statsCollector struct {
+	stats Stats
+}
+
+func (s *statsCollector) addRead(bytes int) {
+	atomic.AddInt64(&s.stats.Reads, 1)
+	atomic.AddInt64(&s.stats.BytesRead, int64(bytes))
+}
+
+func (s *statsCollector) addWrite(bytes int) {
+	atomic.AddInt64(&s.stats.Writes, 1)
+	atomic.AddInt64(&s.stats.BytesWritten, int64(bytes))
+}
+
+func (s *statsCollector) addLookup() {
+	atomic.AddInt64(&s.stats.Lookups, 1)
+}
+
+func (s *statsCollector) addCacheHit() {
+	atomic.AddInt64(&s.stats.CacheHits, 1)
+}
+
+func (s *statsCollector) snapshot() Stats {
+	return Stats{
+		Reads:        atomic.LoadInt64(&s.stats.Reads),
+		Writes:       atomic.LoadInt64(&s.stats.Writes),
+		BytesRead:    atomic.LoadInt64(&s.stats.BytesRead),
+		BytesWritten: atomic.LoadInt64(&s.stats.BytesWritten),
+		Lookups:      atomic.LoadInt64(&s.stats.Lookups),
+		CacheHits:    atomic.LoadInt64(&s.stats.CacheHits),
+	}
+}
+
+func (s *statsCollector) statsJSON() ([]byte, error) {
+	return json.Marshal(s.snapshot())
+}
+
+// statsNode is statsFile, it's its own handle. Each open reads a fresh
+// snapshot.
+type statsNode struct {
+	fs *filesystem
+}
+
+func (n *statsNode) Attr(ctx context.Context, a *fuse.Attr) error {
+	data, err := n.fs.statsJSON()
+	if err != nil {
+		return err
+	}
+	a.Valid = time.Nanosecond
+	a.Mode = 0444
+	a.Size = uint64(len(data))
+	a.Inode = n.fs.inode(client.NewFile("", "", statsFile))
+	return nil
+}
+
+func (n *statsNode) Open(ctx context.Context, request *fuse.OpenRequest, response *fuse.OpenResponse) (fs.Handle, error) {
+	if !request.Flags.IsReadOnly() {
+		return nil, fuse.EPERM
+	}
+	// The snapshot read may be longer than the size Attr reported, direct
+	// IO keeps the kernel from cutting it short.
+	response.Flags |= fuse.OpenDirectIO
+	return n, nil
+}
+
+func (n *statsNode) ReadAll(ctx context.Context) ([]byte, error) {
+	return n.fs.statsJSON()
+}
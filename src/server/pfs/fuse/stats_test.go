@@ -0,0 +1,50 @@
+package fuse
+
+import (
+	"encoding/json"
+	"testing"
+
+	"bazil.org/fuse"
+	"bazil.org/fuse/fs"
+	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"golang.org/x/net/context"
+)
+
+func TestStats(t *testing.T) {
+	commit := &pfsclient.Commit{Repo: &pfsclient.Repo{Name: "repo"}, ID: "commit"}
+	filesystem := newFilesystem(putFileAPIClient{}, nil, []*CommitMount{{Commit: commit}})
+	d := &directory{
+		fs:   filesystem,
+		Node: Node{File: &pfsclient.File{Commit: commit}, Write: true},
+	}
+	ctx := context.Background()
+	for _, name := range []string{"file1", "file2"} {
+		_, h, err := d.Create(ctx, &fuse.CreateRequest{Name: name}, &fuse.CreateResponse{})
+		require.NoError(t, err)
+		require.NoError(t, h.(*handle).Write(ctx, &fuse.WriteRequest{Data: []byte("foo\n")}, &fuse.WriteResponse{}))
+		require.NoError(t, h.(*handle).Release(ctx, &fuse.ReleaseRequest{}))
+	}
+
+	root, err := filesystem.Root()
+	require.NoError(t, err)
+	node, err := root.(*directory).Lookup(ctx, statsFile)
+	require.NoError(t, err)
+	var attr fuse.Attr
+	require.NoError(t, node.Attr(ctx, &attr))
+	h, err := node.(fs.NodeOpener).Open(ctx, &fuse.OpenRequest{Flags: fuse.OpenReadOnly}, &fuse.OpenResponse{})
+	require.NoError(t, err)
+	data, err := h.(fs.HandleReadAller).ReadAll(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(len(data)), attr.Size)
+	var stats Stats
+	require.NoError(t, json.Unmarshal(data, &stats))
+	require.Equal(t, int64(2), stats.Writes)
+	require.Equal(t, int64(8), stats.BytesWritten)
+	require.Equal(t, int64(0), stats.Reads)
+	require.Equal(t, int64(1), stats.Lookups)
+
+	dirents, err := root.(*directory).readRepos(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []fuse.Dirent{{Name: "repo", Type: fuse.DT_Dir}, {Name: statsFile, Type: fuse.DT_File}}, dirents)
+}
@@ -0,0 +1,148 @@
+package fuse
+
+import (
+	"bytes"
+	"path"
+	"strings"
+	"syscall"
+	"time"
+
+	"bazil.org/fuse"
+	"bazil.org/fuse/fs"
+	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errorutil"
+	"github.com/pachyderm/pachyderm/src/client/pkg/loglevel"
+	"go.pedge.io/lion/proto"
+	"golang.org/x/net/context"
+)
+
+// PFS has no symlinks, so a symlink is stored as a regular file holding its
+// target, named with symlinkSuffix after the link's name. Listings and lookups
+// recognize links by name alone, without reading them, and strip the suffix.
+const symlinkSuffix = ".__pfs_symlink__"
+
+// maxSymlinkSize is the size of the largest symlink file, targets are at
+// most PATH_MAX long.
+const maxSymlinkSize = 4096
+
+// Symlinks used to be stored under the link's own name, holding
+// legacySymlinkPrefix followed by the target. Lookup still reads regular files
+// small enough to be one of those, listings show them as regular files.
+const legacySymlinkPrefix = "__pfs_symlink__:"
+
+// isLegacySymlinkSize returns true if a file of size bytes can hold a symlink
+// in the legacy format.
+func isLegacySymlinkSize(size uint64) bool {
+	return size > uint64(len(legacySymlinkPrefix)) && size <= uint64(len(legacySymlinkPrefix)+maxSymlinkSize)
+}
+
+// isSymlinkPath returns true if the file at p in PFS holds a symlink.
+func isSymlinkPath(p string) bool {
+	return strings.HasSuffix(p, symlinkSuffix)
+}
+
+func (d *directory) Symlink(ctx context.Context, request *fuse.SymlinkRequest) (result fs.Node, retErr error) {
+	defer d.fs.observe("DirectorySymlink", time.Now(), &retErr)
+	defer func() {
+		if retErr != nil {
+			protolion.Error(&DirectorySymlink{&d.Node, request.NewName, request.Target, getNode(result), errorutil.String(retErr)})
+		} else if loglevel.DebugEnabled() {
+			protolion.Debug(&DirectorySymlink{&d.Node, request.NewName, request.Target, getNode(result), errorutil.String(retErr)})
+		}
+	}()
+	if d.File.Commit.ID == "" || !d.Write {
+		return nil, fuse.EPERM
+	}
+	directory := d.copy()
+	directory.File.Path = path.Join(directory.File.Path, request.NewName)
+	d.fs.removeNegativeEntry(directory.File)
+	d.fs.removeDirCacheEntry(d.File)
+	directory.File.Path += symlinkSuffix
+	w, err := d.fs.apiClient.PutFileWriter(
+		directory.File.Commit.Repo.Name,
+		directory.File.Commit.ID,
+		directory.File.Path,
+		pfsclient.Delimiter_NONE,
+		d.fs.handleID,
+	)
+	if err != nil {
+		return nil, rpcError(err, "PutFile", directory.File)
+	}
+	if _, err := w.Write([]byte(request.Target)); err != nil {
+		w.Close()
+		return nil, rpcError(err, "PutFile", directory.File)
+	}
+	if err := w.Close(); err != nil {
+		return nil, rpcError(err, "PutFile", directory.File)
+	}
+	localResult := &file{
+		directory: *directory,
+		size:      int64(len(request.Target)),
+		symlink:   true,
+	}
+	d.fs.addNode(&localResult.Node)
+	return localResult, nil
+}
+
+func (f *file) Readlink(ctx context.Context, request *fuse.ReadlinkRequest) (result string, retErr error) {
+	defer f.fs.observe("FileReadlink", time.Now(), &retErr)
+	defer func() {
+		if retErr != nil {
+			protolion.Error(&FileReadlink{&f.Node, result, errorutil.String(retErr)})
+		} else if loglevel.DebugEnabled() {
+			protolion.Debug(&FileReadlink{&f.Node, result, errorutil.String(retErr)})
+		}
+	}()
+	if !f.symlink {
+		return "", fuse.Errno(syscall.EINVAL)
+	}
+	if !isSymlinkPath(f.File.Path) {
+		target, ok, err := f.readLegacySymlink()
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			// It was overwritten since it was looked up.
+			return "", fuse.Errno(syscall.EINVAL)
+		}
+		return target, nil
+	}
+	var buffer bytes.Buffer
+	if err := f.fs.apiClient.GetFileUnsafe(
+		f.File.Commit.Repo.Name,
+		f.File.Commit.ID,
+		f.File.Path,
+		0,
+		int64(maxSymlinkSize),
+		f.fs.getFromCommitID(f.getRepoOrAliasName()),
+		f.Shard,
+		f.fs.handleID,
+		&buffer,
+	); err != nil {
+		return "", rpcError(err, "GetFile", f.File)
+	}
+	return buffer.String(), nil
+}
+
+// readLegacySymlink returns the target of the symlink d's file holds in the
+// legacy format, ok is false if the file isn't such a symlink.
+func (d *directory) readLegacySymlink() (target string, ok bool, retErr error) {
+	var buffer bytes.Buffer
+	if err := d.fs.apiClient.GetFileUnsafe(
+		d.File.Commit.Repo.Name,
+		d.File.Commit.ID,
+		d.File.Path,
+		0,
+		int64(len(legacySymlinkPrefix)+maxSymlinkSize),
+		d.fs.getFromCommitID(d.getRepoOrAliasName()),
+		d.Shard,
+		d.fs.handleID,
+		&buffer,
+	); err != nil {
+		return "", false, rpcError(err, "GetFile", d.File)
+	}
+	if !strings.HasPrefix(buffer.String(), legacySymlinkPrefix) {
+		return "", false, nil
+	}
+	return strings.TrimPrefix(buffer.String(), legacySymlinkPrefix), true, nil
+}
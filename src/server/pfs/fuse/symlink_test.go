@@ -0,0 +1,178 @@
+package fuse
+
+import (
+	"io"
+	"os"
+	"sort"
+	"testing"
+
+	"bazil.org/fuse"
+	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"go.pedge.io/pb/go/google/protobuf"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// listMemoryAPIClient is a memoryAPIClient which also lists its files, by
+// name, fails to delete files that don't exist, and counts GetFile calls.
+type listMemoryAPIClient struct {
+	*memoryAPIClient
+	getFiles int
+}
+
+func newListMemoryAPIClient() *listMemoryAPIClient {
+	return &listMemoryAPIClient{memoryAPIClient: newMemoryAPIClient()}
+}
+
+func (c *listMemoryAPIClient) GetFile(ctx context.Context, request *pfsclient.GetFileRequest, opts ...grpc.CallOption) (pfsclient.API_GetFileClient, error) {
+	c.getFiles++
+	return c.memoryAPIClient.GetFile(ctx, request, opts...)
+}
+
+func (c *listMemoryAPIClient) DeleteFile(ctx context.Context, request *pfsclient.DeleteFileRequest, opts ...grpc.CallOption) (*google_protobuf.Empty, error) {
+	if _, ok := c.files[request.File.Path]; !ok {
+		return nil, grpc.Errorf(codes.NotFound, "file %s not found", request.File.Path)
+	}
+	return c.memoryAPIClient.DeleteFile(ctx, request, opts...)
+}
+
+func (c *listMemoryAPIClient) ListFileStream(ctx context.Context, request *pfsclient.ListFileRequest, opts ...grpc.CallOption) (pfsclient.API_ListFileStreamClient, error) {
+	var fileInfos []*pfsclient.FileInfo
+	for path, data := range c.files {
+		fileInfos = append(fileInfos, &pfsclient.FileInfo{
+			File:      &pfsclient.File{Commit: request.File.Commit, Path: path},
+			FileType:  pfsclient.FileType_FILE_TYPE_REGULAR,
+			SizeBytes: uint64(len(data)),
+		})
+	}
+	sort.Sort(fileInfosByPath(fileInfos))
+	return &memoryListFileStreamClient{fileInfos: fileInfos}, nil
+}
+
+type fileInfosByPath []*pfsclient.FileInfo
+
+func (s fileInfosByPath) Len() int           { return len(s) }
+func (s fileInfosByPath) Less(i, j int) bool { return s[i].File.Path < s[j].File.Path }
+func (s fileInfosByPath) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+type memoryListFileStreamClient struct {
+	grpc.ClientStream
+	fileInfos []*pfsclient.FileInfo
+}
+
+func (c *memoryListFileStreamClient) Recv() (*pfsclient.FileInfo, error) {
+	if len(c.fileInfos) == 0 {
+		return nil, io.EOF
+	}
+	fileInfo := c.fileInfos[0]
+	c.fileInfos = c.fileInfos[1:]
+	return fileInfo, nil
+}
+
+func newSymlinkTestDirectory(apiClient pfsclient.APIClient, write bool) *directory {
+	commit := &pfsclient.Commit{Repo: &pfsclient.Repo{Name: "repo"}, ID: "commit"}
+	return &directory{
+		fs:   newFilesystem(apiClient, nil, []*CommitMount{{Commit: commit}}),
+		Node: Node{File: &pfsclient.File{Commit: commit}, Write: write},
+	}
+}
+
+func TestSymlinkRoundTrip(t *testing.T) {
+	apiClient := newListMemoryAPIClient()
+	apiClient.files["run-42"] = []byte("foo\n")
+	apiClient.files["notes"] = []byte("x")
+	ctx := context.Background()
+	_, err := newSymlinkTestDirectory(apiClient, true).Symlink(ctx, &fuse.SymlinkRequest{NewName: "latest", Target: "run-42"})
+	require.NoError(t, err)
+
+	// Read back as another mount would, through a read-only commit. Links
+	// are recognized without reading any file.
+	d := newSymlinkTestDirectory(apiClient, false)
+	dirents, err := d.ReadDirAll(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []fuse.Dirent{
+		{Name: "latest", Type: fuse.DT_Link},
+		{Name: "notes", Type: fuse.DT_File},
+		{Name: "run-42", Type: fuse.DT_File},
+	}, dirents)
+	require.Equal(t, 0, apiClient.getFiles)
+	node, err := d.Lookup(ctx, "latest")
+	require.NoError(t, err)
+	require.Equal(t, 0, apiClient.getFiles)
+	var attr fuse.Attr
+	require.NoError(t, node.Attr(ctx, &attr))
+	require.Equal(t, os.ModeSymlink|0777, attr.Mode)
+	target, err := node.(*file).Readlink(ctx, &fuse.ReadlinkRequest{})
+	require.NoError(t, err)
+	require.Equal(t, "run-42", target)
+
+	node, err = d.Lookup(ctx, "notes")
+	require.NoError(t, err)
+	_, err = node.(*file).Readlink(ctx, &fuse.ReadlinkRequest{})
+	require.YesError(t, err)
+	// The file behind a link isn't visible under its own name
+	_, err = d.Lookup(ctx, "latest"+symlinkSuffix)
+	require.Equal(t, fuse.ENOENT, err)
+}
+
+func TestSymlinkLegacyFormat(t *testing.T) {
+	apiClient := newListMemoryAPIClient()
+	apiClient.files["latest"] = []byte(legacySymlinkPrefix + "run-42")
+	apiClient.files["notes"] = []byte("x")
+	apiClient.files["report"] = []byte("not a symlink, but the size of one")
+	ctx := context.Background()
+	d := newSymlinkTestDirectory(apiClient, false)
+
+	node, err := d.Lookup(ctx, "latest")
+	require.NoError(t, err)
+	require.Equal(t, 1, apiClient.getFiles)
+	var attr fuse.Attr
+	require.NoError(t, node.Attr(ctx, &attr))
+	require.Equal(t, os.ModeSymlink|0777, attr.Mode)
+	require.Equal(t, uint64(len("run-42")), attr.Size)
+	target, err := node.(*file).Readlink(ctx, &fuse.ReadlinkRequest{})
+	require.NoError(t, err)
+	require.Equal(t, "run-42", target)
+
+	// Files too small to hold a link aren't read
+	node, err = d.Lookup(ctx, "notes")
+	require.NoError(t, err)
+	require.Equal(t, 2, apiClient.getFiles)
+	_, err = node.(*file).Readlink(ctx, &fuse.ReadlinkRequest{})
+	require.YesError(t, err)
+	node, err = d.Lookup(ctx, "report")
+	require.NoError(t, err)
+	_, err = node.(*file).Readlink(ctx, &fuse.ReadlinkRequest{})
+	require.YesError(t, err)
+}
+
+func TestSymlinkRenameAndRemove(t *testing.T) {
+	apiClient := newListMemoryAPIClient()
+	ctx := context.Background()
+	d := newSymlinkTestDirectory(apiClient, true)
+	_, err := d.Symlink(ctx, &fuse.SymlinkRequest{NewName: "old", Target: "run-42"})
+	require.NoError(t, err)
+
+	require.NoError(t, d.Rename(ctx, &fuse.RenameRequest{OldName: "old", NewName: "new"}, d))
+	dirents, err := d.ReadDirAll(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []fuse.Dirent{{Name: "new", Type: fuse.DT_Link}}, dirents)
+	node, err := d.Lookup(ctx, "new")
+	require.NoError(t, err)
+	target, err := node.(*file).Readlink(ctx, &fuse.ReadlinkRequest{})
+	require.NoError(t, err)
+	require.Equal(t, "run-42", target)
+
+	require.NoError(t, d.Remove(ctx, &fuse.RemoveRequest{Name: "new"}))
+	require.Equal(t, 0, len(apiClient.files))
+	require.YesError(t, d.Remove(ctx, &fuse.RemoveRequest{Name: "new"}))
+}
+
+func TestSymlinkReadOnly(t *testing.T) {
+	apiClient := newListMemoryAPIClient()
+	_, err := newSymlinkTestDirectory(apiClient, false).Symlink(context.Background(), &fuse.SymlinkRequest{NewName: "latest", Target: "run-42"})
+	require.Equal(t, fuse.EPERM, err)
+	require.Equal(t, 0, len(apiClient.files))
+}
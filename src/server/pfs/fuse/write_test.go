@@ -0,0 +1,90 @@
+package fuse
+
+import (
+	"fmt"
+	"testing"
+
+	"bazil.org/fuse"
+	"github.com/golang/protobuf/proto"
+	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"go.pedge.io/pb/go/google/protobuf"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// putFileAPIClient answers PutFile with a stream which marshals and drops
+// each request, the rest of its methods panic.
+type putFileAPIClient struct {
+	pfsclient.APIClient
+}
+
+func (c putFileAPIClient) PutFile(ctx context.Context, opts ...grpc.CallOption) (pfsclient.API_PutFileClient, error) {
+	return &discardPutFileClient{}, nil
+}
+
+type discardPutFileClient struct {
+	grpc.ClientStream
+	requests int
+}
+
+func (c *discardPutFileClient) Send(request *pfsclient.PutFileRequest) error {
+	c.requests++
+	_, err := proto.Marshal(request)
+	return err
+}
+
+func (c *discardPutFileClient) CloseAndRecv() (*google_protobuf.Empty, error) {
+	return google_protobuf.EmptyInstance, nil
+}
+
+const benchmarkWriteLines = 10000
+
+func benchmarkLine(i int) []byte {
+	return []byte(fmt.Sprintf("%d,some,csv,row\n", i))
+}
+
+// BenchmarkWriteUnbuffered sends each line straight to PutFile, as handles
+// did before they buffered.
+func BenchmarkWriteUnbuffered(b *testing.B) {
+	fs := newFilesystem(putFileAPIClient{}, nil, nil)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		w, err := fs.apiClient.PutFileWriter("repo", "commit", "file", pfsclient.Delimiter_LINE, fs.handleID)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for j := 0; j < benchmarkWriteLines; j++ {
+			if _, err := w.Write(benchmarkLine(j)); err != nil {
+				b.Fatal(err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkWriteBuffered(b *testing.B) {
+	f := &file{
+		directory: directory{
+			fs:   newFilesystem(putFileAPIClient{}, nil, nil),
+			Node: Node{File: &pfsclient.File{Commit: &pfsclient.Commit{Repo: &pfsclient.Repo{Name: "repo"}, ID: "commit"}, Path: "file"}},
+		},
+	}
+	ctx := context.Background()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		h := f.newHandle(0)
+		var offset int64
+		for j := 0; j < benchmarkWriteLines; j++ {
+			request := &fuse.WriteRequest{Data: benchmarkLine(j), Offset: offset}
+			if err := h.Write(ctx, request, &fuse.WriteResponse{}); err != nil {
+				b.Fatal(err)
+			}
+			offset += int64(len(request.Data))
+		}
+		if err := h.Release(ctx, &fuse.ReleaseRequest{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
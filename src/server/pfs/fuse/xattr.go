@@ -0,0 +1,114 @@
+package fuse
+
+import (
+	"strconv"
+	"time"
+
+	"bazil.org/fuse"
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/errorutil"
+	"github.com/pachyderm/pachyderm/src/client/pkg/loglevel"
+	"go.pedge.io/lion/proto"
+	"go.pedge.io/proto/time"
+	"golang.org/x/net/context"
+)
+
+// The extended attributes nodes expose their PFS metadata through, they're
+// read only.
+const (
+	xattrCommitID       = "pfs.commit.id"
+	xattrRepoName       = "pfs.repo.name"
+	xattrFileSizeBytes  = "pfs.file.size_bytes"
+	xattrCommitFinished = "pfs.commit.finished"
+)
+
+// Files inherit these from directory.
+
+func (d *directory) Getxattr(ctx context.Context, request *fuse.GetxattrRequest, response *fuse.GetxattrResponse) (retErr error) {
+	defer d.fs.observe("DirectoryGetxattr", time.Now(), &retErr)
+	defer func() {
+		if retErr != nil {
+			protolion.Error(&DirectoryGetxattr{&d.Node, request.Name, errorutil.String(retErr)})
+		} else if loglevel.DebugEnabled() {
+			protolion.Debug(&DirectoryGetxattr{&d.Node, request.Name, errorutil.String(retErr)})
+		}
+	}()
+	if !d.hasXattr(request.Name) {
+		return fuse.ErrNoXattr
+	}
+	switch request.Name {
+	case xattrCommitID:
+		response.Xattr = []byte(d.File.Commit.ID)
+	case xattrRepoName:
+		response.Xattr = []byte(d.File.Commit.Repo.Name)
+	case xattrFileSizeBytes:
+		fileInfo, err := d.fs.apiClient.InspectFileUnsafe(
+			d.File.Commit.Repo.Name,
+			d.File.Commit.ID,
+			d.File.Path,
+			d.fs.getFromCommitID(d.getRepoOrAliasName()),
+			d.Shard,
+			d.fs.handleID,
+		)
+		if err != nil {
+			return rpcError(err, "InspectFile", d.File)
+		}
+		response.Xattr = []byte(strconv.FormatUint(fileInfo.SizeBytes, 10))
+	case xattrCommitFinished:
+		commitInfo, err := d.fs.apiClient.InspectCommit(d.File.Commit.Repo.Name, d.File.Commit.ID)
+		if err != nil {
+			return rpcError(err, "InspectCommit", client.NewFile(d.File.Commit.Repo.Name, d.File.Commit.ID, ""))
+		}
+		// Unfinished commits have an empty value.
+		if commitInfo.Finished != nil {
+			response.Xattr = []byte(prototime.TimestampToTime(commitInfo.Finished).UTC().Format(time.RFC3339Nano))
+		}
+	}
+	return nil
+}
+
+func (d *directory) Listxattr(ctx context.Context, request *fuse.ListxattrRequest, response *fuse.ListxattrResponse) (retErr error) {
+	defer d.fs.observe("DirectoryListxattr", time.Now(), &retErr)
+	var names []string
+	defer func() {
+		if retErr != nil {
+			protolion.Error(&DirectoryListxattr{&d.Node, names, errorutil.String(retErr)})
+		} else if loglevel.DebugEnabled() {
+			protolion.Debug(&DirectoryListxattr{&d.Node, names, errorutil.String(retErr)})
+		}
+	}()
+	for _, name := range []string{xattrCommitID, xattrRepoName, xattrFileSizeBytes, xattrCommitFinished} {
+		if d.hasXattr(name) {
+			names = append(names, name)
+		}
+	}
+	response.Append(names...)
+	return nil
+}
+
+func (d *directory) Setxattr(ctx context.Context, request *fuse.SetxattrRequest) (retErr error) {
+	defer d.fs.observe("DirectorySetxattr", time.Now(), &retErr)
+	defer func() {
+		if retErr != nil {
+			protolion.Error(&DirectorySetxattr{&d.Node, request.Name, errorutil.String(retErr)})
+		} else if loglevel.DebugEnabled() {
+			protolion.Debug(&DirectorySetxattr{&d.Node, request.Name, errorutil.String(retErr)})
+		}
+	}()
+	return fuse.EPERM
+}
+
+// hasXattr returns whether the node has the attribute name, the root has
+// none, repos only have their name and the commit directories themselves
+// have no size.
+func (d *directory) hasXattr(name string) bool {
+	switch name {
+	case xattrRepoName:
+		return d.File.Commit.Repo.Name != ""
+	case xattrCommitID, xattrCommitFinished:
+		return d.File.Commit.ID != ""
+	case xattrFileSizeBytes:
+		return d.File.Commit.ID != "" && d.File.Path != ""
+	}
+	return false
+}
@@ -719,6 +719,81 @@ func (a *apiServer) ListFile(ctx context.Context, request *pfs.ListFileRequest)
 	}, nil
 }
 
+// ListFileStream is like ListFile but sends each info as soon as a shard
+// streams it. Regular files live in a single shard, directories can be in
+// several, so only the first info for each directory is sent, as ListFile
+// only keeps the first.
+func (a *apiServer) ListFileStream(request *pfs.ListFileRequest, apiListFileStreamServer pfs.API_ListFileStreamServer) (retErr error) {
+	defer func(start time.Time) { a.Log(request, google_protobuf.EmptyInstance, retErr, time.Since(start)) }(time.Now())
+	a.versionLock.RLock()
+	defer a.versionLock.RUnlock()
+
+	ctx, done := a.getVersionContext(apiListFileStreamServer.Context())
+	defer close(done)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	clientConns, err := a.router.GetAllClientConns(a.version)
+	if err != nil {
+		return err
+	}
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	seenDirectories := make(map[string]bool)
+	errCh := make(chan error, 1)
+	for _, clientConn := range clientConns {
+		defer clientConn.Close()
+		wg.Add(1)
+		go func(clientConn *grpc.ClientConn) {
+			defer wg.Done()
+			err := func() error {
+				listFileStreamClient, err := pfs.NewInternalAPIClient(clientConn).ListFileStream(ctx, request)
+				if err != nil {
+					return err
+				}
+				for {
+					fileInfo, err := listFileStreamClient.Recv()
+					if err == io.EOF {
+						return nil
+					}
+					if err != nil {
+						return err
+					}
+					if err := func() error {
+						lock.Lock()
+						defer lock.Unlock()
+						if fileInfo.FileType == pfs.FileType_FILE_TYPE_DIR {
+							if seenDirectories[fileInfo.File.Path] {
+								return nil
+							}
+							seenDirectories[fileInfo.File.Path] = true
+						}
+						return apiListFileStreamServer.Send(fileInfo)
+					}(); err != nil {
+						return err
+					}
+				}
+			}()
+			if err != nil {
+				select {
+				case errCh <- err:
+					// error reported
+					cancel()
+				default:
+					// not the first error
+				}
+			}
+		}(clientConn)
+	}
+	wg.Wait()
+	select {
+	case err := <-errCh:
+		return err
+	default:
+	}
+	return nil
+}
+
 func (a *apiServer) DeleteFile(ctx context.Context, request *pfs.DeleteFileRequest) (response *google_protobuf.Empty, retErr error) {
 	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
 	a.versionLock.RLock()
@@ -13,6 +13,7 @@ import (
 	"go.pedge.io/proto/stream"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 
 	"github.com/pachyderm/pachyderm/src/client"
@@ -407,7 +408,12 @@ func (a *internalAPIServer) InspectFile(ctx context.Context, request *pfs.Inspec
 	if err != nil {
 		return nil, err
 	}
-	return a.driver.InspectFile(request.File, request.Shard, request.FromCommit, shard, request.Unsafe, request.Handle)
+	fileInfo, err := a.driver.InspectFile(request.File, request.Shard, request.FromCommit, shard, request.Unsafe, request.Handle)
+	if _, ok := err.(*pfsserver.ErrFileNotFound); ok {
+		// So that callers can tell a missing file from a failed call
+		return nil, grpcErrorf(codes.NotFound, "%s", err.Error())
+	}
+	return fileInfo, err
 }
 
 func (a *internalAPIServer) ListFile(ctx context.Context, request *pfs.ListFileRequest) (response *pfs.FileInfos, retErr error) {
@@ -457,6 +463,30 @@ func (a *internalAPIServer) ListFile(ctx context.Context, request *pfs.ListFileR
 	}, nil
 }
 
+func (a *internalAPIServer) ListFileStream(request *pfs.ListFileRequest, apiListFileStreamServer pfs.InternalAPI_ListFileStreamServer) (retErr error) {
+	defer func(start time.Time) { a.Log(request, nil, retErr, time.Since(start)) }(time.Now())
+	version, err := a.getVersion(apiListFileStreamServer.Context())
+	if err != nil {
+		return err
+	}
+	shards, err := a.router.GetShards(version)
+	if err != nil {
+		return err
+	}
+	// Unlike ListFile the shards are listed one after the other, so that
+	// each info can be sent as soon as the driver builds it.
+	for shard := range shards {
+		if err := a.driver.ListFileStream(request.File, request.Shard,
+			request.FromCommit, shard, request.Recurse, request.Unsafe, request.Handle,
+			apiListFileStreamServer.Send); err != nil {
+			if _, ok := err.(*pfsserver.ErrFileNotFound); !ok {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (a *internalAPIServer) DeleteFile(ctx context.Context, request *pfs.DeleteFileRequest) (response *google_protobuf.Empty, retErr error) {
 	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
 	version, err := a.getVersion(ctx)
@@ -16,6 +16,7 @@ import (
 
 	"go.pedge.io/proto/server"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 
 	pclient "github.com/pachyderm/pachyderm/src/client"
 	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
@@ -529,6 +530,42 @@ func TestListFileTwoCommits(t *testing.T) {
 	require.Equal(t, 2*numFiles, len(fileInfos))
 }
 
+func TestListFileStream(t *testing.T) {
+	t.Parallel()
+	client, _ := getClientAndServer(t)
+
+	repo := "test"
+	require.NoError(t, client.CreateRepo(repo))
+	commit, err := client.StartCommit(repo, "", "")
+	require.NoError(t, err)
+	numFiles := 50
+	for i := 0; i < numFiles; i++ {
+		_, err = client.PutFile(repo, commit.ID, fmt.Sprintf("file%d", i), strings.NewReader("foo\n"))
+		require.NoError(t, err)
+		// the directories are spread over every shard their children are in
+		_, err = client.PutFile(repo, commit.ID, fmt.Sprintf("dir%d/file%d", i%5, i), strings.NewReader("foo\n"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, client.FinishCommit(repo, commit.ID))
+
+	fileInfos, err := client.ListFile(repo, commit.ID, "", "", nil, false)
+	require.NoError(t, err)
+	require.Equal(t, numFiles+5, len(fileInfos))
+	expected := make(map[string]pfsclient.FileType)
+	for _, fileInfo := range fileInfos {
+		expected[fileInfo.File.Path] = fileInfo.FileType
+	}
+	fileInfoCh, errCh := client.ListFileStream(context.Background(), repo, commit.ID, "", "", nil, false)
+	streamed := make(map[string]pfsclient.FileType)
+	for fileInfo := range fileInfoCh {
+		_, ok := streamed[fileInfo.File.Path]
+		require.False(t, ok)
+		streamed[fileInfo.File.Path] = fileInfo.FileType
+	}
+	require.NoError(t, <-errCh)
+	require.Equal(t, expected, streamed)
+}
+
 func TestPutSameFileInParallel(t *testing.T) {
 	t.Parallel()
 	client, _ := getClientAndServer(t)
@@ -699,6 +736,45 @@ func TestDeleteFile(t *testing.T) {
 	require.YesError(t, err)
 }
 
+func TestMoveFile(t *testing.T) {
+	t.Parallel()
+	client, _ := getClientAndServer(t)
+
+	repo := "test"
+	require.NoError(t, client.CreateRepo(repo))
+	commit, err := client.StartCommit(repo, "", "")
+	require.NoError(t, err)
+	handle := "handle"
+	for path, content := range map[string]string{"foo": "foo\n", "dir/a": "a\n", "dir/sub/b": "b\n", "bar": "old\n"} {
+		writer, err := client.PutFileWriter(repo, commit.ID, path, pfsclient.Delimiter_LINE, handle)
+		require.NoError(t, err)
+		_, err = writer.Write([]byte(content))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+	}
+	// A file replaces whatever was at its new path
+	require.NoError(t, client.MoveFile(repo, commit.ID, "foo", "bar", handle))
+	require.NoError(t, client.MoveFile(repo, commit.ID, "dir", "moved", handle))
+	err = client.MoveFile(repo, commit.ID, "missing", "other", handle)
+	require.YesError(t, err)
+	require.Equal(t, codes.NotFound, grpc.Code(err))
+	require.NoError(t, client.FinishCommit(repo, commit.ID))
+
+	for path, content := range map[string]string{"bar": "foo\n", "moved/a": "a\n", "moved/sub/b": "b\n"} {
+		var buffer bytes.Buffer
+		require.NoError(t, client.GetFile(repo, commit.ID, path, 0, 0, "", nil, &buffer))
+		require.Equal(t, content, buffer.String())
+	}
+	// Nothing is left at the old paths
+	for _, path := range []string{"foo", "dir", "dir/a", "dir/sub", "dir/sub/b"} {
+		_, err := client.InspectFile(repo, commit.ID, path, "", nil)
+		require.YesError(t, err)
+	}
+	fileInfos, err := client.ListFile(repo, commit.ID, "", "", nil, false)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(fileInfos))
+}
+
 func TestInspectDir(t *testing.T) {
 	t.Parallel()
 	client, _ := getClientAndServer(t)
@@ -0,0 +1,118 @@
+package clusterstatus
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/shard"
+	"github.com/pachyderm/pachyderm/src/server/pps/persist"
+	google_protobuf "go.pedge.io/pb/go/google/protobuf"
+	"go.pedge.io/pkg/time"
+	"golang.org/x/net/context"
+)
+
+const (
+	// RoutingSubsystem is the name of the sharder's SubsystemStatus.
+	RoutingSubsystem = "routing"
+	// PersistSubsystem is the name of the metadata store's SubsystemStatus.
+	PersistSubsystem = "persist"
+)
+
+// Get checks the sharder and the metadata store and returns the status of
+// both. A subsystem which can't be reached, or doesn't respond before ctx
+// is done, is reported as DOWN rather than failing the whole call.
+func Get(ctx context.Context, sharder shard.Sharder, persistClient persist.APIClient) *ClusterStatus {
+	return get(ctx, sharder, persistClient, pkgtime.NewSystemTimer())
+}
+
+func get(ctx context.Context, sharder shard.Sharder, persistClient persist.APIClient, timer pkgtime.Timer) *ClusterStatus {
+	routing := make(chan *SubsystemStatus, 1)
+	go func() {
+		routing <- check(ctx, RoutingSubsystem, timer, func() ([]string, error) {
+			return routingReasons(sharder)
+		})
+	}()
+	persistStatus := check(ctx, PersistSubsystem, timer, func() ([]string, error) {
+		_, err := persistClient.ListPipelineInfos(ctx, &persist.ListPipelineInfosRequest{})
+		return nil, err
+	})
+	result := &ClusterStatus{
+		Subsystems: []*SubsystemStatus{<-routing, persistStatus},
+	}
+	for _, subsystem := range result.Subsystems {
+		if subsystem.Health > result.Health {
+			result.Health = subsystem.Health
+		}
+	}
+	return result
+}
+
+// check runs f, which returns the reasons a subsystem is degraded, and
+// reports the subsystem as DOWN if f fails or doesn't return before ctx is
+// done.
+func check(ctx context.Context, name string, timer pkgtime.Timer, f func() ([]string, error)) *SubsystemStatus {
+	type checkResult struct {
+		reasons []string
+		err     error
+	}
+	start := timer.Now()
+	done := make(chan checkResult, 1)
+	go func() {
+		reasons, err := f()
+		done <- checkResult{reasons, err}
+	}()
+	result := &SubsystemStatus{Name: name}
+	select {
+	case checked := <-done:
+		switch {
+		case checked.err != nil:
+			result.Health = Health_DOWN
+			result.Reasons = []string{checked.err.Error()}
+		case len(checked.reasons) > 0:
+			result.Health = Health_DEGRADED
+			result.Reasons = checked.reasons
+		}
+	case <-ctx.Done():
+		result.Health = Health_DOWN
+		result.Reasons = []string{ctx.Err().Error()}
+	}
+	result.Latency = google_protobuf.DurationToProto(timer.Now().Sub(start))
+	return result
+}
+
+// routingReasons returns the reasons routing is degraded, an error means
+// it's down.
+func routingReasons(sharder shard.Sharder) ([]string, error) {
+	liveness, err := sharder.GetLiveness()
+	if err != nil {
+		return nil, err
+	}
+	if len(liveness.Servers) == 0 {
+		return nil, fmt.Errorf("no servers are registered")
+	}
+	var latestVersion int64
+	for _, members := range []map[string]*shard.MemberLiveness{liveness.Servers, liveness.Frontends} {
+		for _, member := range members {
+			if member.Version > latestVersion {
+				latestVersion = member.Version
+			}
+		}
+	}
+	var reasons []string
+	for kind, members := range map[string]map[string]*shard.MemberLiveness{
+		"server":   liveness.Servers,
+		"frontend": liveness.Frontends,
+	} {
+		for address, member := range members {
+			switch member.Status {
+			case shard.LivenessLagging, shard.LivenessNearExpiry:
+				reasons = append(reasons, fmt.Sprintf("%s %s is %s", kind, address, member.Status))
+			}
+			if member.Version != latestVersion {
+				reasons = append(reasons, fmt.Sprintf("%s %s is on version %d, not %d", kind, address, member.Version, latestVersion))
+			}
+		}
+	}
+	sort.Strings(reasons)
+	return reasons, nil
+}
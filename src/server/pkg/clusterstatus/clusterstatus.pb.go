@@ -0,0 +1,120 @@
+// Code generated by protoc-gen-go.
+// source: server/pkg/clusterstatus/clusterstatus.proto
+// DO NOT EDIT!
+
+/*
+Package clusterstatus is a generated protocol buffer package.
+
+It is generated from these files:
+	server/pkg/clusterstatus/clusterstatus.proto
+
+It has these top-level messages:
+	SubsystemStatus
+	ClusterStatus
+*/
+package clusterstatus
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import google_protobuf "go.pedge.io/pb/go/google/protobuf"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+const _ = proto.ProtoPackageIsVersion1
+
+type Health int32
+
+const (
+	Health_HEALTHY  Health = 0
+	Health_DEGRADED Health = 1
+	Health_DOWN     Health = 2
+)
+
+var Health_name = map[int32]string{
+	0: "HEALTHY",
+	1: "DEGRADED",
+	2: "DOWN",
+}
+var Health_value = map[string]int32{
+	"HEALTHY":  0,
+	"DEGRADED": 1,
+	"DOWN":     2,
+}
+
+func (x Health) String() string {
+	return proto.EnumName(Health_name, int32(x))
+}
+func (Health) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+type SubsystemStatus struct {
+	Name   string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Health Health `protobuf:"varint,2,opt,name=health,enum=clusterstatus.Health" json:"health,omitempty"`
+	// reasons explain why the subsystem isn't healthy.
+	Reasons []string `protobuf:"bytes,3,rep,name=reasons" json:"reasons,omitempty"`
+	// latency is how long checking the subsystem took.
+	Latency *google_protobuf.Duration `protobuf:"bytes,4,opt,name=latency" json:"latency,omitempty"`
+}
+
+func (m *SubsystemStatus) Reset()                    { *m = SubsystemStatus{} }
+func (m *SubsystemStatus) String() string            { return proto.CompactTextString(m) }
+func (*SubsystemStatus) ProtoMessage()               {}
+func (*SubsystemStatus) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *SubsystemStatus) GetLatency() *google_protobuf.Duration {
+	if m != nil {
+		return m.Latency
+	}
+	return nil
+}
+
+type ClusterStatus struct {
+	// health is the worst health of any subsystem.
+	Health     Health             `protobuf:"varint,1,opt,name=health,enum=clusterstatus.Health" json:"health,omitempty"`
+	Subsystems []*SubsystemStatus `protobuf:"bytes,2,rep,name=subsystems" json:"subsystems,omitempty"`
+}
+
+func (m *ClusterStatus) Reset()                    { *m = ClusterStatus{} }
+func (m *ClusterStatus) String() string            { return proto.CompactTextString(m) }
+func (*ClusterStatus) ProtoMessage()               {}
+func (*ClusterStatus) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *ClusterStatus) GetSubsystems() []*SubsystemStatus {
+	if m != nil {
+		return m.Subsystems
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*SubsystemStatus)(nil), "clusterstatus.SubsystemStatus")
+	proto.RegisterType((*ClusterStatus)(nil), "clusterstatus.ClusterStatus")
+	proto.RegisterEnum("clusterstatus.Health", Health_name, Health_value)
+}
+
+var fileDescriptor0 = []byte{
+	// 280 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x90, 0x4f, 0x4b, 0xc3, 0x30,
+	0x18, 0xc6, 0x4d, 0x5b, 0xda, 0xed, 0xad, 0xd3, 0x12, 0x10, 0xa2, 0x87, 0x51, 0x76, 0x2a, 0xe2,
+	0x5a, 0xe8, 0xee, 0xc2, 0xb0, 0xc5, 0x1e, 0x44, 0x21, 0x13, 0xc4, 0x63, 0x3a, 0x63, 0x27, 0x76,
+	0xcd, 0x48, 0x52, 0x61, 0x17, 0xbf, 0x8c, 0x5f, 0x54, 0xe8, 0x1f, 0xb4, 0x3b, 0xed, 0x96, 0x37,
+	0xef, 0xf3, 0xe4, 0xf9, 0x3d, 0x81, 0x1b, 0xc5, 0xe5, 0x17, 0x97, 0xd1, 0xee, 0xb3, 0x88, 0xd6,
+	0x65, 0xad, 0x34, 0x97, 0x4a, 0x33, 0x5d, 0xab, 0xe1, 0x14, 0xee, 0xa4, 0xd0, 0x02, 0x4f, 0x06,
+	0x97, 0x57, 0xd3, 0x42, 0x88, 0xa2, 0xe4, 0x51, 0xb3, 0xcc, 0xeb, 0xf7, 0xe8, 0xad, 0x96, 0x4c,
+	0x7f, 0x88, 0xaa, 0x95, 0xcf, 0x7e, 0x10, 0x9c, 0xaf, 0xea, 0x5c, 0xed, 0x95, 0xe6, 0xdb, 0x55,
+	0xe3, 0xc1, 0x18, 0xac, 0x8a, 0x6d, 0x39, 0x41, 0x3e, 0x0a, 0xc6, 0xb4, 0x39, 0xe3, 0x39, 0xd8,
+	0x1b, 0xce, 0x4a, 0xbd, 0x21, 0x86, 0x8f, 0x82, 0xb3, 0xf8, 0x22, 0x1c, 0x86, 0x67, 0xcd, 0x92,
+	0x76, 0x22, 0x4c, 0xc0, 0x91, 0x9c, 0x29, 0x51, 0x29, 0x62, 0xfa, 0x66, 0x30, 0xa6, 0xfd, 0x88,
+	0x17, 0xe0, 0x94, 0x4c, 0xf3, 0x6a, 0xbd, 0x27, 0x96, 0x8f, 0x02, 0x37, 0xbe, 0x0c, 0x5b, 0xc4,
+	0xb0, 0x47, 0x0c, 0x93, 0x0e, 0x91, 0xf6, 0xca, 0xd9, 0x37, 0x4c, 0xee, 0xda, 0xb8, 0x0e, 0xf1,
+	0x0f, 0x07, 0x1d, 0x83, 0x73, 0x0b, 0xa0, 0xfa, 0x92, 0x8a, 0x18, 0xbe, 0x19, 0xb8, 0xf1, 0xf4,
+	0xc0, 0x72, 0xf0, 0x0b, 0xf4, 0x9f, 0xe3, 0x7a, 0x0e, 0x76, 0xfb, 0x22, 0x76, 0xc1, 0xc9, 0xd2,
+	0xe5, 0xc3, 0x73, 0xf6, 0xea, 0x9d, 0xe0, 0x53, 0x18, 0x25, 0xe9, 0x3d, 0x5d, 0x26, 0x69, 0xe2,
+	0x21, 0x3c, 0x02, 0x2b, 0x79, 0x7a, 0x79, 0xf4, 0x8c, 0xdc, 0x6e, 0xaa, 0x2c, 0x7e, 0x07, 0x00,
+	0xc2, 0x39, 0xc4, 0xea, 0xba, 0x01, 0x00, 0x00,
+}
@@ -0,0 +1,111 @@
+package clusterstatus
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pkg/shard"
+	"github.com/pachyderm/pachyderm/src/server/pps/persist"
+	"go.pedge.io/pkg/time"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestGet(t *testing.T) {
+	now := time.Unix(1000, 0)
+	healthy := func(version int64) *shard.MemberLiveness {
+		return &shard.MemberLiveness{LastRefreshed: now, Version: version, Status: shard.LivenessHealthy}
+	}
+	for _, test := range []struct {
+		name    string
+		sharder *testSharder
+		persist *testPersist
+	}{
+		{
+			"healthy",
+			&testSharder{liveness: &shard.Liveness{
+				Servers:   map[string]*shard.MemberLiveness{"server0": healthy(3), "server1": healthy(3)},
+				Frontends: map[string]*shard.MemberLiveness{"frontend": healthy(3)},
+			}},
+			&testPersist{},
+		},
+		{
+			"degraded",
+			&testSharder{liveness: &shard.Liveness{
+				Servers: map[string]*shard.MemberLiveness{
+					"server0": healthy(3),
+					"server1": {LastRefreshed: now.Add(-12 * time.Second), Version: 3, Status: shard.LivenessLagging},
+					"server2": {LastRefreshed: now.Add(-16 * time.Second), Version: 2, Status: shard.LivenessNearExpiry},
+				},
+				Frontends: map[string]*shard.MemberLiveness{"frontend": healthy(2)},
+			}},
+			&testPersist{},
+		},
+		{
+			"down",
+			&testSharder{hang: true},
+			&testPersist{err: fmt.Errorf("rethinkdb: connection refused")},
+		},
+		{
+			"no_servers",
+			&testSharder{liveness: &shard.Liveness{
+				Frontends: map[string]*shard.MemberLiveness{"frontend": healthy(3)},
+			}},
+			&testPersist{},
+		},
+	} {
+		test.sharder.release = make(chan struct{})
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		timer := pkgtime.NewFakeTimer()
+		timer.Set(now.Unix(), 0)
+		status := get(ctx, test.sharder, test.persist, timer)
+		cancel()
+		close(test.sharder.release)
+		encoded, err := (&jsonpb.Marshaler{Indent: "  "}).MarshalToString(status)
+		require.NoError(t, err)
+		path := filepath.Join("testdata", test.name+".json")
+		if *updateGolden {
+			require.NoError(t, ioutil.WriteFile(path, []byte(encoded+"\n"), 0644))
+		}
+		golden, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, string(golden), encoded+"\n", test.name)
+	}
+}
+
+type testSharder struct {
+	shard.Sharder
+	liveness *shard.Liveness
+	// hang makes GetLiveness block until release is closed, like a sharder
+	// whose discovery service is unreachable.
+	hang    bool
+	release chan struct{}
+}
+
+func (s *testSharder) GetLiveness() (*shard.Liveness, error) {
+	if s.hang {
+		<-s.release
+		return nil, fmt.Errorf("released")
+	}
+	return s.liveness, nil
+}
+
+type testPersist struct {
+	persist.APIClient
+	err error
+}
+
+func (p *testPersist) ListPipelineInfos(ctx context.Context, request *persist.ListPipelineInfosRequest, opts ...grpc.CallOption) (*persist.PipelineInfos, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &persist.PipelineInfos{}, nil
+}
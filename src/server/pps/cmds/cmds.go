@@ -204,8 +204,9 @@ Examples:
 	}
 	listJob.Flags().StringVarP(&pipelineName, "pipeline", "p", "", "Limit to jobs made by pipeline.")
 
+	var follow bool
 	getLogs := &cobra.Command{
-		Use:   "get-logs job-id",
+		Use:   "get-logs [-f] job-id",
 		Short: "Return logs from a job.",
 		Long:  "Return logs from a job.",
 		Run: pkgcmd.RunFixedArgs(1, func(args []string) error {
@@ -213,9 +214,13 @@ Examples:
 			if err != nil {
 				return err
 			}
+			if follow {
+				return client.FollowLogs(args[0], os.Stdout)
+			}
 			return client.GetLogs(args[0], os.Stdout)
 		}),
 	}
+	getLogs.Flags().BoolVarP(&follow, "follow", "f", false, "Keep writing logs as the job produces them.")
 
 	pipeline := &cobra.Command{
 		Use:   "pipeline",
@@ -20,6 +20,10 @@ It has these top-level messages:
 	ListPipelineInfosRequest
 	UpdatePipelineStateRequest
 	Shard
+	ListJobRequest
+	DeleteJobInfosResponse
+	PruneJobsRequest
+	JobOutputs
 */
 package persist
 
@@ -60,6 +64,9 @@ type JobInfo struct {
 	PodsSucceeded uint64                      `protobuf:"varint,12,opt,name=pods_succeeded,json=podsSucceeded" json:"pods_succeeded,omitempty"`
 	PodsFailed    uint64                      `protobuf:"varint,13,opt,name=pods_failed,json=podsFailed" json:"pods_failed,omitempty"`
 	ShardModuli   []uint64                    `protobuf:"varint,14,rep,name=shard_moduli,json=shardModuli" json:"shard_moduli,omitempty"`
+	// finished_at is set when the job reaches JOB_SUCCESS or JOB_FAILURE
+	// through UpdateJobState.
+	FinishedAt *google_protobuf1.Timestamp `protobuf:"bytes,15,opt,name=finished_at,json=finishedAt" json:"finished_at,omitempty"`
 }
 
 func (m *JobInfo) Reset()                    { *m = JobInfo{} }
@@ -102,8 +109,16 @@ func (m *JobInfo) GetOutputCommit() *pfs.Commit {
 	return nil
 }
 
+func (m *JobInfo) GetFinishedAt() *google_protobuf1.Timestamp {
+	if m != nil {
+		return m.FinishedAt
+	}
+	return nil
+}
+
 type JobInfos struct {
-	JobInfo []*JobInfo `protobuf:"bytes,1,rep,name=job_info,json=jobInfo" json:"job_info,omitempty"`
+	JobInfo       []*JobInfo `protobuf:"bytes,1,rep,name=job_info,json=jobInfo" json:"job_info,omitempty"`
+	NextPageToken string     `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken" json:"next_page_token,omitempty"`
 }
 
 func (m *JobInfos) Reset()                    { *m = JobInfos{} }
@@ -155,6 +170,11 @@ type PipelineInfo struct {
 	Shard        uint64                         `protobuf:"varint,7,opt,name=shard" json:"shard,omitempty"`
 	State        pachyderm_pps.PipelineState    `protobuf:"varint,8,opt,name=state,enum=pachyderm.pps.PipelineState" json:"state,omitempty"`
 	RecentError  string                         `protobuf:"bytes,9,opt,name=recent_error,json=recentError" json:"recent_error,omitempty"`
+	// updated_at is set by UpdatePipelineInfo.
+	UpdatedAt *google_protobuf1.Timestamp `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt" json:"updated_at,omitempty"`
+	// revision is 1 when the pipeline is created and goes up by one each
+	// time it's updated.
+	Revision uint64 `protobuf:"varint,11,opt,name=revision" json:"revision,omitempty"`
 }
 
 func (m *PipelineInfo) Reset()                    { *m = PipelineInfo{} }
@@ -190,6 +210,13 @@ func (m *PipelineInfo) GetCreatedAt() *google_protobuf1.Timestamp {
 	return nil
 }
 
+func (m *PipelineInfo) GetUpdatedAt() *google_protobuf1.Timestamp {
+	if m != nil {
+		return m.UpdatedAt
+	}
+	return nil
+}
+
 type PipelineInfoChange struct {
 	Pipeline *PipelineInfo `protobuf:"bytes,1,opt,name=pipeline" json:"pipeline,omitempty"`
 	Removed  bool          `protobuf:"varint,2,opt,name=removed" json:"removed,omitempty"`
@@ -277,6 +304,102 @@ func (m *Shard) String() string            { return proto.CompactTextString(m) }
 func (*Shard) ProtoMessage()               {}
 func (*Shard) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
 
+type ListJobRequest struct {
+	Pipeline    *pachyderm_pps.Pipeline  `protobuf:"bytes,1,opt,name=pipeline" json:"pipeline,omitempty"`
+	InputCommit []*pfs.Commit            `protobuf:"bytes,2,rep,name=input_commit,json=inputCommit" json:"input_commit,omitempty"`
+	State       []pachyderm_pps.JobState `protobuf:"varint,3,rep,name=state,enum=pachyderm.pps.JobState" json:"state,omitempty"`
+	// Jobs created at or after created_after and before created_before, nil
+	// means unbounded.
+	CreatedAfter  *google_protobuf1.Timestamp `protobuf:"bytes,4,opt,name=created_after,json=createdAfter" json:"created_after,omitempty"`
+	CreatedBefore *google_protobuf1.Timestamp `protobuf:"bytes,5,opt,name=created_before,json=createdBefore" json:"created_before,omitempty"`
+	// limit caps how many jobs are returned. 0 means every job unless state
+	// or a created_at bound is set, then it means 1000.
+	Limit int64 `protobuf:"varint,6,opt,name=limit" json:"limit,omitempty"`
+	// page_size limits how many jobs are returned and sets next_page_token
+	// when there are more. Paging requires pipeline and no input_commit.
+	PageSize int64 `protobuf:"varint,7,opt,name=page_size,json=pageSize" json:"page_size,omitempty"`
+	// page_token is the next_page_token of the previous page
+	PageToken string `protobuf:"bytes,8,opt,name=page_token,json=pageToken" json:"page_token,omitempty"`
+}
+
+func (m *ListJobRequest) Reset()                    { *m = ListJobRequest{} }
+func (m *ListJobRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListJobRequest) ProtoMessage()               {}
+func (*ListJobRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *ListJobRequest) GetPipeline() *pachyderm_pps.Pipeline {
+	if m != nil {
+		return m.Pipeline
+	}
+	return nil
+}
+
+func (m *ListJobRequest) GetInputCommit() []*pfs.Commit {
+	if m != nil {
+		return m.InputCommit
+	}
+	return nil
+}
+
+func (m *ListJobRequest) GetCreatedAfter() *google_protobuf1.Timestamp {
+	if m != nil {
+		return m.CreatedAfter
+	}
+	return nil
+}
+
+func (m *ListJobRequest) GetCreatedBefore() *google_protobuf1.Timestamp {
+	if m != nil {
+		return m.CreatedBefore
+	}
+	return nil
+}
+
+type DeleteJobInfosResponse struct {
+	// job_infos is how many job infos were deleted, a job that was already
+	// gone isn't counted.
+	JobInfos uint64 `protobuf:"varint,1,opt,name=job_infos,json=jobInfos" json:"job_infos,omitempty"`
+}
+
+func (m *DeleteJobInfosResponse) Reset()                    { *m = DeleteJobInfosResponse{} }
+func (m *DeleteJobInfosResponse) String() string            { return proto.CompactTextString(m) }
+func (*DeleteJobInfosResponse) ProtoMessage()               {}
+func (*DeleteJobInfosResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+type PruneJobsRequest struct {
+	CreatedBefore   *google_protobuf1.Timestamp `protobuf:"bytes,1,opt,name=created_before,json=createdBefore" json:"created_before,omitempty"`
+	KeepPerPipeline uint64                      `protobuf:"varint,2,opt,name=keep_per_pipeline,json=keepPerPipeline" json:"keep_per_pipeline,omitempty"`
+	DryRun          bool                        `protobuf:"varint,3,opt,name=dry_run,json=dryRun" json:"dry_run,omitempty"`
+}
+
+func (m *PruneJobsRequest) Reset()                    { *m = PruneJobsRequest{} }
+func (m *PruneJobsRequest) String() string            { return proto.CompactTextString(m) }
+func (*PruneJobsRequest) ProtoMessage()               {}
+func (*PruneJobsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+func (m *PruneJobsRequest) GetCreatedBefore() *google_protobuf1.Timestamp {
+	if m != nil {
+		return m.CreatedBefore
+	}
+	return nil
+}
+
+type JobOutputs struct {
+	JobOutput []*JobOutput `protobuf:"bytes,1,rep,name=job_output,json=jobOutput" json:"job_output,omitempty"`
+}
+
+func (m *JobOutputs) Reset()                    { *m = JobOutputs{} }
+func (m *JobOutputs) String() string            { return proto.CompactTextString(m) }
+func (*JobOutputs) ProtoMessage()               {}
+func (*JobOutputs) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+
+func (m *JobOutputs) GetJobOutput() []*JobOutput {
+	if m != nil {
+		return m.JobOutput
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*JobInfo)(nil), "pachyderm.pps.persist.JobInfo")
 	proto.RegisterType((*JobInfos)(nil), "pachyderm.pps.persist.JobInfos")
@@ -289,6 +412,10 @@ func init() {
 	proto.RegisterType((*ListPipelineInfosRequest)(nil), "pachyderm.pps.persist.ListPipelineInfosRequest")
 	proto.RegisterType((*UpdatePipelineStateRequest)(nil), "pachyderm.pps.persist.UpdatePipelineStateRequest")
 	proto.RegisterType((*Shard)(nil), "pachyderm.pps.persist.Shard")
+	proto.RegisterType((*ListJobRequest)(nil), "pachyderm.pps.persist.ListJobRequest")
+	proto.RegisterType((*DeleteJobInfosResponse)(nil), "pachyderm.pps.persist.DeleteJobInfosResponse")
+	proto.RegisterType((*PruneJobsRequest)(nil), "pachyderm.pps.persist.PruneJobsRequest")
+	proto.RegisterType((*JobOutputs)(nil), "pachyderm.pps.persist.JobOutputs")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -308,21 +435,46 @@ type APIClient interface {
 	CreateJobInfo(ctx context.Context, in *JobInfo, opts ...grpc.CallOption) (*JobInfo, error)
 	InspectJob(ctx context.Context, in *pachyderm_pps.InspectJobRequest, opts ...grpc.CallOption) (*JobInfo, error)
 	// ordered by time, latest to earliest
-	ListJobInfos(ctx context.Context, in *pachyderm_pps.ListJobRequest, opts ...grpc.CallOption) (*JobInfos, error)
+	ListJobInfos(ctx context.Context, in *ListJobRequest, opts ...grpc.CallOption) (*JobInfos, error)
+	// GetJobInfosByCommit returns the jobs which have the commit among their
+	// inputs, latest first.
+	GetJobInfosByCommit(ctx context.Context, in *pfs.Commit, opts ...grpc.CallOption) (*JobInfos, error)
 	// should only be called when rolling back if a Job does not start!
-	DeleteJobInfo(ctx context.Context, in *pachyderm_pps.Job, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
-	DeleteJobInfosForPipeline(ctx context.Context, in *pachyderm_pps.Pipeline, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
+	// A job's outputs and state live on its JobInfo, so they go with it.
+	DeleteJobInfo(ctx context.Context, in *pachyderm_pps.Job, opts ...grpc.CallOption) (*DeleteJobInfosResponse, error)
+	DeleteJobInfosForPipeline(ctx context.Context, in *pachyderm_pps.Pipeline, opts ...grpc.CallOption) (*DeleteJobInfosResponse, error)
+	// PruneJobs deletes old jobs, a few at a time so the table isn't held
+	// for long.
+	PruneJobs(ctx context.Context, in *PruneJobsRequest, opts ...grpc.CallOption) (*DeleteJobInfosResponse, error)
 	// JobOutput rpcs
 	CreateJobOutput(ctx context.Context, in *JobOutput, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
+	GetJobOutput(ctx context.Context, in *pachyderm_pps.Job, opts ...grpc.CallOption) (*JobOutput, error)
+	// GetJobOutputsByCommit returns the jobs which wrote to the commit,
+	// latest first.
+	GetJobOutputsByCommit(ctx context.Context, in *pfs.Commit, opts ...grpc.CallOption) (*JobOutputs, error)
 	// JobState rpcs
 	CreateJobState(ctx context.Context, in *JobState, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
+	// GetJobState returns the job's state without reading the rest of its
+	// info.
+	GetJobState(ctx context.Context, in *pachyderm_pps.Job, opts ...grpc.CallOption) (*JobState, error)
+	// UpdateJobState sets the state of a job, and its finished_at once it
+	// succeeds or fails, in a single atomic update. It errors rather than
+	// move a job out of JOB_SUCCESS or JOB_FAILURE, or back to JOB_PULLING.
+	UpdateJobState(ctx context.Context, in *JobState, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
 	// StartJob sets the state of the job to "running" if the state
 	// is currently "pulling".
 	// This API updates the job state in a transactional manner.
 	StartJob(ctx context.Context, in *pachyderm_pps.Job, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
+	// WatchJobStates streams the job's state, first the current one and then
+	// each time it changes, until the client goes away.
+	WatchJobStates(ctx context.Context, in *pachyderm_pps.Job, opts ...grpc.CallOption) (API_WatchJobStatesClient, error)
 	// Pipeline rpcs
 	CreatePipelineInfo(ctx context.Context, in *PipelineInfo, opts ...grpc.CallOption) (*PipelineInfo, error)
 	GetPipelineInfo(ctx context.Context, in *pachyderm_pps.Pipeline, opts ...grpc.CallOption) (*PipelineInfo, error)
+	// UpdatePipelineInfo replaces a pipeline's info if its revision is the
+	// revision stored, created_at is kept. It errors if the pipeline has been
+	// updated since, rather than overwrite that update.
+	UpdatePipelineInfo(ctx context.Context, in *PipelineInfo, opts ...grpc.CallOption) (*PipelineInfo, error)
 	// ordered by time, latest to earliest
 	ListPipelineInfos(ctx context.Context, in *ListPipelineInfosRequest, opts ...grpc.CallOption) (*PipelineInfos, error)
 	DeletePipelineInfo(ctx context.Context, in *pachyderm_pps.Pipeline, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
@@ -361,7 +513,7 @@ func (c *aPIClient) InspectJob(ctx context.Context, in *pachyderm_pps.InspectJob
 	return out, nil
 }
 
-func (c *aPIClient) ListJobInfos(ctx context.Context, in *pachyderm_pps.ListJobRequest, opts ...grpc.CallOption) (*JobInfos, error) {
+func (c *aPIClient) ListJobInfos(ctx context.Context, in *ListJobRequest, opts ...grpc.CallOption) (*JobInfos, error) {
 	out := new(JobInfos)
 	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/ListJobInfos", in, out, c.cc, opts...)
 	if err != nil {
@@ -370,8 +522,17 @@ func (c *aPIClient) ListJobInfos(ctx context.Context, in *pachyderm_pps.ListJobR
 	return out, nil
 }
 
-func (c *aPIClient) DeleteJobInfo(ctx context.Context, in *pachyderm_pps.Job, opts ...grpc.CallOption) (*google_protobuf.Empty, error) {
-	out := new(google_protobuf.Empty)
+func (c *aPIClient) GetJobInfosByCommit(ctx context.Context, in *pfs.Commit, opts ...grpc.CallOption) (*JobInfos, error) {
+	out := new(JobInfos)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/GetJobInfosByCommit", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) DeleteJobInfo(ctx context.Context, in *pachyderm_pps.Job, opts ...grpc.CallOption) (*DeleteJobInfosResponse, error) {
+	out := new(DeleteJobInfosResponse)
 	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/DeleteJobInfo", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
@@ -379,8 +540,8 @@ func (c *aPIClient) DeleteJobInfo(ctx context.Context, in *pachyderm_pps.Job, op
 	return out, nil
 }
 
-func (c *aPIClient) DeleteJobInfosForPipeline(ctx context.Context, in *pachyderm_pps.Pipeline, opts ...grpc.CallOption) (*google_protobuf.Empty, error) {
-	out := new(google_protobuf.Empty)
+func (c *aPIClient) DeleteJobInfosForPipeline(ctx context.Context, in *pachyderm_pps.Pipeline, opts ...grpc.CallOption) (*DeleteJobInfosResponse, error) {
+	out := new(DeleteJobInfosResponse)
 	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/DeleteJobInfosForPipeline", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
@@ -388,6 +549,15 @@ func (c *aPIClient) DeleteJobInfosForPipeline(ctx context.Context, in *pachyderm
 	return out, nil
 }
 
+func (c *aPIClient) PruneJobs(ctx context.Context, in *PruneJobsRequest, opts ...grpc.CallOption) (*DeleteJobInfosResponse, error) {
+	out := new(DeleteJobInfosResponse)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/PruneJobs", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *aPIClient) CreateJobOutput(ctx context.Context, in *JobOutput, opts ...grpc.CallOption) (*google_protobuf.Empty, error) {
 	out := new(google_protobuf.Empty)
 	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/CreateJobOutput", in, out, c.cc, opts...)
@@ -397,6 +567,24 @@ func (c *aPIClient) CreateJobOutput(ctx context.Context, in *JobOutput, opts ...
 	return out, nil
 }
 
+func (c *aPIClient) GetJobOutput(ctx context.Context, in *pachyderm_pps.Job, opts ...grpc.CallOption) (*JobOutput, error) {
+	out := new(JobOutput)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/GetJobOutput", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) GetJobOutputsByCommit(ctx context.Context, in *pfs.Commit, opts ...grpc.CallOption) (*JobOutputs, error) {
+	out := new(JobOutputs)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/GetJobOutputsByCommit", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *aPIClient) CreateJobState(ctx context.Context, in *JobState, opts ...grpc.CallOption) (*google_protobuf.Empty, error) {
 	out := new(google_protobuf.Empty)
 	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/CreateJobState", in, out, c.cc, opts...)
@@ -406,6 +594,24 @@ func (c *aPIClient) CreateJobState(ctx context.Context, in *JobState, opts ...gr
 	return out, nil
 }
 
+func (c *aPIClient) GetJobState(ctx context.Context, in *pachyderm_pps.Job, opts ...grpc.CallOption) (*JobState, error) {
+	out := new(JobState)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/GetJobState", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) UpdateJobState(ctx context.Context, in *JobState, opts ...grpc.CallOption) (*google_protobuf.Empty, error) {
+	out := new(google_protobuf.Empty)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/UpdateJobState", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *aPIClient) StartJob(ctx context.Context, in *pachyderm_pps.Job, opts ...grpc.CallOption) (*google_protobuf.Empty, error) {
 	out := new(google_protobuf.Empty)
 	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/StartJob", in, out, c.cc, opts...)
@@ -415,6 +621,38 @@ func (c *aPIClient) StartJob(ctx context.Context, in *pachyderm_pps.Job, opts ..
 	return out, nil
 }
 
+func (c *aPIClient) WatchJobStates(ctx context.Context, in *pachyderm_pps.Job, opts ...grpc.CallOption) (API_WatchJobStatesClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_API_serviceDesc.Streams[0], c.cc, "/pachyderm.pps.persist.API/WatchJobStates", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &aPIWatchJobStatesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type API_WatchJobStatesClient interface {
+	Recv() (*JobState, error)
+	grpc.ClientStream
+}
+
+type aPIWatchJobStatesClient struct {
+	grpc.ClientStream
+}
+
+func (x *aPIWatchJobStatesClient) Recv() (*JobState, error) {
+	m := new(JobState)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *aPIClient) CreatePipelineInfo(ctx context.Context, in *PipelineInfo, opts ...grpc.CallOption) (*PipelineInfo, error) {
 	out := new(PipelineInfo)
 	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/CreatePipelineInfo", in, out, c.cc, opts...)
@@ -433,6 +671,15 @@ func (c *aPIClient) GetPipelineInfo(ctx context.Context, in *pachyderm_pps.Pipel
 	return out, nil
 }
 
+func (c *aPIClient) UpdatePipelineInfo(ctx context.Context, in *PipelineInfo, opts ...grpc.CallOption) (*PipelineInfo, error) {
+	out := new(PipelineInfo)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/UpdatePipelineInfo", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *aPIClient) ListPipelineInfos(ctx context.Context, in *ListPipelineInfosRequest, opts ...grpc.CallOption) (*PipelineInfos, error) {
 	out := new(PipelineInfos)
 	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/ListPipelineInfos", in, out, c.cc, opts...)
@@ -452,7 +699,7 @@ func (c *aPIClient) DeletePipelineInfo(ctx context.Context, in *pachyderm_pps.Pi
 }
 
 func (c *aPIClient) SubscribePipelineInfos(ctx context.Context, in *SubscribePipelineInfosRequest, opts ...grpc.CallOption) (API_SubscribePipelineInfosClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_API_serviceDesc.Streams[0], c.cc, "/pachyderm.pps.persist.API/SubscribePipelineInfos", opts...)
+	stream, err := grpc.NewClientStream(ctx, &_API_serviceDesc.Streams[1], c.cc, "/pachyderm.pps.persist.API/SubscribePipelineInfos", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -528,21 +775,46 @@ type APIServer interface {
 	CreateJobInfo(context.Context, *JobInfo) (*JobInfo, error)
 	InspectJob(context.Context, *pachyderm_pps.InspectJobRequest) (*JobInfo, error)
 	// ordered by time, latest to earliest
-	ListJobInfos(context.Context, *pachyderm_pps.ListJobRequest) (*JobInfos, error)
+	ListJobInfos(context.Context, *ListJobRequest) (*JobInfos, error)
+	// GetJobInfosByCommit returns the jobs which have the commit among their
+	// inputs, latest first.
+	GetJobInfosByCommit(context.Context, *pfs.Commit) (*JobInfos, error)
 	// should only be called when rolling back if a Job does not start!
-	DeleteJobInfo(context.Context, *pachyderm_pps.Job) (*google_protobuf.Empty, error)
-	DeleteJobInfosForPipeline(context.Context, *pachyderm_pps.Pipeline) (*google_protobuf.Empty, error)
+	// A job's outputs and state live on its JobInfo, so they go with it.
+	DeleteJobInfo(context.Context, *pachyderm_pps.Job) (*DeleteJobInfosResponse, error)
+	DeleteJobInfosForPipeline(context.Context, *pachyderm_pps.Pipeline) (*DeleteJobInfosResponse, error)
+	// PruneJobs deletes old jobs, a few at a time so the table isn't held
+	// for long.
+	PruneJobs(context.Context, *PruneJobsRequest) (*DeleteJobInfosResponse, error)
 	// JobOutput rpcs
 	CreateJobOutput(context.Context, *JobOutput) (*google_protobuf.Empty, error)
+	GetJobOutput(context.Context, *pachyderm_pps.Job) (*JobOutput, error)
+	// GetJobOutputsByCommit returns the jobs which wrote to the commit,
+	// latest first.
+	GetJobOutputsByCommit(context.Context, *pfs.Commit) (*JobOutputs, error)
 	// JobState rpcs
 	CreateJobState(context.Context, *JobState) (*google_protobuf.Empty, error)
+	// GetJobState returns the job's state without reading the rest of its
+	// info.
+	GetJobState(context.Context, *pachyderm_pps.Job) (*JobState, error)
+	// UpdateJobState sets the state of a job, and its finished_at once it
+	// succeeds or fails, in a single atomic update. It errors rather than
+	// move a job out of JOB_SUCCESS or JOB_FAILURE, or back to JOB_PULLING.
+	UpdateJobState(context.Context, *JobState) (*google_protobuf.Empty, error)
 	// StartJob sets the state of the job to "running" if the state
 	// is currently "pulling".
 	// This API updates the job state in a transactional manner.
 	StartJob(context.Context, *pachyderm_pps.Job) (*google_protobuf.Empty, error)
+	// WatchJobStates streams the job's state, first the current one and then
+	// each time it changes, until the client goes away.
+	WatchJobStates(*pachyderm_pps.Job, API_WatchJobStatesServer) error
 	// Pipeline rpcs
 	CreatePipelineInfo(context.Context, *PipelineInfo) (*PipelineInfo, error)
 	GetPipelineInfo(context.Context, *pachyderm_pps.Pipeline) (*PipelineInfo, error)
+	// UpdatePipelineInfo replaces a pipeline's info if its revision is the
+	// revision stored, created_at is kept. It errors if the pipeline has been
+	// updated since, rather than overwrite that update.
+	UpdatePipelineInfo(context.Context, *PipelineInfo) (*PipelineInfo, error)
 	// ordered by time, latest to earliest
 	ListPipelineInfos(context.Context, *ListPipelineInfosRequest) (*PipelineInfos, error)
 	DeletePipelineInfo(context.Context, *pachyderm_pps.Pipeline) (*google_protobuf.Empty, error)
@@ -596,7 +868,7 @@ func _API_InspectJob_Handler(srv interface{}, ctx context.Context, dec func(inte
 }
 
 func _API_ListJobInfos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(pachyderm_pps.ListJobRequest)
+	in := new(ListJobRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -608,7 +880,25 @@ func _API_ListJobInfos_Handler(srv interface{}, ctx context.Context, dec func(in
 		FullMethod: "/pachyderm.pps.persist.API/ListJobInfos",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).ListJobInfos(ctx, req.(*pachyderm_pps.ListJobRequest))
+		return srv.(APIServer).ListJobInfos(ctx, req.(*ListJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_GetJobInfosByCommit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(pfs.Commit)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).GetJobInfosByCommit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pachyderm.pps.persist.API/GetJobInfosByCommit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).GetJobInfosByCommit(ctx, req.(*pfs.Commit))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -649,6 +939,24 @@ func _API_DeleteJobInfosForPipeline_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _API_PruneJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PruneJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).PruneJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pachyderm.pps.persist.API/PruneJobs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).PruneJobs(ctx, req.(*PruneJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _API_CreateJobOutput_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(JobOutput)
 	if err := dec(in); err != nil {
@@ -667,6 +975,42 @@ func _API_CreateJobOutput_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _API_GetJobOutput_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(pachyderm_pps.Job)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).GetJobOutput(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pachyderm.pps.persist.API/GetJobOutput",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).GetJobOutput(ctx, req.(*pachyderm_pps.Job))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_GetJobOutputsByCommit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(pfs.Commit)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).GetJobOutputsByCommit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pachyderm.pps.persist.API/GetJobOutputsByCommit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).GetJobOutputsByCommit(ctx, req.(*pfs.Commit))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _API_CreateJobState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(JobState)
 	if err := dec(in); err != nil {
@@ -685,6 +1029,42 @@ func _API_CreateJobState_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _API_GetJobState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(pachyderm_pps.Job)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).GetJobState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pachyderm.pps.persist.API/GetJobState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).GetJobState(ctx, req.(*pachyderm_pps.Job))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_UpdateJobState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobState)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).UpdateJobState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pachyderm.pps.persist.API/UpdateJobState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).UpdateJobState(ctx, req.(*JobState))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _API_StartJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(pachyderm_pps.Job)
 	if err := dec(in); err != nil {
@@ -703,6 +1083,27 @@ func _API_StartJob_Handler(srv interface{}, ctx context.Context, dec func(interf
 	return interceptor(ctx, in, info, handler)
 }
 
+func _API_WatchJobStates_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(pachyderm_pps.Job)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(APIServer).WatchJobStates(m, &aPIWatchJobStatesServer{stream})
+}
+
+type API_WatchJobStatesServer interface {
+	Send(*JobState) error
+	grpc.ServerStream
+}
+
+type aPIWatchJobStatesServer struct {
+	grpc.ServerStream
+}
+
+func (x *aPIWatchJobStatesServer) Send(m *JobState) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _API_CreatePipelineInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(PipelineInfo)
 	if err := dec(in); err != nil {
@@ -739,6 +1140,24 @@ func _API_GetPipelineInfo_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _API_UpdatePipelineInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PipelineInfo)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).UpdatePipelineInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pachyderm.pps.persist.API/UpdatePipelineInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).UpdatePipelineInfo(ctx, req.(*PipelineInfo))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _API_ListPipelineInfos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListPipelineInfosRequest)
 	if err := dec(in); err != nil {
@@ -884,6 +1303,10 @@ var _API_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ListJobInfos",
 			Handler:    _API_ListJobInfos_Handler,
 		},
+		{
+			MethodName: "GetJobInfosByCommit",
+			Handler:    _API_GetJobInfosByCommit_Handler,
+		},
 		{
 			MethodName: "DeleteJobInfo",
 			Handler:    _API_DeleteJobInfo_Handler,
@@ -892,14 +1315,34 @@ var _API_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteJobInfosForPipeline",
 			Handler:    _API_DeleteJobInfosForPipeline_Handler,
 		},
+		{
+			MethodName: "PruneJobs",
+			Handler:    _API_PruneJobs_Handler,
+		},
 		{
 			MethodName: "CreateJobOutput",
 			Handler:    _API_CreateJobOutput_Handler,
 		},
+		{
+			MethodName: "GetJobOutput",
+			Handler:    _API_GetJobOutput_Handler,
+		},
+		{
+			MethodName: "GetJobOutputsByCommit",
+			Handler:    _API_GetJobOutputsByCommit_Handler,
+		},
 		{
 			MethodName: "CreateJobState",
 			Handler:    _API_CreateJobState_Handler,
 		},
+		{
+			MethodName: "GetJobState",
+			Handler:    _API_GetJobState_Handler,
+		},
+		{
+			MethodName: "UpdateJobState",
+			Handler:    _API_UpdateJobState_Handler,
+		},
 		{
 			MethodName: "StartJob",
 			Handler:    _API_StartJob_Handler,
@@ -912,6 +1355,10 @@ var _API_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetPipelineInfo",
 			Handler:    _API_GetPipelineInfo_Handler,
 		},
+		{
+			MethodName: "UpdatePipelineInfo",
+			Handler:    _API_UpdatePipelineInfo_Handler,
+		},
 		{
 			MethodName: "ListPipelineInfos",
 			Handler:    _API_ListPipelineInfos_Handler,
@@ -938,6 +1385,11 @@ var _API_serviceDesc = grpc.ServiceDesc{
 		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchJobStates",
+			Handler:       _API_WatchJobStates_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "SubscribePipelineInfos",
 			Handler:       _API_SubscribePipelineInfos_Handler,
@@ -947,72 +1399,96 @@ var _API_serviceDesc = grpc.ServiceDesc{
 }
 
 var fileDescriptor0 = []byte{
-	// 1057 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x9c, 0x56, 0x5b, 0x73, 0xdb, 0x44,
-	0x14, 0xae, 0x63, 0xc7, 0x97, 0x63, 0x3b, 0x19, 0x96, 0x92, 0x0a, 0xd3, 0x12, 0xa3, 0xc2, 0x70,
-	0x99, 0x41, 0x6e, 0x43, 0x87, 0x81, 0x07, 0xa6, 0xb4, 0x21, 0x2d, 0x06, 0x1a, 0x52, 0x25, 0x3c,
-	0xc0, 0x8b, 0x91, 0xa5, 0x75, 0xa2, 0x8e, 0x6e, 0xac, 0xe4, 0x0e, 0x9d, 0x81, 0xdf, 0x01, 0x3f,
-	0x8b, 0x7f, 0xc3, 0x6b, 0xcf, 0x9e, 0x5d, 0x39, 0xbe, 0xc9, 0x51, 0xf3, 0x90, 0x89, 0xf7, 0xdb,
-	0xef, 0x5c, 0xf6, 0x3b, 0xe7, 0xec, 0x0a, 0xfa, 0x29, 0x17, 0x2f, 0xb9, 0x18, 0x24, 0x49, 0x3a,
-	0x48, 0xb8, 0x48, 0xfd, 0x34, 0xcb, 0xff, 0x5b, 0x89, 0x88, 0xb3, 0x98, 0xbd, 0x93, 0x38, 0xee,
-	0xc5, 0x2b, 0x8f, 0x8b, 0xd0, 0x42, 0x92, 0xa5, 0x37, 0x7b, 0xef, 0x9d, 0xc7, 0xf1, 0x79, 0xc0,
-	0x07, 0x44, 0x1a, 0x4f, 0x27, 0x03, 0x1e, 0x26, 0xd9, 0x2b, 0x65, 0xd3, 0xdb, 0x5f, 0xde, 0xcc,
-	0xfc, 0x90, 0xa7, 0x99, 0x13, 0x26, 0x9a, 0x70, 0xd3, 0x0d, 0x7c, 0x1e, 0x61, 0xa8, 0x49, 0x2a,
-	0xff, 0x96, 0x51, 0x99, 0x4c, 0xa2, 0x51, 0xf3, 0xbf, 0x1a, 0x34, 0x7e, 0x88, 0xc7, 0xc3, 0x68,
-	0x82, 0xc9, 0x40, 0xfd, 0x45, 0x3c, 0x1e, 0xf9, 0x9e, 0x51, 0xe9, 0x57, 0x3e, 0x69, 0xd9, 0xdb,
-	0xb8, 0x1a, 0x7a, 0xec, 0x4b, 0x68, 0x65, 0xc2, 0x89, 0xd2, 0x49, 0x2c, 0x42, 0x63, 0x0b, 0x77,
-	0xda, 0x07, 0x86, 0xb5, 0x98, 0xf7, 0x59, 0xbe, 0x6f, 0x5f, 0x52, 0xd9, 0x5d, 0xe8, 0x26, 0x7e,
-	0xc2, 0x03, 0x3f, 0xe2, 0xa3, 0xc8, 0x09, 0xb9, 0x51, 0x25, 0xaf, 0x9d, 0x1c, 0x3c, 0x46, 0x8c,
-	0xf5, 0xa1, 0x9d, 0x38, 0xc2, 0x09, 0x02, 0x84, 0xd2, 0xd0, 0xa8, 0x21, 0xa5, 0x66, 0xcf, 0x43,
-	0x6c, 0x00, 0x75, 0x3f, 0x4a, 0xa6, 0x59, 0x6a, 0x6c, 0xf7, 0xab, 0x18, 0xfb, 0xd6, 0x52, 0x6c,
-	0xca, 0x1e, 0xf7, 0x6d, 0x4d, 0x63, 0xf7, 0x01, 0xd0, 0x1e, 0x8f, 0x3a, 0xc2, 0xfc, 0x8d, 0x3a,
-	0x25, 0xcc, 0x56, 0x8d, 0xec, 0x96, 0x62, 0xe1, 0x4f, 0xf6, 0x35, 0x80, 0x2b, 0xb8, 0x93, 0x71,
-	0x6f, 0xe4, 0x64, 0x46, 0x83, 0x4c, 0x7a, 0x96, 0xd2, 0xd9, 0xca, 0x75, 0xb6, 0xce, 0x72, 0x9d,
-	0xed, 0x96, 0x66, 0x3f, 0xca, 0xd8, 0x3d, 0xe8, 0xc6, 0xd3, 0x0c, 0x03, 0x8f, 0xdc, 0x38, 0x0c,
-	0xfd, 0xcc, 0x68, 0x92, 0x75, 0xdb, 0x92, 0xca, 0x1f, 0x12, 0x64, 0x77, 0x14, 0x43, 0xad, 0xd8,
-	0xe7, 0xb0, 0x8d, 0x5e, 0x32, 0x6e, 0xb4, 0x90, 0xb9, 0xb3, 0xee, 0x3c, 0xa7, 0x72, 0xdb, 0x56,
-	0x2c, 0xf6, 0x01, 0x74, 0x94, 0xe7, 0x91, 0x1f, 0x79, 0xfc, 0x4f, 0x03, 0x48, 0xc5, 0xb6, 0xc2,
-	0x86, 0x12, 0x92, 0x94, 0x24, 0xf6, 0xd2, 0x11, 0x1a, 0x08, 0xcc, 0xca, 0x68, 0x6b, 0x15, 0x11,
-	0x3b, 0x55, 0x10, 0xfb, 0x08, 0x76, 0x14, 0x65, 0xea, 0xba, 0x9c, 0x7b, 0x48, 0xea, 0x10, 0xa9,
-	0x4b, 0xa4, 0x1c, 0x64, 0xfb, 0x40, 0x56, 0xa3, 0x89, 0xe3, 0x07, 0xc8, 0xe9, 0x12, 0x07, 0x24,
-	0xf4, 0x84, 0x10, 0x19, 0x2a, 0xbd, 0x70, 0x84, 0x37, 0x0a, 0x63, 0x6f, 0x1a, 0xf8, 0xc6, 0x0e,
-	0xd6, 0x04, 0x43, 0x11, 0xf6, 0x8c, 0x20, 0xf3, 0x08, 0x9a, 0xba, 0xa3, 0x52, 0x14, 0xb6, 0x49,
-	0x2d, 0x85, 0x0b, 0x6c, 0x2a, 0x59, 0xbe, 0xf7, 0xad, 0xb5, 0x2d, 0x6f, 0x69, 0x13, 0xbb, 0xf1,
-	0x42, 0xfd, 0x30, 0xcf, 0xa0, 0x85, 0xd8, 0xcf, 0xa4, 0x5c, 0x51, 0x6b, 0xae, 0x88, 0xbf, 0x75,
-	0x85, 0xf8, 0xe6, 0x09, 0x25, 0x47, 0x02, 0x17, 0x39, 0x9d, 0xd5, 0x67, 0xab, 0x4c, 0x7d, 0xcc,
-	0x7f, 0xaa, 0xd0, 0x39, 0xd1, 0x2d, 0x4d, 0x63, 0xb4, 0xd2, 0xf7, 0x95, 0x35, 0x7d, 0x7f, 0xdd,
-	0xa1, 0x5a, 0x9a, 0x97, 0xea, 0xea, 0xbc, 0x3c, 0x98, 0xcd, 0x4b, 0x8d, 0x04, 0xbf, 0xbd, 0xe4,
-	0xf6, 0x32, 0xd7, 0xf9, 0xa1, 0xf9, 0x0c, 0xda, 0x5a, 0x49, 0xc1, 0x93, 0x18, 0x47, 0x4d, 0x66,
-	0xd4, 0x22, 0x1d, 0x6d, 0x04, 0x6c, 0x50, 0xbb, 0xf2, 0xf7, 0xd2, 0xb4, 0xd4, 0xdf, 0x64, 0x5a,
-	0x6e, 0xa2, 0xb6, 0xb2, 0x55, 0x68, 0xc6, 0x6a, 0xb6, 0x5a, 0xb0, 0x83, 0x5c, 0xf1, 0x26, 0x29,
-	0x5e, 0x94, 0xf1, 0xf2, 0x58, 0x08, 0xee, 0xca, 0x29, 0xe7, 0x42, 0xc4, 0x82, 0x86, 0x09, 0xc7,
-	0x42, 0x61, 0x47, 0x12, 0x32, 0x63, 0x60, 0xf3, 0x85, 0x39, 0xbc, 0x70, 0xa2, 0x73, 0xce, 0x1e,
-	0x42, 0x33, 0xaf, 0x04, 0x55, 0xa6, 0x7d, 0x70, 0xb7, 0xa0, 0x25, 0xe7, 0x8d, 0xed, 0x99, 0x11,
-	0x33, 0xa0, 0x21, 0x78, 0x18, 0xbf, 0xc4, 0xf9, 0x90, 0x85, 0x6b, 0xda, 0xf9, 0xd2, 0xfc, 0x15,
-	0xba, 0xf3, 0x36, 0x29, 0xfb, 0x7e, 0xae, 0x15, 0xe6, 0x66, 0xa0, 0x54, 0xc0, 0x59, 0xbf, 0xd0,
-	0x34, 0xfc, 0x05, 0x77, 0x4e, 0xa7, 0xe3, 0xd4, 0x15, 0xfe, 0x98, 0x2f, 0xc4, 0xb0, 0xf9, 0x1f,
-	0x53, 0xd4, 0x99, 0x7d, 0x0c, 0xbb, 0x7e, 0xe4, 0x06, 0x53, 0x4f, 0x46, 0xf2, 0x33, 0xdf, 0x09,
-	0xe8, 0x74, 0x4d, 0x7b, 0x47, 0xc3, 0x43, 0x85, 0x92, 0xd8, 0x54, 0x02, 0xd5, 0x75, 0xb7, 0x0b,
-	0x72, 0x39, 0x95, 0x1c, 0x5d, 0x20, 0xf3, 0x18, 0x8c, 0x9f, 0x10, 0x5c, 0x1b, 0x78, 0xe6, 0xaf,
-	0x52, 0xde, 0xdf, 0xbf, 0x15, 0xe8, 0xfd, 0x92, 0x78, 0x58, 0xc7, 0xc5, 0xda, 0x6a, 0x97, 0xa5,
-	0x26, 0xe8, 0x60, 0x71, 0x4c, 0xaf, 0xd5, 0x34, 0xd5, 0xd5, 0xa6, 0xd9, 0x87, 0x6d, 0x4a, 0x95,
-	0xed, 0x41, 0x3d, 0x9a, 0x86, 0x63, 0x2e, 0x28, 0x7a, 0xcd, 0xd6, 0xab, 0x83, 0xff, 0x01, 0xaa,
-	0x8f, 0x4e, 0x86, 0xec, 0x39, 0x74, 0x0f, 0xa9, 0xaf, 0xf3, 0xe7, 0xf3, 0x8a, 0x9b, 0xad, 0x77,
-	0xc5, 0xbe, 0x79, 0x83, 0x9d, 0x00, 0x0c, 0xa3, 0x34, 0xe1, 0x2e, 0x3d, 0x4a, 0xfd, 0x25, 0xfe,
-	0xe5, 0x96, 0xd6, 0xa9, 0x94, 0xc7, 0x8e, 0x2c, 0xdc, 0xec, 0x3e, 0xbe, 0xb3, 0x64, 0xa1, 0x37,
-	0x73, 0x87, 0xfb, 0x9b, 0x1d, 0xa6, 0xe8, 0xf1, 0x1b, 0xe8, 0x7e, 0xc7, 0x03, 0x7e, 0x79, 0xec,
-	0x35, 0x4f, 0x6b, 0x6f, 0x6f, 0xe5, 0x36, 0x38, 0x92, 0x1f, 0x30, 0x68, 0x7e, 0x0c, 0xef, 0x2e,
-	0x98, 0xa7, 0x4f, 0x62, 0x91, 0x97, 0x8a, 0xdd, 0x2a, 0xa8, 0xe1, 0x06, 0x7f, 0xcf, 0x60, 0x77,
-	0x56, 0x05, 0xfd, 0x56, 0xf4, 0x8b, 0x0f, 0xa1, 0x18, 0x1b, 0xdc, 0xfd, 0x08, 0x3b, 0x33, 0x77,
-	0xea, 0x91, 0xd8, 0x20, 0x09, 0x11, 0x36, 0x38, 0xfb, 0x0a, 0x9a, 0xf4, 0xfc, 0xca, 0x62, 0xbe,
-	0x99, 0x4a, 0xbf, 0x03, 0x53, 0x69, 0x2c, 0x3e, 0x2c, 0x25, 0xae, 0x8d, 0x5e, 0x19, 0x12, 0x46,
-	0x78, 0x0e, 0xbb, 0x4f, 0xf9, 0xc2, 0x40, 0x17, 0xab, 0x5f, 0xd2, 0x65, 0x00, 0x6f, 0xad, 0x5c,
-	0x12, 0x6c, 0x50, 0x60, 0x5b, 0x74, 0x9d, 0xf4, 0x3e, 0x2c, 0x11, 0x4c, 0xf6, 0xe1, 0x53, 0x60,
-	0xaa, 0x91, 0xca, 0x9d, 0xa1, 0x58, 0xeb, 0xbf, 0x61, 0x6f, 0xfd, 0xcd, 0xca, 0x1e, 0x14, 0x5d,
-	0x65, 0x9b, 0x2e, 0xe2, 0xde, 0xa7, 0x25, 0x0e, 0xa0, 0x9e, 0x22, 0xf3, 0xc6, 0xbd, 0x0a, 0x1b,
-	0xc3, 0xdb, 0x6b, 0x6e, 0x42, 0x76, 0xbf, 0xc0, 0x4b, 0xf1, 0xad, 0xb9, 0xe1, 0x88, 0xdf, 0xea,
-	0x46, 0x3c, 0x89, 0xbd, 0xb5, 0x8d, 0x78, 0xf5, 0x3d, 0xf2, 0x18, 0x40, 0x7f, 0x24, 0x5e, 0xdf,
-	0xc7, 0x43, 0x68, 0xc8, 0x8f, 0xc8, 0x6b, 0x3b, 0x78, 0xdc, 0xfa, 0xad, 0xa1, 0xc1, 0x71, 0x9d,
-	0xce, 0xf8, 0xc5, 0xeb, 0x00, 0x00, 0x00, 0xff, 0xff, 0x38, 0x50, 0x88, 0x97, 0x62, 0x0d, 0x00,
-	0x00,
+	// 1448 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xad, 0x58, 0x5b, 0x73, 0xdb, 0x44,
+	0x14, 0xc6, 0x71, 0xe2, 0xcb, 0xf1, 0x25, 0xed, 0xb6, 0x4d, 0x84, 0xdb, 0x92, 0x54, 0xa5, 0x14,
+	0x3a, 0x53, 0xa7, 0x4d, 0x0b, 0x03, 0xc3, 0x43, 0x48, 0xd2, 0xa4, 0x35, 0x90, 0xe2, 0x2a, 0x61,
+	0xb8, 0xbc, 0x08, 0xd9, 0x5a, 0x27, 0x6a, 0xad, 0x0b, 0x5a, 0x39, 0xd3, 0x30, 0xf0, 0x13, 0x78,
+	0x67, 0xf8, 0x11, 0xfc, 0x34, 0x7e, 0x00, 0x4f, 0x9c, 0xbd, 0x48, 0xf1, 0x45, 0xb2, 0x9d, 0x4c,
+	0x1f, 0x32, 0xf1, 0x9e, 0x3d, 0xf7, 0xf3, 0x7d, 0xbb, 0x6b, 0xc3, 0x3a, 0xa3, 0xe1, 0x29, 0x0d,
+	0x37, 0x82, 0x80, 0x6d, 0x04, 0x34, 0x64, 0x0e, 0x8b, 0xe2, 0xff, 0xcd, 0x20, 0xf4, 0x23, 0x9f,
+	0xdc, 0x08, 0xac, 0xee, 0xc9, 0x99, 0x4d, 0x43, 0xb7, 0x89, 0x4a, 0x4d, 0xb5, 0xd9, 0xb8, 0x79,
+	0xec, 0xfb, 0xc7, 0x7d, 0xba, 0x21, 0x94, 0x3a, 0x83, 0xde, 0x06, 0x75, 0x83, 0xe8, 0x4c, 0xda,
+	0x34, 0xd6, 0xc6, 0x37, 0x23, 0xc7, 0xa5, 0x2c, 0xb2, 0xdc, 0x40, 0x29, 0x5c, 0xef, 0xf6, 0x1d,
+	0xea, 0x61, 0xa8, 0x1e, 0xe3, 0x7f, 0xe3, 0x52, 0x9e, 0x4c, 0xa0, 0xa4, 0xfa, 0x9f, 0x4b, 0x50,
+	0xfc, 0xda, 0xef, 0xb4, 0xbc, 0x1e, 0x26, 0x03, 0x85, 0xd7, 0x7e, 0xc7, 0x74, 0x6c, 0x2d, 0xb7,
+	0x9e, 0xfb, 0xb8, 0x6c, 0x2c, 0xe1, 0xaa, 0x65, 0x93, 0xcf, 0xa0, 0x1c, 0x85, 0x96, 0xc7, 0x7a,
+	0x7e, 0xe8, 0x6a, 0x0b, 0xb8, 0x53, 0xd9, 0xd4, 0x9a, 0xa3, 0x79, 0x1f, 0xc5, 0xfb, 0xc6, 0xb9,
+	0x2a, 0xb9, 0x0b, 0xb5, 0xc0, 0x09, 0x68, 0xdf, 0xf1, 0xa8, 0xe9, 0x59, 0x2e, 0xd5, 0xf2, 0xc2,
+	0x6b, 0x35, 0x16, 0xbe, 0x44, 0x19, 0x59, 0x87, 0x4a, 0x60, 0x85, 0x56, 0xbf, 0x8f, 0x22, 0xe6,
+	0x6a, 0x8b, 0xa8, 0xb2, 0x68, 0x0c, 0x8b, 0xc8, 0x06, 0x14, 0x1c, 0x2f, 0x18, 0x44, 0x4c, 0x5b,
+	0x5a, 0xcf, 0x63, 0xec, 0xd5, 0xb1, 0xd8, 0x22, 0x7b, 0xdc, 0x37, 0x94, 0x1a, 0x79, 0x0c, 0x80,
+	0xf6, 0x58, 0xaa, 0x89, 0xf9, 0x6b, 0x05, 0x91, 0x30, 0x99, 0x34, 0x32, 0xca, 0x52, 0x0b, 0x3f,
+	0x92, 0x2f, 0x00, 0xba, 0x21, 0xb5, 0x22, 0x6a, 0x9b, 0x56, 0xa4, 0x15, 0x85, 0x49, 0xa3, 0x29,
+	0xfb, 0xdc, 0x8c, 0xfb, 0xdc, 0x3c, 0x8a, 0xfb, 0x6c, 0x94, 0x95, 0xf6, 0x76, 0x44, 0x1e, 0x41,
+	0xcd, 0x1f, 0x44, 0x18, 0xd8, 0xec, 0xfa, 0xae, 0xeb, 0x44, 0x5a, 0x49, 0x58, 0x57, 0x9a, 0xbc,
+	0xf3, 0xbb, 0x42, 0x64, 0x54, 0xa5, 0x86, 0x5c, 0x91, 0x87, 0xb0, 0x84, 0x5e, 0x22, 0xaa, 0x95,
+	0x51, 0xb3, 0x9e, 0x56, 0xcf, 0x21, 0xdf, 0x36, 0xa4, 0x16, 0xb9, 0x03, 0x55, 0xe9, 0xd9, 0x74,
+	0x3c, 0x9b, 0xbe, 0xd5, 0x40, 0x74, 0xb1, 0x22, 0x65, 0x2d, 0x2e, 0xe2, 0x2a, 0x81, 0x6f, 0x33,
+	0x13, 0x0d, 0x42, 0xcc, 0x4a, 0xab, 0xa8, 0x2e, 0xa2, 0xec, 0x50, 0x8a, 0xc8, 0x3d, 0xa8, 0x4b,
+	0x95, 0x41, 0xb7, 0x4b, 0xa9, 0x8d, 0x4a, 0x55, 0xa1, 0x54, 0x13, 0x4a, 0xb1, 0x90, 0xac, 0x81,
+	0xb0, 0x32, 0x7b, 0x96, 0xd3, 0x47, 0x9d, 0x9a, 0xd0, 0x01, 0x2e, 0xda, 0x17, 0x12, 0x1e, 0x8a,
+	0x9d, 0x58, 0xa1, 0x6d, 0xba, 0xbe, 0x3d, 0xe8, 0x3b, 0x5a, 0x1d, 0x67, 0x82, 0xa1, 0x84, 0xec,
+	0x40, 0x88, 0xc8, 0x97, 0x50, 0xe9, 0x39, 0x9e, 0xc3, 0x4e, 0x64, 0x37, 0x97, 0x67, 0x76, 0x13,
+	0x62, 0xf5, 0xed, 0x48, 0x77, 0xa1, 0xa4, 0xe0, 0xc8, 0x70, 0x2a, 0x25, 0x81, 0x47, 0x5c, 0x20,
+	0x22, 0xf9, 0xec, 0x3f, 0x68, 0xa6, 0xf2, 0xa5, 0xa9, 0x4c, 0x8c, 0xe2, 0x6b, 0x05, 0xe5, 0x8f,
+	0x60, 0xd9, 0xa3, 0x6f, 0x23, 0x33, 0xb0, 0x8e, 0xa9, 0x19, 0xf9, 0x6f, 0xa8, 0x27, 0x90, 0x5b,
+	0x36, 0x6a, 0x5c, 0xdc, 0x46, 0xe9, 0x11, 0x17, 0xea, 0x47, 0x50, 0x46, 0xdb, 0xef, 0xc4, 0x78,
+	0xb2, 0xf0, 0x3f, 0x31, 0xe1, 0x85, 0x19, 0x13, 0xd6, 0xdb, 0xa2, 0x08, 0x31, 0xc5, 0x2c, 0xa7,
+	0x09, 0x08, 0x16, 0xe6, 0x01, 0x81, 0xfe, 0x6f, 0x1e, 0xaa, 0x6d, 0xc5, 0x1b, 0x51, 0xe0, 0x04,
+	0xb9, 0x72, 0x29, 0xe4, 0xba, 0x2c, 0x73, 0xc7, 0x48, 0x99, 0x9f, 0x24, 0xe5, 0xd3, 0x84, 0x94,
+	0x8b, 0x62, 0x30, 0xb7, 0xc6, 0xdc, 0x9e, 0xe7, 0x3a, 0xcc, 0xcc, 0x07, 0x50, 0x51, 0x9d, 0x0c,
+	0x69, 0xe0, 0x23, 0x9f, 0x79, 0x46, 0x65, 0xd1, 0x47, 0x03, 0x05, 0x06, 0xc8, 0x5d, 0xfe, 0x79,
+	0x8c, 0x92, 0x85, 0x8b, 0x50, 0xf2, 0x3a, 0xf6, 0x96, 0xe3, 0x51, 0x10, 0x79, 0xd1, 0x90, 0x0b,
+	0xb2, 0x19, 0x77, 0xbc, 0x24, 0x3a, 0x9e, 0x95, 0xf1, 0x38, 0xf7, 0x42, 0xda, 0xe5, 0x47, 0x09,
+	0x0d, 0x43, 0x3f, 0x14, 0x8c, 0x45, 0xee, 0x49, 0xd9, 0x1e, 0x17, 0xf1, 0x3c, 0x07, 0x81, 0x1d,
+	0xe7, 0x09, 0xb3, 0xf3, 0x54, 0xda, 0x98, 0x67, 0x03, 0x4a, 0x21, 0x3d, 0x75, 0x98, 0xe3, 0x7b,
+	0x8a, 0xb2, 0xc9, 0x5a, 0xf7, 0x81, 0x0c, 0xcf, 0x7b, 0xf7, 0xc4, 0xf2, 0x8e, 0x29, 0xd9, 0x82,
+	0x52, 0x3c, 0x60, 0x31, 0xf0, 0xca, 0xe6, 0xdd, 0x0c, 0x46, 0x0c, 0x1b, 0x1b, 0x89, 0x11, 0xd1,
+	0xa0, 0x18, 0x52, 0xd7, 0x3f, 0x45, 0x6e, 0x73, 0x3c, 0x94, 0x8c, 0x78, 0xa9, 0xff, 0x04, 0xb5,
+	0x61, 0x1b, 0x46, 0x5e, 0x0c, 0x21, 0x6c, 0x88, 0x82, 0x73, 0x05, 0x4c, 0x60, 0xc8, 0x57, 0xfa,
+	0xef, 0x70, 0xfb, 0x70, 0xd0, 0x61, 0xdd, 0xd0, 0xe9, 0xd0, 0x91, 0x18, 0x06, 0xfd, 0x75, 0x80,
+	0x6d, 0x21, 0xf7, 0x61, 0xd9, 0xf1, 0xba, 0xfd, 0x81, 0xcd, 0x23, 0x39, 0x91, 0x63, 0xf5, 0x45,
+	0x75, 0x25, 0xa3, 0xae, 0xc4, 0x2d, 0x29, 0x15, 0x33, 0x14, 0x93, 0x95, 0x60, 0xbe, 0x95, 0x91,
+	0xcb, 0x21, 0xd7, 0x51, 0x73, 0xd7, 0x5f, 0x82, 0xf6, 0x2d, 0x0a, 0x53, 0x03, 0x27, 0xfe, 0x72,
+	0xf3, 0xfb, 0xfb, 0x2b, 0x07, 0x8d, 0xef, 0xc5, 0x0c, 0x47, 0x21, 0xa3, 0x5c, 0xce, 0x45, 0xcc,
+	0xcd, 0x51, 0xf6, 0x5f, 0x0a, 0x8b, 0xf9, 0x09, 0x2c, 0xea, 0x6b, 0xb0, 0x24, 0x52, 0x25, 0x2b,
+	0x50, 0xf0, 0x06, 0x6e, 0x87, 0x86, 0x22, 0xfa, 0xa2, 0xa1, 0x56, 0xfa, 0x7f, 0x0b, 0x50, 0xe7,
+	0xcd, 0xe0, 0xd7, 0x9f, 0xca, 0xf7, 0xc9, 0x04, 0xa4, 0x56, 0x33, 0xb2, 0x19, 0x82, 0x51, 0x13,
+	0xaa, 0x82, 0xd2, 0xe7, 0x27, 0x62, 0x7e, 0xfc, 0x44, 0xac, 0x08, 0x85, 0xf1, 0x2b, 0x2f, 0x8f,
+	0x8a, 0xb3, 0xaf, 0xbc, 0x2d, 0xa8, 0x25, 0xdc, 0xef, 0x45, 0x58, 0xc5, 0xe2, 0x4c, 0x5a, 0x55,
+	0x63, 0xfa, 0x73, 0x7d, 0xb2, 0x0d, 0xf5, 0xd8, 0x41, 0x87, 0xe2, 0x91, 0x46, 0xd5, 0x59, 0x33,
+	0xcd, 0x43, 0x1c, 0x72, 0x47, 0x18, 0xf0, 0x43, 0xa4, 0xef, 0xf0, 0xda, 0xf8, 0xd1, 0x93, 0x37,
+	0xe4, 0x82, 0xdc, 0x84, 0xb2, 0xb8, 0x52, 0x98, 0xf3, 0x1b, 0x15, 0xc7, 0x4b, 0x1e, 0xbb, 0x82,
+	0x82, 0x43, 0x5c, 0x93, 0xdb, 0xfc, 0xe1, 0x91, 0xdc, 0x37, 0x25, 0x31, 0x1f, 0xa1, 0x2e, 0xef,
+	0x9a, 0x4f, 0x61, 0xe5, 0x19, 0xed, 0xd3, 0x88, 0xc6, 0x17, 0x9c, 0x41, 0x59, 0xe0, 0x7b, 0x8c,
+	0x72, 0xaf, 0xf1, 0x45, 0xc7, 0xd4, 0xc4, 0x4a, 0xea, 0x26, 0x63, 0xfa, 0xdf, 0x39, 0xb8, 0xd2,
+	0x0e, 0x07, 0x1e, 0x37, 0x4b, 0x80, 0x3b, 0x59, 0x60, 0xee, 0xa2, 0x05, 0x3e, 0x80, 0xab, 0x6f,
+	0x28, 0x0d, 0x4c, 0x04, 0xb9, 0x99, 0x20, 0x60, 0x41, 0x04, 0x5f, 0xe6, 0x1b, 0x6d, 0x1a, 0xc6,
+	0x93, 0x27, 0xab, 0x50, 0xb4, 0xc3, 0x33, 0x13, 0xb3, 0x10, 0xb0, 0x2b, 0x19, 0x05, 0x5c, 0x1a,
+	0x03, 0x4f, 0x3f, 0x00, 0x48, 0xee, 0x4f, 0x86, 0x73, 0x03, 0x5e, 0x87, 0x3c, 0xc5, 0xd5, 0x79,
+	0xb1, 0x9e, 0x7d, 0x65, 0x4b, 0x33, 0x83, 0xd7, 0x2e, 0x3f, 0x6e, 0xfe, 0x73, 0x05, 0xf2, 0xdb,
+	0xed, 0x16, 0x79, 0x05, 0xb5, 0x5d, 0x91, 0x6c, 0xfc, 0x34, 0x9d, 0x71, 0xf1, 0x37, 0x66, 0xec,
+	0xeb, 0xef, 0x91, 0x36, 0x40, 0xcb, 0x63, 0x01, 0xed, 0x8a, 0x07, 0xdf, 0x78, 0x56, 0xe7, 0x5b,
+	0xaa, 0xc3, 0x73, 0x78, 0xfc, 0x11, 0xaa, 0x8a, 0x4b, 0xf2, 0xc0, 0xbc, 0x97, 0x61, 0x31, 0x4a,
+	0xb8, 0xc6, 0xda, 0x74, 0xc7, 0x0c, 0x3d, 0xef, 0xc1, 0xb5, 0xe7, 0x34, 0x71, 0xbc, 0x73, 0xa6,
+	0x58, 0x34, 0xcc, 0xaf, 0x79, 0xdc, 0x1c, 0x41, 0x6d, 0x04, 0x70, 0x24, 0xe5, 0x15, 0xdc, 0x78,
+	0x98, 0xe1, 0x27, 0x1d, 0xaa, 0xe8, 0x95, 0xc2, 0xfb, 0xa3, 0x7b, 0xfb, 0xfe, 0x10, 0x50, 0x32,
+	0xce, 0x8e, 0xcb, 0x84, 0x29, 0x27, 0xa8, 0x27, 0xf7, 0xb3, 0x2e, 0x9d, 0x31, 0x5e, 0x5c, 0x3c,
+	0xcc, 0x01, 0x2c, 0x27, 0x48, 0x53, 0xcf, 0xc0, 0x99, 0x88, 0x6d, 0xac, 0x4c, 0xb0, 0x6c, 0x8f,
+	0x7f, 0x3f, 0x43, 0x77, 0xfb, 0x50, 0x95, 0x93, 0x53, 0xbe, 0xd2, 0x3a, 0x3e, 0xd3, 0x3f, 0xfa,
+	0x69, 0xc1, 0x8d, 0x61, 0x3f, 0x19, 0x18, 0xb8, 0x33, 0xcb, 0x13, 0x47, 0xc1, 0x37, 0x50, 0x4f,
+	0x2a, 0x94, 0x4f, 0xd2, 0x29, 0xd0, 0x11, 0x0a, 0x53, 0xea, 0x7b, 0x06, 0x15, 0x99, 0x97, 0xf4,
+	0x94, 0x56, 0xde, 0x2c, 0xef, 0x32, 0x25, 0x79, 0x83, 0xbe, 0x8b, 0x94, 0x3e, 0x87, 0x92, 0xf8,
+	0x92, 0xc3, 0x69, 0x9d, 0x96, 0x4f, 0xb6, 0x65, 0x0b, 0xea, 0x3f, 0x58, 0x51, 0xf7, 0x24, 0x0e,
+	0xc2, 0x2e, 0x59, 0xcf, 0xa3, 0x1c, 0xf9, 0x05, 0x88, 0x6c, 0xf2, 0xe8, 0x23, 0x7d, 0x8e, 0xb7,
+	0x52, 0x63, 0x1e, 0x25, 0x4c, 0xf6, 0x15, 0x2c, 0x63, 0xe7, 0x47, 0xdc, 0x67, 0x92, 0x6d, 0x4e,
+	0x97, 0x98, 0xf4, 0xe8, 0x43, 0xe6, 0x9d, 0x27, 0xdd, 0x87, 0xab, 0x13, 0x6f, 0x2f, 0xb2, 0x31,
+	0xe5, 0x9c, 0x4c, 0x7b, 0xa5, 0x35, 0x3e, 0x9c, 0x23, 0x18, 0x47, 0xfa, 0x73, 0x20, 0x92, 0xe7,
+	0xf3, 0x75, 0x29, 0x1b, 0x18, 0x7f, 0xc0, 0x4a, 0xfa, 0x83, 0x95, 0x3c, 0xcd, 0x7a, 0x21, 0x4e,
+	0x7b, 0xdf, 0x36, 0x3e, 0x99, 0xa3, 0x00, 0xf9, 0xc2, 0x17, 0x60, 0xea, 0xc0, 0xb5, 0x94, 0x07,
+	0x26, 0x79, 0x9c, 0xe1, 0x25, 0xfb, 0x31, 0x3a, 0xa5, 0xc4, 0xaf, 0x14, 0x6b, 0xda, 0xbe, 0x9d,
+	0x8a, 0xfa, 0xd9, 0xd7, 0xdf, 0x0e, 0x80, 0xfa, 0xdd, 0xe0, 0xf2, 0x3e, 0xb6, 0xa0, 0xc8, 0x7f,
+	0x57, 0xb8, 0xb4, 0x83, 0x9d, 0xf2, 0xcf, 0x45, 0x25, 0xec, 0x14, 0x44, 0x8d, 0x4f, 0xfe, 0x07,
+	0x7b, 0xe7, 0x4f, 0x68, 0x75, 0x13, 0x00, 0x00,
 }
@@ -1,9 +1,12 @@
 package server
 
 import (
+	"errors"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/dancannon/gorethink"
@@ -12,11 +15,14 @@ import (
 	ppsclient "github.com/pachyderm/pachyderm/src/client/pps"
 	"github.com/pachyderm/pachyderm/src/server/pps/persist"
 
+	"go.pedge.io/lion/proto"
 	"go.pedge.io/pb/go/google/protobuf"
 	"go.pedge.io/pkg/time"
 	"go.pedge.io/proto/rpclog"
 	"go.pedge.io/proto/time"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 )
 
 const (
@@ -24,11 +30,43 @@ const (
 	pipelineNameIndex          Index = "PipelineName"
 	pipelineNameAndCommitIndex Index = "PipelineNameAndCommitIndex"
 	commitIndex                Index = "CommitIndex"
+	// pipelineNameAndCreatedAtIndex orders a pipeline's jobs by creation
+	// time, JobID breaks ties so every job has its own place to page from.
+	pipelineNameAndCreatedAtIndex Index = "PipelineNameAndCreatedAt"
+	// createdAtIndex is pipelineNameAndCreatedAtIndex across pipelines.
+	createdAtIndex Index = "CreatedAt"
+	stateIndex     Index = "State"
+	// inputCommitIndex has an entry for each of a job's input commits, its
+	// keys are [repo name, commit ID].
+	inputCommitIndex Index = "InputCommit"
+	// outputCommitIndex's keys are the [repo name, commit ID] of a job's
+	// output commit.
+	outputCommitIndex Index = "OutputCommit"
 
 	pipelineInfosTable Table = "PipelineInfos"
 	pipelineShardIndex Index = "Shard"
 
+	// metadataTable holds rows about the database itself, keyed by Key.
+	metadataTable    Table = "Metadata"
+	schemaVersionKey       = "schema_version"
+	// schemaVersion is the version of the tables and indexes InitDBs
+	// creates, bump it when they change so migrations can be ordered.
+	schemaVersion = 2
+
 	connectTimeoutSeconds = 5
+
+	// These are raised by writes whose checks fail, the message comes back
+	// as the write's first_error.
+	notFoundMessage          = "not found"
+	conflictMessage          = "revision conflict"
+	illegalTransitionMessage = "illegal transition"
+
+	// defaultListJobInfosLimit caps ListJobInfos requests that filter by
+	// state or created_at, which can match jobs from every pipeline.
+	defaultListJobInfosLimit = 1000
+
+	// pruneJobsBatchSize is how many jobs PruneJobs deletes in each write.
+	pruneJobsBatchSize = 100
 )
 
 type Table string
@@ -52,110 +90,285 @@ var (
 				PrimaryKey: "PipelineName",
 			},
 		},
+		metadataTable: []gorethink.TableCreateOpts{
+			gorethink.TableCreateOpts{
+				PrimaryKey: "Key",
+			},
+		},
 	}
 )
 
+// metadataRow is a row of metadataTable.
+type metadataRow struct {
+	Key   string
+	Value int
+}
+
+// indexDefinition is a secondary index InitDBs creates, indexes on a single field
+// have a nil keys function.
+type indexDefinition struct {
+	table Table
+	name  Index
+	keys  func(row gorethink.Term) interface{}
+	opts  []gorethink.IndexCreateOpts
+}
+
+var indexes = []indexDefinition{
+	{table: jobInfosTable, name: pipelineNameIndex},
+	{table: jobInfosTable, name: commitIndex},
+	{
+		table: jobInfosTable,
+		name:  pipelineNameAndCommitIndex,
+		keys: func(row gorethink.Term) interface{} {
+			return []interface{}{
+				row.Field(pipelineNameIndex),
+				row.Field(commitIndex),
+			}
+		},
+	},
+	{
+		table: jobInfosTable,
+		name:  pipelineNameAndCreatedAtIndex,
+		keys: func(row gorethink.Term) interface{} {
+			return []interface{}{
+				row.Field(pipelineNameIndex),
+				row.Field("CreatedAt").Field("Seconds"),
+				row.Field("CreatedAt").Field("Nanos"),
+				row.Field("JobID"),
+			}
+		},
+	},
+	{
+		table: jobInfosTable,
+		name:  createdAtIndex,
+		keys: func(row gorethink.Term) interface{} {
+			return []interface{}{
+				row.Field("CreatedAt").Field("Seconds"),
+				row.Field("CreatedAt").Field("Nanos"),
+				row.Field("JobID"),
+			}
+		},
+	},
+	{table: jobInfosTable, name: stateIndex},
+	{
+		// Jobs without inputs have no entries in inputCommitIndex.
+		table: jobInfosTable,
+		name:  inputCommitIndex,
+		keys: func(row gorethink.Term) interface{} {
+			return row.Field("Inputs").Default([]interface{}{}).Map(func(input gorethink.Term) interface{} {
+				return []interface{}{
+					input.Field("Commit").Field("Repo").Field("Name"),
+					input.Field("Commit").Field("ID"),
+				}
+			})
+		},
+		opts: []gorethink.IndexCreateOpts{{Multi: true}},
+	},
+	{
+		// Jobs without an output commit have no entry in outputCommitIndex.
+		table: jobInfosTable,
+		name:  outputCommitIndex,
+		keys: func(row gorethink.Term) interface{} {
+			return []interface{}{
+				row.Field("OutputCommit").Field("Repo").Field("Name"),
+				row.Field("OutputCommit").Field("ID"),
+			}
+		},
+	},
+	{table: pipelineInfosTable, name: pipelineShardIndex},
+}
+
 // InitDBs prepares a RethinkDB instance to be used by the rethink server.
 // Rethink servers will error if they are pointed at databases that haven't had InitDBs run on them.
-func InitDBs(address string, databaseName string) error {
+// It only creates the database, tables and indexes that are missing, so
+// it's safe to run again, and it's how databases created by older versions
+// get the tables and indexes added since. alreadyExisted is true if there
+// was nothing to do.
+func InitDBs(address string, databaseName string) (alreadyExisted bool, retErr error) {
 	session, err := connect(address)
 	if err != nil {
-		return err
+		return false, err
 	}
-	if _, err := gorethink.DBCreate(databaseName).RunWrite(session); err != nil {
-		return err
+	defer session.Close()
+	alreadyExisted = true
+	databases, err := listStrings(gorethink.DBList(), session)
+	if err != nil {
+		return false, fmt.Errorf("listing databases failed: %v", err)
 	}
-	for _, table := range tables {
-		tableCreateOpts, ok := tableToTableCreateOpts[table]
-		if ok {
-			if _, err := gorethink.DB(databaseName).TableCreate(table, tableCreateOpts...).RunWrite(session); err != nil {
-				return err
+	if !databases[databaseName] {
+		alreadyExisted = false
+		if _, err := gorethink.DBCreate(databaseName).RunWrite(session); err != nil {
+			return false, err
+		}
+	}
+	existingTables, err := listStrings(gorethink.DB(databaseName).TableList(), session)
+	if err != nil {
+		return false, fmt.Errorf("listing tables in %s failed: %v", databaseName, err)
+	}
+	for _, table := range append(tables, metadataTable) {
+		if existingTables[string(table)] {
+			continue
+		}
+		alreadyExisted = false
+		if _, err := gorethink.DB(databaseName).TableCreate(table, tableToTableCreateOpts[table]...).RunWrite(session); err != nil {
+			return false, err
+		}
+	}
+	existingIndexes := make(map[Table]map[string]bool)
+	for _, index := range indexes {
+		if existingIndexes[index.table] == nil {
+			existingIndexes[index.table], err = listStrings(gorethink.DB(databaseName).Table(index.table).IndexList(), session)
+			if err != nil {
+				return false, fmt.Errorf("listing indexes of %s.%s failed: %v", databaseName, index.table, err)
 			}
+		}
+		if existingIndexes[index.table][string(index.name)] {
+			continue
+		}
+		alreadyExisted = false
+		if index.keys == nil {
+			_, err = gorethink.DB(databaseName).Table(index.table).IndexCreate(index.name, index.opts...).RunWrite(session)
 		} else {
-			if _, err := gorethink.DB(databaseName).TableCreate(table).RunWrite(session); err != nil {
-				return err
-			}
+			_, err = gorethink.DB(databaseName).Table(index.table).IndexCreateFunc(index.name, index.keys, index.opts...).RunWrite(session)
+		}
+		if err != nil {
+			return false, err
 		}
 	}
+	// Queries against an index that's still being built fail, so InitDBs
+	// doesn't return until they're all ready.
+	if err := waitForIndexes(session, databaseName); err != nil {
+		return false, err
+	}
+	updated, err := setSchemaVersion(session, databaseName)
+	if err != nil {
+		return false, err
+	}
+	return alreadyExisted && !updated, nil
+}
 
-	// Create indexes
-	if _, err := gorethink.DB(databaseName).Table(jobInfosTable).IndexCreate(pipelineNameIndex).RunWrite(session); err != nil {
-		return err
+// setSchemaVersion records schemaVersion in the metadata table, it refuses
+// to downgrade a database a newer version has initialized. updated is false
+// if the database already had schemaVersion.
+func setSchemaVersion(session *gorethink.Session, databaseName string) (updated bool, retErr error) {
+	cursor, err := gorethink.DB(databaseName).Table(metadataTable).Get(schemaVersionKey).Run(session)
+	if err != nil {
+		return false, err
 	}
-	if _, err := gorethink.DB(databaseName).Table(jobInfosTable).IndexCreate(commitIndex).RunWrite(session); err != nil {
-		return err
+	defer cursor.Close()
+	var row metadataRow
+	if !cursor.IsNil() {
+		if err := cursor.One(&row); err != nil {
+			return false, err
+		}
 	}
-	if _, err := gorethink.DB(databaseName).Table(jobInfosTable).IndexCreateFunc(
-		pipelineNameAndCommitIndex,
-		func(row gorethink.Term) interface{} {
-			return []interface{}{
-				row.Field(pipelineNameIndex),
-				row.Field(commitIndex),
-			}
-		}).RunWrite(session); err != nil {
-		return err
+	if row.Value == schemaVersion {
+		return false, nil
 	}
-	if _, err := gorethink.DB(databaseName).Table(pipelineInfosTable).IndexCreate(pipelineShardIndex).RunWrite(session); err != nil {
-		return err
+	if row.Value > schemaVersion {
+		return false, fmt.Errorf("database %s has schema version %d, which is newer than %d", databaseName, row.Value, schemaVersion)
 	}
-
-	return nil
+	if _, err := gorethink.DB(databaseName).Table(metadataTable).Insert(
+		metadataRow{Key: schemaVersionKey, Value: schemaVersion},
+		gorethink.InsertOpts{Conflict: "update"},
+	).RunWrite(session); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
-// CheckDBs checks that we have all the tables/indices we need
-func CheckDBs(address string, databaseName string) error {
-	session, err := connect(address)
+// listStrings runs term, which returns a list of names, and returns them as
+// a set.
+func listStrings(term gorethink.Term, session *gorethink.Session) (map[string]bool, error) {
+	cursor, err := term.Run(session)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	var names []string
+	if err := cursor.All(&names); err != nil {
+		return nil, err
+	}
+	result := make(map[string]bool)
+	for _, name := range names {
+		result[name] = true
 	}
+	return result, nil
+}
 
+// waitForIndexes waits for the tables and indexes InitDBs creates to be
+// ready.
+func waitForIndexes(session *gorethink.Session, databaseName string) error {
 	for _, table := range tables {
 		if _, err := gorethink.DB(databaseName).Table(table).Wait().RunWrite(session); err != nil {
 			return err
 		}
 	}
-
-	if _, err := gorethink.DB(databaseName).Table(jobInfosTable).IndexWait(pipelineNameIndex).RunWrite(session); err != nil {
-		return err
-	}
-
-	if _, err := gorethink.DB(databaseName).Table(jobInfosTable).IndexWait(commitIndex).RunWrite(session); err != nil {
-		return err
-	}
-
-	if _, err := gorethink.DB(databaseName).Table(jobInfosTable).IndexWait(pipelineNameAndCommitIndex).RunWrite(session); err != nil {
-		return err
+	for _, index := range indexes {
+		if _, err := gorethink.DB(databaseName).Table(index.table).IndexWait(index.name).RunWrite(session); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	if _, err := gorethink.DB(databaseName).Table(pipelineInfosTable).IndexWait(pipelineShardIndex).RunWrite(session); err != nil {
+// CheckDBs checks that we have all the tables/indices we need
+func CheckDBs(address string, databaseName string) error {
+	session, err := connect(address)
+	if err != nil {
 		return err
 	}
-
-	return nil
+	defer session.Close()
+	return waitForIndexes(session, databaseName)
 }
 
 type rethinkAPIServer struct {
 	protorpclog.Logger
-	session      *gorethink.Session
-	databaseName string
-	timer        pkgtime.Timer
+	// session is replaced by redial when its connection is lost.
+	session     *gorethink.Session
+	sessionLock sync.RWMutex
+	// dial opens a session with the server's RethinkOptions.
+	dial           func() (*gorethink.Session, error)
+	rethinkOptions RethinkOptions
+	databaseName   string
+	timer          pkgtime.Timer
+	// cancel stops the goroutines the server started.
+	cancel context.CancelFunc
+	// pruneJobsInterval, pruneJobsMaxAge and pruneJobsKeepPerPipeline are
+	// set by WithPruneJobs.
+	pruneJobsInterval        time.Duration
+	pruneJobsMaxAge          time.Duration
+	pruneJobsKeepPerPipeline uint64
 }
 
-func newRethinkAPIServer(address string, databaseName string) (*rethinkAPIServer, error) {
-	session, err := connect(address)
+func newRethinkAPIServer(address string, databaseName string, options ...Option) (*rethinkAPIServer, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	a := &rethinkAPIServer{
+		Logger:       protorpclog.NewLogger("pachyderm.ppsclient.persist.API"),
+		databaseName: databaseName,
+		timer:        pkgtime.NewSystemTimer(),
+		cancel:       cancel,
+	}
+	for _, option := range options {
+		option(a)
+	}
+	a.dial = func() (*gorethink.Session, error) {
+		return dial(address, a.rethinkOptions)
+	}
+	session, err := a.dial()
 	if err != nil {
+		cancel()
 		return nil, err
 	}
-	return &rethinkAPIServer{
-		protorpclog.NewLogger("pachyderm.ppsclient.persist.API"),
-		session,
-		databaseName,
-		pkgtime.NewSystemTimer(),
-	}, nil
+	a.session = session
+	if a.pruneJobsInterval > 0 {
+		go a.pruneJobsLoop(ctx)
+	}
+	return a, nil
 }
 
 func (a *rethinkAPIServer) Close() error {
-	return a.session.Close()
+	a.cancel()
+	return a.getSession().Close()
 }
 
 // Timestamp cannot be set
@@ -209,35 +422,119 @@ func (a *rethinkAPIServer) InspectJob(ctx context.Context, request *ppsclient.In
 			return gorethink.Expr(true)
 		},
 	); err != nil {
-		return nil, err
+		return nil, grpcError(err)
 	}
 	return jobInfo, nil
 }
 
-func (a *rethinkAPIServer) ListJobInfos(ctx context.Context, request *ppsclient.ListJobRequest) (response *persist.JobInfos, retErr error) {
+// ListJobInfos answers request from the index that fits its filters best
+// and filter()s on the rest, going through every job only when none of
+// them apply:
+//
+//   - pipeline and input_commit use PipelineNameAndCommitIndex
+//   - pipeline uses PipelineNameAndCreatedAt, which bounds created_at too
+//   - input_commit uses CommitIndex
+//   - state uses State
+//   - otherwise CreatedAt is used, which bounds created_at
+//
+// Jobs come out latest first, the created_at indexes read them in that order
+// and the others are sorted once read.
+func (a *rethinkAPIServer) ListJobInfos(ctx context.Context, request *persist.ListJobRequest) (response *persist.JobInfos, retErr error) {
 	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
 	query := a.getTerm(jobInfosTable)
 	commitIndexVal, err := genCommitIndex(request.InputCommit)
 	if err != nil {
 		return nil, err
 	}
+	if request.Limit < 0 {
+		return nil, fmt.Errorf("request.Limit cannot be negative")
+	}
+	if request.PageSize < 0 {
+		return nil, fmt.Errorf("request.PageSize cannot be negative")
+	}
+	if request.PageSize == 0 && request.PageToken != "" {
+		return nil, fmt.Errorf("request.PageToken requires request.PageSize")
+	}
+	if request.PageSize > 0 && (request.Pipeline == nil || len(request.InputCommit) > 0) {
+		return nil, fmt.Errorf("request.PageSize requires request.Pipeline and no request.InputCommit")
+	}
+	limit := request.Limit
+	if limit == 0 && (len(request.State) > 0 || request.CreatedAfter != nil || request.CreatedBefore != nil) {
+		limit = defaultListJobInfosLimit
+	}
+	if request.PageSize > 0 {
+		// One more job than PageSize is fetched to tell whether there's
+		// another page.
+		limit = request.PageSize + 1
+	}
+	var states []interface{}
+	for _, state := range request.State {
+		states = append(states, state)
+	}
+	stateFiltered := len(states) == 0
+	createdAtFiltered := false
 	if request.Pipeline != nil && len(request.InputCommit) > 0 {
 		query = query.GetAllByIndex(
 			pipelineNameAndCommitIndex,
 			gorethink.Expr([]interface{}{request.Pipeline.Name, commitIndexVal}),
 		)
 	} else if request.Pipeline != nil {
-		query = query.GetAllByIndex(
-			pipelineNameIndex,
-			request.Pipeline.Name,
+		lowerKey, upperKey, err := createdAtBounds(request, request.Pipeline.Name)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Between(
+			lowerKey,
+			upperKey,
+			gorethink.BetweenOpts{Index: pipelineNameAndCreatedAtIndex},
+		).OrderBy(
+			gorethink.OrderByOpts{Index: gorethink.Desc(pipelineNameAndCreatedAtIndex)},
 		)
+		createdAtFiltered = true
 	} else if len(request.InputCommit) > 0 {
 		query = query.GetAllByIndex(
 			commitIndex,
 			gorethink.Expr(commitIndexVal),
 		)
+	} else if len(states) > 0 {
+		query = query.GetAllByIndex(stateIndex, states...)
+		stateFiltered = true
+	} else {
+		lowerKey, upperKey, err := createdAtBounds(request)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Between(
+			lowerKey,
+			upperKey,
+			gorethink.BetweenOpts{Index: createdAtIndex},
+		).OrderBy(
+			gorethink.OrderByOpts{Index: gorethink.Desc(createdAtIndex)},
+		)
+		createdAtFiltered = true
+	}
+	if !stateFiltered {
+		query = query.Filter(func(jobInfo gorethink.Term) gorethink.Term {
+			return gorethink.Expr(states).Contains(jobInfo.Field("State"))
+		})
+	}
+	if !createdAtFiltered {
+		if request.CreatedAfter != nil {
+			query = query.Filter(func(jobInfo gorethink.Term) gorethink.Term {
+				return createdAtKey(jobInfo).Ge([]interface{}{request.CreatedAfter.Seconds, request.CreatedAfter.Nanos})
+			})
+		}
+		if request.CreatedBefore != nil {
+			query = query.Filter(func(jobInfo gorethink.Term) gorethink.Term {
+				return createdAtKey(jobInfo).Lt([]interface{}{request.CreatedBefore.Seconds, request.CreatedBefore.Nanos})
+			})
+		}
+		query = query.OrderBy(gorethink.Desc(createdAtKey))
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
 	}
-	cursor, err := query.Run(a.session)
+	cursor, err := a.run(query)
 	if err != nil {
 		return nil, err
 	}
@@ -257,34 +554,245 @@ func (a *rethinkAPIServer) ListJobInfos(ctx context.Context, request *ppsclient.
 	if err := cursor.Err(); err != nil {
 		return nil, err
 	}
+	if request.PageSize > 0 && int64(len(result.JobInfo)) > request.PageSize {
+		result.JobInfo = result.JobInfo[:request.PageSize]
+		last := result.JobInfo[len(result.JobInfo)-1]
+		result.NextPageToken = encodePageToken(last.CreatedAt, last.JobID)
+	}
 	return result, nil
 }
 
-func (a *rethinkAPIServer) DeleteJobInfo(ctx context.Context, request *ppsclient.Job) (response *google_protobuf.Empty, err error) {
+func (a *rethinkAPIServer) GetJobInfosByCommit(ctx context.Context, request *pfs.Commit) (response *persist.JobInfos, retErr error) {
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	if request.Repo == nil || request.ID == "" {
+		return nil, fmt.Errorf("request.Repo and request.ID should be set")
+	}
+	cursor, err := a.run(a.getTerm(jobInfosTable).GetAllByIndex(
+		inputCommitIndex,
+		gorethink.Expr([]interface{}{request.Repo.Name, request.ID}),
+	).OrderBy(gorethink.Desc(createdAtKey)))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := cursor.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+	result := &persist.JobInfos{}
+	for {
+		jobInfo := &persist.JobInfo{}
+		if !cursor.Next(jobInfo) {
+			break
+		}
+		result.JobInfo = append(result.JobInfo, jobInfo)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DeleteJobInfo is idempotent, deleting a job that's already gone succeeds
+// and counts nothing.
+func (a *rethinkAPIServer) DeleteJobInfo(ctx context.Context, request *ppsclient.Job) (response *persist.DeleteJobInfosResponse, err error) {
 	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
-	if err := a.deleteMessageByPrimaryKey(jobInfosTable, request.ID); err != nil {
+	if request.ID == "" {
+		return nil, fmt.Errorf("request.ID should be set")
+	}
+	writeResponse, err := a.runWrite(a.getTerm(jobInfosTable).Get(request.ID).Delete())
+	if err != nil {
 		return nil, err
 	}
-	return google_protobuf.EmptyInstance, nil
+	return &persist.DeleteJobInfosResponse{JobInfos: uint64(writeResponse.Deleted)}, nil
 }
 
-func (a *rethinkAPIServer) DeleteJobInfosForPipeline(ctx context.Context, request *ppsclient.Pipeline) (response *google_protobuf.Empty, err error) {
+func (a *rethinkAPIServer) DeleteJobInfosForPipeline(ctx context.Context, request *ppsclient.Pipeline) (response *persist.DeleteJobInfosResponse, err error) {
 	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
-	_, err = a.getTerm(jobInfosTable).GetAllByIndex(
+	writeResponse, err := a.runWrite(a.getTerm(jobInfosTable).GetAllByIndex(
 		pipelineNameIndex,
 		request.Name,
-	).Delete().RunWrite(a.session)
-	return google_protobuf.EmptyInstance, err
+	).Delete())
+	if err != nil {
+		return nil, err
+	}
+	return &persist.DeleteJobInfosResponse{JobInfos: uint64(writeResponse.Deleted)}, nil
+}
+
+// PruneJobs finds every job request matches, then deletes them
+// pruneJobsBatchSize at a time.
+func (a *rethinkAPIServer) PruneJobs(ctx context.Context, request *persist.PruneJobsRequest) (response *persist.DeleteJobInfosResponse, retErr error) {
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	if request.CreatedBefore == nil && request.KeepPerPipeline == 0 {
+		return nil, fmt.Errorf("request.CreatedBefore or request.KeepPerPipeline should be set")
+	}
+	jobIDs, err := a.pruneJobIDs(request)
+	if err != nil {
+		return nil, err
+	}
+	if request.DryRun {
+		return &persist.DeleteJobInfosResponse{JobInfos: uint64(len(jobIDs))}, nil
+	}
+	response = &persist.DeleteJobInfosResponse{}
+	for len(jobIDs) > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		batch := jobIDs
+		if len(batch) > pruneJobsBatchSize {
+			batch = batch[:pruneJobsBatchSize]
+		}
+		jobIDs = jobIDs[len(batch):]
+		writeResponse, err := a.runWrite(a.getTerm(jobInfosTable).GetAll(batch...).Delete())
+		if err != nil {
+			return nil, err
+		}
+		response.JobInfos += uint64(writeResponse.Deleted)
+	}
+	return response, nil
+}
+
+// pruneJobIDs returns the IDs of the jobs request matches. With
+// KeepPerPipeline each pipeline's jobs are read on their own, from
+// PipelineNameAndCreatedAt, otherwise they're read from CreatedAt.
+func (a *rethinkAPIServer) pruneJobIDs(request *persist.PruneJobsRequest) ([]interface{}, error) {
+	if request.KeepPerPipeline == 0 {
+		return a.readJobIDs(a.getTerm(jobInfosTable).Between(
+			gorethink.MinVal,
+			[]interface{}{request.CreatedBefore.Seconds, request.CreatedBefore.Nanos},
+			gorethink.BetweenOpts{Index: createdAtIndex},
+		))
+	}
+	var pipelineNames []string
+	cursor, err := a.run(a.getTerm(jobInfosTable).Distinct(gorethink.DistinctOpts{Index: pipelineNameIndex}))
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(&pipelineNames); err != nil {
+		return nil, err
+	}
+	var result []interface{}
+	for _, pipelineName := range pipelineNames {
+		query := a.getTerm(jobInfosTable).Between(
+			[]interface{}{pipelineName, gorethink.MinVal},
+			[]interface{}{pipelineName, gorethink.MaxVal},
+			gorethink.BetweenOpts{Index: pipelineNameAndCreatedAtIndex},
+		).OrderBy(
+			gorethink.OrderByOpts{Index: gorethink.Desc(pipelineNameAndCreatedAtIndex)},
+		).Skip(request.KeepPerPipeline)
+		if request.CreatedBefore != nil {
+			query = query.Filter(func(jobInfo gorethink.Term) gorethink.Term {
+				return createdAtKey(jobInfo).Lt([]interface{}{request.CreatedBefore.Seconds, request.CreatedBefore.Nanos})
+			})
+		}
+		jobIDs, err := a.readJobIDs(query)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, jobIDs...)
+	}
+	return result, nil
+}
+
+func (a *rethinkAPIServer) readJobIDs(query gorethink.Term) ([]interface{}, error) {
+	var jobIDs []string
+	cursor, err := a.run(query.Field("JobID"))
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(&jobIDs); err != nil {
+		return nil, err
+	}
+	var result []interface{}
+	for _, jobID := range jobIDs {
+		result = append(result, jobID)
+	}
+	return result, nil
+}
+
+// pruneJobsLoop prunes jobs as WithPruneJobs set until ctx is done.
+// PruneJobs logs its own errors, and a failed run is tried again at the
+// next interval.
+func (a *rethinkAPIServer) pruneJobsLoop(ctx context.Context) {
+	ticker := time.NewTicker(a.pruneJobsInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		request := &persist.PruneJobsRequest{KeepPerPipeline: a.pruneJobsKeepPerPipeline}
+		if a.pruneJobsMaxAge > 0 {
+			request.CreatedBefore = prototime.TimeToTimestamp(a.timer.Now().Add(-a.pruneJobsMaxAge))
+		}
+		a.PruneJobs(ctx, request)
+	}
 }
 
 func (a *rethinkAPIServer) CreateJobOutput(ctx context.Context, request *persist.JobOutput) (response *google_protobuf.Empty, err error) {
 	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	if request.JobID == "" {
+		return nil, fmt.Errorf("request.JobID should be set")
+	}
 	if err := a.updateMessage(jobInfosTable, request); err != nil {
 		return nil, err
 	}
 	return google_protobuf.EmptyInstance, nil
 }
 
+func (a *rethinkAPIServer) GetJobOutput(ctx context.Context, request *ppsclient.Job) (response *persist.JobOutput, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	if request.ID == "" {
+		return nil, fmt.Errorf("request.ID should be set")
+	}
+	jobInfo := &persist.JobInfo{}
+	if err := a.getMessageByPrimaryKey(jobInfosTable, request.ID, jobInfo); err != nil {
+		return nil, grpcError(err)
+	}
+	if jobInfo.OutputCommit == nil {
+		return nil, grpc.Errorf(codes.NotFound, "job %s has no output", request.ID)
+	}
+	return &persist.JobOutput{
+		JobID:        jobInfo.JobID,
+		OutputCommit: jobInfo.OutputCommit,
+	}, nil
+}
+
+func (a *rethinkAPIServer) GetJobOutputsByCommit(ctx context.Context, request *pfs.Commit) (response *persist.JobOutputs, retErr error) {
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	if request.Repo == nil || request.ID == "" {
+		return nil, fmt.Errorf("request.Repo and request.ID should be set")
+	}
+	cursor, err := a.run(a.getTerm(jobInfosTable).GetAllByIndex(
+		outputCommitIndex,
+		gorethink.Expr([]interface{}{request.Repo.Name, request.ID}),
+	).OrderBy(gorethink.Desc(createdAtKey)))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := cursor.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+	result := &persist.JobOutputs{}
+	for {
+		jobInfo := &persist.JobInfo{}
+		if !cursor.Next(jobInfo) {
+			break
+		}
+		result.JobOutput = append(result.JobOutput, &persist.JobOutput{
+			JobID:        jobInfo.JobID,
+			OutputCommit: jobInfo.OutputCommit,
+		})
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (a *rethinkAPIServer) CreateJobState(ctx context.Context, request *persist.JobState) (response *google_protobuf.Empty, err error) {
 	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
 	if err := a.updateMessage(jobInfosTable, request); err != nil {
@@ -293,6 +801,70 @@ func (a *rethinkAPIServer) CreateJobState(ctx context.Context, request *persist.
 	return google_protobuf.EmptyInstance, nil
 }
 
+func (a *rethinkAPIServer) GetJobState(ctx context.Context, request *ppsclient.Job) (response *persist.JobState, retErr error) {
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	if request.ID == "" {
+		return nil, fmt.Errorf("request.ID should be set")
+	}
+	// Only the state is read, a job's inputs and transform can be large.
+	cursor, err := a.run(a.getTerm(jobInfosTable).Get(request.ID).Do(func(jobInfo gorethink.Term) interface{} {
+		return gorethink.Branch(jobInfo.Eq(nil), nil, jobInfo.Pluck("JobID", "State"))
+	}))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := cursor.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+	jobState := &persist.JobState{}
+	if cursor.IsNil() || !cursor.Next(jobState) {
+		if err := cursor.Err(); err != nil {
+			return nil, err
+		}
+		return nil, grpcError(&ErrNotFound{jobInfosTable, request.ID})
+	}
+	return jobState, nil
+}
+
+func (a *rethinkAPIServer) UpdateJobState(ctx context.Context, request *persist.JobState) (response *google_protobuf.Empty, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	update := map[string]interface{}{
+		"State": request.State,
+	}
+	if request.State == ppsclient.JobState_JOB_SUCCESS || request.State == ppsclient.JobState_JOB_FAILURE {
+		update["FinishedAt"] = a.now()
+	}
+	// The check and the write happen in one update so concurrent updaters
+	// can't race past each other.
+	writeResponse, err := a.runWrite(a.getTerm(jobInfosTable).Get(request.JobID).Update(func(jobInfo gorethink.Term) interface{} {
+		state := jobInfo.Field("State").Default(ppsclient.JobState_JOB_PULLING)
+		return gorethink.Branch(
+			gorethink.Or(
+				state.Eq(ppsclient.JobState_JOB_SUCCESS),
+				state.Eq(ppsclient.JobState_JOB_FAILURE),
+				gorethink.And(
+					state.Eq(ppsclient.JobState_JOB_RUNNING),
+					request.State == ppsclient.JobState_JOB_PULLING,
+				),
+			),
+			gorethink.Error(illegalTransitionMessage),
+			update,
+		)
+	}))
+	if err != nil {
+		if strings.Contains(writeResponse.FirstError, illegalTransitionMessage) {
+			return nil, grpcError(&ErrIllegalTransition{request.JobID, request.State})
+		}
+		return nil, err
+	}
+	if writeResponse.Skipped > 0 {
+		return nil, grpcError(&ErrNotFound{jobInfosTable, request.JobID})
+	}
+	return google_protobuf.EmptyInstance, nil
+}
+
 func (a *rethinkAPIServer) UpdatePipelineState(ctx context.Context, request *persist.UpdatePipelineStateRequest) (response *google_protobuf.Empty, err error) {
 	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
 	if err := a.updateMessage(pipelineInfosTable, request); err != nil {
@@ -308,6 +880,7 @@ func (a *rethinkAPIServer) CreatePipelineInfo(ctx context.Context, request *pers
 		return nil, ErrTimestampSet
 	}
 	request.CreatedAt = a.now()
+	request.Revision = 1
 	if err := a.insertMessage(pipelineInfosTable, request); err != nil {
 		return nil, err
 	}
@@ -318,18 +891,69 @@ func (a *rethinkAPIServer) GetPipelineInfo(ctx context.Context, request *ppsclie
 	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
 	pipelineInfo := &persist.PipelineInfo{}
 	if err := a.getMessageByPrimaryKey(pipelineInfosTable, request.Name, pipelineInfo); err != nil {
-		return nil, err
+		return nil, grpcError(err)
 	}
 	return pipelineInfo, nil
 }
 
+// UpdatePipelineInfo checks the revision and replaces the row in a single
+// write, so of two updates from the same revision only one succeeds.
+// Pipelines created before revisions were added have revision 0.
+func (a *rethinkAPIServer) UpdatePipelineInfo(ctx context.Context, request *persist.PipelineInfo) (response *persist.PipelineInfo, retErr error) {
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	if request.PipelineName == "" {
+		return nil, fmt.Errorf("request.PipelineName should be set")
+	}
+	revision := request.Revision
+	update := proto.Clone(request).(*persist.PipelineInfo)
+	update.CreatedAt = nil
+	update.UpdatedAt = a.now()
+	update.Revision = revision + 1
+	cursor, err := a.runWriteCursor(a.getTerm(pipelineInfosTable).Get(request.PipelineName).Replace(func(pipelineInfo gorethink.Term) interface{} {
+		return gorethink.Branch(
+			pipelineInfo.Eq(nil),
+			gorethink.Error(notFoundMessage),
+			pipelineInfo.Field("Revision").Default(0).Ne(revision),
+			gorethink.Error(conflictMessage),
+			gorethink.Expr(update).Merge(map[string]interface{}{
+				"CreatedAt": pipelineInfo.Field("CreatedAt"),
+			}),
+		)
+	}, gorethink.ReplaceOpts{
+		ReturnChanges: true,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := cursor.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+	var writeResponse pipelineInfoWriteResponse
+	if !cursor.Next(&writeResponse) {
+		return nil, cursor.Err()
+	}
+	switch {
+	case strings.Contains(writeResponse.FirstError, conflictMessage):
+		return nil, ErrConflict
+	case strings.Contains(writeResponse.FirstError, notFoundMessage):
+		return nil, grpcError(&ErrNotFound{pipelineInfosTable, request.PipelineName})
+	case writeResponse.FirstError != "":
+		return nil, errors.New(writeResponse.FirstError)
+	case len(writeResponse.Changes) != 1 || writeResponse.Changes[0].NewVal == nil:
+		return nil, fmt.Errorf("%v %v wasn't updated; this is likely a bug", pipelineInfosTable, request.PipelineName)
+	}
+	return writeResponse.Changes[0].NewVal, nil
+}
+
 func (a *rethinkAPIServer) ListPipelineInfos(ctx context.Context, request *persist.ListPipelineInfosRequest) (response *persist.PipelineInfos, retErr error) {
 	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
 	query := a.getTerm(pipelineInfosTable)
 	if request.Shard != nil {
 		query = query.GetAllByIndex(pipelineShardIndex, request.Shard.Number)
 	}
-	cursor, err := query.Run(a.session)
+	cursor, err := a.run(query)
 	if err != nil {
 		return nil, err
 	}
@@ -365,6 +989,16 @@ type PipelineChangeFeed struct {
 	NewVal *persist.PipelineInfo `gorethink:"new_val,omitempty"`
 }
 
+// pipelineInfoWriteResponse is the result of a write to pipelineInfosTable
+// run with ReturnChanges.
+type pipelineInfoWriteResponse struct {
+	FirstError string               `gorethink:"first_error"`
+	Changes    []PipelineChangeFeed `gorethink:"changes"`
+}
+
+// SubscribePipelineInfos streams changes to pipeline infos until the client
+// goes away, which ends it without an error. If the changefeed dies first the
+// stream ends with an error, since the client would otherwise wait forever.
 func (a *rethinkAPIServer) SubscribePipelineInfos(request *persist.SubscribePipelineInfosRequest, server persist.API_SubscribePipelineInfosServer) (retErr error) {
 	defer func(start time.Time) { a.Log(request, nil, retErr, time.Since(start)) }(time.Now())
 	query := a.getTerm(pipelineInfosTable)
@@ -372,29 +1006,51 @@ func (a *rethinkAPIServer) SubscribePipelineInfos(request *persist.SubscribePipe
 		query = query.GetAllByIndex(pipelineShardIndex, request.Shard.Number)
 	}
 
-	cursor, err := query.Changes(gorethink.ChangesOpts{
+	cursor, err := a.run(query.Changes(gorethink.ChangesOpts{
 		IncludeInitial: request.IncludeInitial,
-	}).Run(a.session)
+	}))
 	if err != nil {
 		return err
 	}
+	ctx := server.Context()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		// Closing the cursor is what unblocks Next.
+		cursor.Close()
+	}()
 
 	var change PipelineChangeFeed
 	for cursor.Next(&change) {
+		var pipelineInfoChange *persist.PipelineInfoChange
 		if change.NewVal != nil {
-			server.Send(&persist.PipelineInfoChange{
+			pipelineInfoChange = &persist.PipelineInfoChange{
 				Pipeline: change.NewVal,
-			})
+			}
 		} else if change.OldVal != nil {
-			server.Send(&persist.PipelineInfoChange{
+			pipelineInfoChange = &persist.PipelineInfoChange{
 				Pipeline: change.OldVal,
 				Removed:  true,
-			})
+			}
 		} else {
 			return fmt.Errorf("neither old_val nor new_val was present in the changefeed; this is likely a bug")
 		}
+		if err := server.Send(pipelineInfoChange); err != nil {
+			return err
+		}
+		change = PipelineChangeFeed{}
 	}
-	return cursor.Err()
+	if ctx.Err() != nil {
+		return nil
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("pipeline infos changefeed failed: %v", err)
+	}
+	return fmt.Errorf("pipeline infos changefeed ended unexpectedly")
 }
 
 func (a *rethinkAPIServer) StartPod(ctx context.Context, request *ppsclient.Job) (response *persist.JobInfo, retErr error) {
@@ -413,11 +1069,11 @@ func (a *rethinkAPIServer) FailPod(ctx context.Context, request *ppsclient.Job)
 }
 
 func (a *rethinkAPIServer) shardOp(ctx context.Context, request *ppsclient.Job, field string) (response *persist.JobInfo, retErr error) {
-	cursor, err := a.getTerm(jobInfosTable).Get(request.ID).Update(map[string]interface{}{
+	cursor, err := a.runWriteCursor(a.getTerm(jobInfosTable).Get(request.ID).Update(map[string]interface{}{
 		field: gorethink.Row.Field(field).Add(1).Default(0),
 	}, gorethink.UpdateOpts{
 		ReturnChanges: true,
-	}).Field("changes").Field("new_val").Run(a.session)
+	}).Field("changes").Field("new_val"))
 	if err != nil {
 		return nil, err
 	}
@@ -432,42 +1088,119 @@ func (a *rethinkAPIServer) shardOp(ctx context.Context, request *ppsclient.Job,
 }
 
 func (a *rethinkAPIServer) StartJob(ctx context.Context, job *ppsclient.Job) (response *google_protobuf.Empty, err error) {
-	_, err = a.getTerm(jobInfosTable).Get(job.ID).Update(gorethink.Branch(
+	_, err = a.runWrite(a.getTerm(jobInfosTable).Get(job.ID).Update(gorethink.Branch(
 		gorethink.Row.Field("State").Eq(ppsclient.JobState_JOB_PULLING),
 		map[string]interface{}{
 			"State": ppsclient.JobState_JOB_RUNNING,
 		},
 		map[string]interface{}{},
-	)).RunWrite(a.session)
+	)))
 	return google_protobuf.EmptyInstance, err
 }
 
+// JobChangeFeed is a change to a row of jobInfosTable.
+type JobChangeFeed struct {
+	OldVal *persist.JobInfo `gorethink:"old_val,omitempty"`
+	NewVal *persist.JobInfo `gorethink:"new_val,omitempty"`
+}
+
+// WatchJobStates ends without an error when the client goes away or the job
+// is deleted. Changes to a job that leave its state alone, such as its pod
+// counts, aren't sent.
+func (a *rethinkAPIServer) WatchJobStates(request *ppsclient.Job, server persist.API_WatchJobStatesServer) (retErr error) {
+	defer func(start time.Time) { a.Log(request, nil, retErr, time.Since(start)) }(time.Now())
+	if request.ID == "" {
+		return fmt.Errorf("request.ID should be set")
+	}
+	state := func(jobInfo gorethink.Term) gorethink.Term {
+		return jobInfo.Field("State").Default(ppsclient.JobState_JOB_PULLING)
+	}
+	cursor, err := a.run(a.getTerm(jobInfosTable).
+		Get(request.ID).
+		Default(gorethink.Error(notFoundMessage)).
+		Changes(gorethink.ChangesOpts{
+			IncludeInitial: true,
+		}).
+		Filter(func(change gorethink.Term) gorethink.Term {
+			return gorethink.Or(
+				change.Field("old_val").Default(nil).Eq(nil),
+				change.Field("new_val").Default(nil).Eq(nil),
+				state(change.Field("old_val")).Ne(state(change.Field("new_val"))),
+			)
+		}))
+	if err != nil {
+		if strings.Contains(err.Error(), notFoundMessage) {
+			err = &ErrNotFound{jobInfosTable, request.ID}
+		}
+		return grpcError(err)
+	}
+	ctx := server.Context()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		// Closing the cursor is what unblocks Next.
+		cursor.Close()
+	}()
+
+	var change JobChangeFeed
+	for cursor.Next(&change) {
+		if change.NewVal == nil {
+			// The job was deleted, its state won't change again.
+			return nil
+		}
+		if err := server.Send(&persist.JobState{
+			JobID: request.ID,
+			State: change.NewVal.State,
+		}); err != nil {
+			return err
+		}
+		change = JobChangeFeed{}
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("job %s changefeed failed: %v", request.ID, err)
+	}
+	return fmt.Errorf("job %s changefeed ended unexpectedly", request.ID)
+}
+
 func (a *rethinkAPIServer) insertMessage(table Table, message proto.Message) error {
-	_, err := a.getTerm(table).Insert(message).RunWrite(a.session)
+	_, err := a.runWrite(a.getTerm(table).Insert(message))
 	return err
 }
 
 func (a *rethinkAPIServer) updateMessage(table Table, message proto.Message) error {
-	_, err := a.getTerm(table).Insert(message, gorethink.InsertOpts{Conflict: "update"}).RunWrite(a.session)
+	_, err := a.runWrite(a.getTerm(table).Insert(message, gorethink.InsertOpts{Conflict: "update"}))
 	return err
 }
 
-func (a *rethinkAPIServer) getMessageByPrimaryKey(table Table, key interface{}, message proto.Message) error {
-	cursor, err := a.getTerm(table).Get(key).Run(a.session)
+// getMessageByPrimaryKey returns an ErrNotFound if there's no row with key.
+func (a *rethinkAPIServer) getMessageByPrimaryKey(table Table, key interface{}, message proto.Message) (retErr error) {
+	cursor, err := a.run(a.getTerm(table).Get(key))
 	if err != nil {
 		return err
 	}
-	if cursor.IsNil() {
-		return fmt.Errorf("%v %v not found", table, key)
-	}
-	if cursor.Next(message) {
-		return cursor.Err()
+	defer func() {
+		if err := cursor.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+	if cursor.IsNil() || !cursor.Next(message) {
+		if err := cursor.Err(); err != nil {
+			return err
+		}
+		return &ErrNotFound{table, key}
 	}
 	return nil
 }
 
 func (a *rethinkAPIServer) deleteMessageByPrimaryKey(table Table, value interface{}) (retErr error) {
-	_, err := a.getTerm(table).Get(value).Delete().RunWrite(a.session)
+	_, err := a.runWrite(a.getTerm(table).Get(value).Delete())
 	return err
 }
 
@@ -485,10 +1218,10 @@ func (a *rethinkAPIServer) waitMessageByPrimaryKey(
 		}).
 		Field("new_val").
 		Filter(predicate)
-	cursor, err := term.Run(a.session)
+	cursor, err := a.run(term)
 	if err != nil {
 		if strings.Contains(err.Error(), "value not found") {
-			err = fmt.Errorf("%v %v not found", table, key)
+			err = &ErrNotFound{table, key}
 		}
 		return err
 	}
@@ -501,6 +1234,21 @@ func (a *rethinkAPIServer) waitMessageByPrimaryKey(
 	return cursor.Err()
 }
 
+// grpcError returns err as clients should see it, an ErrNotFound becomes a
+// gRPC NotFound error, an ErrIllegalTransition a FailedPrecondition one and
+// an ErrUnavailable an Unavailable one.
+func grpcError(err error) error {
+	switch err := err.(type) {
+	case *ErrNotFound:
+		return grpc.Errorf(codes.NotFound, "%s", err.Error())
+	case *ErrIllegalTransition:
+		return grpc.Errorf(codes.FailedPrecondition, "%s", err.Error())
+	case *ErrUnavailable:
+		return grpc.Errorf(codes.Unavailable, "%s", err.Error())
+	}
+	return err
+}
+
 func (a *rethinkAPIServer) getTerm(table Table) gorethink.Term {
 	return gorethink.DB(a.databaseName).Table(table)
 }
@@ -510,12 +1258,175 @@ func (a *rethinkAPIServer) now() *google_protobuf.Timestamp {
 }
 
 func connect(address string) (*gorethink.Session, error) {
+	return dial(address, RethinkOptions{})
+}
+
+func dial(address string, options RethinkOptions) (*gorethink.Session, error) {
 	return gorethink.Connect(gorethink.ConnectOpts{
 		Address: address,
 		Timeout: connectTimeoutSeconds * time.Second,
+		MaxOpen: options.MaxOpen,
+		MaxIdle: options.MaxIdle,
 	})
 }
 
+func (a *rethinkAPIServer) getSession() *gorethink.Session {
+	a.sessionLock.RLock()
+	defer a.sessionLock.RUnlock()
+	return a.session
+}
+
+// redial replaces failed, a session whose connection was lost, with a new
+// one. If another call already replaced it, that session is kept.
+func (a *rethinkAPIServer) redial(failed *gorethink.Session) error {
+	a.sessionLock.Lock()
+	defer a.sessionLock.Unlock()
+	if a.session != failed {
+		return nil
+	}
+	session, err := a.dial()
+	if err != nil {
+		return err
+	}
+	if failed != nil {
+		failed.Close()
+	}
+	a.session = session
+	return nil
+}
+
+// read calls run with the server's session. If the connection was lost it
+// redials and calls run once more, so run must only read.
+func (a *rethinkAPIServer) read(run func(session *gorethink.Session) error) error {
+	session := a.getSession()
+	err := run(session)
+	if !isConnectionError(err) {
+		return err
+	}
+	if err := a.redial(session); err != nil {
+		return &ErrUnavailable{err}
+	}
+	if err := run(a.getSession()); err != nil {
+		if isConnectionError(err) {
+			return &ErrUnavailable{err}
+		}
+		return err
+	}
+	return nil
+}
+
+// write calls run with the server's session. Writes aren't retried, one cut
+// off by a lost connection may still have been applied, so the error is
+// returned as an ErrUnavailable and only later calls get a new session.
+func (a *rethinkAPIServer) write(run func(session *gorethink.Session) error) error {
+	session := a.getSession()
+	err := run(session)
+	if !isConnectionError(err) {
+		return err
+	}
+	if err := a.redial(session); err != nil {
+		protolion.Errorf("error reconnecting to rethink: %s", err.Error())
+	}
+	return &ErrUnavailable{err}
+}
+
+func (a *rethinkAPIServer) run(term gorethink.Term) (*gorethink.Cursor, error) {
+	var cursor *gorethink.Cursor
+	err := a.read(func(session *gorethink.Session) error {
+		var err error
+		cursor, err = term.Run(session)
+		return err
+	})
+	return cursor, err
+}
+
+func (a *rethinkAPIServer) runWrite(term gorethink.Term) (gorethink.WriteResponse, error) {
+	var response gorethink.WriteResponse
+	err := a.write(func(session *gorethink.Session) error {
+		var err error
+		response, err = term.RunWrite(session)
+		return err
+	})
+	return response, err
+}
+
+// runWriteCursor is runWrite for writes whose changes are read from a
+// cursor.
+func (a *rethinkAPIServer) runWriteCursor(term gorethink.Term) (*gorethink.Cursor, error) {
+	var cursor *gorethink.Cursor
+	err := a.write(func(session *gorethink.Session) error {
+		var err error
+		cursor, err = term.Run(session)
+		return err
+	})
+	return cursor, err
+}
+
+// isConnectionError returns true if err means the session's connection to
+// rethink was lost, rather than that the query failed.
+func isConnectionError(err error) bool {
+	switch err.(type) {
+	case gorethink.RQLConnectionError, *gorethink.RQLConnectionError:
+		return true
+	}
+	return err == gorethink.ErrConnectionClosed || err == gorethink.ErrNoConnections
+}
+
+// createdAtBounds returns the keys to pass to Between on one of the
+// created_at indexes, prefixed by prefix, for request's created_at bounds
+// and page token.
+func createdAtBounds(request *persist.ListJobRequest, prefix ...interface{}) ([]interface{}, []interface{}, error) {
+	lowerKey := append(append([]interface{}{}, prefix...), gorethink.MinVal)
+	if request.CreatedAfter != nil {
+		lowerKey = append(append([]interface{}{}, prefix...), request.CreatedAfter.Seconds, request.CreatedAfter.Nanos)
+	}
+	// The upper key is excluded, so is the job the token points to, the
+	// last one of the previous page.
+	upperKey := append(append([]interface{}{}, prefix...), gorethink.MaxVal)
+	if request.CreatedBefore != nil {
+		upperKey = append(append([]interface{}{}, prefix...), request.CreatedBefore.Seconds, request.CreatedBefore.Nanos)
+	}
+	if request.PageToken != "" {
+		createdAt, jobID, err := decodePageToken(request.PageToken)
+		if err != nil {
+			return nil, nil, err
+		}
+		upperKey = append(append([]interface{}{}, prefix...), createdAt.Seconds, createdAt.Nanos, jobID)
+	}
+	return lowerKey, upperKey, nil
+}
+
+// createdAtKey is what jobs are ordered by when they aren't read from a
+// created_at index.
+func createdAtKey(jobInfo gorethink.Term) gorethink.Term {
+	return gorethink.Expr([]interface{}{
+		jobInfo.Field("CreatedAt").Field("Seconds"),
+		jobInfo.Field("CreatedAt").Field("Nanos"),
+	})
+}
+
+// encodePageToken returns the token for the page after the job created at
+// createdAt with jobID.
+func encodePageToken(createdAt *google_protobuf.Timestamp, jobID string) string {
+	return fmt.Sprintf("%d.%d.%s", createdAt.Seconds, createdAt.Nanos, jobID)
+}
+
+func decodePageToken(pageToken string) (*google_protobuf.Timestamp, string, error) {
+	parts := strings.SplitN(pageToken, ".", 3)
+	if len(parts) != 3 || parts[2] == "" {
+		return nil, "", fmt.Errorf("invalid page token %q", pageToken)
+	}
+	seconds, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid page token %q", pageToken)
+	}
+	nanos, err := strconv.ParseInt(parts[1], 10, 32)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid page token %q", pageToken)
+	}
+	return &google_protobuf.Timestamp{Seconds: seconds, Nanos: int32(nanos)}, parts[2], nil
+}
+
 func genCommitIndex(commits []*pfs.Commit) (string, error) {
 	var commitIDs []string
 	for _, commit := range commits {
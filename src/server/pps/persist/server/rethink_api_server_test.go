@@ -0,0 +1,70 @@
+package server
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dancannon/gorethink"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+// newRedialTestServer returns a server with no session, whose dial stub
+// counts its calls and returns no session either.
+func newRedialTestServer(dials *int) *rethinkAPIServer {
+	return &rethinkAPIServer{
+		dial: func() (*gorethink.Session, error) {
+			*dials++
+			return nil, nil
+		},
+	}
+}
+
+func TestReadRetriedAfterLostConnection(t *testing.T) {
+	var dials, runs int
+	a := newRedialTestServer(&dials)
+	// The first run's connection is dropped, the retry succeeds.
+	require.NoError(t, a.read(func(session *gorethink.Session) error {
+		runs++
+		if runs == 1 {
+			return gorethink.RQLConnectionError{}
+		}
+		return nil
+	}))
+	require.Equal(t, 2, runs)
+	require.Equal(t, 1, dials)
+}
+
+func TestReadUnavailable(t *testing.T) {
+	var dials int
+	a := newRedialTestServer(&dials)
+	// Running on no session fails as a closed connection does.
+	_, err := a.run(gorethink.Expr(1))
+	require.True(t, IsErrUnavailable(err))
+	require.True(t, IsErrUnavailable(grpcError(err)))
+	require.Equal(t, 1, dials)
+}
+
+func TestWriteNotRetried(t *testing.T) {
+	var dials, runs int
+	a := newRedialTestServer(&dials)
+	err := a.write(func(session *gorethink.Session) error {
+		runs++
+		return gorethink.ErrConnectionClosed
+	})
+	require.True(t, IsErrUnavailable(err))
+	require.Equal(t, 1, runs)
+	// The session is still replaced for later calls.
+	require.Equal(t, 1, dials)
+}
+
+func TestQueryErrorNotRetried(t *testing.T) {
+	var dials, runs int
+	a := newRedialTestServer(&dials)
+	queryErr := errors.New("query failed")
+	require.Equal(t, queryErr, a.read(func(session *gorethink.Session) error {
+		runs++
+		return queryErr
+	}))
+	require.Equal(t, 1, runs)
+	require.Equal(t, 0, dials)
+}
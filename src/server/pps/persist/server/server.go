@@ -2,21 +2,128 @@ package server
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
+	ppsclient "github.com/pachyderm/pachyderm/src/client/pps"
 	"github.com/pachyderm/pachyderm/src/server/pps/persist"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 )
 
 var (
 	ErrIDSet        = errors.New("pachyderm.pps.persist.server: ID set")
 	ErrIDNotSet     = errors.New("pachyderm.pps.persist.server: ID not set")
 	ErrTimestampSet = errors.New("pachyderm.pps.persist.server: Timestamp set")
+	// ErrConflict is returned by UpdatePipelineInfo when the pipeline was
+	// updated after the revision being updated was read.
+	ErrConflict = errors.New("pachyderm.pps.persist.server: conflict")
 )
 
+// ErrNotFound is returned when the row being read doesn't exist. The API
+// returns it to clients as a gRPC NotFound error, so check errors with
+// IsErrNotFound rather than by type.
+type ErrNotFound struct {
+	Table Table
+	Key   interface{}
+}
+
+func (e *ErrNotFound) Error() string {
+	return fmt.Sprintf("%v %v not found", e.Table, e.Key)
+}
+
+// IsErrNotFound returns true if err is an ErrNotFound, either directly or
+// from a client.
+func IsErrNotFound(err error) bool {
+	if _, ok := err.(*ErrNotFound); ok {
+		return true
+	}
+	return grpc.Code(err) == codes.NotFound
+}
+
+// ErrUnavailable is returned when the connection to rethink was lost. Reads
+// are retried once on a new connection before it's returned, writes aren't
+// since they may have been applied.
+type ErrUnavailable struct {
+	Err error
+}
+
+func (e *ErrUnavailable) Error() string {
+	return fmt.Sprintf("rethink unavailable: %v", e.Err)
+}
+
+// IsErrUnavailable returns true if err is an ErrUnavailable, either directly
+// or from a client.
+func IsErrUnavailable(err error) bool {
+	if _, ok := err.(*ErrUnavailable); ok {
+		return true
+	}
+	return grpc.Code(err) == codes.Unavailable
+}
+
+// ErrIllegalTransition is returned by UpdateJobState when the job can't move
+// to State from its current state. The API returns it to clients as a gRPC
+// FailedPrecondition error, so check errors with IsErrIllegalTransition
+// rather than by type.
+type ErrIllegalTransition struct {
+	JobID string
+	State ppsclient.JobState
+}
+
+func (e *ErrIllegalTransition) Error() string {
+	return fmt.Sprintf("job %s cannot move to %s from its current state", e.JobID, e.State)
+}
+
+// IsErrIllegalTransition returns true if err is an ErrIllegalTransition,
+// either directly or from a client.
+func IsErrIllegalTransition(err error) bool {
+	if _, ok := err.(*ErrIllegalTransition); ok {
+		return true
+	}
+	return grpc.Code(err) == codes.FailedPrecondition
+}
+
 type APIServer interface {
 	persist.APIServer
 	Close() error
 }
 
-func NewRethinkAPIServer(address string, databaseName string) (APIServer, error) {
-	return newRethinkAPIServer(address, databaseName)
+// Option configures the server NewRethinkAPIServer returns.
+type Option func(*rethinkAPIServer)
+
+// WithPruneJobs makes the server call PruneJobs every interval until it's
+// closed. Jobs older than maxAge are pruned, and of each pipeline's jobs
+// only the latest keepPerPipeline are kept. Either can be 0 to leave that
+// policy out, if both are the option does nothing.
+func WithPruneJobs(interval time.Duration, maxAge time.Duration, keepPerPipeline uint64) Option {
+	return func(a *rethinkAPIServer) {
+		if maxAge == 0 && keepPerPipeline == 0 {
+			return
+		}
+		a.pruneJobsInterval = interval
+		a.pruneJobsMaxAge = maxAge
+		a.pruneJobsKeepPerPipeline = keepPerPipeline
+	}
+}
+
+// RethinkOptions configures the server's pool of connections to rethink,
+// zero values leave gorethink's defaults.
+type RethinkOptions struct {
+	// MaxOpen is the most connections the pool opens.
+	MaxOpen int
+	// MaxIdle is how many connections the pool keeps open while they're
+	// idle.
+	MaxIdle int
+}
+
+// WithRethinkOptions sets the options the server's rethink sessions are
+// opened with.
+func WithRethinkOptions(options RethinkOptions) Option {
+	return func(a *rethinkAPIServer) {
+		a.rethinkOptions = options
+	}
+}
+
+func NewRethinkAPIServer(address string, databaseName string, options ...Option) (APIServer, error) {
+	return newRethinkAPIServer(address, databaseName, options...)
 }
@@ -1,15 +1,22 @@
 package testing
 
 import (
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/dancannon/gorethink"
 	"github.com/pachyderm/pachyderm/src/client"
 	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/pachyderm/pachyderm/src/client/pkg/require"
 	"github.com/pachyderm/pachyderm/src/client/pkg/uuid"
 	ppsclient "github.com/pachyderm/pachyderm/src/client/pps"
 	"github.com/pachyderm/pachyderm/src/server/pps/persist"
+	"github.com/pachyderm/pachyderm/src/server/pps/persist/server"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 )
 
 func TestBasicRethink(t *testing.T) {
@@ -22,6 +29,126 @@ func TestBlock(t *testing.T) {
 	RunTestWithRethinkAPIServer(t, testBlock)
 }
 
+func TestUpdateJobState(t *testing.T) {
+	t.Skip()
+	RunTestWithRethinkAPIServer(t, testUpdateJobState)
+}
+
+func TestListJobInfosFilters(t *testing.T) {
+	t.Skip()
+	RunTestWithRethinkAPIServer(t, testListJobInfosFilters)
+}
+
+func TestListJobInfosPaging(t *testing.T) {
+	t.Skip()
+	RunTestWithRethinkAPIServer(t, testListJobInfosPaging)
+}
+
+func TestDeleteJobInfo(t *testing.T) {
+	t.Skip()
+	RunTestWithRethinkAPIServer(t, testDeleteJobInfo)
+}
+
+func TestUpdatePipelineInfo(t *testing.T) {
+	t.Skip()
+	RunTestWithRethinkAPIServer(t, testUpdatePipelineInfo)
+}
+
+func TestSubscribePipelineInfos(t *testing.T) {
+	t.Skip()
+	RunTestWithRethinkAPIServer(t, testSubscribePipelineInfos)
+}
+
+func TestNotFound(t *testing.T) {
+	t.Skip()
+	RunTestWithRethinkAPIServer(t, testNotFound)
+}
+
+func TestPruneJobs(t *testing.T) {
+	t.Skip()
+	RunTestWithRethinkAPIServer(t, testPruneJobs)
+}
+
+func TestGetJobInfosByCommit(t *testing.T) {
+	t.Skip()
+	RunTestWithRethinkAPIServer(t, testGetJobInfosByCommit)
+}
+
+func TestWatchJobStates(t *testing.T) {
+	t.Skip()
+	RunTestWithRethinkAPIServer(t, testWatchJobStates)
+}
+
+func TestJobOutputs(t *testing.T) {
+	t.Skip()
+	RunTestWithRethinkAPIServer(t, testJobOutputs)
+}
+
+func TestGetJobState(t *testing.T) {
+	t.Skip()
+	RunTestWithRethinkAPIServer(t, testGetJobState)
+}
+
+func TestListJobInfosByState(t *testing.T) {
+	t.Skip()
+	RunTestWithRethinkAPIServer(t, testListJobInfosByState)
+}
+
+func BenchmarkGetJobState(b *testing.B) {
+	b.Skip()
+	benchmarkReadJobState(b, func(apiServer persist.APIServer, job *ppsclient.Job) error {
+		_, err := apiServer.GetJobState(context.Background(), job)
+		return err
+	})
+}
+
+func BenchmarkInspectJobState(b *testing.B) {
+	b.Skip()
+	benchmarkReadJobState(b, func(apiServer persist.APIServer, job *ppsclient.Job) error {
+		_, err := apiServer.InspectJob(context.Background(), &ppsclient.InspectJobRequest{Job: job})
+		return err
+	})
+}
+
+func TestInitDBsTwice(t *testing.T) {
+	t.Skip()
+	if testing.Short() {
+		t.Skip("Skipping test because of short mode.")
+	}
+	address := "0.0.0.0:28015"
+	databaseName := uuid.NewWithoutDashes()
+	alreadyExisted, err := server.InitDBs(address, databaseName)
+	require.NoError(t, err)
+	require.False(t, alreadyExisted)
+	before := dbSchema(t, address, databaseName)
+	alreadyExisted, err = server.InitDBs(address, databaseName)
+	require.NoError(t, err)
+	require.True(t, alreadyExisted)
+	require.Equal(t, before, dbSchema(t, address, databaseName))
+	require.NoError(t, server.CheckDBs(address, databaseName))
+}
+
+// dbSchema returns the tables in a database with their indexes, and the
+// rows of its Metadata table.
+func dbSchema(t *testing.T, address string, databaseName string) map[string][]interface{} {
+	session, err := gorethink.Connect(gorethink.ConnectOpts{Address: address, Timeout: 5 * time.Second})
+	require.NoError(t, err)
+	defer session.Close()
+	all := func(term gorethink.Term) []interface{} {
+		cursor, err := term.Run(session)
+		require.NoError(t, err)
+		var result []interface{}
+		require.NoError(t, cursor.All(&result))
+		return result
+	}
+	schema := make(map[string][]interface{})
+	for _, table := range all(gorethink.DB(databaseName).TableList()) {
+		schema[table.(string)] = all(gorethink.DB(databaseName).Table(table).IndexList())
+	}
+	schema["Metadata rows"] = all(gorethink.DB(databaseName).Table("Metadata"))
+	return schema
+}
+
 func testBasicRethink(t *testing.T, apiServer persist.APIServer) {
 	_, err := apiServer.CreatePipelineInfo(
 		context.Background(),
@@ -70,7 +197,7 @@ func testBasicRethink(t *testing.T, apiServer persist.APIServer) {
 	require.Equal(t, "foo", jobInfo.PipelineName)
 	jobInfos, err := apiServer.ListJobInfos(
 		context.Background(),
-		&ppsclient.ListJobRequest{
+		&persist.ListJobRequest{
 			Pipeline: &ppsclient.Pipeline{Name: "foo"},
 		},
 	)
@@ -79,7 +206,7 @@ func testBasicRethink(t *testing.T, apiServer persist.APIServer) {
 	require.Equal(t, jobInfos.JobInfo[0].JobID, jobID)
 	jobInfos, err = apiServer.ListJobInfos(
 		context.Background(),
-		&ppsclient.ListJobRequest{
+		&persist.ListJobRequest{
 			InputCommit: []*pfsclient.Commit{input.Commit},
 		},
 	)
@@ -88,7 +215,7 @@ func testBasicRethink(t *testing.T, apiServer persist.APIServer) {
 	require.Equal(t, jobInfos.JobInfo[0].JobID, jobID)
 	jobInfos, err = apiServer.ListJobInfos(
 		context.Background(),
-		&ppsclient.ListJobRequest{
+		&persist.ListJobRequest{
 			Pipeline:    &ppsclient.Pipeline{Name: "foo"},
 			InputCommit: []*pfsclient.Commit{input.Commit},
 		},
@@ -129,3 +256,654 @@ func testBlock(t *testing.T, apiServer persist.APIServer) {
 	)
 	require.NoError(t, err)
 }
+
+func testListJobInfosPaging(t *testing.T, apiServer persist.APIServer) {
+	var jobIDs []string
+	for i := 0; i < 5; i++ {
+		jobInfo, err := apiServer.CreateJobInfo(
+			context.Background(),
+			&persist.JobInfo{
+				JobID:        uuid.NewWithoutDashes(),
+				PipelineName: "foo",
+			},
+		)
+		require.NoError(t, err)
+		jobIDs = append([]string{jobInfo.JobID}, jobIDs...)
+	}
+	// Pages go from latest to earliest
+	var pagedJobIDs []string
+	request := &persist.ListJobRequest{
+		Pipeline: &ppsclient.Pipeline{Name: "foo"},
+		PageSize: 2,
+	}
+	for i := 0; ; i++ {
+		jobInfos, err := apiServer.ListJobInfos(context.Background(), request)
+		require.NoError(t, err)
+		for _, jobInfo := range jobInfos.JobInfo {
+			pagedJobIDs = append(pagedJobIDs, jobInfo.JobID)
+		}
+		if jobInfos.NextPageToken == "" {
+			require.Equal(t, 2, i)
+			break
+		}
+		require.Equal(t, 2, len(jobInfos.JobInfo))
+		request.PageToken = jobInfos.NextPageToken
+	}
+	require.Equal(t, jobIDs, pagedJobIDs)
+
+	// Without PageSize every job is returned
+	jobInfos, err := apiServer.ListJobInfos(
+		context.Background(),
+		&persist.ListJobRequest{
+			Pipeline: &ppsclient.Pipeline{Name: "foo"},
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 5, len(jobInfos.JobInfo))
+	require.Equal(t, "", jobInfos.NextPageToken)
+
+	// Paging needs a pipeline
+	_, err = apiServer.ListJobInfos(
+		context.Background(),
+		&persist.ListJobRequest{PageSize: 2},
+	)
+	require.YesError(t, err)
+}
+
+func testUpdateJobState(t *testing.T, apiServer persist.APIServer) {
+	jobInfo, err := apiServer.CreateJobInfo(context.Background(), &persist.JobInfo{
+		JobID: uuid.NewWithoutDashes(),
+	})
+	require.NoError(t, err)
+	job := &ppsclient.Job{ID: jobInfo.JobID}
+	_, err = apiServer.UpdateJobState(context.Background(), &persist.JobState{
+		JobID: job.ID,
+		State: ppsclient.JobState_JOB_RUNNING,
+	})
+	require.NoError(t, err)
+	_, err = apiServer.UpdateJobState(context.Background(), &persist.JobState{
+		JobID: job.ID,
+		State: ppsclient.JobState_JOB_PULLING,
+	})
+	require.YesError(t, err)
+	require.Equal(t, codes.FailedPrecondition, grpc.Code(err))
+	require.True(t, server.IsErrIllegalTransition(err))
+
+	// Only one of the concurrent updaters gets to finish the job
+	var wg sync.WaitGroup
+	var succeeded int32
+	for i := 0; i < 10; i++ {
+		state := ppsclient.JobState_JOB_SUCCESS
+		if i%2 == 0 {
+			state = ppsclient.JobState_JOB_FAILURE
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := apiServer.UpdateJobState(context.Background(), &persist.JobState{
+				JobID: job.ID,
+				State: state,
+			}); err == nil {
+				atomic.AddInt32(&succeeded, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(1), succeeded)
+	jobInfo, err = apiServer.InspectJob(context.Background(), &ppsclient.InspectJobRequest{Job: job})
+	require.NoError(t, err)
+	require.True(t, jobInfo.State == ppsclient.JobState_JOB_SUCCESS || jobInfo.State == ppsclient.JobState_JOB_FAILURE)
+	require.NotNil(t, jobInfo.FinishedAt)
+
+	_, err = apiServer.UpdateJobState(context.Background(), &persist.JobState{
+		JobID: job.ID,
+		State: ppsclient.JobState_JOB_RUNNING,
+	})
+	require.YesError(t, err)
+	require.True(t, server.IsErrIllegalTransition(err))
+	_, err = apiServer.UpdateJobState(context.Background(), &persist.JobState{
+		JobID: uuid.NewWithoutDashes(),
+		State: ppsclient.JobState_JOB_RUNNING,
+	})
+	require.YesError(t, err)
+	require.False(t, server.IsErrIllegalTransition(err))
+}
+
+func testListJobInfosFilters(t *testing.T, apiServer persist.APIServer) {
+	var jobInfos []*persist.JobInfo
+	for i := 0; i < 6; i++ {
+		pipelineName := "foo"
+		if i%2 == 1 {
+			pipelineName = "bar"
+		}
+		jobInfo, err := apiServer.CreateJobInfo(
+			context.Background(),
+			&persist.JobInfo{
+				JobID:        uuid.NewWithoutDashes(),
+				PipelineName: pipelineName,
+			},
+		)
+		require.NoError(t, err)
+		if i >= 2 {
+			_, err = apiServer.UpdateJobState(context.Background(), &persist.JobState{
+				JobID: jobInfo.JobID,
+				State: ppsclient.JobState_JOB_FAILURE,
+			})
+			require.NoError(t, err)
+		}
+		jobInfos = append(jobInfos, jobInfo)
+	}
+	jobIDs := func(jobInfos []*persist.JobInfo) []string {
+		var result []string
+		for _, jobInfo := range jobInfos {
+			result = append(result, jobInfo.JobID)
+		}
+		return result
+	}
+	list := func(request *persist.ListJobRequest) []string {
+		result, err := apiServer.ListJobInfos(context.Background(), request)
+		require.NoError(t, err)
+		return jobIDs(result.JobInfo)
+	}
+
+	// Failed jobs across pipelines, latest first
+	require.Equal(t,
+		[]string{jobInfos[5].JobID, jobInfos[4].JobID, jobInfos[3].JobID, jobInfos[2].JobID},
+		list(&persist.ListJobRequest{State: []ppsclient.JobState{ppsclient.JobState_JOB_FAILURE}}),
+	)
+	require.Equal(t,
+		[]string{jobInfos[5].JobID, jobInfos[3].JobID},
+		list(&persist.ListJobRequest{
+			Pipeline: &ppsclient.Pipeline{Name: "bar"},
+			State:    []ppsclient.JobState{ppsclient.JobState_JOB_FAILURE},
+		}),
+	)
+	// created_at bounds include created_after and exclude created_before
+	require.Equal(t,
+		[]string{jobInfos[3].JobID, jobInfos[2].JobID},
+		list(&persist.ListJobRequest{
+			CreatedAfter:  jobInfos[2].CreatedAt,
+			CreatedBefore: jobInfos[4].CreatedAt,
+		}),
+	)
+	require.Equal(t,
+		[]string{jobInfos[4].JobID, jobInfos[2].JobID},
+		list(&persist.ListJobRequest{
+			Pipeline:     &ppsclient.Pipeline{Name: "foo"},
+			CreatedAfter: jobInfos[1].CreatedAt,
+		}),
+	)
+	require.Equal(t,
+		[]string{jobInfos[5].JobID, jobInfos[4].JobID},
+		list(&persist.ListJobRequest{
+			State: []ppsclient.JobState{ppsclient.JobState_JOB_FAILURE},
+			Limit: 2,
+		}),
+	)
+	require.Equal(t, 6, len(list(&persist.ListJobRequest{})))
+}
+
+func testDeleteJobInfo(t *testing.T, apiServer persist.APIServer) {
+	pipelineName := uuid.NewWithoutDashes()
+	var jobIDs []string
+	for i := 0; i < 3; i++ {
+		jobInfo, err := apiServer.CreateJobInfo(context.Background(), &persist.JobInfo{
+			JobID:        uuid.NewWithoutDashes(),
+			PipelineName: pipelineName,
+		})
+		require.NoError(t, err)
+		jobIDs = append(jobIDs, jobInfo.JobID)
+	}
+
+	response, err := apiServer.DeleteJobInfo(context.Background(), &ppsclient.Job{ID: jobIDs[0]})
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), response.JobInfos)
+	_, err = apiServer.InspectJob(context.Background(), &ppsclient.InspectJobRequest{Job: &ppsclient.Job{ID: jobIDs[0]}})
+	require.True(t, server.IsErrNotFound(err))
+	// Deleting it again succeeds but deletes nothing
+	response, err = apiServer.DeleteJobInfo(context.Background(), &ppsclient.Job{ID: jobIDs[0]})
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), response.JobInfos)
+
+	response, err = apiServer.DeleteJobInfosForPipeline(context.Background(), &ppsclient.Pipeline{Name: pipelineName})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), response.JobInfos)
+	jobInfos, err := apiServer.ListJobInfos(context.Background(), &persist.ListJobRequest{
+		Pipeline: &ppsclient.Pipeline{Name: pipelineName},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 0, len(jobInfos.JobInfo))
+}
+
+func testUpdatePipelineInfo(t *testing.T, apiServer persist.APIServer) {
+	pipelineName := uuid.NewWithoutDashes()
+	created, err := apiServer.CreatePipelineInfo(context.Background(), &persist.PipelineInfo{
+		PipelineName: pipelineName,
+		Parallelism:  1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), created.Revision)
+
+	pipelineInfo, err := apiServer.GetPipelineInfo(context.Background(), &ppsclient.Pipeline{Name: pipelineName})
+	require.NoError(t, err)
+	pipelineInfo.Parallelism = 2
+	updated, err := apiServer.UpdatePipelineInfo(context.Background(), pipelineInfo)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), updated.Revision)
+	require.Equal(t, uint64(2), updated.Parallelism)
+	require.Equal(t, created.CreatedAt, updated.CreatedAt)
+	require.NotNil(t, updated.UpdatedAt)
+
+	// Updating the stale revision again conflicts
+	_, err = apiServer.UpdatePipelineInfo(context.Background(), pipelineInfo)
+	require.Equal(t, server.ErrConflict, err)
+
+	// Only one of two concurrent updates from the same revision succeeds
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = apiServer.UpdatePipelineInfo(context.Background(), &persist.PipelineInfo{
+				PipelineName: pipelineName,
+				Parallelism:  uint64(i + 3),
+				Revision:     updated.Revision,
+			})
+		}()
+	}
+	wg.Wait()
+	require.True(t, (errs[0] == nil) != (errs[1] == nil))
+	for _, err := range errs {
+		if err != nil {
+			require.Equal(t, server.ErrConflict, err)
+		}
+	}
+	pipelineInfo, err = apiServer.GetPipelineInfo(context.Background(), &ppsclient.Pipeline{Name: pipelineName})
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), pipelineInfo.Revision)
+
+	_, err = apiServer.UpdatePipelineInfo(context.Background(), &persist.PipelineInfo{
+		PipelineName: uuid.NewWithoutDashes(),
+	})
+	require.True(t, server.IsErrNotFound(err))
+}
+
+// subscribePipelineInfosServer passes what's sent to it on changes.
+type subscribePipelineInfosServer struct {
+	grpc.ServerStream
+	ctx     context.Context
+	changes chan *persist.PipelineInfoChange
+}
+
+func (s *subscribePipelineInfosServer) Context() context.Context {
+	return s.ctx
+}
+
+func (s *subscribePipelineInfosServer) Send(change *persist.PipelineInfoChange) error {
+	s.changes <- change
+	return nil
+}
+
+func testSubscribePipelineInfos(t *testing.T, apiServer persist.APIServer) {
+	initialName := uuid.NewWithoutDashes()
+	_, err := apiServer.CreatePipelineInfo(context.Background(), &persist.PipelineInfo{
+		PipelineName: initialName,
+		Shard:        1,
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &subscribePipelineInfosServer{
+		ctx:     ctx,
+		changes: make(chan *persist.PipelineInfoChange),
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- apiServer.SubscribePipelineInfos(&persist.SubscribePipelineInfosRequest{
+			IncludeInitial: true,
+			Shard:          &persist.Shard{Number: 1},
+		}, stream)
+	}()
+	change := <-stream.changes
+	require.Equal(t, initialName, change.Pipeline.PipelineName)
+	require.False(t, change.Removed)
+
+	name := uuid.NewWithoutDashes()
+	_, err = apiServer.CreatePipelineInfo(context.Background(), &persist.PipelineInfo{
+		PipelineName: name,
+		Shard:        1,
+	})
+	require.NoError(t, err)
+	change = <-stream.changes
+	require.Equal(t, name, change.Pipeline.PipelineName)
+	require.False(t, change.Removed)
+
+	_, err = apiServer.DeletePipelineInfo(context.Background(), &ppsclient.Pipeline{Name: name})
+	require.NoError(t, err)
+	change = <-stream.changes
+	require.Equal(t, name, change.Pipeline.PipelineName)
+	require.True(t, change.Removed)
+
+	// Cancelling ends the stream cleanly
+	cancel()
+	require.NoError(t, <-errCh)
+}
+
+func testNotFound(t *testing.T, apiServer persist.APIServer) {
+	missing := uuid.NewWithoutDashes()
+	_, err := apiServer.GetPipelineInfo(context.Background(), &ppsclient.Pipeline{Name: missing})
+	require.Equal(t, codes.NotFound, grpc.Code(err))
+	require.True(t, server.IsErrNotFound(err))
+	_, err = apiServer.InspectJob(context.Background(), &ppsclient.InspectJobRequest{Job: &ppsclient.Job{ID: missing}})
+	require.Equal(t, codes.NotFound, grpc.Code(err))
+	_, err = apiServer.UpdateJobState(context.Background(), &persist.JobState{JobID: missing, State: ppsclient.JobState_JOB_RUNNING})
+	require.Equal(t, codes.NotFound, grpc.Code(err))
+
+	pipelineName := uuid.NewWithoutDashes()
+	_, err = apiServer.CreatePipelineInfo(context.Background(), &persist.PipelineInfo{PipelineName: pipelineName})
+	require.NoError(t, err)
+	pipelineInfo, err := apiServer.GetPipelineInfo(context.Background(), &ppsclient.Pipeline{Name: pipelineName})
+	require.NoError(t, err)
+	require.Equal(t, pipelineName, pipelineInfo.PipelineName)
+	jobInfo, err := apiServer.CreateJobInfo(context.Background(), &persist.JobInfo{
+		JobID:        uuid.NewWithoutDashes(),
+		PipelineName: pipelineName,
+	})
+	require.NoError(t, err)
+	inspected, err := apiServer.InspectJob(context.Background(), &ppsclient.InspectJobRequest{Job: &ppsclient.Job{ID: jobInfo.JobID}})
+	require.NoError(t, err)
+	require.Equal(t, jobInfo.JobID, inspected.JobID)
+}
+
+func testPruneJobs(t *testing.T, apiServer persist.APIServer) {
+	pipelineName := uuid.NewWithoutDashes()
+	var jobInfos []*persist.JobInfo
+	for i := 0; i < 5; i++ {
+		jobInfo, err := apiServer.CreateJobInfo(context.Background(), &persist.JobInfo{
+			JobID:        uuid.NewWithoutDashes(),
+			PipelineName: pipelineName,
+		})
+		require.NoError(t, err)
+		jobInfos = append(jobInfos, jobInfo)
+	}
+	listJobInfos := func() []*persist.JobInfo {
+		jobInfos, err := apiServer.ListJobInfos(context.Background(), &persist.ListJobRequest{
+			Pipeline: &ppsclient.Pipeline{Name: pipelineName},
+		})
+		require.NoError(t, err)
+		return jobInfos.JobInfo
+	}
+
+	// A dry run only counts
+	response, err := apiServer.PruneJobs(context.Background(), &persist.PruneJobsRequest{
+		KeepPerPipeline: 3,
+		DryRun:          true,
+	})
+	require.NoError(t, err)
+	require.True(t, response.JobInfos >= 2)
+	require.Equal(t, 5, len(listJobInfos()))
+
+	// Only jobs created before the 4th which aren't among the latest 3 go,
+	// so just the first
+	_, err = apiServer.PruneJobs(context.Background(), &persist.PruneJobsRequest{
+		CreatedBefore:   jobInfos[3].CreatedAt,
+		KeepPerPipeline: 3,
+	})
+	require.NoError(t, err)
+	remaining := listJobInfos()
+	require.Equal(t, 4, len(remaining))
+	require.Equal(t, jobInfos[1].JobID, remaining[3].JobID)
+
+	_, err = apiServer.PruneJobs(context.Background(), &persist.PruneJobsRequest{
+		KeepPerPipeline: 2,
+	})
+	require.NoError(t, err)
+	remaining = listJobInfos()
+	require.Equal(t, 2, len(remaining))
+	require.Equal(t, jobInfos[4].JobID, remaining[0].JobID)
+	require.Equal(t, jobInfos[3].JobID, remaining[1].JobID)
+
+	_, err = apiServer.PruneJobs(context.Background(), &persist.PruneJobsRequest{})
+	require.YesError(t, err)
+}
+
+func testGetJobInfosByCommit(t *testing.T, apiServer persist.APIServer) {
+	commit1 := client.NewCommit(uuid.NewWithoutDashes(), uuid.NewWithoutDashes())
+	commit2 := client.NewCommit(uuid.NewWithoutDashes(), uuid.NewWithoutDashes())
+	createJobInfo := func(commits ...*pfsclient.Commit) *persist.JobInfo {
+		var inputs []*ppsclient.JobInput
+		for _, commit := range commits {
+			inputs = append(inputs, &ppsclient.JobInput{Commit: commit})
+		}
+		jobInfo, err := apiServer.CreateJobInfo(context.Background(), &persist.JobInfo{
+			JobID:  uuid.NewWithoutDashes(),
+			Inputs: inputs,
+		})
+		require.NoError(t, err)
+		return jobInfo
+	}
+	both := createJobInfo(commit1, commit2)
+	createJobInfo()
+	only1 := createJobInfo(commit1)
+
+	jobInfos, err := apiServer.GetJobInfosByCommit(context.Background(), commit1)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(jobInfos.JobInfo))
+	require.Equal(t, only1.JobID, jobInfos.JobInfo[0].JobID)
+	require.Equal(t, both.JobID, jobInfos.JobInfo[1].JobID)
+
+	jobInfos, err = apiServer.GetJobInfosByCommit(context.Background(), commit2)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(jobInfos.JobInfo))
+	require.Equal(t, both.JobID, jobInfos.JobInfo[0].JobID)
+
+	// The same commit ID in another repo isn't matched
+	jobInfos, err = apiServer.GetJobInfosByCommit(context.Background(), client.NewCommit(uuid.NewWithoutDashes(), commit1.ID))
+	require.NoError(t, err)
+	require.Equal(t, 0, len(jobInfos.JobInfo))
+}
+
+// watchJobStatesServer passes what's sent to it on states.
+type watchJobStatesServer struct {
+	grpc.ServerStream
+	ctx    context.Context
+	states chan *persist.JobState
+}
+
+func (s *watchJobStatesServer) Context() context.Context {
+	return s.ctx
+}
+
+func (s *watchJobStatesServer) Send(state *persist.JobState) error {
+	s.states <- state
+	return nil
+}
+
+func testWatchJobStates(t *testing.T, apiServer persist.APIServer) {
+	err := apiServer.WatchJobStates(&ppsclient.Job{ID: uuid.NewWithoutDashes()}, &watchJobStatesServer{
+		ctx:    context.Background(),
+		states: make(chan *persist.JobState),
+	})
+	require.Equal(t, codes.NotFound, grpc.Code(err))
+
+	jobInfo, err := apiServer.CreateJobInfo(context.Background(), &persist.JobInfo{
+		JobID:        uuid.NewWithoutDashes(),
+		PipelineName: uuid.NewWithoutDashes(),
+	})
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &watchJobStatesServer{
+		ctx:    ctx,
+		states: make(chan *persist.JobState),
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- apiServer.WatchJobStates(&ppsclient.Job{ID: jobInfo.JobID}, stream)
+	}()
+	state := <-stream.states
+	require.Equal(t, jobInfo.JobID, state.JobID)
+	require.Equal(t, ppsclient.JobState_JOB_PULLING, state.State)
+
+	_, err = apiServer.UpdateJobState(context.Background(), &persist.JobState{JobID: jobInfo.JobID, State: ppsclient.JobState_JOB_RUNNING})
+	require.NoError(t, err)
+	state = <-stream.states
+	require.Equal(t, ppsclient.JobState_JOB_RUNNING, state.State)
+
+	// Changes that leave the state alone aren't sent
+	_, err = apiServer.StartPod(context.Background(), &ppsclient.Job{ID: jobInfo.JobID})
+	require.NoError(t, err)
+	_, err = apiServer.UpdateJobState(context.Background(), &persist.JobState{JobID: jobInfo.JobID, State: ppsclient.JobState_JOB_SUCCESS})
+	require.NoError(t, err)
+	state = <-stream.states
+	require.Equal(t, ppsclient.JobState_JOB_SUCCESS, state.State)
+
+	// Cancelling ends the stream cleanly
+	cancel()
+	require.NoError(t, <-errCh)
+}
+
+func testJobOutputs(t *testing.T, apiServer persist.APIServer) {
+	_, err := apiServer.GetJobOutput(context.Background(), &ppsclient.Job{ID: uuid.NewWithoutDashes()})
+	require.Equal(t, codes.NotFound, grpc.Code(err))
+
+	jobInfo, err := apiServer.CreateJobInfo(context.Background(), &persist.JobInfo{
+		JobID:        uuid.NewWithoutDashes(),
+		PipelineName: uuid.NewWithoutDashes(),
+	})
+	require.NoError(t, err)
+	// The job hasn't recorded an output yet
+	_, err = apiServer.GetJobOutput(context.Background(), &ppsclient.Job{ID: jobInfo.JobID})
+	require.Equal(t, codes.NotFound, grpc.Code(err))
+
+	commit := client.NewCommit(uuid.NewWithoutDashes(), uuid.NewWithoutDashes())
+	_, err = apiServer.CreateJobOutput(context.Background(), &persist.JobOutput{
+		JobID:        jobInfo.JobID,
+		OutputCommit: commit,
+	})
+	require.NoError(t, err)
+	jobOutput, err := apiServer.GetJobOutput(context.Background(), &ppsclient.Job{ID: jobInfo.JobID})
+	require.NoError(t, err)
+	require.Equal(t, jobInfo.JobID, jobOutput.JobID)
+	require.Equal(t, commit.ID, jobOutput.OutputCommit.ID)
+
+	jobOutputs, err := apiServer.GetJobOutputsByCommit(context.Background(), commit)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(jobOutputs.JobOutput))
+	require.Equal(t, jobInfo.JobID, jobOutputs.JobOutput[0].JobID)
+
+	// The same commit ID in another repo isn't matched
+	jobOutputs, err = apiServer.GetJobOutputsByCommit(context.Background(), client.NewCommit(uuid.NewWithoutDashes(), commit.ID))
+	require.NoError(t, err)
+	require.Equal(t, 0, len(jobOutputs.JobOutput))
+}
+
+func testGetJobState(t *testing.T, apiServer persist.APIServer) {
+	_, err := apiServer.GetJobState(context.Background(), &ppsclient.Job{ID: uuid.NewWithoutDashes()})
+	require.Equal(t, codes.NotFound, grpc.Code(err))
+
+	jobInfo, err := apiServer.CreateJobInfo(context.Background(), &persist.JobInfo{
+		JobID:        uuid.NewWithoutDashes(),
+		PipelineName: uuid.NewWithoutDashes(),
+	})
+	require.NoError(t, err)
+	jobState, err := apiServer.GetJobState(context.Background(), &ppsclient.Job{ID: jobInfo.JobID})
+	require.NoError(t, err)
+	require.Equal(t, jobInfo.JobID, jobState.JobID)
+	require.Equal(t, ppsclient.JobState_JOB_PULLING, jobState.State)
+
+	_, err = apiServer.UpdateJobState(context.Background(), &persist.JobState{JobID: jobInfo.JobID, State: ppsclient.JobState_JOB_RUNNING})
+	require.NoError(t, err)
+	jobState, err = apiServer.GetJobState(context.Background(), &ppsclient.Job{ID: jobInfo.JobID})
+	require.NoError(t, err)
+	require.Equal(t, ppsclient.JobState_JOB_RUNNING, jobState.State)
+}
+
+// benchmarkReadJobState reads the state of a job with 1000 inputs with
+// read.
+func benchmarkReadJobState(b *testing.B, read func(apiServer persist.APIServer, job *ppsclient.Job) error) {
+	if testing.Short() {
+		b.Skip("Skipping benchmark because of short mode.")
+	}
+	apiServer, err := NewTestRethinkAPIServer()
+	require.NoError(b, err)
+	defer func() {
+		require.NoError(b, apiServer.Close())
+	}()
+	var inputs []*ppsclient.JobInput
+	for i := 0; i < 1000; i++ {
+		inputs = append(inputs, &ppsclient.JobInput{Commit: client.NewCommit("repo", uuid.NewWithoutDashes())})
+	}
+	jobInfo, err := apiServer.CreateJobInfo(context.Background(), &persist.JobInfo{
+		JobID:        uuid.NewWithoutDashes(),
+		PipelineName: uuid.NewWithoutDashes(),
+		Inputs:       inputs,
+	})
+	require.NoError(b, err)
+	job := &ppsclient.Job{ID: jobInfo.JobID}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := read(apiServer, job); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func testListJobInfosByState(t *testing.T, apiServer persist.APIServer) {
+	// One job is left in each state, the states the others passed through
+	// on the way mustn't match them.
+	states := []ppsclient.JobState{
+		ppsclient.JobState_JOB_PULLING,
+		ppsclient.JobState_JOB_RUNNING,
+		ppsclient.JobState_JOB_FAILURE,
+		ppsclient.JobState_JOB_SUCCESS,
+	}
+	pipelineName := uuid.NewWithoutDashes()
+	jobIDs := make(map[ppsclient.JobState]string)
+	for _, state := range states {
+		jobInfo, err := apiServer.CreateJobInfo(context.Background(), &persist.JobInfo{
+			JobID:        uuid.NewWithoutDashes(),
+			PipelineName: pipelineName,
+		})
+		require.NoError(t, err)
+		jobIDs[state] = jobInfo.JobID
+		if state == ppsclient.JobState_JOB_PULLING {
+			continue
+		}
+		_, err = apiServer.UpdateJobState(context.Background(), &persist.JobState{JobID: jobInfo.JobID, State: ppsclient.JobState_JOB_RUNNING})
+		require.NoError(t, err)
+		if state != ppsclient.JobState_JOB_RUNNING {
+			_, err = apiServer.UpdateJobState(context.Background(), &persist.JobState{JobID: jobInfo.JobID, State: state})
+			require.NoError(t, err)
+		}
+	}
+
+	for _, test := range []struct {
+		state  []ppsclient.JobState
+		expect []ppsclient.JobState
+	}{
+		{[]ppsclient.JobState{ppsclient.JobState_JOB_RUNNING}, []ppsclient.JobState{ppsclient.JobState_JOB_RUNNING}},
+		{[]ppsclient.JobState{ppsclient.JobState_JOB_FAILURE}, []ppsclient.JobState{ppsclient.JobState_JOB_FAILURE}},
+		{[]ppsclient.JobState{ppsclient.JobState_JOB_SUCCESS}, []ppsclient.JobState{ppsclient.JobState_JOB_SUCCESS}},
+		// Latest first
+		{
+			[]ppsclient.JobState{ppsclient.JobState_JOB_FAILURE, ppsclient.JobState_JOB_SUCCESS},
+			[]ppsclient.JobState{ppsclient.JobState_JOB_SUCCESS, ppsclient.JobState_JOB_FAILURE},
+		},
+	} {
+		jobInfos, err := apiServer.ListJobInfos(context.Background(), &persist.ListJobRequest{
+			Pipeline: &ppsclient.Pipeline{Name: pipelineName},
+			State:    test.state,
+		})
+		require.NoError(t, err)
+		var expected, got []string
+		for _, state := range test.expect {
+			expected = append(expected, jobIDs[state])
+		}
+		for _, jobInfo := range jobInfos.JobInfo {
+			got = append(got, jobInfo.JobID)
+		}
+		require.Equal(t, expected, got)
+	}
+}
@@ -25,7 +25,7 @@ func RunTestWithRethinkAPIServer(t *testing.T, testFunc func(t *testing.T, persi
 func NewTestRethinkAPIServer() (server.APIServer, error) {
 	address := "0.0.0.0:28015"
 	databaseName := uuid.NewWithoutDashes()
-	if err := server.InitDBs(address, databaseName); err != nil {
+	if _, err := server.InitDBs(address, databaseName); err != nil {
 		return nil, err
 	}
 	return server.NewRethinkAPIServer(address, databaseName)
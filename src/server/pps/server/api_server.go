@@ -38,6 +38,9 @@ var (
 	suite   = "pachyderm"
 )
 
+// logChunkSize is roughly how many bytes of logs GetLogs sends at a time.
+const logChunkSize = 64 * 1024
+
 func NewErrJobNotFound(job string) error {
 	return fmt.Errorf("Job %v not found", job)
 }
@@ -278,11 +281,11 @@ func (a *apiServer) CreateJob(ctx context.Context, request *ppsclient.CreateJobR
 
 	defer func() {
 		if retErr != nil {
-			if _, err := persistClient.CreateJobState(ctx, &persist.JobState{
+			if _, err := persistClient.UpdateJobState(ctx, &persist.JobState{
 				JobID: persistJobInfo.JobID,
 				State: ppsclient.JobState_JOB_FAILURE,
 			}); err != nil {
-				protolion.Errorf("error from CreateJobState %s", err.Error())
+				protolion.Errorf("error from UpdateJobState %s", err.Error())
 			}
 		}
 	}()
@@ -475,7 +478,12 @@ func (a *apiServer) ListJob(ctx context.Context, request *ppsclient.ListJobReque
 		return nil, err
 	}
 
-	persistJobInfos, err := persistClient.ListJobInfos(ctx, request)
+	persistJobInfos, err := persistClient.ListJobInfos(ctx, &persist.ListJobRequest{
+		Pipeline:    request.Pipeline,
+		InputCommit: request.InputCommit,
+		PageSize:    request.PageSize,
+		PageToken:   request.PageToken,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -488,7 +496,8 @@ func (a *apiServer) ListJob(ctx context.Context, request *ppsclient.ListJobReque
 		jobInfos[i] = jobInfo
 	}
 	return &ppsclient.JobInfos{
-		JobInfo: jobInfos,
+		JobInfo:       jobInfos,
+		NextPageToken: persistJobInfos.NextPageToken,
 	}, nil
 }
 
@@ -509,7 +518,23 @@ func (a *apiServer) GetLogs(request *ppsclient.GetLogsRequest, apiGetLogsServer
 	}
 	// sort the pods to make sure that the indexes are stable
 	sort.Sort(podSlice(podList.Items))
-	logs := make([][]byte, len(podList.Items))
+	send := func(chunk []byte) error {
+		return apiGetLogsServer.Send(&google_protobuf.BytesValue{Value: chunk})
+	}
+	if !request.Follow {
+		for i, pod := range podList.Items {
+			if err := a.streamPodLogs(apiGetLogsServer.Context(), i, pod, false, send); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	// A followed pod's logs don't end until it exits, so every pod is read
+	// at once and their lines are interleaved. The first error stops them
+	// all.
+	ctx, cancel := context.WithCancel(apiGetLogsServer.Context())
+	defer cancel()
+	var lock sync.Mutex
 	var wg sync.WaitGroup
 	errCh := make(chan error, 1)
 	for i, pod := range podList.Items {
@@ -518,21 +543,17 @@ func (a *apiServer) GetLogs(request *ppsclient.GetLogsRequest, apiGetLogsServer
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			result := a.kubeClient.Pods(a.namespace).GetLogs(
-				pod.ObjectMeta.Name, &api.PodLogOptions{}).Do()
-			value, err := result.Raw()
-			if err != nil {
+			if err := a.streamPodLogs(ctx, i, pod, true, func(chunk []byte) error {
+				lock.Lock()
+				defer lock.Unlock()
+				return send(chunk)
+			}); err != nil {
 				select {
 				default:
 				case errCh <- err:
 				}
+				cancel()
 			}
-			var buffer bytes.Buffer
-			scanner := bufio.NewScanner(bytes.NewBuffer(value))
-			for scanner.Scan() {
-				fmt.Fprintf(&buffer, "%d | %s\n", i, scanner.Text())
-			}
-			logs[i] = buffer.Bytes()
 		}()
 	}
 	wg.Wait()
@@ -541,11 +562,55 @@ func (a *apiServer) GetLogs(request *ppsclient.GetLogsRequest, apiGetLogsServer
 	case err := <-errCh:
 		return err
 	}
-	for _, log := range logs {
-		if err := apiGetLogsServer.Send(&google_protobuf.BytesValue{Value: log}); err != nil {
-			return err
+	return nil
+}
+
+// streamPodLogs passes the logs of pod, the ith pod of its job, to send as
+// they're read from kubernetes, with each line prefixed by i. Lines are sent
+// in chunks of about logChunkSize, or one at a time when following so they
+// arrive live. send blocking slows down reading the logs.
+func (a *apiServer) streamPodLogs(ctx context.Context, i int, pod api.Pod, follow bool, send func([]byte) error) (retErr error) {
+	stream, err := a.kubeClient.Pods(a.namespace).GetLogs(
+		pod.ObjectMeta.Name, &api.PodLogOptions{Follow: follow}).Stream()
+	if err != nil {
+		return err
+	}
+	var closeOnce sync.Once
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		// unblocks the scanner if the caller goes away while a followed pod
+		// is quiet, the caller won't see the error
+		select {
+		case <-ctx.Done():
+			closeOnce.Do(func() { stream.Close() })
+		case <-done:
+		}
+	}()
+	defer func() {
+		closeOnce.Do(func() {
+			if err := stream.Close(); err != nil && retErr == nil {
+				retErr = err
+			}
+		})
+	}()
+	var buffer bytes.Buffer
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		fmt.Fprintf(&buffer, "%d | %s\n", i, scanner.Text())
+		if follow || buffer.Len() >= logChunkSize {
+			if err := send(buffer.Bytes()); err != nil {
+				return err
+			}
+			buffer.Reset()
 		}
 	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if buffer.Len() > 0 {
+		return send(buffer.Bytes())
+	}
 	return nil
 }
 
@@ -721,7 +786,7 @@ func (a *apiServer) FinishJob(ctx context.Context, request *ppsserver.FinishJobR
 		if failed || commitInfo.Cancelled {
 			jobState = ppsclient.JobState_JOB_FAILURE
 		}
-		if _, err := persistClient.CreateJobState(ctx, &persist.JobState{
+		if _, err := persistClient.UpdateJobState(ctx, &persist.JobState{
 			JobID: request.Job.ID,
 			State: jobState,
 		}); err != nil {
@@ -859,7 +924,7 @@ func (a *apiServer) DeletePipeline(ctx context.Context, request *ppsclient.Delet
 
 	// Delete kubernetes jobs.  Otherwise we won't be able to create jobs with
 	// the same IDs, since kubernetes jobs simply use these IDs as their names
-	jobInfos, err := persistClient.ListJobInfos(ctx, &ppsclient.ListJobRequest{
+	jobInfos, err := persistClient.ListJobInfos(ctx, &persist.ListJobRequest{
 		Pipeline: request.Pipeline,
 	})
 	if err != nil {
@@ -923,6 +988,9 @@ func (a *apiServer) AddShard(shard uint64) error {
 		for {
 			pipelineChange, err := client.Recv()
 			if err != nil {
+				if ctx.Err() == nil {
+					protolion.Errorf("pipeline subscription for shard %d failed, no more pipeline changes will be seen: %v", shard, err)
+				}
 				return
 			}
 